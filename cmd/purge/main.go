@@ -0,0 +1,29 @@
+package main
+
+import (
+	"construct-backend/internal/bootstrap"
+	"context"
+	"log"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// handler is meant to run on a schedule (e.g. an EventBridge rule once a day).
+func handler(ctx context.Context) error {
+	purged, err := bootstrap.PurgeTrash(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("purged %d trashed records", purged)
+
+	expired, err := bootstrap.PurgeRateLimits(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("purged %d expired rate limit counts", expired)
+	return nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
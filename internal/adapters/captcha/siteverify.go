@@ -0,0 +1,59 @@
+package captcha
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SiteVerifyAdapter implements ports.CaptchaVerifier against the "siteverify"
+// API shared by reCAPTCHA, hCaptcha and Cloudflare Turnstile: the secret and
+// the token are posted as a form and the provider answers {"success": bool}.
+type SiteVerifyAdapter struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+func NewSiteVerifyAdapter(secret, verifyURL string) *SiteVerifyAdapter {
+	return &SiteVerifyAdapter{
+		secret:    secret,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (a *SiteVerifyAdapter) Verify(token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{
+		"secret":   {a.secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	resp, err := a.client.PostForm(a.verifyURL, form)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return result.Success, nil
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService ports.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService ports.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+type attachmentUploadRequest struct {
+	EntityType  string `json:"entity_type"`
+	EntityID    string `json:"entity_id"`
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+func (h *AttachmentHandler) RequestUpload(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req attachmentUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upload, err := h.attachmentService.RequestUpload(projectID, companyID, userID, req.EntityType, req.EntityID, req.FileName, req.ContentType, req.Size)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+func (h *AttachmentHandler) CompleteUpload(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	attachment, err := h.attachmentService.CompleteUpload(c.Param("attachmentId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c.Param("id"), companyID, c.Query("entity_type"), c.Query("entity_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Param("attachmentId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Param("attachmentId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AttachmentHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "attachment not found", "task not found", "diary item not found", "punch item not found", "change order not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "file name is required", "invalid attachment entity", "invalid file size", "file not uploaded", "file content does not match its type":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "file type not allowed":
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case "file too large":
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case "diary entry is locked", "change order is locked":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
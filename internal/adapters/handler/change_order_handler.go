@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChangeOrderHandler struct {
+	changeOrderService ports.ChangeOrderService
+	attachmentService  ports.AttachmentService
+}
+
+func NewChangeOrderHandler(changeOrderService ports.ChangeOrderService, attachmentService ports.AttachmentService) *ChangeOrderHandler {
+	return &ChangeOrderHandler{
+		changeOrderService: changeOrderService,
+		attachmentService:  attachmentService,
+	}
+}
+
+type changeOrderRequest struct {
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	Category           string  `json:"category"`
+	CostImpact         float64 `json:"cost_impact"`
+	ScheduleImpactDays int     `json:"schedule_impact_days"`
+}
+
+func (r changeOrderRequest) input() ports.ChangeOrderInput {
+	return ports.ChangeOrderInput{
+		Title:              r.Title,
+		Description:        r.Description,
+		Category:           r.Category,
+		CostImpact:         r.CostImpact,
+		ScheduleImpactDays: r.ScheduleImpactDays,
+	}
+}
+
+func (h *ChangeOrderHandler) CreateChangeOrder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req changeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.changeOrderService.CreateChangeOrder(c.Param("id"), companyID, userID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListChangeOrders accepts an optional status filter.
+func (h *ChangeOrderHandler) ListChangeOrders(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	orders, err := h.changeOrderService.ListChangeOrders(c.Param("id"), companyID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if err := h.attachmentService.AttachToChangeOrders(orders); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *ChangeOrderHandler) GetChangeOrder(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := h.changeOrderService.GetChangeOrder(c.Param("orderId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	orders := []domain.ChangeOrder{*order}
+	if err := h.attachmentService.AttachToChangeOrders(orders); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders[0])
+}
+
+func (h *ChangeOrderHandler) UpdateChangeOrder(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req changeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.changeOrderService.UpdateChangeOrder(c.Param("orderId"), c.Param("id"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *ChangeOrderHandler) ChangeChangeOrderStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req statusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.changeOrderService.ChangeChangeOrderStatus(c.Param("orderId"), c.Param("id"), companyID, userID, req.Status, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *ChangeOrderHandler) GetChangeOrderHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.changeOrderService.GetChangeOrderHistory(c.Param("orderId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ChangeOrderHandler) DeleteChangeOrder(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.changeOrderService.DeleteChangeOrder(c.Param("orderId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ChangeOrderHandler) ListPublicChangeOrders(c *gin.Context) {
+	orders, err := h.changeOrderService.ListPublicChangeOrders(c.Param("id"), c.GetHeader(publicProjectPinHeader))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "change orders not found"})
+		return
+	}
+	if err := h.attachmentService.AttachToChangeOrders(orders); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// changeOrderDecisionRequest carries the decision token of the link the order
+// was sent with.
+type changeOrderDecisionRequest struct {
+	Token string `json:"token" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+	Note  string `json:"note"`
+}
+
+// ApprovePublicChangeOrder lets the client accept a change order through the
+// public project link.
+func (h *ChangeOrderHandler) ApprovePublicChangeOrder(c *gin.Context) {
+	h.decidePublicChangeOrder(c, domain.ChangeOrderStatusApproved)
+}
+
+// RejectPublicChangeOrder lets the client turn a change order down through
+// the public project link.
+func (h *ChangeOrderHandler) RejectPublicChangeOrder(c *gin.Context) {
+	h.decidePublicChangeOrder(c, domain.ChangeOrderStatusRejected)
+}
+
+func (h *ChangeOrderHandler) decidePublicChangeOrder(c *gin.Context, decision string) {
+	var req changeOrderDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pin := c.GetHeader(publicProjectPinHeader)
+	order, err := h.changeOrderService.DecidePublicChangeOrder(c.Param("id"), c.Param("orderId"), pin, req.Token, req.Name, decision, req.Note)
+	if err != nil {
+		switch err.Error() {
+		case "project not found or not public", "invalid public project access":
+			c.JSON(http.StatusNotFound, gin.H{"error": "change order not found"})
+		default:
+			h.respondError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *ChangeOrderHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "change order not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "change order title is required", "invalid cost category", "invalid change order status", "invalid change order decision", "signer name is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "status transition not allowed", "change order is locked", "change order already decided", "version conflict":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
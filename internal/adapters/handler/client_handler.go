@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
 	"net/http"
 
@@ -71,7 +72,7 @@ func (h *ClientHandler) ListClients(c *gin.Context) {
 		return
 	}
 
-	clients, err := h.clientService.ListClients(companyID)
+	clients, err := h.clientService.ListClients(companyID, c.Query("archived") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -119,13 +120,59 @@ func (h *ClientHandler) DeleteClient(c *gin.Context) {
 
 	id := c.Param("id")
 	if err := h.clientService.DeleteClient(id, companyID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+func (h *ClientHandler) RestoreClient(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.clientService.RestoreClient(id, companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	client, err := h.clientService.GetClient(id, companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *ClientHandler) ArchiveClient(c *gin.Context) {
+	h.setClientArchived(c, true)
+}
+
+func (h *ClientHandler) UnarchiveClient(c *gin.Context) {
+	h.setClientArchived(c, false)
+}
+
+func (h *ClientHandler) setClientArchived(c *gin.Context, archived bool) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	client, err := h.clientService.SetClientArchived(c.Param("id"), companyID, archived)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
 type addCommentRequest struct {
 	Content string `json:"content" binding:"required"`
 }
@@ -146,3 +193,111 @@ func (h *ClientHandler) AddComment(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, comment)
 }
+
+// GetPipeline returns the kanban of the sales pipeline.
+func (h *ClientHandler) GetPipeline(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	pipeline, err := h.clientService.GetPipeline(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline)
+}
+
+func (h *ClientHandler) GetPipelineStages(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	stages, err := h.clientService.GetPipelineStages(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+func (h *ClientHandler) UpdatePipelineStages(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Stages []domain.PipelineStage `json:"stages" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stages, err := h.clientService.UpdatePipelineStages(companyID, req.Stages)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+type clientPipelineRequest struct {
+	Stage             string  `json:"stage"`
+	LeadSource        string  `json:"lead_source"`
+	EstimatedValue    float64 `json:"estimated_value"`
+	ExpectedCloseDate string  `json:"expected_close_date"`
+	LostReason        string  `json:"lost_reason"`
+}
+
+func (h *ClientHandler) UpdateClientPipeline(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req clientPipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.clientService.UpdateClientPipeline(c.Param("id"), companyID, ports.ClientPipelineInput{
+		Stage:             req.Stage,
+		LeadSource:        req.LeadSource,
+		EstimatedValue:    req.EstimatedValue,
+		ExpectedCloseDate: req.ExpectedCloseDate,
+		LostReason:        req.LostReason,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *ClientHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "client not found", "client not found in trash", "record not found", "company not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid pipeline stage", "invalid lead source", "invalid estimated value", "invalid expected close date",
+		"lost reason is required", "pipeline stage name is required", "invalid pipeline stage outcome",
+		"duplicate pipeline stage", "pipeline needs open stages and one won and one lost stage":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "pipeline stage in use":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
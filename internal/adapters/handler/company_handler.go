@@ -124,6 +124,33 @@ func (h *CompanyHandler) UpdatePublicPage(c *gin.Context) {
 	c.JSON(http.StatusOK, company)
 }
 
+func (h *CompanyHandler) UploadLogo(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	role := c.GetString("role")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin access required"})
+		return
+	}
+
+	data, ok := readImageUpload(c)
+	if !ok {
+		return
+	}
+
+	company, err := h.companyService.UploadLogo(companyID, data)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
 func (h *CompanyHandler) ListMembers(c *gin.Context) {
 	companyID := c.GetString("company_id")
 	role := c.GetString("role")
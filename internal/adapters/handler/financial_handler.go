@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FinancialHandler struct {
+	financialService ports.FinancialService
+}
+
+func NewFinancialHandler(financialService ports.FinancialService) *FinancialHandler {
+	return &FinancialHandler{
+		financialService: financialService,
+	}
+}
+
+type budgetLineRequest struct {
+	Category    string  `json:"category" binding:"required"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+type setBudgetRequest struct {
+	Lines []budgetLineRequest `json:"lines" binding:"required,dive"`
+}
+
+type expenseRequest struct {
+	TaskID      string  `json:"task_id"`
+	Category    string  `json:"category" binding:"required"`
+	Description string  `json:"description"`
+	Supplier    string  `json:"supplier"`
+	Amount      float64 `json:"amount" binding:"required"`
+	ExpenseDate string  `json:"expense_date" binding:"required"`
+}
+
+func (h *FinancialHandler) GetBudget(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	lines, err := h.financialService.GetBudget(projectID, companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lines)
+}
+
+func (h *FinancialHandler) SetBudget(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req setBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lines := make([]domain.BudgetLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, domain.BudgetLine{
+			Category:    line.Category,
+			Description: line.Description,
+			Amount:      line.Amount,
+		})
+	}
+
+	budget, err := h.financialService.SetBudget(projectID, companyID, lines)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+func (h *FinancialHandler) CreateExpense(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req expenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.financialService.CreateExpense(projectID, companyID, userID, req.TaskID, req.Category, req.Description, req.Supplier, req.ExpenseDate, req.Amount)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, expense)
+}
+
+func (h *FinancialHandler) ListExpenses(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	expenses, err := h.financialService.ListExpenses(projectID, companyID, c.Query("task_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expenses)
+}
+
+func (h *FinancialHandler) UpdateExpense(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	expenseID := c.Param("expenseId")
+	var req expenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.financialService.UpdateExpense(expenseID, projectID, companyID, req.TaskID, req.Category, req.Description, req.Supplier, req.ExpenseDate, req.Amount)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
+
+func (h *FinancialHandler) DeleteExpense(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	expenseID := c.Param("expenseId")
+	if err := h.financialService.DeleteExpense(expenseID, projectID, companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FinancialHandler) GetFinancials(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	financials, err := h.financialService.GetFinancials(projectID, companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, financials)
+}
+
+func (h *FinancialHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "task not found or access denied", "expense not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid cost category", "invalid amount", "invalid expense date":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"construct-backend/internal/core/services"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readImageUpload reads the "file" field of a multipart image upload. It
+// writes the error response itself and returns false when there is none.
+func readImageUpload(c *gin.Context) ([]byte, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return nil, false
+	}
+	if header.Size > services.MaxImageUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "image too large"})
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxImageUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return data, true
+}
+
+func respondImageError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found", "company not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid image", "image dimensions too large":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "invalid image type":
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case "image too large":
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
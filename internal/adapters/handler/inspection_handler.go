@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InspectionHandler struct {
+	inspectionService ports.InspectionService
+}
+
+func NewInspectionHandler(inspectionService ports.InspectionService) *InspectionHandler {
+	return &InspectionHandler{inspectionService: inspectionService}
+}
+
+type startInspectionRequest struct {
+	TemplateID string `json:"template_id" binding:"required"`
+	TaskID     string `json:"task_id"`
+}
+
+func (h *InspectionHandler) StartInspection(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req startInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inspection, err := h.inspectionService.StartInspection(c.Param("id"), companyID, userID, req.TemplateID, req.TaskID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, inspection)
+}
+
+// ListInspections accepts an optional task_id filter.
+func (h *InspectionHandler) ListInspections(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	inspections, err := h.inspectionService.ListInspections(c.Param("id"), companyID, c.Query("task_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspections)
+}
+
+func (h *InspectionHandler) GetInspection(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	inspection, err := h.inspectionService.GetInspection(c.Param("inspectionId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+type inspectionResultsRequest struct {
+	Notes string `json:"notes"`
+	Items []struct {
+		Result string `json:"result"`
+		Notes  string `json:"notes"`
+	} `json:"items" binding:"required"`
+}
+
+// RecordResults takes the result of every checklist item, in the order of
+// the inspection's items.
+func (h *InspectionHandler) RecordResults(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req inspectionResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]ports.InspectionResultInput, len(req.Items))
+	for index, item := range req.Items {
+		results[index] = ports.InspectionResultInput{Result: item.Result, Notes: item.Notes}
+	}
+
+	inspection, err := h.inspectionService.RecordResults(c.Param("inspectionId"), c.Param("id"), companyID, userID, req.Notes, results)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+type signInspectionRequest struct {
+	Name string `json:"name" binding:"required"`
+	Role string `json:"role"`
+}
+
+func (h *InspectionHandler) SignInspection(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req signInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	inspection, err := h.inspectionService.SignInspection(c.Param("inspectionId"), c.Param("id"), companyID, userID, req.Name, req.Role)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+func (h *InspectionHandler) DeleteInspection(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.inspectionService.DeleteInspection(c.Param("inspectionId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InspectionHandler) GetQualitySummary(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	summary, err := h.inspectionService.GetQualitySummary(c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func (h *InspectionHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "inspection not found", "inspection template not found", "task not found or access denied":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "inspection results must match the checklist items", "invalid inspection result", "signer name is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "inspection is completed", "inspection has unchecked items":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	invoiceService ports.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService ports.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+type invoiceRequest struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount" binding:"required"`
+	DueDate     string  `json:"due_date" binding:"required"`
+	MilestoneID string  `json:"milestone_id"`
+	Notes       string  `json:"notes"`
+}
+
+func (r invoiceRequest) input() ports.InvoiceInput {
+	return ports.InvoiceInput{
+		Description: r.Description,
+		Amount:      r.Amount,
+		DueDate:     r.DueDate,
+		MilestoneID: r.MilestoneID,
+		Notes:       r.Notes,
+	}
+}
+
+func (h *InvoiceHandler) GetPaymentSchedule(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	schedule, err := h.invoiceService.GetPaymentSchedule(c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreatePaymentSchedule adds the installments of a project in one request.
+func (h *InvoiceHandler) CreatePaymentSchedule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Installments []invoiceRequest `json:"installments" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	installments := make([]ports.InvoiceInput, len(req.Installments))
+	for index, installment := range req.Installments {
+		installments[index] = installment.input()
+	}
+
+	invoices, err := h.invoiceService.CreatePaymentSchedule(c.Param("id"), companyID, userID, installments)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoices)
+}
+
+func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req invoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(c.Param("id"), companyID, userID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(c.Param("invoiceId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req invoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.invoiceService.UpdateInvoice(c.Param("invoiceId"), c.Param("id"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) CancelInvoice(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invoice, err := h.invoiceService.CancelInvoice(c.Param("invoiceId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.invoiceService.DeleteInvoice(c.Param("invoiceId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InvoiceHandler) RecordPayment(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Amount float64 `json:"amount" binding:"required"`
+		PaidAt string  `json:"paid_at"`
+		Method string  `json:"method"`
+		Notes  string  `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.invoiceService.RecordPayment(c.Param("invoiceId"), c.Param("id"), companyID, userID, ports.PaymentInput{
+		Amount: req.Amount,
+		PaidAt: req.PaidAt,
+		Method: req.Method,
+		Notes:  req.Notes,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+func (h *InvoiceHandler) DeletePayment(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invoice, err := h.invoiceService.DeletePayment(c.Param("invoiceId"), c.Param("id"), companyID, c.Param("paymentId"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// ListOverdueInvoices lists the company's overdue invoices across projects.
+func (h *InvoiceHandler) ListOverdueInvoices(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invoices, err := h.invoiceService.ListOverdueInvoices(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+func (h *InvoiceHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "invoice not found", "payment not found", "milestone not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "installments are required", "invalid invoice amount", "invalid due date", "invalid payment amount", "invalid payment date",
+		"too many installments":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "invoice is locked", "invoice has payments", "invoice already paid", "payment exceeds outstanding amount",
+		"invoice amount is below the amount paid", "version conflict":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeadHandler struct {
+	leadService ports.LeadService
+}
+
+func NewLeadHandler(leadService ports.LeadService) *LeadHandler {
+	return &LeadHandler{
+		leadService: leadService,
+	}
+}
+
+// publicLeadRequest is the contact form of the public company page. Website
+// is a honeypot: the field is hidden from people, so only bots fill it in.
+type publicLeadRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Phone        string `json:"phone" binding:"required"`
+	Message      string `json:"message"`
+	CaptchaToken string `json:"captcha_token"`
+	Website      string `json:"website"`
+}
+
+// CreatePublicLead captures a prospect from the public company page. Bots
+// caught by the honeypot get the same answer as people, so they cannot tell
+// they were filtered out.
+func (h *LeadHandler) CreatePublicLead(c *gin.Context) {
+	var req publicLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Website != "" {
+		c.JSON(http.StatusCreated, gin.H{"message": "lead received"})
+		return
+	}
+
+	_, err := h.leadService.CreatePublicLead(c.Param("slug"), ports.PublicLeadInput{
+		Name:         req.Name,
+		Phone:        req.Phone,
+		Message:      req.Message,
+		CaptchaToken: req.CaptchaToken,
+		RemoteIP:     clientIP(c),
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "lead received"})
+}
+
+func (h *LeadHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "public page not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "name and phone are required", "message is too long", "captcha verification failed":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
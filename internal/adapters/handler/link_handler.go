@@ -105,7 +105,27 @@ func (h *LinkHandler) DeleteLink(c *gin.Context) {
 
 	id := c.Param("id")
 	if err := h.linkService.DeleteLink(id, companyID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LinkHandler) RestoreLink(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	role := c.GetString("role")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin access required"})
+		return
+	}
+
+	if err := h.linkService.RestoreLink(c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
 		return
 	}
 
@@ -150,3 +170,12 @@ func (h *LinkHandler) UpdateLink(c *gin.Context) {
 
 	c.JSON(http.StatusOK, link)
 }
+
+func (h *LinkHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "link not found", "link not found in trash":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaterialHandler struct {
+	materialService ports.MaterialService
+}
+
+func NewMaterialHandler(materialService ports.MaterialService) *MaterialHandler {
+	return &MaterialHandler{
+		materialService: materialService,
+	}
+}
+
+type materialRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Unit        string  `json:"unit" binding:"required"`
+	Description string  `json:"description"`
+	MinStock    float64 `json:"min_stock"`
+}
+
+type stockMovementRequest struct {
+	MaterialID   string  `json:"material_id" binding:"required"`
+	Type         string  `json:"type" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required"`
+	MovementDate string  `json:"movement_date"`
+	Notes        string  `json:"notes"`
+}
+
+type stockTransferRequest struct {
+	MaterialID   string  `json:"material_id" binding:"required"`
+	ToProjectID  string  `json:"to_project_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required"`
+	MovementDate string  `json:"movement_date"`
+	Notes        string  `json:"notes"`
+}
+
+type materialThresholdRequest struct {
+	MinQuantity float64 `json:"min_quantity"`
+}
+
+type purchaseRequestRequest struct {
+	MaterialID string  `json:"material_id" binding:"required"`
+	Quantity   float64 `json:"quantity" binding:"required"`
+	Notes      string  `json:"notes"`
+}
+
+type purchaseDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+type purchaseReceiptRequest struct {
+	ReceivedDate string `json:"received_date"`
+}
+
+func (h *MaterialHandler) CreateMaterial(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req materialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	material, err := h.materialService.CreateMaterial(companyID, req.Name, req.Unit, req.Description, req.MinStock)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, material)
+}
+
+func (h *MaterialHandler) ListMaterials(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	materials, err := h.materialService.ListMaterials(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, materials)
+}
+
+func (h *MaterialHandler) UpdateMaterial(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("materialId")
+	var req materialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	material, err := h.materialService.UpdateMaterial(id, companyID, req.Name, req.Unit, req.Description, req.MinStock)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, material)
+}
+
+func (h *MaterialHandler) DeleteMaterial(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("materialId")
+	if err := h.materialService.DeleteMaterial(id, companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MaterialHandler) GetProjectStock(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	stock, err := h.materialService.GetProjectStock(projectID, companyID, c.Query("low_stock") == "true")
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stock)
+}
+
+func (h *MaterialHandler) ListStockMovements(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	movements, err := h.materialService.ListStockMovements(projectID, companyID, c.Query("material_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+func (h *MaterialHandler) RecordStockMovement(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req stockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement, err := h.materialService.RecordStockMovement(projectID, companyID, userID, req.MaterialID, req.Type, req.MovementDate, req.Notes, req.Quantity)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movement)
+}
+
+func (h *MaterialHandler) TransferStock(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req stockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movements, err := h.materialService.TransferStock(projectID, companyID, userID, req.MaterialID, req.ToProjectID, req.MovementDate, req.Notes, req.Quantity)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movements)
+}
+
+func (h *MaterialHandler) SetMaterialThreshold(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	materialID := c.Param("materialId")
+	var req materialThresholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	threshold, err := h.materialService.SetMaterialThreshold(projectID, companyID, materialID, req.MinQuantity)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, threshold)
+}
+
+func (h *MaterialHandler) CreatePurchaseRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req purchaseRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.materialService.CreatePurchaseRequest(projectID, companyID, userID, req.MaterialID, req.Notes, req.Quantity)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *MaterialHandler) ListPurchaseRequests(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	requests, err := h.materialService.ListPurchaseRequests(projectID, companyID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func (h *MaterialHandler) ApprovePurchaseRequest(c *gin.Context) {
+	h.decidePurchaseRequest(c, h.materialService.ApprovePurchaseRequest)
+}
+
+func (h *MaterialHandler) RejectPurchaseRequest(c *gin.Context) {
+	h.decidePurchaseRequest(c, h.materialService.RejectPurchaseRequest)
+}
+
+func (h *MaterialHandler) ReceivePurchaseRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	requestID := c.Param("requestId")
+	var req purchaseReceiptRequest
+	// The receipt date is optional, so an empty body is accepted.
+	c.ShouldBindJSON(&req)
+
+	request, err := h.materialService.ReceivePurchaseRequest(requestID, projectID, companyID, userID, req.ReceivedDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *MaterialHandler) decidePurchaseRequest(c *gin.Context, decide func(id, projectID, companyID, userID, note string) (*domain.PurchaseRequest, error)) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	role := c.GetString("role")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin access required"})
+		return
+	}
+
+	projectID := c.Param("id")
+	requestID := c.Param("requestId")
+	var req purchaseDecisionRequest
+	// The decision note is optional, so an empty body is accepted.
+	c.ShouldBindJSON(&req)
+
+	request, err := decide(requestID, projectID, companyID, userID, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *MaterialHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "material not found", "purchase request not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid quantity", "invalid movement type", "invalid movement date", "invalid destination project":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "insufficient stock", "purchase request is not pending", "purchase request is not approved":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
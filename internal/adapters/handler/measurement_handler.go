@@ -0,0 +1,425 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MeasurementHandler struct {
+	measurementService ports.MeasurementService
+}
+
+func NewMeasurementHandler(measurementService ports.MeasurementService) *MeasurementHandler {
+	return &MeasurementHandler{
+		measurementService: measurementService,
+	}
+}
+
+type contractItemRequest struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description" binding:"required"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity" binding:"required"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+func (r contractItemRequest) input() ports.ContractItemInput {
+	return ports.ContractItemInput{
+		Code:        r.Code,
+		Description: r.Description,
+		Unit:        r.Unit,
+		Quantity:    r.Quantity,
+		UnitPrice:   r.UnitPrice,
+	}
+}
+
+type measurementRequest struct {
+	PeriodStart string `json:"period_start" binding:"required"`
+	PeriodEnd   string `json:"period_end" binding:"required"`
+	Notes       string `json:"notes"`
+	Items       []struct {
+		ContractItemID string  `json:"contract_item_id" binding:"required"`
+		Quantity       float64 `json:"quantity"`
+	} `json:"items" binding:"dive"`
+}
+
+// input sums the quantities given for the same contract item.
+func (r measurementRequest) input() ports.MeasurementInput {
+	quantities := make(map[string]float64, len(r.Items))
+	for _, item := range r.Items {
+		quantities[item.ContractItemID] += item.Quantity
+	}
+	return ports.MeasurementInput{
+		PeriodStart: r.PeriodStart,
+		PeriodEnd:   r.PeriodEnd,
+		Notes:       r.Notes,
+		Quantities:  quantities,
+	}
+}
+
+func (h *MeasurementHandler) ListContractItems(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	items, err := h.measurementService.ListContractItems(c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *MeasurementHandler) CreateContractItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contractItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.measurementService.CreateContractItem(c.Param("id"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *MeasurementHandler) UpdateContractItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req contractItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.measurementService.UpdateContractItem(c.Param("itemId"), c.Param("id"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *MeasurementHandler) DeleteContractItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.measurementService.DeleteContractItem(c.Param("itemId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MeasurementHandler) CreateMeasurement(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req measurementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measurement, err := h.measurementService.CreateMeasurement(c.Param("id"), companyID, userID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, measurement)
+}
+
+// ListMeasurements accepts an optional status filter.
+func (h *MeasurementHandler) ListMeasurements(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	measurements, err := h.measurementService.ListMeasurements(c.Param("id"), companyID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, measurements)
+}
+
+func (h *MeasurementHandler) GetMeasurement(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	measurement, err := h.measurementService.GetMeasurement(c.Param("measurementId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, measurement)
+}
+
+func (h *MeasurementHandler) UpdateMeasurement(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req measurementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measurement, err := h.measurementService.UpdateMeasurement(c.Param("measurementId"), c.Param("id"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, measurement)
+}
+
+func (h *MeasurementHandler) ChangeMeasurementStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req statusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measurement, err := h.measurementService.ChangeMeasurementStatus(c.Param("measurementId"), c.Param("id"), companyID, userID, req.Status, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, measurement)
+}
+
+func (h *MeasurementHandler) GetMeasurementHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.measurementService.GetMeasurementHistory(c.Param("measurementId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *MeasurementHandler) DeleteMeasurement(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.measurementService.DeleteMeasurement(c.Param("measurementId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// BillMeasurement creates the invoice of an approved measurement.
+func (h *MeasurementHandler) BillMeasurement(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		DueDate string `json:"due_date" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.measurementService.BillMeasurement(c.Param("measurementId"), c.Param("id"), companyID, userID, req.DueDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+func (h *MeasurementHandler) ExportMeasurementPDF(c *gin.Context) {
+	h.exportMeasurement(c, "pdf")
+}
+
+func (h *MeasurementHandler) ExportMeasurementCSV(c *gin.Context) {
+	h.exportMeasurement(c, "csv")
+}
+
+func (h *MeasurementHandler) exportMeasurement(c *gin.Context, format string) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	measurementID := c.Param("measurementId")
+	data, err := h.measurementService.ExportMeasurement(measurementID, c.Param("id"), companyID, format)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	sendMeasurementExport(c, measurementID, format, data)
+}
+
+func (h *MeasurementHandler) ListPublicMeasurements(c *gin.Context) {
+	measurements, err := h.measurementService.ListPublicMeasurements(c.Param("id"), c.GetHeader(publicProjectPinHeader))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "measurements not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, measurements)
+}
+
+func (h *MeasurementHandler) ExportPublicMeasurementPDF(c *gin.Context) {
+	h.exportPublicMeasurement(c, "pdf")
+}
+
+func (h *MeasurementHandler) ExportPublicMeasurementCSV(c *gin.Context) {
+	h.exportPublicMeasurement(c, "csv")
+}
+
+// exportPublicMeasurement accepts the PIN in the X-Project-Pin header or, for
+// plain download links, the pin query parameter.
+func (h *MeasurementHandler) exportPublicMeasurement(c *gin.Context, format string) {
+	pin := c.GetHeader(publicProjectPinHeader)
+	if pin == "" {
+		pin = c.Query("pin")
+	}
+
+	measurementID := c.Param("measurementId")
+	data, err := h.measurementService.ExportPublicMeasurement(c.Param("id"), measurementID, pin, format)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "measurement not found"})
+		return
+	}
+
+	sendMeasurementExport(c, measurementID, format, data)
+}
+
+// measurementDecisionRequest carries the decision token of the link the
+// measurement was submitted with.
+type measurementDecisionRequest struct {
+	Token string `json:"token" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+	Note  string `json:"note"`
+}
+
+// ApprovePublicMeasurement lets the client approve a measurement through the
+// public project link.
+func (h *MeasurementHandler) ApprovePublicMeasurement(c *gin.Context) {
+	h.decidePublicMeasurement(c, domain.MeasurementStatusApproved)
+}
+
+// RejectPublicMeasurement lets the client turn a measurement down through the
+// public project link.
+func (h *MeasurementHandler) RejectPublicMeasurement(c *gin.Context) {
+	h.decidePublicMeasurement(c, domain.MeasurementStatusRejected)
+}
+
+func (h *MeasurementHandler) decidePublicMeasurement(c *gin.Context, decision string) {
+	var req measurementDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pin := c.GetHeader(publicProjectPinHeader)
+	measurement, err := h.measurementService.DecidePublicMeasurement(c.Param("id"), c.Param("measurementId"), pin, req.Token, req.Name, decision, req.Note)
+	if err != nil {
+		switch err.Error() {
+		case "project not found or not public", "invalid public project access":
+			c.JSON(http.StatusNotFound, gin.H{"error": "measurement not found"})
+		default:
+			h.respondError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, measurement)
+}
+
+func (h *MeasurementHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "contract item not found", "measurement not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "contract item description is required", "invalid contract item amount", "contract items are required",
+		"invalid measurement period", "invalid measured quantity", "measured quantity exceeds contract quantity",
+		"invalid measurement status", "invalid measurement decision", "signer name is required",
+		"invalid export format", "invalid due date", "invalid invoice amount":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "status transition not allowed", "measurement is locked", "measurement already decided",
+		"previous measurement is not approved", "contract item has measurements", "contract quantity is below the measured quantity",
+		"only approved measurements can be billed", "measurement already billed", "measurement has no billable amount":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func sendMeasurementExport(c *gin.Context, measurementID, format string, data []byte) {
+	contentType := "application/pdf"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="medicao-%s.%s"`, measurementID, format))
+	c.Data(http.StatusOK, contentType, data)
+}
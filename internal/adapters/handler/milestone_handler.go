@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MilestoneHandler struct {
+	milestoneService ports.MilestoneService
+}
+
+func NewMilestoneHandler(milestoneService ports.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{
+		milestoneService: milestoneService,
+	}
+}
+
+type milestoneRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	TargetDate  string   `json:"target_date" binding:"required"`
+	Weight      *float64 `json:"weight"`
+}
+
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req milestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.CreateMilestone(projectID, companyID, req.Name, req.Description, req.TargetDate, req.Weight)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, milestone)
+}
+
+func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	milestones, err := h.milestoneService.ListMilestones(projectID, companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	milestoneID := c.Param("milestoneId")
+	var req milestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.UpdateMilestone(milestoneID, projectID, companyID, req.Name, req.Description, req.TargetDate, req.Weight)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	milestoneID := c.Param("milestoneId")
+	if err := h.milestoneService.DeleteMilestone(milestoneID, projectID, companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MilestoneHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "milestone not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "milestone name is required", "invalid target date", "invalid weight":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
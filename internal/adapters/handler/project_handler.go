@@ -6,6 +6,7 @@ import (
 	"construct-backend/internal/core/services"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,12 +15,14 @@ import (
 type ProjectHandler struct {
 	projectService      ports.ProjectService
 	subscriptionService *services.SubscriptionService
+	attachmentService   ports.AttachmentService
 }
 
-func NewProjectHandler(projectService ports.ProjectService, subscriptionService *services.SubscriptionService) *ProjectHandler {
+func NewProjectHandler(projectService ports.ProjectService, subscriptionService *services.SubscriptionService, attachmentService ports.AttachmentService) *ProjectHandler {
 	return &ProjectHandler{
 		projectService:      projectService,
 		subscriptionService: subscriptionService,
+		attachmentService:   attachmentService,
 	}
 }
 
@@ -72,14 +75,15 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 func (h *ProjectHandler) ListProjects(c *gin.Context) {
 	companyID := c.GetString("company_id")
 	clientID := c.Query("client_id")
+	archived := c.Query("archived") == "true"
 
 	var projects []domain.Project
 	var err error
 
 	if clientID != "" {
-		projects, err = h.projectService.ListProjectsByClient(clientID, companyID)
+		projects, err = h.projectService.ListProjectsByClient(clientID, companyID, archived)
 	} else {
-		projects, err = h.projectService.ListProjects(companyID)
+		projects, err = h.projectService.ListProjects(companyID, archived)
 	}
 
 	if err != nil {
@@ -167,17 +171,114 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 
 	id := c.Param("id")
 	if err := h.projectService.DeleteProject(id, companyID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondTaskError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+func (h *ProjectHandler) RestoreProject(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.subscriptionService.CheckProjectLimit(companyID); err != nil {
+		if strings.HasPrefix(err.Error(), "limite_atingido") {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":            err.Error(),
+				"upgrade_required": true,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.projectService.RestoreProject(id, companyID); err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	project, err := h.projectService.GetProject(id, companyID)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, true)
+}
+
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, false)
+}
+
+func (h *ProjectHandler) setProjectArchived(c *gin.Context, archived bool) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	project, err := h.projectService.SetProjectArchived(c.Param("id"), companyID, archived)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+type taskDependencyRequest struct {
+	DependsOnTaskID string `json:"depends_on_task_id" binding:"required"`
+	Type            string `json:"type"`
+	LagDays         int    `json:"lag_days"`
+}
+
 type createTaskRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Status  string `json:"status" binding:"required"`
-	DueDate string `json:"due_date" binding:"required"`
+	Name         string                  `json:"name" binding:"required"`
+	Status       string                  `json:"status"`
+	DueDate      string                  `json:"due_date" binding:"required"`
+	StartDate    string                  `json:"start_date"`
+	Duration     *int                    `json:"duration"`
+	Dependencies []taskDependencyRequest `json:"dependencies"`
+	MilestoneID  *string                 `json:"milestone_id"`
+	Weight       *float64                `json:"weight"`
+	AssigneeIDs  []string                `json:"assignee_ids"`
+	Crew         *string                 `json:"crew"`
+}
+
+type taskAssignmentRequest struct {
+	AssigneeIDs []string `json:"assignee_ids"`
+	Crew        *string  `json:"crew"`
+}
+
+func (req taskDependencyRequest) toDomain() domain.TaskDependency {
+	return domain.TaskDependency{
+		DependsOnTaskID: req.DependsOnTaskID,
+		Type:            req.Type,
+		LagDays:         req.LagDays,
+	}
+}
+
+// toTaskDependencies keeps a nil slice nil so that omitting the field leaves
+// the existing dependencies untouched.
+func toTaskDependencies(dependencies []taskDependencyRequest) []domain.TaskDependency {
+	if dependencies == nil {
+		return nil
+	}
+	result := make([]domain.TaskDependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		result = append(result, dependency.toDomain())
+	}
+	return result
 }
 
 func (h *ProjectHandler) AddTask(c *gin.Context) {
@@ -196,9 +297,21 @@ func (h *ProjectHandler) AddTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.projectService.AddTask(projectID, req.Name, req.Status, req.DueDate, companyID, userID)
+	schedule := ports.TaskScheduleInput{
+		StartDate:    req.StartDate,
+		DueDate:      req.DueDate,
+		Duration:     req.Duration,
+		Dependencies: toTaskDependencies(req.Dependencies),
+		MilestoneID:  req.MilestoneID,
+		Weight:       req.Weight,
+	}
+	assignment := ports.TaskAssignmentInput{
+		AssigneeIDs: req.AssigneeIDs,
+		Crew:        req.Crew,
+	}
+	task, err := h.projectService.AddTask(projectID, req.Name, req.Status, companyID, userID, schedule, assignment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondTaskError(c, err)
 		return
 	}
 
@@ -206,7 +319,13 @@ func (h *ProjectHandler) AddTask(c *gin.Context) {
 }
 
 type updateTaskRequest struct {
-	Status string `json:"status"`
+	Status       string                  `json:"status"`
+	StartDate    string                  `json:"start_date"`
+	DueDate      string                  `json:"due_date"`
+	Duration     *int                    `json:"duration"`
+	Dependencies []taskDependencyRequest `json:"dependencies"`
+	MilestoneID  *string                 `json:"milestone_id"`
+	Weight       *float64                `json:"weight"`
 }
 
 func (h *ProjectHandler) UpdateTask(c *gin.Context) {
@@ -216,14 +335,25 @@ func (h *ProjectHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	userID := c.GetString("user_id")
 	id := c.Param("taskId")
 	var req updateTaskRequest
-	// We use ShouldBindJSON but don't error out if it fails, to maintain compatibility with parameterless PUT (toggle)
-	c.ShouldBindJSON(&req)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	task, err := h.projectService.UpdateTask(id, companyID, req.Status)
+	schedule := ports.TaskScheduleInput{
+		StartDate:    req.StartDate,
+		DueDate:      req.DueDate,
+		Duration:     req.Duration,
+		Dependencies: toTaskDependencies(req.Dependencies),
+		MilestoneID:  req.MilestoneID,
+		Weight:       req.Weight,
+	}
+	task, err := h.projectService.UpdateTask(id, companyID, userID, req.Status, schedule)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondTaskError(c, err)
 		return
 	}
 
@@ -247,35 +377,145 @@ func (h *ProjectHandler) DeleteTask(c *gin.Context) {
 }
 
 type createSubtaskRequest struct {
-	Name   string `json:"name" binding:"required"`
-	Status string `json:"status" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Status      string   `json:"status" binding:"required"`
+	AssigneeIDs []string `json:"assignee_ids"`
+	Crew        *string  `json:"crew"`
 }
 
 type diaryItemRequest struct {
-	Type       string `json:"type" binding:"required"`
-	Label      string `json:"label"`
-	Content    string `json:"content" binding:"required"`
-	Visibility string `json:"visibility" binding:"required"`
+	ID         string                        `json:"id"`
+	Type       string                        `json:"type" binding:"required"`
+	Label      string                        `json:"label"`
+	Content    string                        `json:"content"`
+	Visibility string                        `json:"visibility" binding:"required"`
+	Weather    *domain.DiaryWeather          `json:"weather"`
+	Workforce  []domain.DiaryWorkforce       `json:"workforce"`
+	Equipment  []domain.DiaryEquipment       `json:"equipment"`
+	Occurrence *domain.DiaryOccurrence       `json:"occurrence"`
+	Services   []domain.DiaryExecutedService `json:"services"`
 }
 
+// diaryEntryRequest may leave items out when it names a diary template; the
+// template then provides them.
 type diaryEntryRequest struct {
-	EntryDate string             `json:"entry_date" binding:"required"`
-	Title     string             `json:"title"`
-	Items     []diaryItemRequest `json:"items" binding:"required,min=1"`
+	EntryDate  string             `json:"entry_date" binding:"required"`
+	Title      string             `json:"title"`
+	TemplateID string             `json:"template_id"`
+	Items      []diaryItemRequest `json:"items"`
 }
 
 func validateDiaryItems(items []diaryItemRequest) error {
 	for _, item := range items {
-		if item.Type != "text" && item.Type != "field" {
-			return fmt.Errorf("invalid diary item type")
-		}
 		if item.Visibility != "public" && item.Visibility != "internal" {
 			return fmt.Errorf("invalid diary item visibility")
 		}
+
+		switch item.Type {
+		case domain.DiaryItemText, domain.DiaryItemField:
+			if strings.TrimSpace(item.Content) == "" {
+				return fmt.Errorf("diary item content is required")
+			}
+		case domain.DiaryItemAttachment:
+			// Files are linked to the item after it is saved; content is an
+			// optional caption.
+		case domain.DiaryItemWeather:
+			if item.Weather == nil {
+				return fmt.Errorf("weather is required")
+			}
+			if !domain.IsWeatherCondition(item.Weather.Morning) || !domain.IsWeatherCondition(item.Weather.Afternoon) {
+				return fmt.Errorf("invalid weather condition")
+			}
+			if item.Weather.StoppageHours < 0 || item.Weather.StoppageHours > 24 {
+				return fmt.Errorf("invalid stoppage hours")
+			}
+			if item.Weather.StoppageHours > 0 && !item.Weather.RainStoppage {
+				return fmt.Errorf("stoppage hours require a rain stoppage")
+			}
+		case domain.DiaryItemWorkforce:
+			if len(item.Workforce) == 0 {
+				return fmt.Errorf("workforce is required")
+			}
+			for _, crew := range item.Workforce {
+				if strings.TrimSpace(crew.Trade) == "" {
+					return fmt.Errorf("workforce trade is required")
+				}
+				if crew.Headcount <= 0 {
+					return fmt.Errorf("invalid headcount")
+				}
+			}
+		case domain.DiaryItemEquipment:
+			if len(item.Equipment) == 0 {
+				return fmt.Errorf("equipment is required")
+			}
+			for _, equipment := range item.Equipment {
+				if strings.TrimSpace(equipment.Name) == "" {
+					return fmt.Errorf("equipment name is required")
+				}
+				if equipment.Quantity <= 0 {
+					return fmt.Errorf("invalid equipment quantity")
+				}
+				if equipment.Hours < 0 || equipment.Hours > 24 {
+					return fmt.Errorf("invalid equipment hours")
+				}
+			}
+		case domain.DiaryItemOccurrence:
+			if item.Occurrence == nil {
+				return fmt.Errorf("occurrence is required")
+			}
+			if !domain.IsOccurrenceCategory(item.Occurrence.Category) {
+				return fmt.Errorf("invalid occurrence category")
+			}
+			if !domain.IsOccurrenceSeverity(item.Occurrence.Severity) {
+				return fmt.Errorf("invalid occurrence severity")
+			}
+			if strings.TrimSpace(item.Content) == "" {
+				return fmt.Errorf("diary item content is required")
+			}
+		case domain.DiaryItemService:
+			if len(item.Services) == 0 {
+				return fmt.Errorf("services are required")
+			}
+			for _, service := range item.Services {
+				if strings.TrimSpace(service.Description) == "" {
+					return fmt.Errorf("service description is required")
+				}
+			}
+		default:
+			return fmt.Errorf("invalid diary item type")
+		}
 	}
 	return nil
 }
 
+// diaryItemsFromRequest keeps only the section matching each item's type.
+func diaryItemsFromRequest(requests []diaryItemRequest) []domain.DiaryItem {
+	items := make([]domain.DiaryItem, 0, len(requests))
+	for _, request := range requests {
+		item := domain.DiaryItem{
+			ID:         request.ID,
+			Type:       request.Type,
+			Label:      request.Label,
+			Content:    request.Content,
+			Visibility: request.Visibility,
+		}
+		switch request.Type {
+		case domain.DiaryItemWeather:
+			item.Weather = request.Weather
+		case domain.DiaryItemWorkforce:
+			item.Workforce = request.Workforce
+		case domain.DiaryItemEquipment:
+			item.Equipment = request.Equipment
+		case domain.DiaryItemOccurrence:
+			item.Occurrence = request.Occurrence
+		case domain.DiaryItemService:
+			item.Services = request.Services
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func (h *ProjectHandler) AddSubtask(c *gin.Context) {
 	userID := c.GetString("user_id")
 	companyID := c.GetString("company_id")
@@ -291,9 +531,13 @@ func (h *ProjectHandler) AddSubtask(c *gin.Context) {
 		return
 	}
 
-	subtask, err := h.projectService.AddSubtask(taskID, req.Name, req.Status, companyID, userID)
+	assignment := ports.TaskAssignmentInput{
+		AssigneeIDs: req.AssigneeIDs,
+		Crew:        req.Crew,
+	}
+	subtask, err := h.projectService.AddSubtask(taskID, req.Name, req.Status, companyID, userID, assignment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondTaskError(c, err)
 		return
 	}
 
@@ -320,19 +564,9 @@ func (h *ProjectHandler) CreateDiaryEntry(c *gin.Context) {
 		return
 	}
 
-	items := make([]domain.DiaryItem, 0, len(req.Items))
-	for _, item := range req.Items {
-		items = append(items, domain.DiaryItem{
-			Type:       item.Type,
-			Label:      item.Label,
-			Content:    item.Content,
-			Visibility: item.Visibility,
-		})
-	}
-
-	entry, err := h.projectService.CreateDiaryEntry(projectID, companyID, userID, req.EntryDate, req.Title, items)
+	entry, err := h.projectService.CreateDiaryEntry(projectID, companyID, userID, req.EntryDate, req.Title, req.TemplateID, diaryItemsFromRequest(req.Items))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondDiaryError(c, err)
 		return
 	}
 
@@ -352,6 +586,10 @@ func (h *ProjectHandler) ListDiaryEntries(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := h.attachmentService.AttachToDiaryEntries(entries); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, entries)
 }
@@ -364,11 +602,37 @@ func (h *ProjectHandler) ListPublicDiaryEntries(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "diary not found"})
 		return
 	}
+	// Entries only carry public items here, so attachments of internal items
+	// are never handed out.
+	if err := h.attachmentService.AttachToDiaryEntries(entries); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, entries)
 }
 
+// GetDiarySummary aggregates the structured diary items of a month
+// (?month=2006-01, the current one by default) or of a from/to date range.
+func (h *ProjectHandler) GetDiarySummary(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	summary, err := h.projectService.GetDiarySummary(projectID, companyID, c.Query("month"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
 func (h *ProjectHandler) UpdateDiaryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
 	companyID := c.GetString("company_id")
 	if companyID == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -383,24 +647,19 @@ func (h *ProjectHandler) UpdateDiaryEntry(c *gin.Context) {
 		return
 	}
 
-	if err := validateDiaryItems(req.Items); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "diary items are required"})
 		return
 	}
 
-	items := make([]domain.DiaryItem, 0, len(req.Items))
-	for _, item := range req.Items {
-		items = append(items, domain.DiaryItem{
-			Type:       item.Type,
-			Label:      item.Label,
-			Content:    item.Content,
-			Visibility: item.Visibility,
-		})
+	if err := validateDiaryItems(req.Items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	entry, err := h.projectService.UpdateDiaryEntry(entryID, projectID, companyID, req.EntryDate, req.Title, items)
+	entry, err := h.projectService.UpdateDiaryEntry(entryID, projectID, companyID, userID, req.EntryDate, req.Title, diaryItemsFromRequest(req.Items))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondDiaryError(c, err)
 		return
 	}
 
@@ -417,13 +676,211 @@ func (h *ProjectHandler) DeleteDiaryEntry(c *gin.Context) {
 	projectID := c.Param("id")
 	entryID := c.Param("entryId")
 	if err := h.projectService.DeleteDiaryEntry(entryID, projectID, companyID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondDiaryError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+func (h *ProjectHandler) SubmitDiaryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entry, err := h.projectService.SubmitDiaryEntry(c.Param("entryId"), c.Param("id"), companyID, userID)
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *ProjectHandler) ApproveDiaryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	role := c.GetString("role")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin access required"})
+		return
+	}
+
+	entry, err := h.projectService.ApproveDiaryEntry(c.Param("entryId"), c.Param("id"), companyID, userID)
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+type rejectDiaryEntryRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+func (h *ProjectHandler) RejectDiaryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	role := c.GetString("role")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin access required"})
+		return
+	}
+
+	var req rejectDiaryEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.projectService.RejectDiaryEntry(c.Param("entryId"), c.Param("id"), companyID, userID, req.Note)
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+type diaryAmendmentRequest struct {
+	Title string             `json:"title"`
+	Items []diaryItemRequest `json:"items" binding:"required,min=1"`
+}
+
+// AmendDiaryEntry opens a correction of an approved entry as a new draft;
+// the original stays as it was signed.
+func (h *ProjectHandler) AmendDiaryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req diaryAmendmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateDiaryItems(req.Items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.projectService.AmendDiaryEntry(c.Param("entryId"), c.Param("id"), companyID, userID, req.Title, diaryItemsFromRequest(req.Items))
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *ProjectHandler) GetDiaryEntryHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.projectService.GetDiaryEntryHistory(c.Param("entryId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ProjectHandler) ListDiaryRevisions(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	revisions, err := h.projectService.ListDiaryRevisions(c.Param("entryId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffDiaryRevisions compares ?from and ?to revision numbers; by default the
+// latest revision against the one before it.
+func (h *ProjectHandler) DiffDiaryRevisions(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var revisions [2]int
+	for i, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+			return
+		}
+		revisions[i] = number
+	}
+
+	diff, err := h.projectService.DiffDiaryRevisions(c.Param("entryId"), c.Param("id"), companyID, revisions[0], revisions[1])
+	if err != nil {
+		h.respondDiaryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+type acknowledgeDiaryEntryRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AcknowledgePublicDiaryEntry lets the client sign an approved entry off
+// through the public project link.
+func (h *ProjectHandler) AcknowledgePublicDiaryEntry(c *gin.Context) {
+	var req acknowledgeDiaryEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pin := c.GetHeader(publicProjectPinHeader)
+	entry, err := h.projectService.AcknowledgePublicDiaryEntry(c.Param("id"), c.Param("entryId"), pin, req.Name)
+	if err != nil {
+		switch err.Error() {
+		case "project not found or not public", "invalid public project access":
+			c.JSON(http.StatusNotFound, gin.H{"error": "diary not found"})
+		default:
+			h.respondDiaryError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
 func (h *ProjectHandler) UpdateSubtask(c *gin.Context) {
 	companyID := c.GetString("company_id")
 	if companyID == "" {
@@ -502,3 +959,228 @@ func (h *ProjectHandler) ListTasks(c *gin.Context) {
 
 	c.JSON(http.StatusOK, tasks)
 }
+
+func (h *ProjectHandler) GetSchedule(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	schedule, err := h.projectService.GetSchedule(projectID, companyID)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+type statusChangeRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+func (h *ProjectHandler) ChangeProjectStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	var req statusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.ChangeProjectStatus(id, companyID, userID, req.Status, req.Note)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) GetProjectStatusHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	history, err := h.projectService.GetProjectStatusHistory(id, companyID)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ProjectHandler) ChangeTaskStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("taskId")
+	var req statusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.projectService.ChangeTaskStatus(id, companyID, userID, req.Status, req.Note)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *ProjectHandler) GetTaskStatusHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("taskId")
+	history, err := h.projectService.GetTaskStatusHistory(id, companyID)
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ProjectHandler) AssignTask(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("taskId")
+	var req taskAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.projectService.AssignTask(id, companyID, ports.TaskAssignmentInput{
+		AssigneeIDs: req.AssigneeIDs,
+		Crew:        req.Crew,
+	})
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *ProjectHandler) AssignSubtask(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("subtaskId")
+	var req taskAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subtask, err := h.projectService.AssignSubtask(id, companyID, ports.TaskAssignmentInput{
+		AssigneeIDs: req.AssigneeIDs,
+		Crew:        req.Crew,
+	})
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subtask)
+}
+
+func (h *ProjectHandler) ListMyTasks(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tasks, err := h.projectService.ListMyTasks(userID, companyID, c.Query("status"), c.Query("due_from"), c.Query("due_to"))
+	if err != nil {
+		h.respondTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *ProjectHandler) GetWorkload(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	workload, err := h.projectService.GetWorkload(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, workload)
+}
+
+func (h *ProjectHandler) respondTaskError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "task not found or access denied", "subtask not found or access denied",
+		"record not found", "milestone not found", "project not found in trash":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid start date", "invalid due date", "invalid duration", "due date before start date",
+		"invalid dependency", "invalid dependency type", "dependency cycle detected", "invalid weight",
+		"invalid project status", "invalid task status", "invalid subtask status", "assignee is not a company member", "invalid due date filter":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "status transition not allowed", "version conflict":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *ProjectHandler) respondDiaryError(c *gin.Context, err error) {
+	if strings.HasPrefix(err.Error(), "required diary item missing") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch err.Error() {
+	case "project not found or access denied", "diary entry not found", "revision not found", "diary template not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid entry date", "service task not found", "invalid month", "invalid from date", "invalid to date", "invalid date range",
+		"signer name is required", "invalid revision range", "diary items are required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "diary entry is locked", "status transition not allowed", "only approved diary entries can be amended", "diary entry already amended",
+		"version conflict":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PunchListHandler struct {
+	punchListService  ports.PunchListService
+	attachmentService ports.AttachmentService
+}
+
+func NewPunchListHandler(punchListService ports.PunchListService, attachmentService ports.AttachmentService) *PunchListHandler {
+	return &PunchListHandler{
+		punchListService:  punchListService,
+		attachmentService: attachmentService,
+	}
+}
+
+type punchItemRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Floor       string `json:"floor"`
+	Room        string `json:"room"`
+	Trade       string `json:"trade"`
+	Priority    string `json:"priority"`
+	DueDate     string `json:"due_date"`
+}
+
+func (h *PunchListHandler) CreatePunchItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	var req punchItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.punchListService.CreatePunchItem(projectID, companyID, userID, req.Title, req.Description, req.Floor, req.Room, req.Trade, req.Priority, req.DueDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// ListPunchItems accepts optional status, trade and floor filters.
+func (h *PunchListHandler) ListPunchItems(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	items, err := h.punchListService.ListPunchItems(c.Param("id"), companyID, c.Query("status"), c.Query("trade"), c.Query("floor"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if err := h.attachmentService.AttachToPunchItems(items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *PunchListHandler) GetPunchItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	item, err := h.punchListService.GetPunchItem(c.Param("itemId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	items := []domain.PunchItem{*item}
+	if err := h.attachmentService.AttachToPunchItems(items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items[0])
+}
+
+func (h *PunchListHandler) UpdatePunchItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req punchItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.punchListService.UpdatePunchItem(c.Param("itemId"), c.Param("id"), companyID, req.Title, req.Description, req.Floor, req.Room, req.Trade, req.Priority, req.DueDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *PunchListHandler) ChangePunchItemStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req statusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.punchListService.ChangePunchItemStatus(c.Param("itemId"), c.Param("id"), companyID, userID, req.Status, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *PunchListHandler) GetPunchItemHistory(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.punchListService.GetPunchItemHistory(c.Param("itemId"), c.Param("id"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *PunchListHandler) DeletePunchItem(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.punchListService.DeletePunchItem(c.Param("itemId"), c.Param("id"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PunchListHandler) ExportPunchList(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	pdf, err := h.punchListService.ExportPunchList(projectID, companyID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	sendPunchListPDF(c, projectID, pdf)
+}
+
+func (h *PunchListHandler) ListPublicPunchItems(c *gin.Context) {
+	items, err := h.punchListService.ListPublicPunchItems(c.Param("id"), c.GetHeader(publicProjectPinHeader))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "punch list not found"})
+		return
+	}
+	if err := h.attachmentService.AttachToPunchItems(items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+type approvePunchItemRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ApprovePublicPunchItem lets the client accept a verified fix through the
+// public project link.
+func (h *PunchListHandler) ApprovePublicPunchItem(c *gin.Context) {
+	var req approvePunchItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pin := c.GetHeader(publicProjectPinHeader)
+	item, err := h.punchListService.ApprovePublicPunchItem(c.Param("id"), c.Param("itemId"), pin, req.Name)
+	if err != nil {
+		switch err.Error() {
+		case "project not found or not public", "invalid public project access":
+			c.JSON(http.StatusNotFound, gin.H{"error": "punch list not found"})
+		default:
+			h.respondError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// ExportPublicPunchList accepts the PIN in the X-Project-Pin header or, for
+// plain download links, the pin query parameter.
+func (h *PunchListHandler) ExportPublicPunchList(c *gin.Context) {
+	projectID := c.Param("id")
+	pin := c.GetHeader(publicProjectPinHeader)
+	if pin == "" {
+		pin = c.Query("pin")
+	}
+
+	pdf, err := h.punchListService.ExportPublicPunchList(projectID, pin)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "punch list not found"})
+		return
+	}
+
+	sendPunchListPDF(c, projectID, pdf)
+}
+
+func (h *PunchListHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied", "punch item not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "punch item title is required", "invalid punch item priority", "invalid punch item status", "invalid due date", "signer name is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "status transition not allowed", "only verified punch items can be approved", "punch item already approved":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func sendPunchListPDF(c *gin.Context, projectID string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="pendencias-%s.pdf"`, projectID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
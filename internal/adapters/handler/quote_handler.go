@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"construct-backend/internal/core/services"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuoteHandler struct {
+	quoteService        ports.QuoteService
+	subscriptionService *services.SubscriptionService
+}
+
+func NewQuoteHandler(quoteService ports.QuoteService, subscriptionService *services.SubscriptionService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService:        quoteService,
+		subscriptionService: subscriptionService,
+	}
+}
+
+type quoteRequest struct {
+	ClientID        string             `json:"client_id" binding:"required"`
+	Title           string             `json:"title" binding:"required"`
+	Address         string             `json:"address"`
+	Notes           string             `json:"notes"`
+	ValidUntil      string             `json:"valid_until"`
+	Items           []domain.QuoteItem `json:"items"`
+	BDIPercent      float64            `json:"bdi_percent"`
+	DiscountPercent float64            `json:"discount_percent"`
+}
+
+func (r quoteRequest) input() ports.QuoteInput {
+	return ports.QuoteInput{
+		ClientID:        r.ClientID,
+		Title:           r.Title,
+		Address:         r.Address,
+		Notes:           r.Notes,
+		ValidUntil:      r.ValidUntil,
+		Items:           r.Items,
+		BDIPercent:      r.BDIPercent,
+		DiscountPercent: r.DiscountPercent,
+	}
+}
+
+func (h *QuoteHandler) CreateQuote(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req quoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.quoteService.CreateQuote(companyID, userID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// ListQuotes accepts optional status and client_id filters.
+func (h *QuoteHandler) ListQuotes(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	quotes, err := h.quoteService.ListQuotes(companyID, c.Query("status"), c.Query("client_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotes)
+}
+
+func (h *QuoteHandler) GetQuote(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	quote, err := h.quoteService.GetQuote(c.Param("quoteId"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) UpdateQuote(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req quoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.quoteService.UpdateQuote(c.Param("quoteId"), companyID, req.input())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) SendQuote(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	quote, err := h.quoteService.SendQuote(c.Param("quoteId"), companyID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) ListQuoteVersions(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	versions, err := h.quoteService.ListQuoteVersions(c.Param("quoteId"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func (h *QuoteHandler) DeleteQuote(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.quoteService.DeleteQuote(c.Param("quoteId"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *QuoteHandler) ExportQuote(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	quoteID := c.Param("quoteId")
+	pdf, err := h.quoteService.ExportQuote(quoteID, companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	sendQuotePDF(c, quoteID, pdf)
+}
+
+// ConvertQuote creates the project of an accepted quote. The name defaults to
+// the quote's title and the start date to today.
+func (h *QuoteHandler) ConvertQuote(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Name      string `json:"name"`
+		StartDate string `json:"start_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.checkProjectLimit(c, companyID) {
+		return
+	}
+
+	project, err := h.quoteService.ConvertQuote(c.Param("quoteId"), companyID, userID, req.Name, req.StartDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func (h *QuoteHandler) GetPublicQuote(c *gin.Context) {
+	quote, err := h.quoteService.GetPublicQuote(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "quote not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) ExportPublicQuote(c *gin.Context) {
+	token := c.Param("token")
+	pdf, err := h.quoteService.ExportPublicQuote(token)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	sendQuotePDF(c, token, pdf)
+}
+
+type quoteDecisionRequest struct {
+	Name string `json:"name" binding:"required"`
+	Note string `json:"note"`
+}
+
+// AcceptPublicQuote lets the client accept a quote through its public link.
+func (h *QuoteHandler) AcceptPublicQuote(c *gin.Context) {
+	h.decidePublicQuote(c, domain.QuoteStatusAccepted)
+}
+
+// RejectPublicQuote lets the client turn a quote down through its public
+// link.
+func (h *QuoteHandler) RejectPublicQuote(c *gin.Context) {
+	h.decidePublicQuote(c, domain.QuoteStatusRejected)
+}
+
+func (h *QuoteHandler) decidePublicQuote(c *gin.Context, decision string) {
+	var req quoteDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.quoteService.DecidePublicQuote(c.Param("token"), req.Name, decision, req.Note)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) checkProjectLimit(c *gin.Context, companyID string) bool {
+	if err := h.subscriptionService.CheckProjectLimit(companyID); err != nil {
+		if strings.HasPrefix(err.Error(), "limite_atingido") {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":            err.Error(),
+				"upgrade_required": true,
+			})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func (h *QuoteHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "quote not found", "client not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "quote title is required", "quote items are required", "quote item description is required", "invalid quote item amount",
+		"invalid quote percentage", "invalid cost category", "invalid validity date", "invalid quote status",
+		"invalid quote decision", "signer name is required", "invalid start date", "project is too large":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "quote is locked", "only draft quotes can be sent", "quote already decided", "quote has expired",
+		"only accepted quotes can be converted", "quote already converted":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func sendQuotePDF(c *gin.Context, id string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="orcamento-%s.pdf"`, id))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
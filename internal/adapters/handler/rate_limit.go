@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimit allows each client IP at most limit requests per fixed window on
+// the routes it guards; routes sharing a scope share the budget. Counts are
+// kept in the shared store, so the limit holds across instances. When the
+// store fails the request goes through: the limit is a guard, not a
+// dependency of the routes.
+func rateLimit(store ports.RateLimitRepository, scope string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now().Truncate(window)
+		key := fmt.Sprintf("%s#%s#%d", scope, clientIP(c), start.Unix())
+
+		count, err := store.HitRateLimit(key, start.Add(window))
+		if err != nil {
+			log.Printf("rate limit %s: %v", scope, err)
+			c.Next()
+			return
+		}
+		if count > limit {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// pinAttempts allows each client IP at most limit rejected requests per fixed
+// window on the routes it guards, which all check the project PIN and answer
+// a wrong one like a missing project. Only rejections count, so browsing with
+// the right PIN is not throttled; once the budget is spent every guarded route
+// answers 429 until the window ends, whatever the PIN. Like rateLimit, it lets
+// requests through when the store fails.
+func pinAttempts(store ports.RateLimitRepository, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now().Truncate(window)
+		key := fmt.Sprintf("pin#%s#%d", clientIP(c), start.Unix())
+
+		count, err := store.GetRateLimit(key)
+		if err != nil {
+			log.Printf("pin attempts: %v", err)
+		} else if count >= limit {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+
+		switch c.Writer.Status() {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+			if _, err := store.HitRateLimit(key, start.Add(window)); err != nil {
+				log.Printf("pin attempts: %v", err)
+			}
+		}
+	}
+}
+
+// clientIP identifies the caller. ClientIP only honours forwarding headers
+// from the router's trusted proxies. Behind API Gateway the remote address is
+// the source IP the gateway saw, without a port, which ClientIP cannot parse.
+func clientIP(c *gin.Context) string {
+	if ip := c.ClientIP(); ip != "" {
+		return ip
+	}
+	return strings.TrimSpace(c.Request.RemoteAddr)
+}
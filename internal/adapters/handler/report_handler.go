@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	diaryReportService ports.DiaryReportService
+}
+
+func NewReportHandler(diaryReportService ports.DiaryReportService) *ReportHandler {
+	return &ReportHandler{
+		diaryReportService: diaryReportService,
+	}
+}
+
+func (h *ReportHandler) ExportDiary(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	pdf, err := h.diaryReportService.ExportDiary(projectID, companyID, c.Query("from"), c.Query("to"), c.Query("visibility"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	sendDiaryPDF(c, projectID, pdf)
+}
+
+// ExportPublicDiary accepts the PIN in the X-Project-Pin header or, for plain
+// download links, the pin query parameter.
+func (h *ReportHandler) ExportPublicDiary(c *gin.Context) {
+	projectID := c.Param("id")
+	pin := c.GetHeader(publicProjectPinHeader)
+	if pin == "" {
+		pin = c.Query("pin")
+	}
+
+	pdf, err := h.diaryReportService.ExportPublicDiary(projectID, pin, c.Query("from"), c.Query("to"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid from date", "invalid to date", "invalid date range":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": "diary not found"})
+		}
+		return
+	}
+
+	sendDiaryPDF(c, projectID, pdf)
+}
+
+func (h *ReportHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "project not found or access denied":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid visibility", "invalid from date", "invalid to date", "invalid date range":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func sendDiaryPDF(c *gin.Context, projectID string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rdo-%s.pdf"`, projectID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
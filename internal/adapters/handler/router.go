@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"construct-backend/internal/core/ports"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -19,6 +21,23 @@ func SetupRouter(
 	clientHandler *ClientHandler,
 	companyHandler *CompanyHandler,
 	subscriptionHandler *SubscriptionHandler,
+	financialHandler *FinancialHandler,
+	materialHandler *MaterialHandler,
+	milestoneHandler *MilestoneHandler,
+	templateHandler *TemplateHandler,
+	trashHandler *TrashHandler,
+	punchListHandler *PunchListHandler,
+	inspectionHandler *InspectionHandler,
+	changeOrderHandler *ChangeOrderHandler,
+	quoteHandler *QuoteHandler,
+	invoiceHandler *InvoiceHandler,
+	measurementHandler *MeasurementHandler,
+	leadHandler *LeadHandler,
+	syncHandler *SyncHandler,
+	attachmentHandler *AttachmentHandler,
+	storageHandler *StorageHandler,
+	reportHandler *ReportHandler,
+	rateLimits ports.RateLimitRepository,
 	jwtSecret string,
 ) *gin.Engine {
 	r := gin.Default()
@@ -33,11 +52,44 @@ func SetupRouter(
 	r.POST("/auth/google", authHandler.GoogleLogin)
 	r.POST("/signup/google", authHandler.GoogleLogin)
 	r.POST("/auth/verify", authHandler.TokenVerify)
+	// Public writes are guarded only by the project PIN or a link token; the
+	// budget is shared across the routes. Every route under a public project
+	// checks the PIN, and they share a budget of rejected attempts, so the PIN
+	// can not be guessed through any of them.
+	publicWrite := rateLimit(rateLimits, "public-write", 30, time.Hour)
+	pinChecked := pinAttempts(rateLimits, 20, time.Hour)
+
 	r.GET("/public/company/:slug", companyHandler.GetPublicPage)
-	r.POST("/public/projects/:id/verify-pin", projectHandler.VerifyPublicProjectPin)
-	r.GET("/public/projects/:id", projectHandler.GetPublicProject)
-	r.GET("/public/projects/:id/diary", projectHandler.ListPublicDiaryEntries)
+	r.POST("/public/company/:slug/leads", rateLimit(rateLimits, "lead", 5, time.Hour), leadHandler.CreatePublicLead)
+	public := r.Group("/public/projects/:id", pinChecked)
+	{
+		public.POST("/verify-pin", publicWrite, projectHandler.VerifyPublicProjectPin)
+		public.GET("", projectHandler.GetPublicProject)
+		public.GET("/diary", projectHandler.ListPublicDiaryEntries)
+		public.GET("/diary/export.pdf", reportHandler.ExportPublicDiary)
+		public.POST("/diary/:entryId/acknowledge", publicWrite, projectHandler.AcknowledgePublicDiaryEntry)
+		public.GET("/punch-list", punchListHandler.ListPublicPunchItems)
+		public.GET("/punch-list/export.pdf", punchListHandler.ExportPublicPunchList)
+		public.POST("/punch-list/:itemId/approve", publicWrite, punchListHandler.ApprovePublicPunchItem)
+		public.GET("/change-orders", changeOrderHandler.ListPublicChangeOrders)
+		public.POST("/change-orders/:orderId/approve", publicWrite, changeOrderHandler.ApprovePublicChangeOrder)
+		public.POST("/change-orders/:orderId/reject", publicWrite, changeOrderHandler.RejectPublicChangeOrder)
+		public.GET("/measurements", measurementHandler.ListPublicMeasurements)
+		public.GET("/measurements/:measurementId/export.pdf", measurementHandler.ExportPublicMeasurementPDF)
+		public.GET("/measurements/:measurementId/export.csv", measurementHandler.ExportPublicMeasurementCSV)
+		public.POST("/measurements/:measurementId/approve", publicWrite, measurementHandler.ApprovePublicMeasurement)
+		public.POST("/measurements/:measurementId/reject", publicWrite, measurementHandler.RejectPublicMeasurement)
+	}
+	r.GET("/public/quotes/:token", quoteHandler.GetPublicQuote)
+	r.GET("/public/quotes/:token/export.pdf", quoteHandler.ExportPublicQuote)
+	r.POST("/public/quotes/:token/accept", publicWrite, quoteHandler.AcceptPublicQuote)
+	r.POST("/public/quotes/:token/reject", publicWrite, quoteHandler.RejectPublicQuote)
 	r.POST("/click/link/:id", linkHandler.TrackClick)
+	// Arquivos do storage local — sem JWT (validado por assinatura da URL)
+	if storageHandler != nil {
+		r.PUT("/storage/*key", storageHandler.Upload)
+		r.GET("/storage/*key", storageHandler.Download)
+	}
 	// Webhook do gateway de pagamento — sem autenticação JWT (validado por assinatura)
 	r.POST("/webhooks/payment", subscriptionHandler.HandleWebhook)
 	r.GET("/health", func(c *gin.Context) {
@@ -60,43 +112,183 @@ func SetupRouter(
 		api.GET("/projects", projectHandler.ListProjects)
 		api.GET("/projects/:id", projectHandler.GetProject)
 		api.POST("/projects", projectHandler.CreateProject)
+		api.POST("/projects/from-template/:templateId", templateHandler.CreateProjectFromTemplate)
 		api.PUT("/projects/:id", projectHandler.UpdateProject)
 		api.DELETE("/projects/:id", projectHandler.DeleteProject)
+		api.POST("/projects/:id/restore", projectHandler.RestoreProject)
+		api.POST("/projects/:id/archive", projectHandler.ArchiveProject)
+		api.POST("/projects/:id/unarchive", projectHandler.UnarchiveProject)
 
+		api.POST("/projects/:id/clone", templateHandler.CloneProject)
 		api.POST("/projects/:id/tasks", projectHandler.AddTask)
 		api.GET("/projects/:id/tasks", projectHandler.ListTasks)
+		api.GET("/projects/:id/schedule", projectHandler.GetSchedule)
+		api.POST("/projects/:id/status", projectHandler.ChangeProjectStatus)
+		api.GET("/projects/:id/status-history", projectHandler.GetProjectStatusHistory)
+		api.GET("/projects/:id/milestones", milestoneHandler.ListMilestones)
+		api.POST("/projects/:id/milestones", milestoneHandler.CreateMilestone)
+		api.PUT("/projects/:id/milestones/:milestoneId", milestoneHandler.UpdateMilestone)
+		api.DELETE("/projects/:id/milestones/:milestoneId", milestoneHandler.DeleteMilestone)
 		api.POST("/projects/:id/diary", projectHandler.CreateDiaryEntry)
 		api.GET("/projects/:id/diary", projectHandler.ListDiaryEntries)
+		api.GET("/projects/:id/diary/export.pdf", reportHandler.ExportDiary)
+		api.GET("/projects/:id/diary/summary", projectHandler.GetDiarySummary)
 		api.PUT("/projects/:id/diary/:entryId", projectHandler.UpdateDiaryEntry)
 		api.DELETE("/projects/:id/diary/:entryId", projectHandler.DeleteDiaryEntry)
+		api.POST("/projects/:id/diary/:entryId/submit", projectHandler.SubmitDiaryEntry)
+		api.POST("/projects/:id/diary/:entryId/approve", projectHandler.ApproveDiaryEntry)
+		api.POST("/projects/:id/diary/:entryId/reject", projectHandler.RejectDiaryEntry)
+		api.POST("/projects/:id/diary/:entryId/amendments", projectHandler.AmendDiaryEntry)
+		api.GET("/projects/:id/diary/:entryId/history", projectHandler.GetDiaryEntryHistory)
+		api.GET("/projects/:id/diary/:entryId/revisions", projectHandler.ListDiaryRevisions)
+		api.GET("/projects/:id/diary/:entryId/revisions/diff", projectHandler.DiffDiaryRevisions)
+		api.GET("/projects/:id/punch-list", punchListHandler.ListPunchItems)
+		api.POST("/projects/:id/punch-list", punchListHandler.CreatePunchItem)
+		api.GET("/projects/:id/punch-list/export.pdf", punchListHandler.ExportPunchList)
+		api.GET("/projects/:id/punch-list/:itemId", punchListHandler.GetPunchItem)
+		api.PUT("/projects/:id/punch-list/:itemId", punchListHandler.UpdatePunchItem)
+		api.DELETE("/projects/:id/punch-list/:itemId", punchListHandler.DeletePunchItem)
+		api.POST("/projects/:id/punch-list/:itemId/status", punchListHandler.ChangePunchItemStatus)
+		api.GET("/projects/:id/punch-list/:itemId/status-history", punchListHandler.GetPunchItemHistory)
+		api.GET("/projects/:id/inspections", inspectionHandler.ListInspections)
+		api.POST("/projects/:id/inspections", inspectionHandler.StartInspection)
+		api.GET("/projects/:id/inspections/:inspectionId", inspectionHandler.GetInspection)
+		api.PUT("/projects/:id/inspections/:inspectionId", inspectionHandler.RecordResults)
+		api.DELETE("/projects/:id/inspections/:inspectionId", inspectionHandler.DeleteInspection)
+		api.POST("/projects/:id/inspections/:inspectionId/sign", inspectionHandler.SignInspection)
+		api.GET("/projects/:id/quality-summary", inspectionHandler.GetQualitySummary)
+		api.GET("/projects/:id/change-orders", changeOrderHandler.ListChangeOrders)
+		api.POST("/projects/:id/change-orders", changeOrderHandler.CreateChangeOrder)
+		api.GET("/projects/:id/change-orders/:orderId", changeOrderHandler.GetChangeOrder)
+		api.PUT("/projects/:id/change-orders/:orderId", changeOrderHandler.UpdateChangeOrder)
+		api.DELETE("/projects/:id/change-orders/:orderId", changeOrderHandler.DeleteChangeOrder)
+		api.POST("/projects/:id/change-orders/:orderId/status", changeOrderHandler.ChangeChangeOrderStatus)
+		api.GET("/projects/:id/change-orders/:orderId/status-history", changeOrderHandler.GetChangeOrderHistory)
+		api.GET("/projects/:id/contract-items", measurementHandler.ListContractItems)
+		api.POST("/projects/:id/contract-items", measurementHandler.CreateContractItem)
+		api.PUT("/projects/:id/contract-items/:itemId", measurementHandler.UpdateContractItem)
+		api.DELETE("/projects/:id/contract-items/:itemId", measurementHandler.DeleteContractItem)
+		api.GET("/projects/:id/measurements", measurementHandler.ListMeasurements)
+		api.POST("/projects/:id/measurements", measurementHandler.CreateMeasurement)
+		api.GET("/projects/:id/measurements/:measurementId", measurementHandler.GetMeasurement)
+		api.PUT("/projects/:id/measurements/:measurementId", measurementHandler.UpdateMeasurement)
+		api.DELETE("/projects/:id/measurements/:measurementId", measurementHandler.DeleteMeasurement)
+		api.POST("/projects/:id/measurements/:measurementId/status", measurementHandler.ChangeMeasurementStatus)
+		api.GET("/projects/:id/measurements/:measurementId/status-history", measurementHandler.GetMeasurementHistory)
+		api.GET("/projects/:id/measurements/:measurementId/export.pdf", measurementHandler.ExportMeasurementPDF)
+		api.GET("/projects/:id/measurements/:measurementId/export.csv", measurementHandler.ExportMeasurementCSV)
+		api.POST("/projects/:id/measurements/:measurementId/invoice", measurementHandler.BillMeasurement)
+		api.GET("/projects/:id/payment-schedule", invoiceHandler.GetPaymentSchedule)
+		api.POST("/projects/:id/payment-schedule", invoiceHandler.CreatePaymentSchedule)
+		api.POST("/projects/:id/invoices", invoiceHandler.CreateInvoice)
+		api.GET("/projects/:id/invoices/:invoiceId", invoiceHandler.GetInvoice)
+		api.PUT("/projects/:id/invoices/:invoiceId", invoiceHandler.UpdateInvoice)
+		api.DELETE("/projects/:id/invoices/:invoiceId", invoiceHandler.DeleteInvoice)
+		api.POST("/projects/:id/invoices/:invoiceId/cancel", invoiceHandler.CancelInvoice)
+		api.POST("/projects/:id/invoices/:invoiceId/payments", invoiceHandler.RecordPayment)
+		api.DELETE("/projects/:id/invoices/:invoiceId/payments/:paymentId", invoiceHandler.DeletePayment)
+		api.GET("/projects/:id/attachments", attachmentHandler.ListAttachments)
+		api.POST("/projects/:id/attachments", attachmentHandler.RequestUpload)
+		api.GET("/projects/:id/attachments/:attachmentId", attachmentHandler.GetAttachment)
+		api.POST("/projects/:id/attachments/:attachmentId/complete", attachmentHandler.CompleteUpload)
+		api.DELETE("/projects/:id/attachments/:attachmentId", attachmentHandler.DeleteAttachment)
+		api.GET("/projects/:id/budget", financialHandler.GetBudget)
+		api.PUT("/projects/:id/budget", financialHandler.SetBudget)
+		api.GET("/projects/:id/expenses", financialHandler.ListExpenses)
+		api.POST("/projects/:id/expenses", financialHandler.CreateExpense)
+		api.PUT("/projects/:id/expenses/:expenseId", financialHandler.UpdateExpense)
+		api.DELETE("/projects/:id/expenses/:expenseId", financialHandler.DeleteExpense)
+		api.GET("/projects/:id/financials", financialHandler.GetFinancials)
+		api.GET("/projects/:id/materials", materialHandler.GetProjectStock)
+		api.GET("/projects/:id/materials/movements", materialHandler.ListStockMovements)
+		api.POST("/projects/:id/materials/movements", materialHandler.RecordStockMovement)
+		api.POST("/projects/:id/materials/transfers", materialHandler.TransferStock)
+		api.PUT("/projects/:id/materials/:materialId/threshold", materialHandler.SetMaterialThreshold)
+		api.GET("/projects/:id/materials/purchase-requests", materialHandler.ListPurchaseRequests)
+		api.POST("/projects/:id/materials/purchase-requests", materialHandler.CreatePurchaseRequest)
+		api.POST("/projects/:id/materials/purchase-requests/:requestId/approve", materialHandler.ApprovePurchaseRequest)
+		api.POST("/projects/:id/materials/purchase-requests/:requestId/reject", materialHandler.RejectPurchaseRequest)
+		api.POST("/projects/:id/materials/purchase-requests/:requestId/receive", materialHandler.ReceivePurchaseRequest)
 		api.GET("/tasks/:taskId", projectHandler.GetTask)
 		api.PUT("/tasks/:taskId", projectHandler.UpdateTask)
 		api.DELETE("/tasks/:taskId", projectHandler.DeleteTask)
+		api.POST("/tasks/:taskId/status", projectHandler.ChangeTaskStatus)
+		api.PUT("/tasks/:taskId/assignees", projectHandler.AssignTask)
+		api.GET("/tasks/:taskId/status-history", projectHandler.GetTaskStatusHistory)
 
 		api.POST("/tasks/:taskId/subtasks", projectHandler.AddSubtask)
 		api.GET("/subtasks/:subtaskId", projectHandler.GetSubtask)
 		api.PUT("/subtasks/:subtaskId", projectHandler.UpdateSubtask)
 		api.DELETE("/subtasks/:subtaskId", projectHandler.DeleteSubtask)
+		api.PUT("/subtasks/:subtaskId/assignees", projectHandler.AssignSubtask)
+		api.GET("/me/tasks", projectHandler.ListMyTasks)
+
+		api.GET("/project-templates", templateHandler.ListTemplates)
+		api.POST("/project-templates", templateHandler.CreateTemplate)
+		api.GET("/project-templates/:templateId", templateHandler.GetTemplate)
+		api.PUT("/project-templates/:templateId", templateHandler.UpdateTemplate)
+		api.DELETE("/project-templates/:templateId", templateHandler.DeleteTemplate)
+		api.GET("/diary-templates", templateHandler.ListDiaryTemplates)
+		api.POST("/diary-templates", templateHandler.CreateDiaryTemplate)
+		api.GET("/diary-templates/:templateId", templateHandler.GetDiaryTemplate)
+		api.PUT("/diary-templates/:templateId", templateHandler.UpdateDiaryTemplate)
+		api.DELETE("/diary-templates/:templateId", templateHandler.DeleteDiaryTemplate)
+		api.GET("/inspection-templates", templateHandler.ListInspectionTemplates)
+		api.POST("/inspection-templates", templateHandler.CreateInspectionTemplate)
+		api.GET("/inspection-templates/:templateId", templateHandler.GetInspectionTemplate)
+		api.PUT("/inspection-templates/:templateId", templateHandler.UpdateInspectionTemplate)
+		api.DELETE("/inspection-templates/:templateId", templateHandler.DeleteInspectionTemplate)
 
 		api.GET("/links", linkHandler.ListLinks)
 		api.GET("/links/analytics", linkHandler.GetAnalytics)
 		api.POST("/links", linkHandler.CreateLink)
 		api.DELETE("/links/:id", linkHandler.DeleteLink)
 		api.PUT("/links/:id", linkHandler.UpdateLink)
+		api.POST("/links/:id/restore", linkHandler.RestoreLink)
 
 		api.GET("/user/username", userHandler.GetUsername)
 
 		api.POST("/clients", clientHandler.CreateClient)
 		api.GET("/clients", clientHandler.ListClients)
+		api.GET("/clients/pipeline", clientHandler.GetPipeline)
+		api.GET("/clients/pipeline/stages", clientHandler.GetPipelineStages)
+		api.PUT("/clients/pipeline/stages", clientHandler.UpdatePipelineStages)
 		api.GET("/clients/:id", clientHandler.GetClient)
 		api.PUT("/clients/:id", clientHandler.UpdateClient)
 		api.DELETE("/clients/:id", clientHandler.DeleteClient)
+		api.POST("/clients/:id/restore", clientHandler.RestoreClient)
+		api.POST("/clients/:id/archive", clientHandler.ArchiveClient)
+		api.POST("/clients/:id/unarchive", clientHandler.UnarchiveClient)
 		api.POST("/clients/:id/comments", clientHandler.AddComment)
+		api.PUT("/clients/:id/pipeline", clientHandler.UpdateClientPipeline)
+
+		api.GET("/invoices/overdue", invoiceHandler.ListOverdueInvoices)
+
+		api.GET("/quotes", quoteHandler.ListQuotes)
+		api.POST("/quotes", quoteHandler.CreateQuote)
+		api.GET("/quotes/:quoteId", quoteHandler.GetQuote)
+		api.PUT("/quotes/:quoteId", quoteHandler.UpdateQuote)
+		api.DELETE("/quotes/:quoteId", quoteHandler.DeleteQuote)
+		api.POST("/quotes/:quoteId/send", quoteHandler.SendQuote)
+		api.GET("/quotes/:quoteId/versions", quoteHandler.ListQuoteVersions)
+		api.GET("/quotes/:quoteId/export.pdf", quoteHandler.ExportQuote)
+		api.POST("/quotes/:quoteId/convert", quoteHandler.ConvertQuote)
+
+		api.GET("/materials", materialHandler.ListMaterials)
+		api.POST("/materials", materialHandler.CreateMaterial)
+		api.PUT("/materials/:materialId", materialHandler.UpdateMaterial)
+		api.DELETE("/materials/:materialId", materialHandler.DeleteMaterial)
+
+		api.GET("/trash", trashHandler.ListTrash)
+
+		api.POST("/sync", syncHandler.Sync)
 
 		api.GET("/company", companyHandler.GetCompany)
 		api.PUT("/company", companyHandler.UpdateCompany)
 		api.PUT("/company/public-page", companyHandler.UpdatePublicPage)
+		api.POST("/company/logo", companyHandler.UploadLogo)
 		api.GET("/company/members", companyHandler.ListMembers)
+		api.GET("/company/members/workload", projectHandler.GetWorkload)
 		api.POST("/company/members", companyHandler.AddMember)
 
 		// Subscription routes
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageHandler serves the presigned URLs of storage drivers that keep files
+// on this server. Requests are authenticated by their signature, not a JWT;
+// only downloads of public keys go without one.
+type StorageHandler struct {
+	store ports.SignedFileStore
+}
+
+func NewStorageHandler(store ports.SignedFileStore) *StorageHandler {
+	return &StorageHandler{
+		store: store,
+	}
+}
+
+func (h *StorageHandler) Upload(c *gin.Context) {
+	key, ok := h.verify(c, http.MethodPut)
+	if !ok {
+		return
+	}
+
+	if c.Request.ContentLength > domain.MaxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+		return
+	}
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxAttachmentSize)
+	if err := h.store.Write(key, body); err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *StorageHandler) Download(c *gin.Context) {
+	key := storageKey(c)
+	if !strings.HasPrefix(key, ports.PublicStoragePrefix) {
+		var ok bool
+		if key, ok = h.verify(c, http.MethodGet); !ok {
+			return
+		}
+	}
+
+	path, err := h.store.Path(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+	if name := c.Query("name"); name != "" {
+		c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": name}))
+	}
+	c.File(path)
+}
+
+func (h *StorageHandler) verify(c *gin.Context, method string) (string, bool) {
+	key := storageKey(c)
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid signature"})
+		return "", false
+	}
+	if err := h.store.VerifySignature(method, key, expires, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return key, true
+}
+
+// storageKey cleans the requested key so "public/../" cannot reach private
+// files without a signature.
+func storageKey(c *gin.Context) string {
+	return strings.TrimPrefix(path.Clean("/"+c.Param("key")), "/")
+}
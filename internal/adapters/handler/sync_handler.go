@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxSyncChanges caps how many offline changes one sync applies, as the feed
+// it returns is capped per page.
+const maxSyncChanges = 500
+
+type SyncHandler struct {
+	syncService ports.SyncService
+}
+
+func NewSyncHandler(syncService ports.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+type syncRequest struct {
+	Cursor  string              `json:"cursor"`
+	Changes []syncChangeRequest `json:"changes" binding:"dive"`
+}
+
+// syncChangeRequest is one offline change. Data holds the record in the shape
+// of its entity type and is left out for deletes.
+type syncChangeRequest struct {
+	EntityType  string          `json:"entity_type" binding:"required"`
+	Operation   string          `json:"operation" binding:"required"`
+	ID          string          `json:"id" binding:"required"`
+	ProjectID   string          `json:"project_id"`
+	BaseVersion int             `json:"base_version"`
+	Data        json.RawMessage `json:"data"`
+}
+
+type syncTaskRequest struct {
+	updateTaskRequest
+	Name        string   `json:"name"`
+	AssigneeIDs []string `json:"assignee_ids"`
+	Crew        *string  `json:"crew"`
+}
+
+type syncSubtaskRequest struct {
+	TaskID      string   `json:"task_id"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	AssigneeIDs []string `json:"assignee_ids"`
+	Crew        *string  `json:"crew"`
+}
+
+func (h *SyncHandler) Sync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req syncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.Changes) > maxSyncChanges {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d changes per sync", maxSyncChanges)})
+		return
+	}
+
+	mutations := make([]ports.SyncMutation, 0, len(req.Changes))
+	for index, change := range req.Changes {
+		mutation, err := change.toMutation()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("change %d: %s", index, err.Error())})
+			return
+		}
+		mutations = append(mutations, mutation)
+	}
+
+	response, err := h.syncService.Sync(companyID, userID, req.Cursor, mutations)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (req syncChangeRequest) toMutation() (ports.SyncMutation, error) {
+	mutation := ports.SyncMutation{
+		EntityType:  req.EntityType,
+		Operation:   req.Operation,
+		ID:          req.ID,
+		ProjectID:   req.ProjectID,
+		BaseVersion: req.BaseVersion,
+	}
+	if req.Operation != domain.SyncOperationUpsert || len(req.Data) == 0 {
+		return mutation, nil
+	}
+
+	switch req.EntityType {
+	case domain.SyncEntityDiaryEntry:
+		var data diaryEntryRequest
+		if err := binding.JSON.BindBody(req.Data, &data); err != nil {
+			return mutation, err
+		}
+		if err := validateDiaryItems(data.Items); err != nil {
+			return mutation, err
+		}
+		mutation.DiaryEntry = &ports.SyncDiaryEntryInput{
+			EntryDate:  data.EntryDate,
+			Title:      data.Title,
+			TemplateID: data.TemplateID,
+			Items:      diaryItemsFromRequest(data.Items),
+		}
+	case domain.SyncEntityTask:
+		var data syncTaskRequest
+		if err := binding.JSON.BindBody(req.Data, &data); err != nil {
+			return mutation, err
+		}
+		mutation.Task = &ports.SyncTaskInput{
+			Name:   data.Name,
+			Status: data.Status,
+			Schedule: ports.TaskScheduleInput{
+				StartDate:    data.StartDate,
+				DueDate:      data.DueDate,
+				Duration:     data.Duration,
+				Dependencies: toTaskDependencies(data.Dependencies),
+				MilestoneID:  data.MilestoneID,
+				Weight:       data.Weight,
+			},
+			Assignment: ports.TaskAssignmentInput{
+				AssigneeIDs: data.AssigneeIDs,
+				Crew:        data.Crew,
+			},
+		}
+	case domain.SyncEntitySubtask:
+		var data syncSubtaskRequest
+		if err := binding.JSON.BindBody(req.Data, &data); err != nil {
+			return mutation, err
+		}
+		mutation.Subtask = &ports.SyncSubtaskInput{
+			TaskID: data.TaskID,
+			Name:   data.Name,
+			Status: data.Status,
+			Assignment: ports.TaskAssignmentInput{
+				AssigneeIDs: data.AssigneeIDs,
+				Crew:        data.Crew,
+			},
+		}
+	}
+	return mutation, nil
+}
@@ -0,0 +1,409 @@
+package handler
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"construct-backend/internal/core/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService     ports.TemplateService
+	subscriptionService *services.SubscriptionService
+}
+
+func NewTemplateHandler(templateService ports.TemplateService, subscriptionService *services.SubscriptionService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService:     templateService,
+		subscriptionService: subscriptionService,
+	}
+}
+
+type templateRequest struct {
+	Name              string                    `json:"name" binding:"required"`
+	Description       string                    `json:"description"`
+	Tasks             []domain.TemplateTask     `json:"tasks"`
+	DiaryFieldPresets []domain.DiaryFieldPreset `json:"diary_field_presets"`
+}
+
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(companyID, req.Name, req.Description, req.Tasks, req.DiaryFieldPresets)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(c.Param("templateId"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Param("templateId"), companyID, req.Name, req.Description, req.Tasks, req.DiaryFieldPresets)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Param("templateId"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) CloneProject(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	userID := c.GetString("user_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Name      string `json:"name"`
+		StartDate string `json:"start_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.checkProjectLimit(c, companyID) {
+		return
+	}
+
+	project, err := h.templateService.CloneProject(c.Param("id"), companyID, userID, req.Name, req.StartDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func (h *TemplateHandler) CreateProjectFromTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	userID := c.GetString("user_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Name      string `json:"name" binding:"required"`
+		ClientID  string `json:"client_id"`
+		Address   string `json:"address"`
+		Summary   string `json:"summary"`
+		StartDate string `json:"start_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.checkProjectLimit(c, companyID) {
+		return
+	}
+
+	project, err := h.templateService.CreateProjectFromTemplate(c.Param("templateId"), companyID, userID, req.Name, req.ClientID, req.Address, req.Summary, req.StartDate)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+type diaryTemplateRequest struct {
+	Name        string                     `json:"name" binding:"required"`
+	Description string                     `json:"description"`
+	Items       []domain.DiaryTemplateItem `json:"items"`
+}
+
+func (h *TemplateHandler) CreateDiaryTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req diaryTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateDiaryTemplate(companyID, req.Name, req.Description, req.Items)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) ListDiaryTemplates(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := h.templateService.ListDiaryTemplates(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetDiaryTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	template, err := h.templateService.GetDiaryTemplate(c.Param("templateId"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) UpdateDiaryTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req diaryTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateDiaryTemplate(c.Param("templateId"), companyID, req.Name, req.Description, req.Items)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) DeleteDiaryTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.DeleteDiaryTemplate(c.Param("templateId"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+type inspectionTemplateRequest struct {
+	Name        string                          `json:"name" binding:"required"`
+	Description string                          `json:"description"`
+	Items       []domain.InspectionTemplateItem `json:"items"`
+}
+
+func (h *TemplateHandler) CreateInspectionTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req inspectionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateInspectionTemplate(companyID, req.Name, req.Description, req.Items)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) ListInspectionTemplates(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := h.templateService.ListInspectionTemplates(companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetInspectionTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	template, err := h.templateService.GetInspectionTemplate(c.Param("templateId"), companyID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) UpdateInspectionTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req inspectionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateInspectionTemplate(c.Param("templateId"), companyID, req.Name, req.Description, req.Items)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *TemplateHandler) DeleteInspectionTemplate(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.DeleteInspectionTemplate(c.Param("templateId"), companyID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) checkProjectLimit(c *gin.Context, companyID string) bool {
+	if err := h.subscriptionService.CheckProjectLimit(companyID); err != nil {
+		if strings.HasPrefix(err.Error(), "limite_atingido") {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":            err.Error(),
+				"upgrade_required": true,
+			})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func (h *TemplateHandler) respondError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "template not found", "diary template not found", "inspection template not found", "project not found or access denied":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "template name is required", "template task name is required", "invalid template task schedule",
+		"invalid dependency", "invalid dependency type", "dependency cycle detected",
+		"diary field label is required", "invalid diary item visibility", "invalid start date",
+		"diary template items are required", "invalid diary item type", "duplicate diary template item",
+		"inspection checklist items are required", "inspection item label is required", "duplicate inspection item",
+		"template is too large", "project is too large":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"construct-backend/internal/core/ports"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService ports.TrashService
+}
+
+func NewTrashHandler(trashService ports.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	companyID := c.GetString("company_id")
+	if companyID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	trash, err := h.trashService.ListTrash(companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
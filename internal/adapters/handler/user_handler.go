@@ -84,13 +84,27 @@ func (h *UserHandler) GetPublicProfile(c *gin.Context) {
 }
 
 func (h *UserHandler) UploadAvatar(c *gin.Context) {
-	_, err := c.FormFile("file")
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	data, ok := readImageUpload(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.UploadAvatar(userID, data)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		respondImageError(c, err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, gin.H{
+		"avatar":            user.Avatar,
+		"avatar_thumbnails": user.AvatarThumbnails,
+	})
 }
 
 func (h *UserHandler) UpdateBio(c *gin.Context) {
@@ -128,14 +142,15 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"id":         user.ID,
-		"username":   user.Username,
-		"name":       user.Name,
-		"bio":        user.Bio,
-		"avatar":     user.Avatar,
-		"email":      user.Email,
-		"phone":      user.Phone,
-		"company_id": user.CompanyID,
+		"id":                user.ID,
+		"username":          user.Username,
+		"name":              user.Name,
+		"bio":               user.Bio,
+		"avatar":            user.Avatar,
+		"avatar_thumbnails": user.AvatarThumbnails,
+		"email":             user.Email,
+		"phone":             user.Phone,
+		"company_id":        user.CompanyID,
 	})
 }
 
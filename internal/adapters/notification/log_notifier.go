@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"construct-backend/internal/core/domain"
+	"log"
+)
+
+// LogNotifier implements ports.LeadNotifier by writing to the application
+// log. It records identifiers only: the prospect's name, phone and message
+// stay in the client record, and the admins are counted rather than named.
+// Replace it with an adapter for a delivery channel (email, push) by
+// implementing the same interface.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) NotifyNewLead(company *domain.Company, admins []domain.User, lead *domain.Client, message string) error {
+	log.Printf("new lead for company %s: client %s, %d admin(s) to notify", company.ID, lead.ID, len(admins))
+	return nil
+}
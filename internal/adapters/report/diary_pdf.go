@@ -0,0 +1,202 @@
+package report
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"strings"
+)
+
+var weekdays = []string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+var weatherLabels = map[string]string{
+	domain.WeatherClear:  "céu limpo",
+	domain.WeatherCloudy: "nublado",
+	domain.WeatherRain:   "chuva",
+	domain.WeatherStorm:  "temporal",
+}
+
+var occurrenceLabels = map[string]string{
+	domain.OccurrenceIncident: "Incidente",
+	domain.OccurrenceAccident: "Acidente",
+	domain.OccurrenceDelay:    "Atraso",
+	domain.OccurrenceVisit:    "Visita",
+	domain.OccurrenceOther:    "Outros",
+}
+
+var severityLabels = map[string]string{
+	domain.SeverityLow:    "baixa",
+	domain.SeverityMedium: "média",
+	domain.SeverityHigh:   "alta",
+}
+
+// DiaryPDFRenderer renders diary reports as an A4 "Relatório Diário de Obra".
+type DiaryPDFRenderer struct{}
+
+func NewDiaryPDFRenderer() *DiaryPDFRenderer {
+	return &DiaryPDFRenderer{}
+}
+
+func (r *DiaryPDFRenderer) RenderDiaryReport(report *domain.DiaryReport) ([]byte, error) {
+	doc := &diaryDocument{document: newDocument("Relatório Diário de Obra", report.Project.Name), report: report}
+
+	doc.letterhead(report.Company, report.Logo, "RELATÓRIO DIÁRIO DE OBRA (RDO)")
+	doc.projectInfo()
+	doc.entries()
+	doc.signatures(report.Company, report.Project)
+
+	return doc.output()
+}
+
+type diaryDocument struct {
+	*document
+	report *domain.DiaryReport
+}
+
+func (d *diaryDocument) projectInfo() {
+	project := d.report.Project
+
+	period := "Todo o período"
+	switch {
+	case d.report.From != nil && d.report.To != nil:
+		period = fmt.Sprintf("%s a %s", d.report.From.Format(dateLayout), d.report.To.Format(dateLayout))
+	case d.report.From != nil:
+		period = "A partir de " + d.report.From.Format(dateLayout)
+	case d.report.To != nil:
+		period = "Até " + d.report.To.Format(dateLayout)
+	}
+
+	rows := [][2]string{
+		{"Obra", project.Name},
+		{"Endereço", project.Address},
+		{"Cliente", clientName(project)},
+		{"Situação", projectStatusLabel(project.Status)},
+		{"Período", period},
+		{"Emitido em", d.report.GeneratedAt.Format(dateLayout + " 15:04")},
+	}
+	switch d.report.Visibility {
+	case domain.DiaryVisibilityPublic:
+		rows = append(rows, [2]string{"Conteúdo", "Somente itens públicos"})
+	case domain.DiaryVisibilityInternal:
+		rows = append(rows, [2]string{"Conteúdo", "Somente itens internos"})
+	}
+
+	d.infoRows(rows)
+}
+
+func (d *diaryDocument) entries() {
+	if len(d.report.Entries) == 0 {
+		d.pdf.SetFont("Helvetica", "I", 10)
+		d.pdf.CellFormat(0, 8, d.tr("Nenhum registro no período."), "", 1, "L", false, 0, "")
+		return
+	}
+
+	for _, entry := range d.report.Entries {
+		d.ensureSpace(20)
+		title := fmt.Sprintf("%s (%s)", entry.EntryDate.Format(dateLayout), weekdays[entry.EntryDate.Weekday()])
+		if entry.Title != "" {
+			title += " — " + entry.Title
+		}
+		d.pdf.SetFillColor(230, 230, 230)
+		d.pdf.SetFont("Helvetica", "B", 11)
+		d.pdf.CellFormat(0, 8, d.tr(title), "", 1, "L", true, 0, "")
+		if signOff := entrySignOff(entry); signOff != "" {
+			d.pdf.SetFont("Helvetica", "I", 8)
+			d.pdf.MultiCell(0, 4, d.tr(signOff), "", "L", false)
+		}
+		d.pdf.Ln(2)
+
+		for _, item := range entry.Items {
+			d.item(item)
+		}
+		d.pdf.Ln(3)
+	}
+}
+
+func (d *diaryDocument) item(item domain.DiaryItem) {
+	label := item.Label
+	if d.report.Visibility == domain.DiaryVisibilityAll && item.Visibility == domain.DiaryVisibilityInternal {
+		label = strings.TrimSpace(label + " (interno)")
+	}
+
+	switch {
+	case item.Type == domain.DiaryItemField && label != "":
+		d.labelledLine(label, item.Content)
+	case item.Type == domain.DiaryItemWeather && item.Weather != nil:
+		weather := fmt.Sprintf("manhã %s, tarde %s", weatherLabels[item.Weather.Morning], weatherLabels[item.Weather.Afternoon])
+		if item.Weather.RainStoppage {
+			weather += fmt.Sprintf("; paralisação por chuva (%s h)", formatNumber(item.Weather.StoppageHours))
+		}
+		d.labelledLine(sectionLabel(label, "Clima"), weather)
+	case item.Type == domain.DiaryItemWorkforce:
+		lines := make([]string, 0, len(item.Workforce))
+		total := 0
+		for _, crew := range item.Workforce {
+			lines = append(lines, fmt.Sprintf("%s: %d", crew.Trade, crew.Headcount))
+			total += crew.Headcount
+		}
+		d.labelledLine(sectionLabel(label, "Efetivo"), fmt.Sprintf("%s (total %d)", strings.Join(lines, ", "), total))
+	case item.Type == domain.DiaryItemEquipment:
+		lines := make([]string, 0, len(item.Equipment))
+		for _, equipment := range item.Equipment {
+			lines = append(lines, fmt.Sprintf("%s x%d, %s h", equipment.Name, equipment.Quantity, formatNumber(equipment.Hours)))
+		}
+		d.labelledLine(sectionLabel(label, "Equipamentos"), strings.Join(lines, "; "))
+	case item.Type == domain.DiaryItemOccurrence && item.Occurrence != nil:
+		heading := fmt.Sprintf("%s (%s)", occurrenceLabels[item.Occurrence.Category], severityLabels[item.Occurrence.Severity])
+		d.labelledLine(sectionLabel(label, "Ocorrência"), heading+" — "+item.Content)
+	case item.Type == domain.DiaryItemService:
+		d.pdf.SetFont("Helvetica", "B", 10)
+		d.pdf.CellFormat(0, 5, d.tr(sectionLabel(label, "Serviços executados")), "", 1, "L", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 10)
+		for _, service := range item.Services {
+			d.pdf.MultiCell(0, 5, d.tr("• "+service.Description), "", "L", false)
+		}
+		d.pdf.Ln(1)
+	default:
+		if label != "" {
+			d.pdf.SetFont("Helvetica", "B", 10)
+			d.pdf.CellFormat(0, 5, d.tr(label), "", 1, "L", false, 0, "")
+		}
+		if item.Content != "" {
+			style := ""
+			if item.Type == "attachment" {
+				style = "I"
+			}
+			d.pdf.SetFont("Helvetica", style, 10)
+			d.pdf.MultiCell(0, 5, d.tr(item.Content), "", "L", false)
+		}
+		d.pdf.Ln(1)
+	}
+
+	d.photos(d.report.Photos[item.ID])
+	d.files(d.report.Files[item.ID])
+}
+
+// entrySignOff describes who approved and acknowledged an entry, and whether
+// it amends or was amended by another one.
+func entrySignOff(entry domain.DiaryEntry) string {
+	var parts []string
+	if entry.AmendsEntryID != "" {
+		parts = append(parts, "Retificação de registro anterior")
+	}
+	if entry.ApprovedAt != nil {
+		parts = append(parts, "Aprovado por "+sectionLabel(entry.ApproverName, "responsável técnico")+" em "+entry.ApprovedAt.Format(dateLayout+" 15:04"))
+	} else {
+		parts = append(parts, "Não aprovado")
+	}
+	if entry.AcknowledgedAt != nil {
+		parts = append(parts, "Ciente: "+entry.AcknowledgedBy+" em "+entry.AcknowledgedAt.Format(dateLayout+" 15:04"))
+	}
+	if entry.AmendedByID != "" {
+		parts = append(parts, "Retificado por registro posterior")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// sectionLabel prefers the item's own label over the section's default.
+func sectionLabel(label, fallback string) string {
+	if label != "" {
+		return label
+	}
+	return fallback
+}
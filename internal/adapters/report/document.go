@@ -0,0 +1,266 @@
+package report
+
+import (
+	"bytes"
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin   = 15.0
+	bottomMargin = 20.0
+	photoGap     = 6.0
+	photoMaxH    = 70.0
+	dateLayout   = "02/01/2006"
+)
+
+var projectStatusLabels = map[string]string{
+	domain.ProjectStatusPlanning:   "Planejamento",
+	domain.ProjectStatusInProgress: "Em andamento",
+	domain.ProjectStatusPaused:     "Paralisada",
+	domain.ProjectStatusCompleted:  "Concluída",
+	domain.ProjectStatusCancelled:  "Cancelada",
+}
+
+// document is an A4 report under construction, with the layout every report
+// shares: the company letterhead, a block of project details, photo grids and
+// a page footer naming the project.
+type document struct {
+	pdf    *fpdf.Fpdf
+	tr     func(string) string
+	images int
+}
+
+func newDocument(title, projectName string) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, bottomMargin)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(title, true)
+
+	d := &document{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() { d.footer(projectName) })
+	pdf.AddPage()
+	return d
+}
+
+func (d *document) output() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// letterhead prints the company's logo and details followed by the report
+// heading.
+func (d *document) letterhead(company *domain.Company, logo *domain.ReportImage, heading string) {
+	textX := pageMargin
+	top := d.pdf.GetY()
+	logoBottom := top
+
+	if logo != nil {
+		width, height := fitBox(logo, 30, 20)
+		d.pdf.ImageOptions(d.registerImage(logo), pageMargin, top, width, height, false, fpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+		textX = pageMargin + 35
+		logoBottom = top + height
+	}
+
+	d.pdf.SetXY(textX, top)
+	d.pdf.SetFont("Helvetica", "B", 12)
+	d.pdf.CellFormat(0, 6, d.tr(companyName(company)), "", 2, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{
+		labelled("CNPJ", company.CNPJ),
+		company.Address,
+		joinNonEmpty(" · ", company.Phone, company.Email),
+	} {
+		if line != "" {
+			d.pdf.CellFormat(0, 4.5, d.tr(line), "", 2, "L", false, 0, "")
+		}
+	}
+
+	d.pdf.SetXY(pageMargin, max(d.pdf.GetY(), logoBottom)+4)
+	d.pdf.SetFont("Helvetica", "B", 14)
+	d.pdf.CellFormat(0, 9, d.tr(heading), "TB", 1, "C", false, 0, "")
+	d.pdf.Ln(3)
+}
+
+// infoRows prints label/value pairs, skipping empty values.
+func (d *document) infoRows(rows [][2]string) {
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		d.pdf.SetFont("Helvetica", "B", 10)
+		d.pdf.CellFormat(28, 6, d.tr(row[0]+":"), "", 0, "L", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 10)
+		d.pdf.MultiCell(0, 6, d.tr(row[1]), "", "L", false)
+	}
+	d.pdf.Ln(4)
+}
+
+// photos lays images out two per row, each scaled into half the page width.
+func (d *document) photos(photos []domain.ReportImage) {
+	pageWidth, _ := d.pdf.GetPageSize()
+	cellWidth := (pageWidth - 2*pageMargin - photoGap) / 2
+
+	for i := 0; i < len(photos); i += 2 {
+		row := photos[i:min(i+2, len(photos))]
+		rowHeight := 0.0
+		for _, photo := range row {
+			_, height := fitBox(&photo, cellWidth, photoMaxH)
+			rowHeight = max(rowHeight, height)
+		}
+		d.ensureSpace(rowHeight + 8)
+
+		top := d.pdf.GetY()
+		for col, photo := range row {
+			width, height := fitBox(&photo, cellWidth, photoMaxH)
+			x := pageMargin + float64(col)*(cellWidth+photoGap)
+			d.pdf.ImageOptions(d.registerImage(&photo), x, top, width, height, false, fpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+			d.pdf.SetFont("Helvetica", "", 7)
+			d.pdf.SetXY(x, top+rowHeight+0.5)
+			d.pdf.CellFormat(cellWidth, 4, d.tr(photo.Name), "", 0, "L", false, 0, "")
+		}
+		d.pdf.SetXY(pageMargin, top+rowHeight+6)
+	}
+}
+
+func (d *document) files(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	d.pdf.SetFont("Helvetica", "I", 9)
+	d.pdf.MultiCell(0, 5, d.tr("Anexos: "+strings.Join(files, ", ")), "", "L", false)
+}
+
+// signatures leaves room for the company's and the client's signatures.
+func (d *document) signatures(company *domain.Company, project *domain.Project) {
+	d.ensureSpace(40)
+	d.pdf.Ln(15)
+	pageWidth, _ := d.pdf.GetPageSize()
+	width := (pageWidth - 2*pageMargin - 20) / 2
+	top := d.pdf.GetY()
+
+	blocks := [][2]string{
+		{"Responsável técnico", companyName(company)},
+		{"Cliente / Fiscalização", clientName(project)},
+	}
+	for col, block := range blocks {
+		x := pageMargin + float64(col)*(width+20)
+		d.pdf.Line(x, top, x+width, top)
+		d.pdf.SetXY(x, top+1)
+		d.pdf.SetFont("Helvetica", "B", 9)
+		d.pdf.CellFormat(width, 5, d.tr(block[0]), "", 2, "C", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 9)
+		d.pdf.CellFormat(width, 5, d.tr(block[1]), "", 2, "C", false, 0, "")
+	}
+}
+
+func (d *document) footer(projectName string) {
+	d.pdf.SetY(-15)
+	d.pdf.SetFont("Helvetica", "I", 8)
+	d.pdf.CellFormat(0, 10, d.tr(fmt.Sprintf("%s — Página %d de {nb}", projectName, d.pdf.PageNo())), "", 0, "C", false, 0, "")
+}
+
+func (d *document) labelledLine(label, content string) {
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.Write(5, d.tr(label+": "))
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.Write(5, d.tr(content))
+	d.pdf.Ln(6)
+}
+
+func (d *document) ensureSpace(height float64) {
+	_, pageHeight := d.pdf.GetPageSize()
+	if d.pdf.GetY()+height > pageHeight-bottomMargin {
+		d.pdf.AddPage()
+	}
+}
+
+func (d *document) registerImage(image *domain.ReportImage) string {
+	d.images++
+	name := fmt.Sprintf("image-%d", d.images)
+	d.pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "JPG"}, bytes.NewReader(image.Data))
+	return name
+}
+
+// fitBox scales an image to fit within maxWidth x maxHeight millimetres,
+// keeping its aspect ratio.
+func fitBox(image *domain.ReportImage, maxWidth, maxHeight float64) (float64, float64) {
+	if image.Width == 0 || image.Height == 0 {
+		return maxWidth, maxHeight
+	}
+	ratio := float64(image.Height) / float64(image.Width)
+	width, height := maxWidth, maxWidth*ratio
+	if height > maxHeight {
+		height = maxHeight
+		width = maxHeight / ratio
+	}
+	return width, height
+}
+
+func companyName(company *domain.Company) string {
+	if company.PublicName != "" {
+		return company.PublicName
+	}
+	return company.Name
+}
+
+func clientName(project *domain.Project) string {
+	if project.Client == nil {
+		return ""
+	}
+	return project.Client.Name
+}
+
+func projectStatusLabel(status string) string {
+	if label := projectStatusLabels[status]; label != "" {
+		return label
+	}
+	return status
+}
+
+func formatNumber(value float64) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', -1, 64), ".", ",", 1)
+}
+
+// formatCurrency formats an amount in reais, as in "R$ 1.234,50".
+func formatCurrency(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	whole, cents, _ := strings.Cut(strconv.FormatFloat(value, 'f', 2, 64), ".")
+
+	var grouped strings.Builder
+	for index, digit := range whole {
+		if index > 0 && (len(whole)-index)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + "R$ " + grouped.String() + "," + cents
+}
+
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + value
+}
+
+func joinNonEmpty(separator string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"bytes"
+	"construct-backend/internal/core/domain"
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// MeasurementCSVRenderer exports measurements as a spreadsheet: semicolon
+// separated with decimal commas and a byte order mark, the way Excel opens
+// files in Brazilian Portuguese.
+type MeasurementCSVRenderer struct{}
+
+func NewMeasurementCSVRenderer() *MeasurementCSVRenderer {
+	return &MeasurementCSVRenderer{}
+}
+
+func (r *MeasurementCSVRenderer) RenderMeasurementReport(report *domain.MeasurementReport) ([]byte, error) {
+	measurement := report.Measurement
+
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	writer := csv.NewWriter(&buf)
+	writer.Comma = ';'
+
+	rows := [][]string{
+		{"Obra", report.Project.Name},
+		{"Medição", strconv.Itoa(measurement.Number)},
+		{"Período", measurement.PeriodStart.Format(dateLayout) + " a " + measurement.PeriodEnd.Format(dateLayout)},
+		{"Situação", measurementStatusLabels[measurement.Status]},
+		{},
+		{"Item", "Descrição", "Unidade", "Preço unitário", "Contratado", "Anterior", "No período", "Acumulado", "% acumulado", "Valor no período"},
+	}
+	for _, line := range measurement.Items {
+		rows = append(rows, []string{
+			line.Code,
+			line.Description,
+			line.Unit,
+			formatDecimal(line.UnitPrice),
+			formatNumber(line.PlannedQuantity),
+			formatNumber(line.PreviousQuantity),
+			formatNumber(line.ExecutedQuantity),
+			formatNumber(line.AccumulatedQuantity),
+			formatNumber(line.AccumulatedPercent),
+			formatDecimal(line.Amount),
+		})
+	}
+	rows = append(rows,
+		[]string{},
+		[]string{"Valor desta medição", formatDecimal(measurement.Amount)},
+		[]string{"Acumulado até esta medição", formatDecimal(measurement.AccumulatedAmount)},
+		[]string{"Valor do contrato", formatDecimal(measurement.ContractTotal)},
+		[]string{"Execução acumulada (%)", formatNumber(measurement.AccumulatedPercent)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatDecimal formats an amount with two decimals and a decimal comma.
+func formatDecimal(value float64) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', 2, 64), ".", ",", 1)
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+)
+
+var measurementStatusLabels = map[string]string{
+	domain.MeasurementStatusDraft:     "Rascunho",
+	domain.MeasurementStatusSubmitted: "Enviada ao cliente",
+	domain.MeasurementStatusApproved:  "Aprovada",
+	domain.MeasurementStatusRejected:  "Recusada",
+}
+
+// MeasurementPDFRenderer renders measurements as an A4 "Boletim de Medição".
+type MeasurementPDFRenderer struct{}
+
+func NewMeasurementPDFRenderer() *MeasurementPDFRenderer {
+	return &MeasurementPDFRenderer{}
+}
+
+func (r *MeasurementPDFRenderer) RenderMeasurementReport(report *domain.MeasurementReport) ([]byte, error) {
+	doc := &measurementDocument{document: newDocument("Boletim de Medição", report.Project.Name), report: report}
+
+	doc.letterhead(report.Company, report.Logo, fmt.Sprintf("BOLETIM DE MEDIÇÃO Nº %d", report.Measurement.Number))
+	doc.measurementInfo()
+	doc.items()
+	doc.totals()
+	doc.signatures(report.Company, report.Project)
+
+	return doc.output()
+}
+
+type measurementDocument struct {
+	*document
+	report *domain.MeasurementReport
+}
+
+// measurementColumns are the widths of the items table, filling the 180mm
+// between the margins.
+var measurementColumns = []struct {
+	header string
+	width  float64
+}{
+	{"Item", 14},
+	{"Descrição", 50},
+	{"Un.", 10},
+	{"Contratado", 18},
+	{"Anterior", 18},
+	{"No período", 18},
+	{"Acumulado", 18},
+	{"%", 12},
+	{"Valor", 22},
+}
+
+func (d *measurementDocument) measurementInfo() {
+	project := d.report.Project
+	measurement := d.report.Measurement
+
+	rows := [][2]string{
+		{"Obra", project.Name},
+		{"Endereço", project.Address},
+		{"Cliente", clientName(project)},
+		{"Período", measurement.PeriodStart.Format(dateLayout) + " a " + measurement.PeriodEnd.Format(dateLayout)},
+		{"Situação", measurementStatusLabels[measurement.Status]},
+		{"Emitido em", d.report.GeneratedAt.Format(dateLayout + " 15:04")},
+	}
+	if measurement.DecidedAt != nil {
+		rows = append(rows, [2]string{measurementStatusLabels[measurement.Status] + " por", measurement.DecidedBy + " em " + measurement.DecidedAt.Format(dateLayout+" 15:04")})
+	}
+	d.infoRows(rows)
+}
+
+func (d *measurementDocument) items() {
+	d.tableHeader()
+	d.pdf.SetFont("Helvetica", "", 7)
+	for _, line := range d.report.Measurement.Items {
+		if d.ensureRow() {
+			d.tableHeader()
+			d.pdf.SetFont("Helvetica", "", 7)
+		}
+		values := []string{
+			line.Code,
+			line.Description,
+			line.Unit,
+			formatNumber(line.PlannedQuantity),
+			formatNumber(line.PreviousQuantity),
+			formatNumber(line.ExecutedQuantity),
+			formatNumber(line.AccumulatedQuantity),
+			formatNumber(line.AccumulatedPercent),
+			formatCurrency(line.Amount),
+		}
+		for index, value := range values {
+			align := "R"
+			if index < 3 {
+				align = "L"
+			}
+			newLine := 0
+			if index == len(values)-1 {
+				newLine = 1
+			}
+			d.pdf.CellFormat(measurementColumns[index].width, 6, d.tr(value), "1", newLine, align, false, 0, "")
+		}
+	}
+	d.pdf.Ln(4)
+}
+
+func (d *measurementDocument) tableHeader() {
+	d.ensureSpace(14)
+	d.pdf.SetFillColor(200, 200, 200)
+	d.pdf.SetFont("Helvetica", "B", 7)
+	for index, column := range measurementColumns {
+		newLine := 0
+		if index == len(measurementColumns)-1 {
+			newLine = 1
+		}
+		d.pdf.CellFormat(column.width, 7, d.tr(column.header), "1", newLine, "C", true, 0, "")
+	}
+}
+
+// ensureRow starts a new page when the next row would not fit, reporting
+// whether it did so the table header can be repeated.
+func (d *measurementDocument) ensureRow() bool {
+	page := d.pdf.PageNo()
+	d.ensureSpace(6)
+	return d.pdf.PageNo() != page
+}
+
+func (d *measurementDocument) totals() {
+	measurement := d.report.Measurement
+
+	rows := [][2]string{
+		{"Valor desta medição", formatCurrency(measurement.Amount)},
+		{"Acumulado até esta medição", formatCurrency(measurement.AccumulatedAmount)},
+		{"Valor do contrato", formatCurrency(measurement.ContractTotal)},
+		{"Execução acumulada", formatNumber(measurement.AccumulatedPercent) + "%"},
+	}
+
+	d.ensureSpace(float64(len(rows))*7 + 4)
+	for index, row := range rows {
+		style := ""
+		if index == 0 {
+			style = "B"
+		}
+		d.pdf.SetFont("Helvetica", style, 10)
+		d.pdf.CellFormat(140, 7, d.tr(row[0]), "", 0, "R", false, 0, "")
+		d.pdf.CellFormat(40, 7, d.tr(row[1]), "", 1, "R", false, 0, "")
+	}
+
+	if measurement.Notes != "" {
+		d.pdf.Ln(2)
+		d.ensureSpace(20)
+		d.pdf.SetFont("Helvetica", "B", 11)
+		d.pdf.CellFormat(0, 7, d.tr("Observações"), "", 1, "L", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 10)
+		d.pdf.MultiCell(0, 5, d.tr(measurement.Notes), "", "L", false)
+	}
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+)
+
+var punchStatusLabels = map[string]string{
+	domain.PunchStatusOpen:     "Aberto",
+	domain.PunchStatusFixed:    "Corrigido",
+	domain.PunchStatusVerified: "Verificado",
+}
+
+var punchPriorityLabels = map[string]string{
+	domain.PunchPriorityLow:    "baixa",
+	domain.PunchPriorityMedium: "média",
+	domain.PunchPriorityHigh:   "alta",
+}
+
+// PunchListPDFRenderer renders punch lists as an A4 "Lista de Pendências".
+type PunchListPDFRenderer struct{}
+
+func NewPunchListPDFRenderer() *PunchListPDFRenderer {
+	return &PunchListPDFRenderer{}
+}
+
+func (r *PunchListPDFRenderer) RenderPunchListReport(report *domain.PunchListReport) ([]byte, error) {
+	doc := &punchListDocument{document: newDocument("Lista de Pendências", report.Project.Name), report: report}
+
+	doc.letterhead(report.Company, report.Logo, "LISTA DE PENDÊNCIAS")
+	doc.projectInfo()
+	doc.items()
+	doc.signatures(report.Company, report.Project)
+
+	return doc.output()
+}
+
+type punchListDocument struct {
+	*document
+	report *domain.PunchListReport
+}
+
+func (d *punchListDocument) projectInfo() {
+	project := d.report.Project
+
+	counts := map[string]int{}
+	for _, item := range d.report.Items {
+		counts[item.Status]++
+	}
+	summary := fmt.Sprintf("%d itens: %d abertos, %d corrigidos, %d verificados",
+		len(d.report.Items), counts[domain.PunchStatusOpen], counts[domain.PunchStatusFixed], counts[domain.PunchStatusVerified])
+
+	rows := [][2]string{
+		{"Obra", project.Name},
+		{"Endereço", project.Address},
+		{"Cliente", clientName(project)},
+		{"Situação", projectStatusLabel(project.Status)},
+		{"Emitido em", d.report.GeneratedAt.Format(dateLayout + " 15:04")},
+		{"Resumo", summary},
+	}
+	if d.report.Status != "" {
+		rows = append(rows, [2]string{"Filtro", "Somente itens com situação " + punchStatusLabels[d.report.Status]})
+	}
+	d.infoRows(rows)
+}
+
+func (d *punchListDocument) items() {
+	if len(d.report.Items) == 0 {
+		d.pdf.SetFont("Helvetica", "I", 10)
+		d.pdf.CellFormat(0, 8, d.tr("Nenhuma pendência registrada."), "", 1, "L", false, 0, "")
+		return
+	}
+
+	for index, item := range d.report.Items {
+		d.ensureSpace(30)
+		d.pdf.SetFillColor(230, 230, 230)
+		d.pdf.SetFont("Helvetica", "B", 11)
+		d.pdf.CellFormat(0, 8, d.tr(fmt.Sprintf("%d. %s", index+1, item.Title)), "", 1, "L", true, 0, "")
+		d.pdf.Ln(1)
+
+		if location := joinNonEmpty(" · ", item.Floor, item.Room); location != "" {
+			d.labelledLine("Local", location)
+		}
+		if item.Trade != "" {
+			d.labelledLine("Responsável", item.Trade)
+		}
+		d.labelledLine("Prioridade", punchPriorityLabels[item.Priority])
+		if item.DueDate != nil {
+			d.labelledLine("Prazo", item.DueDate.Format(dateLayout))
+		}
+		d.labelledLine("Situação", punchItemProgress(item))
+		if item.ClientApprovedAt != nil {
+			d.labelledLine("Aprovado pelo cliente", item.ClientApprovedBy+" em "+item.ClientApprovedAt.Format(dateLayout+" 15:04"))
+		}
+		if item.Description != "" {
+			d.pdf.SetFont("Helvetica", "", 10)
+			d.pdf.MultiCell(0, 5, d.tr(item.Description), "", "L", false)
+			d.pdf.Ln(1)
+		}
+
+		d.photos(d.report.Photos[item.ID])
+		d.files(d.report.Files[item.ID])
+		d.pdf.Ln(3)
+	}
+}
+
+// punchItemProgress is the item's status with the dates it was fixed and
+// verified.
+func punchItemProgress(item domain.PunchItem) string {
+	progress := punchStatusLabels[item.Status]
+	if item.FixedAt != nil {
+		progress += " · corrigido em " + item.FixedAt.Format(dateLayout)
+	}
+	if item.VerifiedAt != nil {
+		progress += " · verificado em " + item.VerifiedAt.Format(dateLayout)
+	}
+	return progress
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+)
+
+var quoteStatusLabels = map[string]string{
+	domain.QuoteStatusDraft:    "Rascunho",
+	domain.QuoteStatusSent:     "Enviado",
+	domain.QuoteStatusAccepted: "Aceito",
+	domain.QuoteStatusRejected: "Recusado",
+}
+
+// QuotePDFRenderer renders quotes as an A4 "Orçamento".
+type QuotePDFRenderer struct{}
+
+func NewQuotePDFRenderer() *QuotePDFRenderer {
+	return &QuotePDFRenderer{}
+}
+
+func (r *QuotePDFRenderer) RenderQuoteReport(report *domain.QuoteReport) ([]byte, error) {
+	quote := report.Quote
+	title := fmt.Sprintf("Orçamento nº %d", quote.Number)
+	doc := &quoteDocument{document: newDocument(title, title+" — "+quote.Title), report: report}
+
+	doc.letterhead(report.Company, report.Logo, "ORÇAMENTO")
+	doc.quoteInfo()
+	doc.items()
+	doc.totals()
+	doc.notes()
+	doc.signatures(report.Company, &domain.Project{Client: report.Client})
+
+	return doc.output()
+}
+
+type quoteDocument struct {
+	*document
+	report *domain.QuoteReport
+}
+
+func (d *quoteDocument) quoteInfo() {
+	quote := d.report.Quote
+
+	rows := [][2]string{
+		{"Número", fmt.Sprintf("%d (versão %d)", quote.Number, quote.Version)},
+		{"Título", quote.Title},
+		{"Cliente", d.report.Client.Name},
+		{"Endereço", quote.Address},
+		{"Situação", quoteStatusLabels[quote.Status]},
+		{"Emitido em", d.report.GeneratedAt.Format(dateLayout + " 15:04")},
+	}
+	if quote.ValidUntil != nil {
+		rows = append(rows, [2]string{"Válido até", quote.ValidUntil.Format(dateLayout)})
+	}
+	if quote.DecidedAt != nil {
+		rows = append(rows, [2]string{quoteStatusLabels[quote.Status] + " por", quote.DecidedBy + " em " + quote.DecidedAt.Format(dateLayout+" 15:04")})
+	}
+	d.infoRows(rows)
+}
+
+// items prints the priced lines in a table, grouped under their stages in the
+// order the stages first appear.
+func (d *quoteDocument) items() {
+	var stages []string
+	byStage := map[string][]domain.QuoteItem{}
+	for _, item := range d.report.Quote.Items {
+		if _, ok := byStage[item.Stage]; !ok {
+			stages = append(stages, item.Stage)
+		}
+		byStage[item.Stage] = append(byStage[item.Stage], item)
+	}
+
+	widths := []float64{68, 18, 14, 29, 18, 33}
+	d.tableHeader(widths)
+	for _, stage := range stages {
+		if stage != "" {
+			d.ensureSpace(14)
+			d.pdf.SetFillColor(230, 230, 230)
+			d.pdf.SetFont("Helvetica", "B", 10)
+			d.pdf.CellFormat(0, 7, d.tr(stage), "1", 1, "L", true, 0, "")
+		}
+		d.pdf.SetFont("Helvetica", "", 9)
+		for _, item := range byStage[stage] {
+			d.ensureSpace(7)
+			d.pdf.CellFormat(widths[0], 6, d.tr(item.Description), "1", 0, "L", false, 0, "")
+			d.pdf.CellFormat(widths[1], 6, formatNumber(item.Quantity), "1", 0, "R", false, 0, "")
+			d.pdf.CellFormat(widths[2], 6, d.tr(item.Unit), "1", 0, "C", false, 0, "")
+			d.pdf.CellFormat(widths[3], 6, d.tr(formatCurrency(item.UnitPrice)), "1", 0, "R", false, 0, "")
+			discount := ""
+			if item.DiscountPercent != 0 {
+				discount = formatNumber(item.DiscountPercent) + "%"
+			}
+			d.pdf.CellFormat(widths[4], 6, discount, "1", 0, "R", false, 0, "")
+			d.pdf.CellFormat(widths[5], 6, d.tr(formatCurrency(item.Total)), "1", 1, "R", false, 0, "")
+		}
+	}
+	d.pdf.Ln(4)
+}
+
+func (d *quoteDocument) tableHeader(widths []float64) {
+	d.ensureSpace(14)
+	d.pdf.SetFillColor(200, 200, 200)
+	d.pdf.SetFont("Helvetica", "B", 9)
+	headers := []string{"Descrição", "Qtd.", "Un.", "Preço unit.", "Desc.", "Total"}
+	for index, header := range headers {
+		line := 0
+		if index == len(headers)-1 {
+			line = 1
+		}
+		d.pdf.CellFormat(widths[index], 7, d.tr(header), "1", line, "C", true, 0, "")
+	}
+}
+
+func (d *quoteDocument) totals() {
+	quote := d.report.Quote
+
+	rows := [][2]string{{"Subtotal", formatCurrency(quote.Subtotal)}}
+	if quote.BDIPercent != 0 {
+		rows = append(rows, [2]string{"BDI (" + formatNumber(quote.BDIPercent) + "%)", formatCurrency(quote.BDIAmount)})
+	}
+	if quote.DiscountPercent != 0 {
+		rows = append(rows, [2]string{"Desconto (" + formatNumber(quote.DiscountPercent) + "%)", "- " + formatCurrency(quote.DiscountAmount)})
+	}
+	rows = append(rows, [2]string{"Total", formatCurrency(quote.Total)})
+
+	d.ensureSpace(float64(len(rows))*7 + 4)
+	for index, row := range rows {
+		style := ""
+		if index == len(rows)-1 {
+			style = "B"
+		}
+		d.pdf.SetFont("Helvetica", style, 10)
+		d.pdf.CellFormat(147, 7, d.tr(row[0]), "", 0, "R", false, 0, "")
+		d.pdf.CellFormat(33, 7, d.tr(row[1]), "", 1, "R", false, 0, "")
+	}
+	d.pdf.Ln(4)
+}
+
+func (d *quoteDocument) notes() {
+	if d.report.Quote.Notes == "" {
+		return
+	}
+	d.ensureSpace(20)
+	d.pdf.SetFont("Helvetica", "B", 11)
+	d.pdf.CellFormat(0, 7, d.tr("Observações"), "", 1, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(0, 5, d.tr(d.report.Quote.Notes), "", "L", false)
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -30,10 +32,50 @@ const (
 	entityDiaryEntry = "diary_entry"
 	entityLink       = "link"
 	entityLinkClick  = "link_click"
+	entityBudgetLine = "budget_line"
+	entityExpense    = "expense"
+
+	entityMaterial          = "material"
+	entityStockMovement     = "stock_movement"
+	entityMaterialThreshold = "material_threshold"
+	entityPurchaseRequest   = "purchase_request"
+
+	entityMilestone        = "milestone"
+	entityStatusTransition = "status_transition"
+	entityProjectTemplate  = "project_template"
+	entityAttachment       = "attachment"
+	entityDiaryRevision    = "diary_revision"
+	entityDiaryTemplate    = "diary_template"
+	entitySyncChange       = "sync_change"
+	entityPunchItem        = "punch_item"
+
+	entityInspectionTemplate = "inspection_template"
+	entityInspection         = "inspection"
+	entityChangeOrder        = "change_order"
+	entityQuote              = "quote"
+	entityQuoteVersion       = "quote_version"
+	entityInvoice            = "invoice"
+	entityInvoiceNumber      = "invoice_number"
+	entityContractItem       = "contract_item"
+	entityMeasurement        = "measurement"
+	entityRateLimit          = "rate_limit"
 )
 
+const maxTransactItems = 100
+
+// dynamoAPI is the part of the DynamoDB client the repository uses.
+type dynamoAPI interface {
+	dynamodb.QueryAPIClient
+	dynamodb.ScanAPIClient
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
 type DynamoRepository struct {
-	client    *dynamodb.Client
+	client    dynamoAPI
 	tableName string
 }
 
@@ -58,6 +100,9 @@ type dynamoItem struct {
 	IsPublic   bool   `dynamodbav:"is_public,omitempty"`
 	CreatedAt  string `dynamodbav:"created_at,omitempty"`
 	EntryDate  string `dynamodbav:"entry_date,omitempty"`
+	Sequence   int64  `dynamodbav:"sequence,omitempty"`
+	Hits       int    `dynamodbav:"hits,omitempty"`
+	ExpiresAt  int64  `dynamodbav:"expires_at,omitempty"`
 
 	User       *domain.User       `dynamodbav:"user,omitempty"`
 	Company    *domain.Company    `dynamodbav:"company,omitempty"`
@@ -69,6 +114,31 @@ type dynamoItem struct {
 	DiaryEntry *domain.DiaryEntry `dynamodbav:"diary_entry,omitempty"`
 	Link       *domain.Link       `dynamodbav:"link,omitempty"`
 	LinkClick  *domain.LinkClick  `dynamodbav:"link_click,omitempty"`
+	BudgetLine *domain.BudgetLine `dynamodbav:"budget_line,omitempty"`
+	Expense    *domain.Expense    `dynamodbav:"expense,omitempty"`
+
+	Material          *domain.Material          `dynamodbav:"material,omitempty"`
+	StockMovement     *domain.StockMovement     `dynamodbav:"stock_movement,omitempty"`
+	MaterialThreshold *domain.MaterialThreshold `dynamodbav:"material_threshold,omitempty"`
+	PurchaseRequest   *domain.PurchaseRequest   `dynamodbav:"purchase_request,omitempty"`
+
+	Milestone        *domain.Milestone          `dynamodbav:"milestone,omitempty"`
+	StatusTransition *domain.StatusTransition   `dynamodbav:"status_transition,omitempty"`
+	ProjectTemplate  *domain.ProjectTemplate    `dynamodbav:"project_template,omitempty"`
+	Attachment       *domain.Attachment         `dynamodbav:"attachment,omitempty"`
+	DiaryRevision    *domain.DiaryEntryRevision `dynamodbav:"diary_revision,omitempty"`
+	DiaryTemplate    *domain.DiaryTemplate      `dynamodbav:"diary_template,omitempty"`
+	SyncChange       *domain.SyncChange         `dynamodbav:"sync_change,omitempty"`
+	PunchItem        *domain.PunchItem          `dynamodbav:"punch_item,omitempty"`
+
+	InspectionTemplate *domain.InspectionTemplate `dynamodbav:"inspection_template,omitempty"`
+	Inspection         *domain.Inspection         `dynamodbav:"inspection,omitempty"`
+	ChangeOrder        *domain.ChangeOrder        `dynamodbav:"change_order,omitempty"`
+	Quote              *domain.Quote              `dynamodbav:"quote,omitempty"`
+	QuoteVersion       *domain.QuoteVersion       `dynamodbav:"quote_version,omitempty"`
+	Invoice            *domain.Invoice            `dynamodbav:"invoice,omitempty"`
+	ContractItem       *domain.ContractItem       `dynamodbav:"contract_item,omitempty"`
+	Measurement        *domain.Measurement        `dynamodbav:"measurement,omitempty"`
 }
 
 func NewDynamoRepository(ctx context.Context, tableName string) (*DynamoRepository, error) {
@@ -103,6 +173,101 @@ func (r *DynamoRepository) putItem(ctx context.Context, item dynamoItem) error {
 	return err
 }
 
+// transactPut writes all items atomically. DynamoDB caps a transaction at 100 items.
+func (r *DynamoRepository) transactPut(ctx context.Context, items []dynamoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if len(items) > maxTransactItems {
+		return fmt.Errorf("transaction exceeds %d items", maxTransactItems)
+	}
+
+	writes := make([]types.TransactWriteItem, 0, len(items))
+	for _, item := range items {
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return err
+		}
+		writes = append(writes, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName: aws.String(r.tableName),
+				Item:      av,
+			},
+		})
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: writes,
+	})
+	return err
+}
+
+// transactConditional applies writes in one transaction. A failed condition
+// on any of them is a version conflict.
+func (r *DynamoRepository) transactConditional(ctx context.Context, writes []types.TransactWriteItem) error {
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: writes,
+	})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return ports.ErrVersionConflict
+			}
+		}
+	}
+	return err
+}
+
+// transactPutAll writes items in transactions of at most maxTransactItems.
+// The chunks are separate transactions, so readers can see the ones already
+// written; when a later chunk fails, those are deleted again on a best-effort
+// basis. Callers write the parent item afterwards, so that a partial write is
+// never reachable through it.
+func (r *DynamoRepository) transactPutAll(ctx context.Context, items []dynamoItem) error {
+	for start := 0; start < len(items); start += maxTransactItems {
+		end := start + maxTransactItems
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := r.transactPut(ctx, items[start:end]); err != nil {
+			for _, written := range items[:start] {
+				// Best effort: the original error is what the caller needs.
+				_ = r.deleteItem(ctx, written.PK, written.SK)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// transactDeleteAll deletes items in transactions of at most
+// maxTransactItems, in order. Callers list the parent item last: if a chunk
+// fails the parent is still there, so the delete can simply be retried.
+func (r *DynamoRepository) transactDeleteAll(ctx context.Context, items []dynamoItem) error {
+	for start := 0; start < len(items); start += maxTransactItems {
+		end := start + maxTransactItems
+		if end > len(items) {
+			end = len(items)
+		}
+		writes := make([]types.TransactWriteItem, 0, end-start)
+		for _, item := range items[start:end] {
+			writes = append(writes, types.TransactWriteItem{
+				Delete: &types.Delete{
+					TableName: aws.String(r.tableName),
+					Key:       key(item.PK, item.SK),
+				},
+			})
+		}
+		if _, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: writes,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *DynamoRepository) deleteItem(ctx context.Context, pk, sk string) error {
 	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(r.tableName),
@@ -300,13 +465,14 @@ func (r *DynamoRepository) GetPublicProfile(username string) (*domain.PublicProf
 		return nil, err
 	}
 	return &domain.PublicProfile{
-		ID:        user.ID,
-		Username:  user.Username,
-		Name:      user.Name,
-		Bio:       user.Bio,
-		Avatar:    user.Avatar,
-		CompanyID: user.CompanyID,
-		Links:     links,
+		ID:               user.ID,
+		Username:         user.Username,
+		Name:             user.Name,
+		Bio:              user.Bio,
+		Avatar:           user.Avatar,
+		AvatarThumbnails: user.AvatarThumbnails,
+		CompanyID:        user.CompanyID,
+		Links:            links,
 	}, nil
 }
 
@@ -320,6 +486,18 @@ func (r *DynamoRepository) UpdateBio(userID, bio string) error {
 	return r.CreateUser(user)
 }
 
+func (r *DynamoRepository) UpdateAvatar(user *domain.User) error {
+	current, err := r.GetUserByID(user.ID)
+	if err != nil {
+		return err
+	}
+	current.Avatar = user.Avatar
+	current.AvatarThumbnails = user.AvatarThumbnails
+	current.AvatarKey = user.AvatarKey
+	current.UpdatedAt = time.Now()
+	return r.CreateUser(current)
+}
+
 func (r *DynamoRepository) UpdateProfile(user *domain.User) error {
 	current, err := r.GetUserByID(user.ID)
 	if err != nil {
@@ -441,7 +619,7 @@ func (r *DynamoRepository) GetClientByID(id, companyID string) (*domain.Client,
 	if err != nil {
 		return nil, err
 	}
-	if item.Client == nil {
+	if item.Client == nil || item.Client.DeletedAt != nil {
 		return nil, gorm.ErrRecordNotFound
 	}
 	client := *item.Client
@@ -453,6 +631,10 @@ func (r *DynamoRepository) GetClientByID(id, companyID string) (*domain.Client,
 	return &client, nil
 }
 
+func (r *DynamoRepository) GetClientWithTrashed(id, companyID string) (*domain.Client, error) {
+	return r.clientItemByID(id, companyID)
+}
+
 func (r *DynamoRepository) GetAllClients(companyID string) ([]domain.Client, error) {
 	items, err := r.query(context.Background(),
 		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(clientSK(""))),
@@ -462,7 +644,7 @@ func (r *DynamoRepository) GetAllClients(companyID string) ([]domain.Client, err
 	}
 	clients := make([]domain.Client, 0, len(items))
 	for _, item := range items {
-		if item.Client != nil {
+		if item.Client != nil && item.Client.DeletedAt == nil {
 			clients = append(clients, *item.Client)
 		}
 	}
@@ -474,8 +656,68 @@ func (r *DynamoRepository) UpdateClient(client *domain.Client) error {
 	return r.CreateClient(client)
 }
 
+// DeleteClient permanently removes a client and its comments. It refuses while
+// any project, trashed or not, quote or invoice still references the client.
 func (r *DynamoRepository) DeleteClient(id, companyID string) error {
-	return r.deleteItem(context.Background(), companyPK(companyID), clientSK(id))
+	ctx := context.Background()
+	if _, err := r.clientItemByID(id, companyID); err != nil {
+		return err
+	}
+	projects, err := r.query(ctx,
+		expression.Key("GSI3PK").Equal(expression.Value(clientPK(id))).And(expression.Key("GSI3SK").BeginsWith(projectPK(""))),
+		withIndex("GSI3"),
+		withLimit(1),
+	)
+	if err != nil {
+		return err
+	}
+	if len(projects) > 0 {
+		return ports.ErrClientInUse
+	}
+	billed, err := r.clientBilled(ctx, id, companyID)
+	if err != nil {
+		return err
+	}
+	if billed {
+		return ports.ErrClientInUse
+	}
+	comments, err := r.query(ctx,
+		expression.Key("PK").Equal(expression.Value(clientPK(id))),
+	)
+	if err != nil {
+		return err
+	}
+	items := append(comments, dynamoItem{PK: companyPK(companyID), SK: clientSK(id)})
+	return r.transactDeleteAll(ctx, items)
+}
+
+// clientBilled reports whether a quote or an invoice of the company names the
+// client. Neither is indexed by client, so the company's are read.
+func (r *DynamoRepository) clientBilled(ctx context.Context, id, companyID string) (bool, error) {
+	quotes, err := r.query(ctx,
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(quoteSK(""))),
+	)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range quotes {
+		if item.Quote != nil && item.Quote.ClientID == id {
+			return true, nil
+		}
+	}
+	invoices, err := r.query(ctx,
+		expression.Key("GSI2PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("GSI2SK").BeginsWith(invoiceSK(""))),
+		withIndex("GSI2"),
+	)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range invoices {
+		if item.Invoice != nil && item.Invoice.ClientID == id {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (r *DynamoRepository) AddComment(comment *domain.Comment) error {
@@ -509,9 +751,8 @@ func (r *DynamoRepository) commentsByClient(clientID string) ([]domain.Comment,
 
 // ProjectRepository
 
-func (r *DynamoRepository) CreateProject(project *domain.Project) error {
-	item := projectItem(project)
-	return r.putItem(context.Background(), item)
+func (r *DynamoRepository) CreateProject(project *domain.Project, transitions ...*domain.StatusTransition) error {
+	return r.putWithTransitions(projectItem(project), transitions)
 }
 
 func (r *DynamoRepository) GetAllProjects(companyID string) ([]domain.Project, error) {
@@ -523,7 +764,7 @@ func (r *DynamoRepository) GetAllProjects(companyID string) ([]domain.Project, e
 	}
 	projects := make([]domain.Project, 0, len(items))
 	for _, item := range items {
-		if item.Project == nil {
+		if item.Project == nil || item.Project.DeletedAt != nil {
 			continue
 		}
 		project, err := r.enrichProject(*item.Project)
@@ -545,7 +786,7 @@ func (r *DynamoRepository) GetProjectsByClientID(clientID, companyID string) ([]
 	}
 	projects := make([]domain.Project, 0, len(items))
 	for _, item := range items {
-		if item.Project == nil || item.Project.CompanyID != companyID {
+		if item.Project == nil || item.Project.CompanyID != companyID || item.Project.DeletedAt != nil {
 			continue
 		}
 		project, err := r.enrichProject(*item.Project)
@@ -562,7 +803,7 @@ func (r *DynamoRepository) GetProjectByID(id, companyID string) (*domain.Project
 	if err != nil {
 		return nil, err
 	}
-	if item.Project == nil {
+	if item.Project == nil || item.Project.DeletedAt != nil {
 		return nil, gorm.ErrRecordNotFound
 	}
 	return r.enrichProject(*item.Project)
@@ -577,87 +818,245 @@ func (r *DynamoRepository) GetPublicProjectByID(id string) (*domain.Project, err
 	if err != nil {
 		return nil, err
 	}
-	if len(items) == 0 || items[0].Project == nil || !items[0].Project.IsPublic {
+	if len(items) == 0 || items[0].Project == nil || !items[0].Project.IsPublic || items[0].Project.DeletedAt != nil {
 		return nil, gorm.ErrRecordNotFound
 	}
 	return r.enrichProject(*items[0].Project)
 }
 
-func (r *DynamoRepository) UpdateProject(project *domain.Project) error {
+func (r *DynamoRepository) UpdateProject(project *domain.Project, transitions ...*domain.StatusTransition) error {
 	project.UpdatedAt = time.Now()
-	return r.CreateProject(project)
+	return r.CreateProject(project, transitions...)
 }
 
+// UpdateProjectProgress sets the progress inside the stored project item. The
+// condition keeps it from creating an item for a project that is gone.
+func (r *DynamoRepository) UpdateProjectProgress(id, companyID string, progress float64) error {
+	expr, err := expression.NewBuilder().
+		WithUpdate(expression.Set(expression.Name("project.Progress"), expression.Value(progress))).
+		WithCondition(expression.AttributeExists(expression.Name("PK"))).
+		Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.tableName),
+		Key:                       key(companyPK(companyID), projectSK(id)),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+// DeleteProject permanently removes a project together with every item stored
+// under its partition (tasks, milestones, diary, financials, materials, status
+// history) and the subtasks of its tasks.
 func (r *DynamoRepository) DeleteProject(id, companyID string) error {
-	return r.deleteItem(context.Background(), companyPK(companyID), projectSK(id))
+	ctx := context.Background()
+	if _, err := r.projectItemByID(id, companyID); err != nil {
+		return err
+	}
+	children, err := r.query(ctx,
+		expression.Key("PK").Equal(expression.Value(projectPK(id))),
+	)
+	if err != nil {
+		return err
+	}
+	var items []dynamoItem
+	for _, child := range children {
+		if child.Task != nil {
+			subtasks, err := r.query(ctx,
+				expression.Key("PK").Equal(expression.Value(taskPK(child.Task.ID))),
+			)
+			if err != nil {
+				return err
+			}
+			items = append(items, subtasks...)
+		}
+		items = append(items, child)
+	}
+	items = append(items, dynamoItem{PK: companyPK(companyID), SK: projectSK(id)})
+	return r.transactDeleteAll(ctx, items)
 }
 
-func (r *DynamoRepository) AddTask(task *domain.Task) error {
-	item := dynamoItem{
-		PK:         projectPK(task.ProjectID),
-		SK:         taskSK(task.ID),
-		GSI1PK:     taskPK(task.ID),
-		GSI1SK:     companyPK(task.CompanyID),
-		GSI2PK:     companyPK(task.CompanyID),
-		GSI2SK:     taskStatusSK(task.Status, task.ID),
-		EntityType: entityTask,
-		ID:         task.ID,
-		CompanyID:  task.CompanyID,
-		UserID:     task.UserID,
-		ProjectID:  task.ProjectID,
-		Status:     task.Status,
-		CreatedAt:  timeKey(task.CreatedAt),
-		Task:       task,
+// AddTask stores the task and its subtasks as separate items. Offline sync
+// creates tasks under IDs chosen by the client, so one already in use, by
+// this company or another, is a version conflict rather than a second item.
+func (r *DynamoRepository) AddTask(task *domain.Task, transitions ...*domain.StatusTransition) error {
+	if err := r.checkNewID(taskPK(task.ID)); err != nil {
+		return err
 	}
-	return r.putItem(context.Background(), item)
+	startVersions(task)
+	stored := *task
+	stored.Subtasks = nil
+	put, err := r.conditionalPutWrite(taskItem(&stored), newItemCondition())
+	if err != nil {
+		return err
+	}
+	writes := []types.TransactWriteItem{put}
+	for i := range task.Subtasks {
+		put, err := r.conditionalPutWrite(subtaskItem(&task.Subtasks[i]), newItemCondition())
+		if err != nil {
+			return err
+		}
+		writes = append(writes, put)
+	}
+	history, err := r.transitionWrites(transitions)
+	if err != nil {
+		return err
+	}
+	return r.writeSynced(context.Background(), task.CompanyID, append(writes, history...), taskChanges(task, false))
 }
 
 func (r *DynamoRepository) AddSubtask(subtask *domain.Subtask) error {
-	item := dynamoItem{
-		PK:         taskPK(subtask.TaskID),
-		SK:         subtaskSK(subtask.ID),
-		GSI1PK:     subtaskPK(subtask.ID),
-		GSI1SK:     companyPK(subtask.CompanyID),
-		EntityType: entitySubtask,
-		ID:         subtask.ID,
-		CompanyID:  subtask.CompanyID,
-		UserID:     subtask.UserID,
-		TaskID:     subtask.TaskID,
-		Status:     subtask.Status,
-		CreatedAt:  timeKey(subtask.CreatedAt),
-		Subtask:    subtask,
+	task, err := r.taskItemByID(subtask.TaskID, subtask.CompanyID)
+	if err != nil {
+		return err
 	}
-	return r.putItem(context.Background(), item)
+	if err := r.checkNewID(subtaskPK(subtask.ID)); err != nil {
+		return err
+	}
+	subtask.Version = 1
+	put, err := r.conditionalPutWrite(subtaskItem(subtask), newItemCondition())
+	if err != nil {
+		return err
+	}
+	return r.writeSynced(context.Background(), subtask.CompanyID,
+		[]types.TransactWriteItem{put},
+		[]domain.SyncChange{subtaskChange(subtask, task.ProjectID, false)},
+	)
 }
 
-func (r *DynamoRepository) UpdateTask(task *domain.Task) error {
-	return r.AddTask(task)
+func (r *DynamoRepository) UpdateTask(task *domain.Task, transitions ...*domain.StatusTransition) error {
+	read := task.Version
+	task.Version++
+	put, err := r.versionedPutWrite(taskItem(task), "task.Version", read)
+	var history []types.TransactWriteItem
+	if err == nil {
+		history, err = r.transitionWrites(transitions)
+	}
+	if err == nil {
+		err = r.writeSynced(context.Background(), task.CompanyID,
+			append([]types.TransactWriteItem{put}, history...),
+			[]domain.SyncChange{syncChange(task.CompanyID, task.ProjectID, domain.SyncEntityTask, task.ID, task.Version, false)},
+		)
+	}
+	if err != nil {
+		task.Version = read
+	}
+	return err
 }
 
 func (r *DynamoRepository) UpdateSubtask(subtask *domain.Subtask) error {
-	return r.AddSubtask(subtask)
+	task, err := r.taskItemByID(subtask.TaskID, subtask.CompanyID)
+	if err != nil {
+		return err
+	}
+	read := subtask.Version
+	subtask.Version++
+	put, err := r.versionedPutWrite(subtaskItem(subtask), "subtask.Version", read)
+	if err == nil {
+		err = r.writeSynced(context.Background(), subtask.CompanyID,
+			[]types.TransactWriteItem{put},
+			[]domain.SyncChange{subtaskChange(subtask, task.ProjectID, false)},
+		)
+	}
+	if err != nil {
+		subtask.Version = read
+	}
+	return err
 }
 
-func (r *DynamoRepository) UpdateSubtaskByTaskID(taskID string) error {
+// UpdateSubtaskByTaskID completes the task's open subtasks, each with its own
+// version, in transactions of syncBatchSize subtasks.
+func (r *DynamoRepository) UpdateSubtaskByTaskID(taskID, companyID string) error {
+	task, err := r.taskItemByID(taskID, companyID)
+	if err != nil {
+		return err
+	}
 	subtasks, err := r.subtasksByTask(taskID)
 	if err != nil {
 		return err
 	}
-	for _, subtask := range subtasks {
-		subtask.Status = "Completed"
-		if err := r.AddSubtask(&subtask); err != nil {
+	var (
+		writes  []types.TransactWriteItem
+		changes []domain.SyncChange
+	)
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		if subtask.CompanyID != companyID || subtask.Status == domain.SubtaskStatusCompleted {
+			continue
+		}
+		read := subtask.Version
+		subtask.Status = domain.SubtaskStatusCompleted
+		subtask.Version++
+		put, err := r.versionedPutWrite(subtaskItem(subtask), "subtask.Version", read)
+		if err != nil {
 			return err
 		}
+		writes = append(writes, put)
+		changes = append(changes, subtaskChange(subtask, task.ProjectID, false))
+		if len(writes) == syncBatchSize {
+			if err := r.writeSynced(context.Background(), companyID, writes, changes); err != nil {
+				return err
+			}
+			writes, changes = nil, nil
+		}
 	}
-	return nil
+	if len(writes) == 0 {
+		return nil
+	}
+	return r.writeSynced(context.Background(), companyID, writes, changes)
 }
 
+// DeleteTask removes a task with its subtasks. Expenses booked against the
+// task are kept but no longer attributed to it. The task goes last, so a
+// failed delete can simply be retried.
 func (r *DynamoRepository) DeleteTask(id, companyID string) error {
+	ctx := context.Background()
 	task, err := r.GetTaskByID(id, companyID)
 	if err != nil {
 		return err
 	}
-	return r.deleteItem(context.Background(), projectPK(task.ProjectID), taskSK(id))
+	expenses, err := r.GetExpensesByProject(task.ProjectID, companyID)
+	if err != nil {
+		return err
+	}
+	for _, expense := range expenses {
+		if expense.TaskID != id {
+			continue
+		}
+		expense.TaskID = ""
+		if err := r.putItem(ctx, expenseItem(&expense)); err != nil {
+			return err
+		}
+	}
+	for start := 0; start < len(task.Subtasks); start += syncBatchSize {
+		end := start + syncBatchSize
+		if end > len(task.Subtasks) {
+			end = len(task.Subtasks)
+		}
+		writes := make([]types.TransactWriteItem, 0, end-start)
+		changes := make([]domain.SyncChange, 0, end-start)
+		for i := range task.Subtasks[start:end] {
+			subtask := &task.Subtasks[start+i]
+			writes = append(writes, r.deleteWrite(taskPK(id), subtaskSK(subtask.ID)))
+			changes = append(changes, subtaskChange(subtask, task.ProjectID, true))
+		}
+		if err := r.writeSynced(ctx, companyID, writes, changes); err != nil {
+			return err
+		}
+	}
+	return r.writeSynced(ctx, companyID,
+		[]types.TransactWriteItem{r.deleteWrite(projectPK(task.ProjectID), taskSK(id))},
+		[]domain.SyncChange{syncChange(companyID, task.ProjectID, domain.SyncEntityTask, id, task.Version, true)},
+	)
 }
 
 func (r *DynamoRepository) DeleteSubtask(id, companyID string) error {
@@ -665,10 +1064,53 @@ func (r *DynamoRepository) DeleteSubtask(id, companyID string) error {
 	if err != nil {
 		return err
 	}
-	return r.deleteItem(context.Background(), taskPK(subtask.TaskID), subtaskSK(id))
+	task, err := r.taskItemByID(subtask.TaskID, companyID)
+	if err != nil {
+		return err
+	}
+	return r.writeSynced(context.Background(), companyID,
+		[]types.TransactWriteItem{r.deleteWrite(taskPK(subtask.TaskID), subtaskSK(id))},
+		[]domain.SyncChange{subtaskChange(subtask, task.ProjectID, true)},
+	)
 }
 
 func (r *DynamoRepository) GetTaskByID(id, companyID string) (*domain.Task, error) {
+	task, err := r.taskItemByID(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+	subtasks, err := r.subtasksByTask(id)
+	if err != nil {
+		return nil, err
+	}
+	task.Subtasks = subtasks
+	return task, nil
+}
+
+// checkNewID fails with ErrVersionConflict when an item of any company is
+// indexed under gsi1PK, the ID key of tasks and subtasks.
+func (r *DynamoRepository) checkNewID(gsi1PK string) error {
+	items, err := r.query(context.Background(),
+		expression.Key("GSI1PK").Equal(expression.Value(gsi1PK)),
+		withIndex("GSI1"),
+		withLimit(1),
+	)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		return ports.ErrVersionConflict
+	}
+	return nil
+}
+
+// newItemCondition holds only while nothing is stored at the item's key.
+func newItemCondition() expression.ConditionBuilder {
+	return expression.AttributeNotExists(expression.Name("PK"))
+}
+
+// taskItemByID reads the stored task without its subtasks.
+func (r *DynamoRepository) taskItemByID(id, companyID string) (*domain.Task, error) {
 	items, err := r.query(context.Background(),
 		expression.Key("GSI1PK").Equal(expression.Value(taskPK(id))),
 		withIndex("GSI1"),
@@ -680,13 +1122,7 @@ func (r *DynamoRepository) GetTaskByID(id, companyID string) (*domain.Task, erro
 	if len(items) == 0 || items[0].Task == nil || items[0].Task.CompanyID != companyID {
 		return nil, gorm.ErrRecordNotFound
 	}
-	task := *items[0].Task
-	subtasks, err := r.subtasksByTask(id)
-	if err != nil {
-		return nil, err
-	}
-	task.Subtasks = subtasks
-	return &task, nil
+	return items[0].Task, nil
 }
 
 func (r *DynamoRepository) GetSubtaskByID(id, companyID string) (*domain.Subtask, error) {
@@ -727,9 +1163,44 @@ func (r *DynamoRepository) GetTasksByProjectID(projectID string) ([]domain.Task,
 	return tasks, nil
 }
 
+func (r *DynamoRepository) GetTasksByCompanyID(companyID string) ([]domain.Task, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("GSI2PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("GSI2SK").BeginsWith(taskSK(""))),
+		withIndex("GSI2"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := r.trashedProjectIDs(companyID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]domain.Task, 0, len(items))
+	for _, item := range items {
+		if item.Task == nil || trashed[item.Task.ProjectID] {
+			continue
+		}
+		task := *item.Task
+		subtasks, err := r.subtasksByTask(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		task.Subtasks = subtasks
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
 func (r *DynamoRepository) CreateDiaryEntry(entry *domain.DiaryEntry) error {
-	item := diaryEntryItem(entry)
-	return r.putItem(context.Background(), item)
+	entry.Version = 1
+	put, err := r.putWrite(diaryEntryItem(entry))
+	if err != nil {
+		return err
+	}
+	return r.writeSynced(context.Background(), entry.CompanyID,
+		[]types.TransactWriteItem{put},
+		[]domain.SyncChange{diaryEntryChange(entry, false)},
+	)
 }
 
 func (r *DynamoRepository) GetDiaryEntriesByProject(projectID, companyID string) ([]domain.DiaryEntry, error) {
@@ -758,7 +1229,7 @@ func (r *DynamoRepository) GetPublicDiaryEntriesByProject(projectID string) ([]d
 	}
 	entries := make([]domain.DiaryEntry, 0, len(items))
 	for _, item := range items {
-		if item.DiaryEntry == nil {
+		if item.DiaryEntry == nil || !item.DiaryEntry.Published() {
 			continue
 		}
 		entry := *item.DiaryEntry
@@ -791,8 +1262,63 @@ func (r *DynamoRepository) GetDiaryEntryByID(id, projectID, companyID string) (*
 	return nil, gorm.ErrRecordNotFound
 }
 
+// UpdateDiaryEntry rewrites the entry. Its sort key carries the entry date, so
+// moving an entry to another day also removes the item stored under the old
+// one, in the same transaction.
 func (r *DynamoRepository) UpdateDiaryEntry(entry *domain.DiaryEntry) error {
-	return r.CreateDiaryEntry(entry)
+	previous, err := r.GetDiaryEntryByID(entry.ID, entry.ProjectID, entry.CompanyID)
+	if err != nil {
+		return err
+	}
+	oldSK := diarySK(previous.EntryDate, previous.ID)
+	if oldSK == diarySK(entry.EntryDate, entry.ID) {
+		return r.saveDiaryEntry(entry)
+	}
+
+	read := entry.Version
+	entry.Version++
+	put, err := r.putWrite(diaryEntryItem(entry))
+	if err != nil {
+		entry.Version = read
+		return err
+	}
+	remove, err := r.versionedDeleteWrite(projectPK(entry.ProjectID), oldSK, "diary_entry.Version", read)
+	if err == nil {
+		err = r.writeSynced(context.Background(), entry.CompanyID,
+			[]types.TransactWriteItem{remove, put},
+			[]domain.SyncChange{diaryEntryChange(entry, false)},
+		)
+	}
+	if err != nil {
+		entry.Version = read
+	}
+	return err
+}
+
+func (r *DynamoRepository) UpdateDiaryEntryStatus(entry *domain.DiaryEntry, transitions ...*domain.StatusTransition) error {
+	return r.saveDiaryEntry(entry, transitions...)
+}
+
+// saveDiaryEntry rewrites the entry in place if it is still at the version
+// read, together with the status history entries given.
+func (r *DynamoRepository) saveDiaryEntry(entry *domain.DiaryEntry, transitions ...*domain.StatusTransition) error {
+	read := entry.Version
+	entry.Version++
+	put, err := r.versionedPutWrite(diaryEntryItem(entry), "diary_entry.Version", read)
+	var history []types.TransactWriteItem
+	if err == nil {
+		history, err = r.transitionWrites(transitions)
+	}
+	if err == nil {
+		err = r.writeSynced(context.Background(), entry.CompanyID,
+			append([]types.TransactWriteItem{put}, history...),
+			[]domain.SyncChange{diaryEntryChange(entry, false)},
+		)
+	}
+	if err != nil {
+		entry.Version = read
+	}
+	return err
 }
 
 func (r *DynamoRepository) DeleteDiaryEntry(id, projectID, companyID string) error {
@@ -800,7 +1326,20 @@ func (r *DynamoRepository) DeleteDiaryEntry(id, projectID, companyID string) err
 	if err != nil {
 		return err
 	}
-	return r.deleteItem(context.Background(), projectPK(projectID), diarySK(entry.EntryDate, entry.ID))
+	ctx := context.Background()
+	revisions, err := r.query(ctx,
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(diaryRevisionSKPrefix(id))),
+	)
+	if err != nil {
+		return err
+	}
+	if err := r.transactDeleteAll(ctx, revisions); err != nil {
+		return err
+	}
+	return r.writeSynced(ctx, companyID,
+		[]types.TransactWriteItem{r.deleteWrite(projectPK(projectID), diarySK(entry.EntryDate, entry.ID))},
+		[]domain.SyncChange{diaryEntryChange(entry, true)},
+	)
 }
 
 func (r *DynamoRepository) enrichProject(project domain.Project) (*domain.Project, error) {
@@ -815,6 +1354,11 @@ func (r *DynamoRepository) enrichProject(project domain.Project) (*domain.Projec
 		return nil, err
 	}
 	project.Tasks = tasks
+	milestones, err := r.GetMilestonesByProject(project.ID, project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	project.Milestones = milestones
 	return &project, nil
 }
 
@@ -863,7 +1407,7 @@ func (r *DynamoRepository) GetAllLinks(companyID string) ([]domain.Link, error)
 	}
 	links := make([]domain.Link, 0, len(items))
 	for _, item := range items {
-		if item.Link != nil {
+		if item.Link != nil && item.Link.DeletedAt == nil {
 			links = append(links, *item.Link)
 		}
 	}
@@ -902,8 +1446,20 @@ func (r *DynamoRepository) UpdateLink(link *domain.Link) error {
 	return r.CreateLink(link)
 }
 
+// DeleteLink permanently removes a link and its recorded clicks.
 func (r *DynamoRepository) DeleteLink(id, companyID string) error {
-	return r.deleteItem(context.Background(), companyPK(companyID), linkSK(id))
+	ctx := context.Background()
+	if _, err := r.linkItemByID(id, companyID); err != nil {
+		return err
+	}
+	clicks, err := r.query(ctx,
+		expression.Key("PK").Equal(expression.Value(linkPK(id))),
+	)
+	if err != nil {
+		return err
+	}
+	items := append(clicks, dynamoItem{PK: companyPK(companyID), SK: linkSK(id)})
+	return r.transactDeleteAll(ctx, items)
 }
 
 func (r *DynamoRepository) RegisterClick(linkID string) error {
@@ -988,7 +1544,8 @@ func (r *DynamoRepository) CountProjectsInProgress(companyID string) (int64, err
 	}
 	var count int64
 	for _, project := range projects {
-		if project.Status != "Completed" {
+		status := domain.NormalizeProjectStatus(project.Status)
+		if status != domain.ProjectStatusCompleted && status != domain.ProjectStatusCancelled {
 			count++
 		}
 	}
@@ -1002,7 +1559,7 @@ func (r *DynamoRepository) CountCompletedProjects(companyID string) (int64, erro
 	}
 	var count int64
 	for _, project := range projects {
-		if project.Status == "Completed" {
+		if domain.NormalizeProjectStatus(project.Status) == domain.ProjectStatusCompleted {
 			count++
 		}
 	}
@@ -1017,9 +1574,13 @@ func (r *DynamoRepository) CountActiveTasks(companyID string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	trashed, err := r.trashedProjectIDs(companyID)
+	if err != nil {
+		return 0, err
+	}
 	var count int64
 	for _, item := range items {
-		if item.Task != nil && item.Task.Status != "Completed" {
+		if item.Task != nil && !trashed[item.Task.ProjectID] && domain.NormalizeTaskStatus(item.Task.Status) != domain.TaskStatusDone {
 			count++
 		}
 	}
@@ -1063,6 +1624,42 @@ func projectItem(project *domain.Project) dynamoItem {
 	}
 }
 
+func taskItem(task *domain.Task) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(task.ProjectID),
+		SK:         taskSK(task.ID),
+		GSI1PK:     taskPK(task.ID),
+		GSI1SK:     companyPK(task.CompanyID),
+		GSI2PK:     companyPK(task.CompanyID),
+		GSI2SK:     taskStatusSK(task.Status, task.ID),
+		EntityType: entityTask,
+		ID:         task.ID,
+		CompanyID:  task.CompanyID,
+		UserID:     task.UserID,
+		ProjectID:  task.ProjectID,
+		Status:     task.Status,
+		CreatedAt:  timeKey(task.CreatedAt),
+		Task:       task,
+	}
+}
+
+func subtaskItem(subtask *domain.Subtask) dynamoItem {
+	return dynamoItem{
+		PK:         taskPK(subtask.TaskID),
+		SK:         subtaskSK(subtask.ID),
+		GSI1PK:     subtaskPK(subtask.ID),
+		GSI1SK:     companyPK(subtask.CompanyID),
+		EntityType: entitySubtask,
+		ID:         subtask.ID,
+		CompanyID:  subtask.CompanyID,
+		UserID:     subtask.UserID,
+		TaskID:     subtask.TaskID,
+		Status:     subtask.Status,
+		CreatedAt:  timeKey(subtask.CreatedAt),
+		Subtask:    subtask,
+	}
+}
+
 func diaryEntryItem(entry *domain.DiaryEntry) dynamoItem {
 	return dynamoItem{
 		PK:         projectPK(entry.ProjectID),
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository
+
+func (r *DynamoRepository) CreateAttachment(attachment *domain.Attachment) error {
+	return r.putItem(context.Background(), dynamoItem{
+		PK:         projectPK(attachment.ProjectID),
+		SK:         attachmentSK(attachment.ID),
+		EntityType: entityAttachment,
+		ID:         attachment.ID,
+		CompanyID:  attachment.CompanyID,
+		ProjectID:  attachment.ProjectID,
+		Status:     attachment.Status,
+		CreatedAt:  timeKey(attachment.CreatedAt),
+		Attachment: attachment,
+	})
+}
+
+func (r *DynamoRepository) GetAttachmentsByProject(projectID, companyID string) ([]domain.Attachment, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(attachmentSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]domain.Attachment, 0, len(items))
+	for _, item := range items {
+		if item.Attachment != nil && item.Attachment.CompanyID == companyID {
+			attachments = append(attachments, *item.Attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+	})
+	return attachments, nil
+}
+
+func (r *DynamoRepository) GetAttachmentByID(id, projectID, companyID string) (*domain.Attachment, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), attachmentSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Attachment == nil || item.Attachment.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Attachment, nil
+}
+
+func (r *DynamoRepository) UpdateAttachment(attachment *domain.Attachment) error {
+	return r.CreateAttachment(attachment)
+}
+
+func (r *DynamoRepository) DeleteAttachment(id, projectID, companyID string) error {
+	if _, err := r.GetAttachmentByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), attachmentSK(id))
+}
+
+func attachmentSK(id string) string { return "ATTACHMENT#" + id }
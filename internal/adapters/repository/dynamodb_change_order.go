@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// ChangeOrderRepository
+
+func (r *DynamoRepository) CreateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error {
+	return r.putWithTransitions(changeOrderItem(order), transitions)
+}
+
+func (r *DynamoRepository) GetChangeOrdersByProject(projectID, companyID string) ([]domain.ChangeOrder, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(changeOrderSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]domain.ChangeOrder, 0, len(items))
+	for _, item := range items {
+		if item.ChangeOrder != nil && item.ChangeOrder.CompanyID == companyID {
+			orders = append(orders, *item.ChangeOrder)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Number < orders[j].Number
+	})
+	return orders, nil
+}
+
+func (r *DynamoRepository) GetChangeOrderByID(id, projectID, companyID string) (*domain.ChangeOrder, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), changeOrderSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.ChangeOrder == nil || item.ChangeOrder.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.ChangeOrder, nil
+}
+
+func (r *DynamoRepository) UpdateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error {
+	return r.CreateChangeOrder(order, transitions...)
+}
+
+func (r *DynamoRepository) DecideChangeOrder(order *domain.ChangeOrder, previous string, transition *domain.StatusTransition) error {
+	put, err := r.conditionalPutWrite(changeOrderItem(order), expression.Name("change_order.Status").Equal(expression.Value(previous)))
+	if err != nil {
+		return err
+	}
+	history, err := r.transitionWrites([]*domain.StatusTransition{transition})
+	if err != nil {
+		return err
+	}
+	return r.transactConditional(context.Background(), append([]types.TransactWriteItem{put}, history...))
+}
+
+// ShiftChangeOrderWork writes the shift in transactions of syncBatchSize
+// records. Each also rewrites the order with the records done so far, on
+// condition that it is still as last written, so parts are applied in order
+// and only once.
+func (r *DynamoRepository) ShiftChangeOrderWork(order *domain.ChangeOrder, tasks []domain.Task, milestones []domain.Milestone) error {
+	type shift struct {
+		id     string
+		task   int
+		write  types.TransactWriteItem
+		change *domain.SyncChange
+	}
+	shifts := make([]shift, 0, len(tasks)+len(milestones))
+	for i := range tasks {
+		stored := tasks[i]
+		stored.Subtasks = nil
+		stored.Version++
+		put, err := r.versionedPutWrite(taskItem(&stored), "task.Version", tasks[i].Version)
+		if err != nil {
+			return err
+		}
+		change := syncChange(stored.CompanyID, stored.ProjectID, domain.SyncEntityTask, stored.ID, stored.Version, false)
+		shifts = append(shifts, shift{id: stored.ID, task: i, write: put, change: &change})
+	}
+	for i := range milestones {
+		put, err := r.putWrite(milestoneItem(&milestones[i]))
+		if err != nil {
+			return err
+		}
+		shifts = append(shifts, shift{id: milestones[i].ID, task: -1, write: put})
+	}
+
+	ctx := context.Background()
+	for start := 0; start == 0 || start < len(shifts); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(shifts))
+		next := *order
+		next.ShiftedWork = append([]string(nil), order.ShiftedWork...)
+		next.UpdatedAt = time.Now()
+		if end == len(shifts) {
+			shiftedAt := next.UpdatedAt
+			next.ScheduleShiftedAt = &shiftedAt
+		}
+		var changes []domain.SyncChange
+		for _, s := range shifts[start:end] {
+			next.ShiftedWork = append(next.ShiftedWork, s.id)
+			if s.change != nil {
+				changes = append(changes, *s.change)
+			}
+		}
+
+		put, err := r.conditionalPutWrite(changeOrderItem(&next),
+			expression.Name("change_order.UpdatedAt").Equal(expression.Value(order.UpdatedAt)))
+		if err != nil {
+			return err
+		}
+		writes := []types.TransactWriteItem{put}
+		for _, s := range shifts[start:end] {
+			writes = append(writes, s.write)
+		}
+		if err := r.writeSynced(ctx, order.CompanyID, writes, changes); err != nil {
+			return err
+		}
+
+		*order = next
+		for _, s := range shifts[start:end] {
+			if s.task >= 0 {
+				tasks[s.task].Version++
+			}
+		}
+	}
+	return nil
+}
+
+func (r *DynamoRepository) DeleteChangeOrder(id, projectID, companyID string) error {
+	if _, err := r.GetChangeOrderByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), changeOrderSK(id))
+}
+
+func changeOrderItem(order *domain.ChangeOrder) dynamoItem {
+	return dynamoItem{
+		PK:          projectPK(order.ProjectID),
+		SK:          changeOrderSK(order.ID),
+		EntityType:  entityChangeOrder,
+		ID:          order.ID,
+		CompanyID:   order.CompanyID,
+		ProjectID:   order.ProjectID,
+		Status:      order.Status,
+		CreatedAt:   timeKey(order.CreatedAt),
+		ChangeOrder: order,
+	}
+}
+
+func changeOrderSK(id string) string { return "CHANGEORDER#" + id }
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+)
+
+// Diary revisions
+
+func (r *DynamoRepository) CreateDiaryRevision(revision *domain.DiaryEntryRevision) error {
+	return r.putItem(context.Background(), dynamoItem{
+		PK:            projectPK(revision.ProjectID),
+		SK:            diaryRevisionSK(revision.EntryID, revision.Revision),
+		EntityType:    entityDiaryRevision,
+		ID:            revision.ID,
+		CompanyID:     revision.CompanyID,
+		UserID:        revision.UserID,
+		ProjectID:     revision.ProjectID,
+		CreatedAt:     timeKey(revision.CreatedAt),
+		DiaryRevision: revision,
+	})
+}
+
+func (r *DynamoRepository) GetDiaryRevisions(entryID, projectID, companyID string) ([]domain.DiaryEntryRevision, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(diaryRevisionSKPrefix(entryID))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]domain.DiaryEntryRevision, 0, len(items))
+	for _, item := range items {
+		if item.DiaryRevision != nil && item.DiaryRevision.CompanyID == companyID {
+			revisions = append(revisions, *item.DiaryRevision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+func diaryRevisionSKPrefix(entryID string) string { return "REVISION#" + entryID + "#" }
+
+func diaryRevisionSK(entryID string, revision int) string {
+	return diaryRevisionSKPrefix(entryID) + fmt.Sprintf("%06d", revision)
+}
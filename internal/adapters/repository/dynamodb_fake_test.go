@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamo is an in-memory table for repository tests. It understands the
+// expressions the repository builds for keys and filters (equality,
+// greater-than and begins_with) and enforces the transaction size limit; it
+// rejects condition and update expressions rather than ignoring them.
+type fakeDynamo struct {
+	mu    sync.Mutex
+	items map[string]map[string]types.AttributeValue
+
+	// transactions counts the TransactWriteItems calls that were applied.
+	transactions int
+	// failTransaction makes the n-th TransactWriteItems call (1-based) fail
+	// without writing anything; zero disables it.
+	failTransaction int
+	calls           int
+}
+
+func newFakeDynamo() *fakeDynamo {
+	return &fakeDynamo{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func newFakeDynamoRepository() (*DynamoRepository, *fakeDynamo) {
+	fake := newFakeDynamo()
+	return &DynamoRepository{client: fake, tableName: "test"}, fake
+}
+
+func (f *fakeDynamo) GetItem(_ context.Context, params *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: f.items[fakeKey(params.Key)]}, nil
+}
+
+func (f *fakeDynamo) PutItem(_ context.Context, params *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if params.ConditionExpression != nil {
+		return nil, fmt.Errorf("fake dynamo: conditions are not supported")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[fakeKey(params.Item)] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamo) DeleteItem(_ context.Context, params *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if params.ConditionExpression != nil {
+		return nil, fmt.Errorf("fake dynamo: conditions are not supported")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, fakeKey(params.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (f *fakeDynamo) UpdateItem(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return nil, fmt.Errorf("fake dynamo: updates are not supported")
+}
+
+func (f *fakeDynamo) TransactWriteItems(_ context.Context, params *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if len(params.TransactItems) > maxTransactItems {
+		return nil, fmt.Errorf("fake dynamo: transaction of %d items", len(params.TransactItems))
+	}
+	if f.calls == f.failTransaction {
+		return nil, fmt.Errorf("fake dynamo: transaction %d failed", f.calls)
+	}
+	for _, write := range params.TransactItems {
+		switch {
+		case write.Put != nil && write.Put.ConditionExpression == nil:
+		case write.Delete != nil && write.Delete.ConditionExpression == nil:
+		default:
+			return nil, fmt.Errorf("fake dynamo: unsupported transaction write")
+		}
+	}
+	for _, write := range params.TransactItems {
+		if write.Put != nil {
+			f.items[fakeKey(write.Put.Item)] = write.Put.Item
+		} else {
+			delete(f.items, fakeKey(write.Delete.Key))
+		}
+	}
+	f.transactions++
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (f *fakeDynamo) Query(_ context.Context, params *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clauses, err := parseFakeClauses(aws.ToString(params.KeyConditionExpression), params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	sortKey := "SK"
+	if params.IndexName != nil {
+		sortKey = aws.ToString(params.IndexName) + "SK"
+	}
+
+	var items []map[string]types.AttributeValue
+	for _, item := range f.items {
+		if matchesFakeClauses(item, clauses) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		less := fakeString(items[i], sortKey) < fakeString(items[j], sortKey)
+		if params.ScanIndexForward != nil && !*params.ScanIndexForward {
+			return !less
+		}
+		return less
+	})
+	if params.Limit != nil && int(*params.Limit) < len(items) {
+		items = items[:*params.Limit]
+	}
+	return &dynamodb.QueryOutput{Items: items, Count: int32(len(items))}, nil
+}
+
+func (f *fakeDynamo) Scan(_ context.Context, params *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clauses, err := parseFakeClauses(aws.ToString(params.FilterExpression), params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]types.AttributeValue
+	for _, item := range f.items {
+		if matchesFakeClauses(item, clauses) {
+			items = append(items, item)
+		}
+	}
+	return &dynamodb.ScanOutput{Items: items, Count: int32(len(items))}, nil
+}
+
+// countPartition returns how many items are stored under pk.
+func (f *fakeDynamo) countPartition(pk string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, item := range f.items {
+		if fakeString(item, "PK") == pk {
+			count++
+		}
+	}
+	return count
+}
+
+type fakeClause struct {
+	attribute string
+	operator  string
+	value     string
+}
+
+var (
+	fakeComparison = regexp.MustCompile(`^\(?(#\d+) (=|>) (:\d+)\)?$`)
+	fakeBeginsWith = regexp.MustCompile(`^\(?begins_with \((#\d+), (:\d+)\)\)?$`)
+)
+
+func parseFakeClauses(expression string, names map[string]string, values map[string]types.AttributeValue) ([]fakeClause, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	var clauses []fakeClause
+	for _, part := range strings.Split(expression, " AND ") {
+		if match := fakeComparison.FindStringSubmatch(part); match != nil {
+			clauses = append(clauses, fakeClause{attribute: names[match[1]], operator: match[2], value: fakeValue(values[match[3]])})
+			continue
+		}
+		if match := fakeBeginsWith.FindStringSubmatch(part); match != nil {
+			clauses = append(clauses, fakeClause{attribute: names[match[1]], operator: "begins_with", value: fakeValue(values[match[2]])})
+			continue
+		}
+		return nil, fmt.Errorf("fake dynamo: unsupported expression %q", part)
+	}
+	return clauses, nil
+}
+
+func matchesFakeClauses(item map[string]types.AttributeValue, clauses []fakeClause) bool {
+	for _, clause := range clauses {
+		if _, ok := item[clause.attribute]; !ok {
+			return false
+		}
+		value := fakeString(item, clause.attribute)
+		switch clause.operator {
+		case "=":
+			if value != clause.value {
+				return false
+			}
+		case ">":
+			if value <= clause.value {
+				return false
+			}
+		case "begins_with":
+			if !strings.HasPrefix(value, clause.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func fakeKey(item map[string]types.AttributeValue) string {
+	return fakeString(item, "PK") + "|" + fakeString(item, "SK")
+}
+
+func fakeString(item map[string]types.AttributeValue, attribute string) string {
+	return fakeValue(item[attribute])
+}
+
+func fakeValue(value types.AttributeValue) string {
+	if s, ok := value.(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
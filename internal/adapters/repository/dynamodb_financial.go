@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// FinancialRepository
+
+func (r *DynamoRepository) GetBudgetLines(projectID, companyID string) ([]domain.BudgetLine, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(budgetLineSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]domain.BudgetLine, 0, len(items))
+	for _, item := range items {
+		if item.BudgetLine != nil && item.BudgetLine.CompanyID == companyID {
+			lines = append(lines, *item.BudgetLine)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Category == lines[j].Category {
+			return lines[i].CreatedAt.Before(lines[j].CreatedAt)
+		}
+		return lines[i].Category < lines[j].Category
+	})
+	return lines, nil
+}
+
+// ReplaceBudgetLines swaps the project's budget in one transaction when the
+// old and the new lines fit in it. Larger budgets are written in chunks, the
+// new lines first: a failed put leaves the old budget as it was, and a failed
+// delete leaves old lines that the next replace removes.
+func (r *DynamoRepository) ReplaceBudgetLines(projectID, companyID string, lines []domain.BudgetLine) error {
+	ctx := context.Background()
+	current, err := r.GetBudgetLines(projectID, companyID)
+	if err != nil {
+		return err
+	}
+	stale := make([]dynamoItem, len(current))
+	for index := range current {
+		stale[index] = budgetLineItem(&current[index])
+	}
+	items := make([]dynamoItem, len(lines))
+	for index := range lines {
+		items[index] = budgetLineItem(&lines[index])
+	}
+
+	if len(stale)+len(items) > maxTransactItems {
+		if err := r.transactPutAll(ctx, items); err != nil {
+			return err
+		}
+		return r.transactDeleteAll(ctx, stale)
+	}
+
+	writes := make([]types.TransactWriteItem, 0, len(stale)+len(items))
+	for _, item := range stale {
+		writes = append(writes, r.deleteWrite(item.PK, item.SK))
+	}
+	for _, item := range items {
+		write, err := r.putWrite(item)
+		if err != nil {
+			return err
+		}
+		writes = append(writes, write)
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: writes,
+	})
+	return err
+}
+
+func (r *DynamoRepository) CreateExpense(expense *domain.Expense) error {
+	return r.putItem(context.Background(), expenseItem(expense))
+}
+
+func (r *DynamoRepository) GetExpensesByProject(projectID, companyID string) ([]domain.Expense, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(expenseSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	expenses := make([]domain.Expense, 0, len(items))
+	for _, item := range items {
+		if item.Expense != nil && item.Expense.CompanyID == companyID {
+			expenses = append(expenses, *item.Expense)
+		}
+	}
+	sort.Slice(expenses, func(i, j int) bool {
+		if expenses[i].ExpenseDate.Equal(expenses[j].ExpenseDate) {
+			return expenses[i].CreatedAt.After(expenses[j].CreatedAt)
+		}
+		return expenses[i].ExpenseDate.After(expenses[j].ExpenseDate)
+	})
+	return expenses, nil
+}
+
+func (r *DynamoRepository) GetExpenseByID(id, projectID, companyID string) (*domain.Expense, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), expenseSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Expense == nil || item.Expense.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Expense, nil
+}
+
+func (r *DynamoRepository) UpdateExpense(expense *domain.Expense) error {
+	return r.CreateExpense(expense)
+}
+
+func (r *DynamoRepository) DeleteExpense(id, projectID, companyID string) error {
+	if _, err := r.GetExpenseByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), expenseSK(id))
+}
+
+func budgetLineItem(line *domain.BudgetLine) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(line.ProjectID),
+		SK:         budgetLineSK(line.ID),
+		EntityType: entityBudgetLine,
+		ID:         line.ID,
+		CompanyID:  line.CompanyID,
+		ProjectID:  line.ProjectID,
+		CreatedAt:  timeKey(line.CreatedAt),
+		BudgetLine: line,
+	}
+}
+
+func expenseItem(expense *domain.Expense) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(expense.ProjectID),
+		SK:         expenseSK(expense.ID),
+		EntityType: entityExpense,
+		ID:         expense.ID,
+		CompanyID:  expense.CompanyID,
+		UserID:     expense.UserID,
+		ProjectID:  expense.ProjectID,
+		TaskID:     expense.TaskID,
+		CreatedAt:  timeKey(expense.CreatedAt),
+		Expense:    expense,
+	}
+}
+
+func budgetLineSK(id string) string { return "BUDGET#" + id }
+func expenseSK(id string) string    { return "EXPENSE#" + id }
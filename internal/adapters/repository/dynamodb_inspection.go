@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"gorm.io/gorm"
+)
+
+// InspectionRepository
+
+func (r *DynamoRepository) CreateInspection(inspection *domain.Inspection) error {
+	return r.putItem(context.Background(), inspectionItem(inspection))
+}
+
+func (r *DynamoRepository) GetInspectionsByProject(projectID, companyID string) ([]domain.Inspection, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(inspectionSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	inspections := make([]domain.Inspection, 0, len(items))
+	for _, item := range items {
+		if item.Inspection != nil && item.Inspection.CompanyID == companyID {
+			inspections = append(inspections, *item.Inspection)
+		}
+	}
+	sort.Slice(inspections, func(i, j int) bool {
+		return inspections[i].CreatedAt.Before(inspections[j].CreatedAt)
+	})
+	return inspections, nil
+}
+
+func (r *DynamoRepository) GetInspectionByID(id, projectID, companyID string) (*domain.Inspection, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), inspectionSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Inspection == nil || item.Inspection.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Inspection, nil
+}
+
+func (r *DynamoRepository) UpdateInspection(inspection *domain.Inspection) error {
+	return r.CreateInspection(inspection)
+}
+
+func (r *DynamoRepository) DeleteInspection(id, projectID, companyID string) error {
+	if _, err := r.GetInspectionByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), inspectionSK(id))
+}
+
+func inspectionItem(inspection *domain.Inspection) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(inspection.ProjectID),
+		SK:         inspectionSK(inspection.ID),
+		EntityType: entityInspection,
+		ID:         inspection.ID,
+		CompanyID:  inspection.CompanyID,
+		ProjectID:  inspection.ProjectID,
+		Status:     inspection.Status,
+		CreatedAt:  timeKey(inspection.CreatedAt),
+		Inspection: inspection,
+	}
+}
+
+func inspectionSK(id string) string { return "INSPECTION#" + id }
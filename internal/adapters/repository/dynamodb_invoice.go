@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// InvoiceRepository
+
+// CreateInvoices writes each invoice with an item claiming its number in the
+// project, all in one transaction, so two schedules created at the same time
+// can not both take a number.
+func (r *DynamoRepository) CreateInvoices(invoices []domain.Invoice) error {
+	if 2*len(invoices) > maxTransactItems {
+		return fmt.Errorf("too many invoices for one transaction")
+	}
+	writes := make([]types.TransactWriteItem, 0, 2*len(invoices))
+	for index := range invoices {
+		for _, item := range []dynamoItem{invoiceItem(&invoices[index]), invoiceNumberItem(&invoices[index])} {
+			write, err := r.conditionalPutWrite(item, newItemCondition())
+			if err != nil {
+				return err
+			}
+			writes = append(writes, write)
+		}
+	}
+	return r.transactConditional(context.Background(), writes)
+}
+
+func (r *DynamoRepository) GetInvoicesByProject(projectID, companyID string) ([]domain.Invoice, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(invoiceSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	invoices := make([]domain.Invoice, 0, len(items))
+	for _, item := range items {
+		if item.Invoice != nil && item.Invoice.CompanyID == companyID {
+			invoices = append(invoices, *item.Invoice)
+		}
+	}
+	sortInvoices(invoices)
+	return invoices, nil
+}
+
+func (r *DynamoRepository) GetInvoicesByCompany(companyID string) ([]domain.Invoice, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("GSI2PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("GSI2SK").BeginsWith(invoiceSK(""))),
+		withIndex("GSI2"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := r.trashedProjectIDs(companyID)
+	if err != nil {
+		return nil, err
+	}
+	invoices := make([]domain.Invoice, 0, len(items))
+	for _, item := range items {
+		if item.Invoice != nil && !trashed[item.Invoice.ProjectID] {
+			invoices = append(invoices, *item.Invoice)
+		}
+	}
+	sortInvoices(invoices)
+	return invoices, nil
+}
+
+func (r *DynamoRepository) GetInvoiceByID(id, projectID, companyID string) (*domain.Invoice, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), invoiceSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Invoice == nil || item.Invoice.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Invoice, nil
+}
+
+func (r *DynamoRepository) UpdateInvoice(invoice *domain.Invoice) error {
+	read := invoice.Version
+	invoice.Version++
+	write, err := r.versionedPutWrite(invoiceItem(invoice), "invoice.Version", read)
+	if err == nil {
+		err = r.transactConditional(context.Background(), []types.TransactWriteItem{write})
+	}
+	if err != nil {
+		invoice.Version = read
+	}
+	return err
+}
+
+// DeleteInvoice removes the invoice and frees its number.
+func (r *DynamoRepository) DeleteInvoice(id, projectID, companyID string) error {
+	invoice, err := r.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return err
+	}
+	return r.transactDeleteAll(context.Background(), []dynamoItem{invoiceItem(invoice), invoiceNumberItem(invoice)})
+}
+
+func sortInvoices(invoices []domain.Invoice) {
+	sort.Slice(invoices, func(i, j int) bool {
+		if invoices[i].DueDate.Equal(invoices[j].DueDate) {
+			return invoices[i].Number < invoices[j].Number
+		}
+		return invoices[i].DueDate.Before(invoices[j].DueDate)
+	})
+}
+
+func invoiceItem(invoice *domain.Invoice) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(invoice.ProjectID),
+		SK:         invoiceSK(invoice.ID),
+		GSI2PK:     companyPK(invoice.CompanyID),
+		GSI2SK:     invoiceSK(invoice.ID),
+		EntityType: entityInvoice,
+		ID:         invoice.ID,
+		CompanyID:  invoice.CompanyID,
+		ClientID:   invoice.ClientID,
+		ProjectID:  invoice.ProjectID,
+		Status:     invoice.Status,
+		CreatedAt:  timeKey(invoice.CreatedAt),
+		Invoice:    invoice,
+	}
+}
+
+// invoiceNumberItem holds an invoice's number in the project partition. Its
+// key is unique per number, which is what makes the number unique.
+func invoiceNumberItem(invoice *domain.Invoice) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(invoice.ProjectID),
+		SK:         invoiceNumberSK(invoice.Number),
+		EntityType: entityInvoiceNumber,
+		ID:         invoice.ID,
+		CompanyID:  invoice.CompanyID,
+		ProjectID:  invoice.ProjectID,
+	}
+}
+
+func invoiceSK(id string) string { return "INVOICE#" + id }
+
+func invoiceNumberSK(number int) string { return fmt.Sprintf("INVOICE_NUMBER#%06d", number) }
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"gorm.io/gorm"
+)
+
+// MaterialRepository
+
+func (r *DynamoRepository) CreateMaterial(material *domain.Material) error {
+	item := dynamoItem{
+		PK:         companyPK(material.CompanyID),
+		SK:         materialSK(material.ID),
+		EntityType: entityMaterial,
+		ID:         material.ID,
+		CompanyID:  material.CompanyID,
+		CreatedAt:  timeKey(material.CreatedAt),
+		Material:   material,
+	}
+	return r.putItem(context.Background(), item)
+}
+
+func (r *DynamoRepository) GetMaterialsByCompany(companyID string) ([]domain.Material, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(materialSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	materials := make([]domain.Material, 0, len(items))
+	for _, item := range items {
+		if item.Material != nil {
+			materials = append(materials, *item.Material)
+		}
+	}
+	sort.Slice(materials, func(i, j int) bool {
+		return materials[i].Name < materials[j].Name
+	})
+	return materials, nil
+}
+
+func (r *DynamoRepository) GetMaterialByID(id, companyID string) (*domain.Material, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), materialSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Material == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Material, nil
+}
+
+func (r *DynamoRepository) UpdateMaterial(material *domain.Material) error {
+	return r.CreateMaterial(material)
+}
+
+func (r *DynamoRepository) DeleteMaterial(id, companyID string) error {
+	return r.deleteItem(context.Background(), companyPK(companyID), materialSK(id))
+}
+
+func (r *DynamoRepository) CreateStockMovements(movements []domain.StockMovement) error {
+	items := make([]dynamoItem, 0, len(movements))
+	for index := range movements {
+		movement := &movements[index]
+		items = append(items, dynamoItem{
+			PK:            projectPK(movement.ProjectID),
+			SK:            stockMovementSK(movement.CreatedAt, movement.ID),
+			EntityType:    entityStockMovement,
+			ID:            movement.ID,
+			CompanyID:     movement.CompanyID,
+			UserID:        movement.UserID,
+			ProjectID:     movement.ProjectID,
+			CreatedAt:     timeKey(movement.CreatedAt),
+			StockMovement: movement,
+		})
+	}
+	return r.transactPut(context.Background(), items)
+}
+
+func (r *DynamoRepository) GetStockMovementsByProject(projectID, companyID string) ([]domain.StockMovement, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(stockMovementSKPrefix())),
+	)
+	if err != nil {
+		return nil, err
+	}
+	movements := make([]domain.StockMovement, 0, len(items))
+	for _, item := range items {
+		if item.StockMovement != nil && item.StockMovement.CompanyID == companyID {
+			movements = append(movements, *item.StockMovement)
+		}
+	}
+	sort.Slice(movements, func(i, j int) bool {
+		if movements[i].MovementDate.Equal(movements[j].MovementDate) {
+			return movements[i].CreatedAt.After(movements[j].CreatedAt)
+		}
+		return movements[i].MovementDate.After(movements[j].MovementDate)
+	})
+	return movements, nil
+}
+
+func (r *DynamoRepository) SaveMaterialThreshold(threshold *domain.MaterialThreshold) error {
+	item := dynamoItem{
+		PK:                projectPK(threshold.ProjectID),
+		SK:                materialThresholdSK(threshold.MaterialID),
+		EntityType:        entityMaterialThreshold,
+		ID:                threshold.ID,
+		CompanyID:         threshold.CompanyID,
+		ProjectID:         threshold.ProjectID,
+		MaterialThreshold: threshold,
+	}
+	return r.putItem(context.Background(), item)
+}
+
+func (r *DynamoRepository) GetMaterialThresholdsByProject(projectID, companyID string) ([]domain.MaterialThreshold, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(materialThresholdSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	thresholds := make([]domain.MaterialThreshold, 0, len(items))
+	for _, item := range items {
+		if item.MaterialThreshold != nil && item.MaterialThreshold.CompanyID == companyID {
+			thresholds = append(thresholds, *item.MaterialThreshold)
+		}
+	}
+	return thresholds, nil
+}
+
+func (r *DynamoRepository) CreatePurchaseRequest(request *domain.PurchaseRequest) error {
+	item := dynamoItem{
+		PK:              projectPK(request.ProjectID),
+		SK:              purchaseRequestSK(request.ID),
+		EntityType:      entityPurchaseRequest,
+		ID:              request.ID,
+		CompanyID:       request.CompanyID,
+		UserID:          request.RequestedBy,
+		ProjectID:       request.ProjectID,
+		Status:          request.Status,
+		CreatedAt:       timeKey(request.CreatedAt),
+		PurchaseRequest: request,
+	}
+	return r.putItem(context.Background(), item)
+}
+
+func (r *DynamoRepository) GetPurchaseRequestsByProject(projectID, companyID string) ([]domain.PurchaseRequest, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(purchaseRequestSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]domain.PurchaseRequest, 0, len(items))
+	for _, item := range items {
+		if item.PurchaseRequest != nil && item.PurchaseRequest.CompanyID == companyID {
+			requests = append(requests, *item.PurchaseRequest)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+	return requests, nil
+}
+
+func (r *DynamoRepository) GetPurchaseRequestByID(id, projectID, companyID string) (*domain.PurchaseRequest, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), purchaseRequestSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.PurchaseRequest == nil || item.PurchaseRequest.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.PurchaseRequest, nil
+}
+
+func (r *DynamoRepository) UpdatePurchaseRequest(request *domain.PurchaseRequest) error {
+	return r.CreatePurchaseRequest(request)
+}
+
+func materialSK(id string) string          { return "MATERIAL#" + id }
+func materialThresholdSK(id string) string { return "MATERIAL_THRESHOLD#" + id }
+func purchaseRequestSK(id string) string   { return "PURCHASE#" + id }
+
+func stockMovementSKPrefix() string {
+	return "STOCK#"
+}
+
+func stockMovementSK(createdAt time.Time, id string) string {
+	return stockMovementSKPrefix() + timeKey(createdAt) + "#" + id
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// MeasurementRepository
+
+func (r *DynamoRepository) CreateContractItem(item *domain.ContractItem) error {
+	return r.putItem(context.Background(), contractItemItem(item))
+}
+
+func (r *DynamoRepository) GetContractItemsByProject(projectID, companyID string) ([]domain.ContractItem, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(contractItemSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	contractItems := make([]domain.ContractItem, 0, len(items))
+	for _, item := range items {
+		if item.ContractItem != nil && item.ContractItem.CompanyID == companyID {
+			contractItems = append(contractItems, *item.ContractItem)
+		}
+	}
+	sort.Slice(contractItems, func(i, j int) bool {
+		return contractItems[i].CreatedAt.Before(contractItems[j].CreatedAt)
+	})
+	return contractItems, nil
+}
+
+func (r *DynamoRepository) GetContractItemByID(id, projectID, companyID string) (*domain.ContractItem, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), contractItemSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.ContractItem == nil || item.ContractItem.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.ContractItem, nil
+}
+
+func (r *DynamoRepository) UpdateContractItem(item *domain.ContractItem) error {
+	return r.CreateContractItem(item)
+}
+
+func (r *DynamoRepository) DeleteContractItem(id, projectID, companyID string) error {
+	if _, err := r.GetContractItemByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), contractItemSK(id))
+}
+
+func (r *DynamoRepository) CreateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error {
+	return r.putWithTransitions(measurementItem(measurement), transitions)
+}
+
+func (r *DynamoRepository) GetMeasurementsByProject(projectID, companyID string) ([]domain.Measurement, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(measurementSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	measurements := make([]domain.Measurement, 0, len(items))
+	for _, item := range items {
+		if item.Measurement != nil && item.Measurement.CompanyID == companyID {
+			measurements = append(measurements, *item.Measurement)
+		}
+	}
+	sort.Slice(measurements, func(i, j int) bool {
+		return measurements[i].Number < measurements[j].Number
+	})
+	return measurements, nil
+}
+
+func (r *DynamoRepository) GetMeasurementByID(id, projectID, companyID string) (*domain.Measurement, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), measurementSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Measurement == nil || item.Measurement.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Measurement, nil
+}
+
+func (r *DynamoRepository) UpdateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error {
+	return r.CreateMeasurement(measurement, transitions...)
+}
+
+func (r *DynamoRepository) DecideMeasurement(measurement *domain.Measurement, previous string, transition *domain.StatusTransition) error {
+	put, err := r.conditionalPutWrite(measurementItem(measurement), expression.Name("measurement.Status").Equal(expression.Value(previous)))
+	if err != nil {
+		return err
+	}
+	history, err := r.transitionWrites([]*domain.StatusTransition{transition})
+	if err != nil {
+		return err
+	}
+	return r.transactConditional(context.Background(), append([]types.TransactWriteItem{put}, history...))
+}
+
+func (r *DynamoRepository) DeleteMeasurement(id, projectID, companyID string) error {
+	if _, err := r.GetMeasurementByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), measurementSK(id))
+}
+
+func contractItemItem(item *domain.ContractItem) dynamoItem {
+	return dynamoItem{
+		PK:           projectPK(item.ProjectID),
+		SK:           contractItemSK(item.ID),
+		EntityType:   entityContractItem,
+		ID:           item.ID,
+		CompanyID:    item.CompanyID,
+		ProjectID:    item.ProjectID,
+		CreatedAt:    timeKey(item.CreatedAt),
+		ContractItem: item,
+	}
+}
+
+func measurementItem(measurement *domain.Measurement) dynamoItem {
+	return dynamoItem{
+		PK:          projectPK(measurement.ProjectID),
+		SK:          measurementSK(measurement.ID),
+		EntityType:  entityMeasurement,
+		ID:          measurement.ID,
+		CompanyID:   measurement.CompanyID,
+		ProjectID:   measurement.ProjectID,
+		Status:      measurement.Status,
+		CreatedAt:   timeKey(measurement.CreatedAt),
+		Measurement: measurement,
+	}
+}
+
+func contractItemSK(id string) string { return "CONTRACTITEM#" + id }
+
+func measurementSK(id string) string { return "MEASUREMENT#" + id }
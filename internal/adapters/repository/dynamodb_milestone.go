@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"gorm.io/gorm"
+)
+
+// MilestoneRepository
+
+func (r *DynamoRepository) CreateMilestone(milestone *domain.Milestone) error {
+	return r.putItem(context.Background(), milestoneItem(milestone))
+}
+
+func (r *DynamoRepository) GetMilestonesByProject(projectID, companyID string) ([]domain.Milestone, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(milestoneSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	milestones := make([]domain.Milestone, 0, len(items))
+	for _, item := range items {
+		if item.Milestone != nil && item.Milestone.CompanyID == companyID {
+			milestones = append(milestones, *item.Milestone)
+		}
+	}
+	sortMilestones(milestones)
+	return milestones, nil
+}
+
+func (r *DynamoRepository) GetMilestoneByID(id, projectID, companyID string) (*domain.Milestone, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), milestoneSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Milestone == nil || item.Milestone.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Milestone, nil
+}
+
+func (r *DynamoRepository) UpdateMilestone(milestone *domain.Milestone) error {
+	return r.CreateMilestone(milestone)
+}
+
+func (r *DynamoRepository) DeleteMilestone(id, projectID, companyID string) error {
+	if _, err := r.GetMilestoneByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), milestoneSK(id))
+}
+
+func (r *DynamoRepository) CountOverdueMilestones(companyID string) (int64, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("GSI2PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("GSI2SK").BeginsWith(milestoneSK(""))),
+		withIndex("GSI2"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	trashed, err := r.trashedProjectIDs(companyID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var count int64
+	for _, item := range items {
+		if item.Milestone != nil && !trashed[item.Milestone.ProjectID] && item.Milestone.CompletedAt == nil && item.Milestone.TargetDate.Before(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func sortMilestones(milestones []domain.Milestone) {
+	sort.Slice(milestones, func(i, j int) bool {
+		if milestones[i].TargetDate.Equal(milestones[j].TargetDate) {
+			return milestones[i].CreatedAt.Before(milestones[j].CreatedAt)
+		}
+		return milestones[i].TargetDate.Before(milestones[j].TargetDate)
+	})
+}
+
+func milestoneItem(milestone *domain.Milestone) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(milestone.ProjectID),
+		SK:         milestoneSK(milestone.ID),
+		GSI2PK:     companyPK(milestone.CompanyID),
+		GSI2SK:     milestoneSK(milestone.ID),
+		EntityType: entityMilestone,
+		ID:         milestone.ID,
+		CompanyID:  milestone.CompanyID,
+		ProjectID:  milestone.ProjectID,
+		CreatedAt:  timeKey(milestone.CreatedAt),
+		Milestone:  milestone,
+	}
+}
+
+func milestoneSK(id string) string { return "MILESTONE#" + id }
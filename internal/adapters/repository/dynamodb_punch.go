@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"gorm.io/gorm"
+)
+
+// PunchListRepository
+
+func (r *DynamoRepository) CreatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error {
+	return r.putWithTransitions(punchItemItem(item), transitions)
+}
+
+func (r *DynamoRepository) GetPunchItemsByProject(projectID, companyID string) ([]domain.PunchItem, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(punchItemSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	punchItems := make([]domain.PunchItem, 0, len(items))
+	for _, item := range items {
+		if item.PunchItem != nil && item.PunchItem.CompanyID == companyID {
+			punchItems = append(punchItems, *item.PunchItem)
+		}
+	}
+	sort.Slice(punchItems, func(i, j int) bool {
+		return punchItems[i].CreatedAt.Before(punchItems[j].CreatedAt)
+	})
+	return punchItems, nil
+}
+
+func (r *DynamoRepository) GetPunchItemByID(id, projectID, companyID string) (*domain.PunchItem, error) {
+	item, err := r.getItem(context.Background(), projectPK(projectID), punchItemSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.PunchItem == nil || item.PunchItem.CompanyID != companyID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.PunchItem, nil
+}
+
+func (r *DynamoRepository) UpdatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error {
+	return r.CreatePunchItem(item, transitions...)
+}
+
+func (r *DynamoRepository) DeletePunchItem(id, projectID, companyID string) error {
+	if _, err := r.GetPunchItemByID(id, projectID, companyID); err != nil {
+		return err
+	}
+	return r.deleteItem(context.Background(), projectPK(projectID), punchItemSK(id))
+}
+
+func punchItemItem(item *domain.PunchItem) dynamoItem {
+	return dynamoItem{
+		PK:         projectPK(item.ProjectID),
+		SK:         punchItemSK(item.ID),
+		EntityType: entityPunchItem,
+		ID:         item.ID,
+		CompanyID:  item.CompanyID,
+		ProjectID:  item.ProjectID,
+		Status:     item.Status,
+		CreatedAt:  timeKey(item.CreatedAt),
+		PunchItem:  item,
+	}
+}
+
+func punchItemSK(id string) string { return "PUNCH#" + id }
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// QuoteRepository
+//
+// Quotes live under the company, with their sent versions alongside. Sent
+// quotes are also indexed by their public token on GSI1.
+
+func (r *DynamoRepository) CreateQuote(quote *domain.Quote) error {
+	return r.putItem(context.Background(), quoteItem(quote))
+}
+
+func quoteItem(quote *domain.Quote) dynamoItem {
+	item := dynamoItem{
+		PK:         companyPK(quote.CompanyID),
+		SK:         quoteSK(quote.ID),
+		EntityType: entityQuote,
+		ID:         quote.ID,
+		CompanyID:  quote.CompanyID,
+		Status:     quote.Status,
+		CreatedAt:  timeKey(quote.CreatedAt),
+		Quote:      quote,
+	}
+	if quote.PublicToken != "" {
+		item.GSI1PK = quoteTokenPK(quote.PublicToken)
+		item.GSI1SK = companyPK(quote.CompanyID)
+	}
+	return item
+}
+
+func (r *DynamoRepository) GetQuotesByCompany(companyID string) ([]domain.Quote, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(quoteSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	quotes := make([]domain.Quote, 0, len(items))
+	for _, item := range items {
+		if item.Quote != nil {
+			quotes = append(quotes, *item.Quote)
+		}
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Number > quotes[j].Number
+	})
+	return quotes, nil
+}
+
+func (r *DynamoRepository) GetQuoteByID(id, companyID string) (*domain.Quote, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), quoteSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Quote == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Quote, nil
+}
+
+func (r *DynamoRepository) GetQuoteByPublicToken(token string) (*domain.Quote, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	items, err := r.query(context.Background(),
+		expression.Key("GSI1PK").Equal(expression.Value(quoteTokenPK(token))),
+		withIndex("GSI1"),
+		withLimit(1),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 || items[0].Quote == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return items[0].Quote, nil
+}
+
+func (r *DynamoRepository) UpdateQuote(quote *domain.Quote) error {
+	return r.CreateQuote(quote)
+}
+
+// SetQuoteProject saves the quote only while the stored one still points at
+// the project previous, so that two conversions cannot both claim it.
+func (r *DynamoRepository) SetQuoteProject(quote *domain.Quote, previous string) error {
+	av, err := attributevalue.MarshalMap(quoteItem(quote))
+	if err != nil {
+		return err
+	}
+	condition := expression.Name("quote.ProjectID").Equal(expression.Value(previous))
+	if previous == "" {
+		condition = condition.Or(expression.AttributeNotExists(expression.Name("quote.ProjectID")))
+	}
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.tableName),
+		Item:                      av,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return ports.ErrVersionConflict
+	}
+	return err
+}
+
+// DeleteQuote removes the quote together with its sent versions.
+func (r *DynamoRepository) DeleteQuote(id, companyID string) error {
+	versions, err := r.GetQuoteVersions(id, companyID)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err := r.deleteItem(context.Background(), companyPK(companyID), quoteVersionSK(id, version.Version)); err != nil {
+			return err
+		}
+	}
+	return r.deleteItem(context.Background(), companyPK(companyID), quoteSK(id))
+}
+
+func (r *DynamoRepository) CreateQuoteVersion(version *domain.QuoteVersion) error {
+	return r.putItem(context.Background(), dynamoItem{
+		PK:           companyPK(version.CompanyID),
+		SK:           quoteVersionSK(version.QuoteID, version.Version),
+		EntityType:   entityQuoteVersion,
+		ID:           version.ID,
+		CompanyID:    version.CompanyID,
+		CreatedAt:    timeKey(version.CreatedAt),
+		QuoteVersion: version,
+	})
+}
+
+func (r *DynamoRepository) GetQuoteVersions(quoteID, companyID string) ([]domain.QuoteVersion, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith("QUOTEVERSION#"+quoteID+"#")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]domain.QuoteVersion, 0, len(items))
+	for _, item := range items {
+		if item.QuoteVersion != nil {
+			versions = append(versions, *item.QuoteVersion)
+		}
+	}
+	return versions, nil
+}
+
+func quoteSK(id string) string { return "QUOTE#" + id }
+
+func quoteVersionSK(quoteID string, version int) string {
+	return fmt.Sprintf("QUOTEVERSION#%s#%06d", quoteID, version)
+}
+
+func quoteTokenPK(token string) string { return "QUOTETOKEN#" + token }
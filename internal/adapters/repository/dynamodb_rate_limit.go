@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// RateLimitRepository
+//
+// Each count is an item of its own, keyed by the rate limit key. expires_at
+// is in epoch seconds, so it can also serve as the table's TTL attribute.
+
+func (r *DynamoRepository) HitRateLimit(limitKey string, expiresAt time.Time) (int, error) {
+	expr, err := expression.NewBuilder().WithUpdate(
+		expression.Add(expression.Name("hits"), expression.Value(1)).
+			Set(expression.Name("entity_type"), expression.Value(entityRateLimit)).
+			Set(expression.Name("expires_at"), expression.Value(expiresAt.Unix())),
+	).Build()
+	if err != nil {
+		return 0, err
+	}
+
+	out, err := r.client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.tableName),
+		Key:                       key(rateLimitPK(limitKey), metadataSK()),
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var updated dynamoItem
+	if err := attributevalue.UnmarshalMap(out.Attributes, &updated); err != nil {
+		return 0, err
+	}
+	return updated.Hits, nil
+}
+
+func (r *DynamoRepository) GetRateLimit(limitKey string) (int, error) {
+	item, err := r.getItem(context.Background(), rateLimitPK(limitKey), metadataSK())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return item.Hits, nil
+}
+
+func (r *DynamoRepository) PurgeRateLimits(before time.Time) (int, error) {
+	ctx := context.Background()
+	items, err := r.scanByEntity(ctx, entityRateLimit)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, item := range items {
+		if item.ExpiresAt >= before.Unix() {
+			continue
+		}
+		if err := r.deleteItem(ctx, item.PK, item.SK); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func rateLimitPK(key string) string { return "RATELIMIT#" + key }
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// StatusHistoryRepository
+
+func (r *DynamoRepository) CreateStatusTransition(transition *domain.StatusTransition) error {
+	return r.putItem(context.Background(), statusTransitionItem(transition))
+}
+
+// putWithTransitions writes item together with the status history entries
+// recording what it does, in one transaction.
+func (r *DynamoRepository) putWithTransitions(item dynamoItem, transitions []*domain.StatusTransition) error {
+	if len(transitions) == 0 {
+		return r.putItem(context.Background(), item)
+	}
+	items := []dynamoItem{item}
+	for _, transition := range transitions {
+		items = append(items, statusTransitionItem(transition))
+	}
+	return r.transactPut(context.Background(), items)
+}
+
+// transitionWrites are the puts of status history entries, for a transaction
+// that writes the records they belong to.
+func (r *DynamoRepository) transitionWrites(transitions []*domain.StatusTransition) ([]types.TransactWriteItem, error) {
+	writes := make([]types.TransactWriteItem, 0, len(transitions))
+	for _, transition := range transitions {
+		put, err := r.putWrite(statusTransitionItem(transition))
+		if err != nil {
+			return nil, err
+		}
+		writes = append(writes, put)
+	}
+	return writes, nil
+}
+
+func statusTransitionItem(transition *domain.StatusTransition) dynamoItem {
+	return dynamoItem{
+		PK:               projectPK(transition.ProjectID),
+		SK:               statusTransitionSK(transition.EntityType, transition.EntityID, transition.CreatedAt, transition.ID),
+		EntityType:       entityStatusTransition,
+		ID:               transition.ID,
+		CompanyID:        transition.CompanyID,
+		UserID:           transition.UserID,
+		ProjectID:        transition.ProjectID,
+		Status:           transition.ToStatus,
+		CreatedAt:        timeKey(transition.CreatedAt),
+		StatusTransition: transition,
+	}
+}
+
+// GetStatusTransitions relies on the sort key embedding the timestamp, so the
+// query already returns the history in chronological order.
+func (r *DynamoRepository) GetStatusTransitions(projectID, entityType, entityID, companyID string) ([]domain.StatusTransition, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(projectPK(projectID))).And(expression.Key("SK").BeginsWith(statusTransitionSKPrefix(entityType, entityID))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	transitions := make([]domain.StatusTransition, 0, len(items))
+	for _, item := range items {
+		if item.StatusTransition != nil && item.StatusTransition.CompanyID == companyID {
+			transitions = append(transitions, *item.StatusTransition)
+		}
+	}
+	return transitions, nil
+}
+
+func statusTransitionSKPrefix(entityType, entityID string) string {
+	return "STATUS#" + entityType + "#" + entityID + "#"
+}
+
+func statusTransitionSK(entityType, entityID string, createdAt time.Time, id string) string {
+	return statusTransitionSKPrefix(entityType, entityID) + timeKey(createdAt) + "#" + id
+}
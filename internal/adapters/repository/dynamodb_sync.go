@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SyncRepository
+//
+// The change feed of a company lives in its own partition, sorted by cursor.
+// The partition's METADATA item holds the company's change sequence counter.
+
+// maxSyncAttempts bounds the retries of a synced write that lost the race for
+// the sequence counter to another writer.
+const maxSyncAttempts = 5
+
+// syncBatchSize is how many records one synced transaction can write: each
+// takes a change feed entry, and the sequence counter takes one more item.
+const syncBatchSize = (maxTransactItems - 1) / 2
+
+// writeSynced applies writes together with the change feed entries they
+// produce in one transaction. The sequence counter is part of the transaction
+// and conditioned on the value read, so cursors are handed out in commit order:
+// a reader never skips a change that commits late. A failed condition on one
+// of the writes is a version conflict.
+func (r *DynamoRepository) writeSynced(ctx context.Context, companyID string, writes []types.TransactWriteItem, changes []domain.SyncChange) error {
+	for attempt := 0; attempt < maxSyncAttempts; attempt++ {
+		sequence, err := r.syncSequence(ctx, companyID)
+		if err != nil {
+			return err
+		}
+
+		transact := make([]types.TransactWriteItem, 0, len(writes)+len(changes)+1)
+		transact = append(transact, writes...)
+		for i := range changes {
+			change := &changes[i]
+			change.Cursor = syncCursor(sequence + int64(i) + 1)
+			put, err := r.putWrite(dynamoItem{
+				PK:         syncPK(change.CompanyID),
+				SK:         change.Cursor,
+				EntityType: entitySyncChange,
+				ID:         change.ID,
+				CompanyID:  change.CompanyID,
+				ProjectID:  change.ProjectID,
+				CreatedAt:  timeKey(change.ChangedAt),
+				SyncChange: change,
+			})
+			if err != nil {
+				return err
+			}
+			transact = append(transact, put)
+		}
+		counter, err := r.sequenceWrite(companyID, sequence, sequence+int64(len(changes)))
+		if err != nil {
+			return err
+		}
+		transact = append(transact, counter)
+		if len(transact) > maxTransactItems {
+			return fmt.Errorf("transaction exceeds %d items", maxTransactItems)
+		}
+
+		_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: transact,
+		})
+		if err == nil {
+			return nil
+		}
+		var canceled *types.TransactionCanceledException
+		if !errors.As(err, &canceled) {
+			return err
+		}
+		counterMoved := false
+		for index, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+				continue
+			}
+			if index < len(writes) {
+				return ports.ErrVersionConflict
+			}
+			counterMoved = true
+		}
+		if !counterMoved {
+			return err
+		}
+	}
+	return fmt.Errorf("sync sequence is busy")
+}
+
+func (r *DynamoRepository) syncSequence(ctx context.Context, companyID string) (int64, error) {
+	out, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            key(syncPK(companyID), metadataSK()),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.Item) == 0 {
+		return 0, nil
+	}
+	var item dynamoItem
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return 0, err
+	}
+	return item.Sequence, nil
+}
+
+// sequenceWrite moves the company's counter from read to next, failing when
+// another writer moved it first.
+func (r *DynamoRepository) sequenceWrite(companyID string, read, next int64) (types.TransactWriteItem, error) {
+	condition := expression.Name("sequence").Equal(expression.Value(read))
+	if read == 0 {
+		condition = expression.AttributeNotExists(expression.Name("sequence"))
+	}
+	expr, err := expression.NewBuilder().
+		WithUpdate(expression.Set(expression.Name("sequence"), expression.Value(next))).
+		WithCondition(condition).
+		Build()
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 aws.String(r.tableName),
+			Key:                       key(syncPK(companyID), metadataSK()),
+			UpdateExpression:          expr.Update(),
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		},
+	}, nil
+}
+
+func (r *DynamoRepository) putWrite(item dynamoItem) (types.TransactWriteItem, error) {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item:      av,
+		},
+	}, nil
+}
+
+// versionedPutWrite overwrites item only while the record stored at its key is
+// still at version read. versionPath names the record's version attribute,
+// such as "task.Version"; records written before versions existed have none.
+func (r *DynamoRepository) versionedPutWrite(item dynamoItem, versionPath string, read int) (types.TransactWriteItem, error) {
+	return r.conditionalPutWrite(item, versionCondition(versionPath, read))
+}
+
+// conditionalPutWrite overwrites item only while condition holds for the
+// record stored at its key.
+func (r *DynamoRepository) conditionalPutWrite(item dynamoItem, condition expression.ConditionBuilder) (types.TransactWriteItem, error) {
+	write, err := r.putWrite(item)
+	if err != nil {
+		return write, err
+	}
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return write, err
+	}
+	write.Put.ConditionExpression = expr.Condition()
+	write.Put.ExpressionAttributeNames = expr.Names()
+	write.Put.ExpressionAttributeValues = expr.Values()
+	return write, nil
+}
+
+func (r *DynamoRepository) deleteWrite(pk, sk string) types.TransactWriteItem {
+	return types.TransactWriteItem{
+		Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key:       key(pk, sk),
+		},
+	}
+}
+
+// versionedDeleteWrite deletes the record at pk/sk only while it is still at
+// version read.
+func (r *DynamoRepository) versionedDeleteWrite(pk, sk, versionPath string, read int) (types.TransactWriteItem, error) {
+	write := r.deleteWrite(pk, sk)
+	expr, err := expression.NewBuilder().WithCondition(versionCondition(versionPath, read)).Build()
+	if err != nil {
+		return write, err
+	}
+	write.Delete.ConditionExpression = expr.Condition()
+	write.Delete.ExpressionAttributeNames = expr.Names()
+	write.Delete.ExpressionAttributeValues = expr.Values()
+	return write, nil
+}
+
+func versionCondition(versionPath string, read int) expression.ConditionBuilder {
+	condition := expression.Name(versionPath).Equal(expression.Value(read))
+	if read == 0 {
+		condition = condition.Or(expression.AttributeNotExists(expression.Name(versionPath)))
+	}
+	return condition
+}
+
+func (r *DynamoRepository) GetSyncChanges(companyID, cursor string, limit int) ([]domain.SyncChange, error) {
+	ctx := context.Background()
+	expr, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("PK").Equal(expression.Value(syncPK(companyID))).And(expression.Key("SK").GreaterThan(expression.Value(cursor))),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(int32(limit)),
+	}
+
+	changes := make([]domain.SyncChange, 0, limit)
+	for len(changes) < limit {
+		out, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		var items []dynamoItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.SyncChange != nil && len(changes) < limit {
+				changes = append(changes, *item.SyncChange)
+			}
+		}
+		if out.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+	return changes, nil
+}
+
+func (r *DynamoRepository) GetLatestSyncCursor(companyID string) (string, error) {
+	ctx := context.Background()
+	sequence, err := r.syncSequence(ctx, companyID)
+	if err != nil {
+		return "", err
+	}
+	if sequence > 0 {
+		return syncCursor(sequence), nil
+	}
+
+	// Companies whose feed predates the counter.
+	expr, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("PK").Equal(expression.Value(syncPK(companyID))),
+	).Build()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(1),
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.Items) == 0 {
+		return "", nil
+	}
+	var item dynamoItem
+	if err := attributevalue.UnmarshalMap(out.Items[0], &item); err != nil {
+		return "", err
+	}
+	return item.SK, nil
+}
+
+func syncPK(companyID string) string { return "SYNC#" + companyID }
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// TemplateRepository
+
+func (r *DynamoRepository) CreateTemplate(template *domain.ProjectTemplate) error {
+	item := dynamoItem{
+		PK:              companyPK(template.CompanyID),
+		SK:              templateSK(template.ID),
+		EntityType:      entityProjectTemplate,
+		ID:              template.ID,
+		CompanyID:       template.CompanyID,
+		CreatedAt:       timeKey(template.CreatedAt),
+		ProjectTemplate: template,
+	}
+	return r.putItem(context.Background(), item)
+}
+
+func (r *DynamoRepository) GetTemplatesByCompany(companyID string) ([]domain.ProjectTemplate, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(templateSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]domain.ProjectTemplate, 0, len(items))
+	for _, item := range items {
+		if item.ProjectTemplate != nil {
+			templates = append(templates, *item.ProjectTemplate)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *DynamoRepository) GetTemplateByID(id, companyID string) (*domain.ProjectTemplate, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), templateSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.ProjectTemplate == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.ProjectTemplate, nil
+}
+
+func (r *DynamoRepository) UpdateTemplate(template *domain.ProjectTemplate) error {
+	return r.CreateTemplate(template)
+}
+
+func (r *DynamoRepository) DeleteTemplate(id, companyID string) error {
+	return r.deleteItem(context.Background(), companyPK(companyID), templateSK(id))
+}
+
+func (r *DynamoRepository) CreateDiaryTemplate(template *domain.DiaryTemplate) error {
+	return r.putItem(context.Background(), dynamoItem{
+		PK:            companyPK(template.CompanyID),
+		SK:            diaryTemplateSK(template.ID),
+		EntityType:    entityDiaryTemplate,
+		ID:            template.ID,
+		CompanyID:     template.CompanyID,
+		CreatedAt:     timeKey(template.CreatedAt),
+		DiaryTemplate: template,
+	})
+}
+
+func (r *DynamoRepository) GetDiaryTemplatesByCompany(companyID string) ([]domain.DiaryTemplate, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(diaryTemplateSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]domain.DiaryTemplate, 0, len(items))
+	for _, item := range items {
+		if item.DiaryTemplate != nil {
+			templates = append(templates, *item.DiaryTemplate)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *DynamoRepository) GetDiaryTemplateByID(id, companyID string) (*domain.DiaryTemplate, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), diaryTemplateSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.DiaryTemplate == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.DiaryTemplate, nil
+}
+
+func (r *DynamoRepository) UpdateDiaryTemplate(template *domain.DiaryTemplate) error {
+	return r.CreateDiaryTemplate(template)
+}
+
+func (r *DynamoRepository) DeleteDiaryTemplate(id, companyID string) error {
+	return r.deleteItem(context.Background(), companyPK(companyID), diaryTemplateSK(id))
+}
+
+func (r *DynamoRepository) CreateInspectionTemplate(template *domain.InspectionTemplate) error {
+	return r.putItem(context.Background(), dynamoItem{
+		PK:                 companyPK(template.CompanyID),
+		SK:                 inspectionTemplateSK(template.ID),
+		EntityType:         entityInspectionTemplate,
+		ID:                 template.ID,
+		CompanyID:          template.CompanyID,
+		CreatedAt:          timeKey(template.CreatedAt),
+		InspectionTemplate: template,
+	})
+}
+
+func (r *DynamoRepository) GetInspectionTemplatesByCompany(companyID string) ([]domain.InspectionTemplate, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(inspectionTemplateSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]domain.InspectionTemplate, 0, len(items))
+	for _, item := range items {
+		if item.InspectionTemplate != nil {
+			templates = append(templates, *item.InspectionTemplate)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *DynamoRepository) GetInspectionTemplateByID(id, companyID string) (*domain.InspectionTemplate, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), inspectionTemplateSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.InspectionTemplate == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.InspectionTemplate, nil
+}
+
+func (r *DynamoRepository) UpdateInspectionTemplate(template *domain.InspectionTemplate) error {
+	return r.CreateInspectionTemplate(template)
+}
+
+func (r *DynamoRepository) DeleteInspectionTemplate(id, companyID string) error {
+	return r.deleteItem(context.Background(), companyPK(companyID), inspectionTemplateSK(id))
+}
+
+// CreateProjectBundle writes the project and all its children. Subtasks are
+// stored as their own items, so they are not embedded in the task items. The
+// project item goes last, together with its status history and change feed
+// entry; if it cannot be written, the children are removed again.
+func (r *DynamoRepository) CreateProjectBundle(bundle *domain.ProjectBundle) error {
+	var items []dynamoItem
+	for index := range bundle.Milestones {
+		items = append(items, milestoneItem(&bundle.Milestones[index]))
+	}
+	for index := range bundle.BudgetLines {
+		items = append(items, budgetLineItem(&bundle.BudgetLines[index]))
+	}
+	for index := range bundle.Tasks {
+		startVersions(&bundle.Tasks[index])
+		task := bundle.Tasks[index]
+		for subtaskIndex := range task.Subtasks {
+			items = append(items, subtaskItem(&task.Subtasks[subtaskIndex]))
+		}
+		task.Subtasks = nil
+		items = append(items, taskItem(&task))
+	}
+	ctx := context.Background()
+	if err := r.transactPutAll(ctx, items); err != nil {
+		return err
+	}
+	put, err := r.putWrite(projectItem(bundle.Project))
+	if err != nil {
+		return err
+	}
+	writes := []types.TransactWriteItem{put}
+	for index := range bundle.Transitions {
+		put, err := r.putWrite(statusTransitionItem(&bundle.Transitions[index]))
+		if err != nil {
+			return err
+		}
+		writes = append(writes, put)
+	}
+	if err := r.writeSynced(ctx, bundle.Project.CompanyID, writes,
+		[]domain.SyncChange{projectChange(bundle.Project.ID, bundle.Project.CompanyID, false)},
+	); err != nil {
+		// Best effort: without the project item the children are unreachable,
+		// and the original error is what the caller needs.
+		_ = r.transactDeleteAll(ctx, items)
+		return err
+	}
+	return nil
+}
+
+func templateSK(id string) string { return "TEMPLATE#" + id }
+
+func diaryTemplateSK(id string) string { return "DIARYTEMPLATE#" + id }
+
+func inspectionTemplateSK(id string) string { return "INSPECTIONTEMPLATE#" + id }
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func seedDynamoProject(t *testing.T, repo *DynamoRepository, fixture *projectFixture) {
+	t.Helper()
+	ctx := context.Background()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("seed project: %v", err)
+		}
+	}
+
+	must(repo.CreateProject(fixture.Project))
+	for i := range fixture.Tasks {
+		task := fixture.Tasks[i]
+		for j := range task.Subtasks {
+			must(repo.putItem(ctx, subtaskItem(&task.Subtasks[j])))
+		}
+		task.Subtasks = nil
+		must(repo.putItem(ctx, taskItem(&task)))
+	}
+	must(repo.putItem(ctx, diaryEntryItem(&fixture.DiaryEntry)))
+	must(repo.CreateDiaryRevision(&fixture.Revision))
+	must(repo.ReplaceBudgetLines(fixture.Project.ID, fixture.Project.CompanyID, []domain.BudgetLine{fixture.BudgetLine}))
+	must(repo.CreateExpense(&fixture.Expense))
+	must(repo.CreateMilestone(&fixture.Milestone))
+	must(repo.CreateStatusTransition(&fixture.Transition))
+	must(repo.CreateStockMovements([]domain.StockMovement{fixture.Movement}))
+	must(repo.SaveMaterialThreshold(&fixture.Threshold))
+	must(repo.CreatePurchaseRequest(&fixture.Request))
+	must(repo.CreatePunchItem(&fixture.PunchItem))
+	must(repo.CreateInspection(&fixture.Inspection))
+	must(repo.CreateChangeOrder(&fixture.ChangeOrder))
+	must(repo.putItem(ctx, invoiceItem(&fixture.Invoice)))
+	must(repo.putItem(ctx, invoiceNumberItem(&fixture.Invoice)))
+	must(repo.CreateContractItem(&fixture.ContractItem))
+	must(repo.CreateMeasurement(&fixture.Measurement))
+	must(repo.CreateAttachment(&fixture.Attachment))
+}
+
+func assertDynamoProjectGone(t *testing.T, repo *DynamoRepository, fake *fakeDynamo, fixture *projectFixture) {
+	t.Helper()
+	if _, err := repo.projectItemByID(fixture.Project.ID, fixture.Project.CompanyID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("project item still readable, err = %v", err)
+	}
+	if count := fake.countPartition(projectPK(fixture.Project.ID)); count != 0 {
+		t.Fatalf("%d items left in the project partition", count)
+	}
+	for _, taskID := range fixture.taskIDs() {
+		if count := fake.countPartition(taskPK(taskID)); count != 0 {
+			t.Fatalf("%d subtasks left for task %s", count, taskID)
+		}
+	}
+}
+
+func TestDynamoDeleteProjectRemovesEveryChild(t *testing.T) {
+	repo, fake := newFakeDynamoRepository()
+	companyID := uuid.New().String()
+
+	// Enough tasks that the delete spans several transactions.
+	fixture := newProjectFixture(companyID, "", 60)
+	other := newProjectFixture(companyID, "", 2)
+	seedDynamoProject(t, repo, fixture)
+	seedDynamoProject(t, repo, other)
+	otherItems := fake.countPartition(projectPK(other.Project.ID))
+
+	if err := repo.DeleteProject(fixture.Project.ID, companyID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+
+	assertDynamoProjectGone(t, repo, fake, fixture)
+	if fake.transactions < 2 {
+		t.Fatalf("delete ran in %d transaction(s), want the chunked path", fake.transactions)
+	}
+	if count := fake.countPartition(projectPK(other.Project.ID)); count != otherItems {
+		t.Fatalf("other project has %d items, want %d", count, otherItems)
+	}
+	if _, err := repo.projectItemByID(other.Project.ID, companyID); err != nil {
+		t.Fatalf("other project: %v", err)
+	}
+	for _, taskID := range other.taskIDs() {
+		if count := fake.countPartition(taskPK(taskID)); count != 1 {
+			t.Fatalf("other project's task %s has %d subtasks, want 1", taskID, count)
+		}
+	}
+}
+
+func TestDynamoDeleteProjectKeepsProjectWhenAChunkFails(t *testing.T) {
+	repo, fake := newFakeDynamoRepository()
+	companyID := uuid.New().String()
+	fixture := newProjectFixture(companyID, "", 60)
+	seedDynamoProject(t, repo, fixture)
+
+	fake.failTransaction = fake.calls + 2
+	if err := repo.DeleteProject(fixture.Project.ID, companyID); err == nil {
+		t.Fatal("DeleteProject succeeded with a failing transaction")
+	}
+	if _, err := repo.projectItemByID(fixture.Project.ID, companyID); err != nil {
+		t.Fatalf("project item gone after a partial delete: %v", err)
+	}
+
+	fake.failTransaction = 0
+	if err := repo.DeleteProject(fixture.Project.ID, companyID); err != nil {
+		t.Fatalf("retrying DeleteProject: %v", err)
+	}
+	assertDynamoProjectGone(t, repo, fake, fixture)
+}
+
+func TestDynamoTransactDeleteAllChunksTransactions(t *testing.T) {
+	repo, fake := newFakeDynamoRepository()
+	ctx := context.Background()
+	items := make([]dynamoItem, 0, 2*maxTransactItems+1)
+	for i := 0; i < cap(items); i++ {
+		item := dynamoItem{PK: projectPK("chunked"), SK: expenseSK(uuid.New().String()), EntityType: entityExpense}
+		if err := repo.putItem(ctx, item); err != nil {
+			t.Fatalf("putItem: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := repo.transactDeleteAll(ctx, items); err != nil {
+		t.Fatalf("transactDeleteAll: %v", err)
+	}
+	if fake.transactions != 3 {
+		t.Fatalf("ran %d transactions, want 3", fake.transactions)
+	}
+	if count := fake.countPartition(projectPK("chunked")); count != 0 {
+		t.Fatalf("%d items left", count)
+	}
+}
+
+func TestDynamoAddTaskRejectsAnIDInUse(t *testing.T) {
+	repo, fake := newFakeDynamoRepository()
+	owner := newProjectFixture(uuid.New().String(), "", 1)
+	seedDynamoProject(t, repo, owner)
+
+	other := newProjectFixture(uuid.New().String(), "", 0)
+	task := owner.Tasks[0]
+	task.ProjectID = other.Project.ID
+	task.CompanyID = other.Project.CompanyID
+	task.Subtasks = nil
+	if err := repo.AddTask(&task); !errors.Is(err, ports.ErrVersionConflict) {
+		t.Fatalf("AddTask with another company's task ID: err = %v, want a version conflict", err)
+	}
+	if count := fake.countPartition(projectPK(other.Project.ID)); count != 0 {
+		t.Fatalf("%d items written for the duplicate task", count)
+	}
+	if _, err := repo.taskItemByID(task.ID, owner.Project.CompanyID); err != nil {
+		t.Fatalf("owner's task: %v", err)
+	}
+}
+
+func TestDynamoReplaceBudgetLinesLeavesOnlyTheNewBudget(t *testing.T) {
+	repo, _ := newFakeDynamoRepository()
+	projectID, companyID := uuid.New().String(), uuid.New().String()
+	budget := func(size int) []domain.BudgetLine {
+		lines := make([]domain.BudgetLine, size)
+		for index := range lines {
+			lines[index] = domain.BudgetLine{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID, Category: domain.CostCategoryLabor}
+		}
+		return lines
+	}
+
+	// 10 + 20 lines fit in one transaction; 20 + 90 are written in chunks.
+	for _, size := range []int{10, 20, 90} {
+		lines := budget(size)
+		if err := repo.ReplaceBudgetLines(projectID, companyID, lines); err != nil {
+			t.Fatalf("replace with %d lines: %v", size, err)
+		}
+		stored, err := repo.GetBudgetLines(projectID, companyID)
+		if err != nil {
+			t.Fatalf("read budget: %v", err)
+		}
+		want := make(map[string]bool, size)
+		for _, line := range lines {
+			want[line.ID] = true
+		}
+		if len(stored) != size {
+			t.Fatalf("%d lines stored, want %d", len(stored), size)
+		}
+		for _, line := range stored {
+			if !want[line.ID] {
+				t.Fatalf("line %s of an earlier budget is still stored", line.ID)
+			}
+		}
+	}
+}
+
+func TestDynamoDeleteClientRefusesWhileQuotedOrInvoiced(t *testing.T) {
+	repo, _ := newFakeDynamoRepository()
+	companyID := uuid.New().String()
+	newClient := func() *domain.Client {
+		client := &domain.Client{ID: uuid.New().String(), CompanyID: companyID}
+		if err := repo.CreateClient(client); err != nil {
+			t.Fatalf("create client: %v", err)
+		}
+		return client
+	}
+
+	quoted := newClient()
+	if err := repo.CreateQuote(&domain.Quote{ID: uuid.New().String(), CompanyID: companyID, ClientID: quoted.ID}); err != nil {
+		t.Fatalf("create quote: %v", err)
+	}
+	invoiced := newClient()
+	invoice := domain.Invoice{ID: uuid.New().String(), ProjectID: uuid.New().String(), CompanyID: companyID, ClientID: invoiced.ID}
+	if err := repo.putItem(context.Background(), invoiceItem(&invoice)); err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+	unused := newClient()
+
+	for name, client := range map[string]*domain.Client{"quoted": quoted, "invoiced": invoiced} {
+		if err := repo.DeleteClient(client.ID, companyID); !errors.Is(err, ports.ErrClientInUse) {
+			t.Fatalf("delete %s client: err = %v, want ErrClientInUse", name, err)
+		}
+	}
+	if err := repo.DeleteClient(unused.ID, companyID); err != nil {
+		t.Fatalf("delete unused client: %v", err)
+	}
+	if _, err := repo.clientItemByID(unused.ID, companyID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("unused client still readable, err = %v", err)
+	}
+}
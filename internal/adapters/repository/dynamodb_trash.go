@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm"
+)
+
+// TrashRepository
+
+// TrashProject and RestoreProject also tell offline clients that the
+// project's tasks, subtasks and diary entries went away or came back.
+func (r *DynamoRepository) TrashProject(id, companyID string, deletedAt time.Time) error {
+	project, err := r.projectItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if project.DeletedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	project.DeletedAt = &deletedAt
+	return r.saveProjectSynced(project, true)
+}
+
+func (r *DynamoRepository) RestoreProject(id, companyID string) error {
+	project, err := r.projectItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if project.DeletedAt == nil {
+		return gorm.ErrRecordNotFound
+	}
+	project.DeletedAt = nil
+	return r.saveProjectSynced(project, false)
+}
+
+func (r *DynamoRepository) saveProjectSynced(project *domain.Project, deleted bool) error {
+	project.UpdatedAt = time.Now()
+	put, err := r.putWrite(projectItem(project))
+	if err != nil {
+		return err
+	}
+	return r.writeSynced(context.Background(), project.CompanyID,
+		[]types.TransactWriteItem{put},
+		[]domain.SyncChange{projectChange(project.ID, project.CompanyID, deleted)},
+	)
+}
+
+func (r *DynamoRepository) TrashClient(id, companyID string, deletedAt time.Time) error {
+	client, err := r.clientItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if client.DeletedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	client.DeletedAt = &deletedAt
+	return r.UpdateClient(client)
+}
+
+func (r *DynamoRepository) RestoreClient(id, companyID string) error {
+	client, err := r.clientItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if client.DeletedAt == nil {
+		return gorm.ErrRecordNotFound
+	}
+	client.DeletedAt = nil
+	return r.UpdateClient(client)
+}
+
+func (r *DynamoRepository) TrashLink(id, companyID string, deletedAt time.Time) error {
+	link, err := r.linkItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if link.DeletedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	link.DeletedAt = &deletedAt
+	return r.UpdateLink(link)
+}
+
+func (r *DynamoRepository) RestoreLink(id, companyID string) error {
+	link, err := r.linkItemByID(id, companyID)
+	if err != nil {
+		return err
+	}
+	if link.DeletedAt == nil {
+		return gorm.ErrRecordNotFound
+	}
+	link.DeletedAt = nil
+	return r.UpdateLink(link)
+}
+
+func (r *DynamoRepository) GetTrash(companyID string) (*domain.Trash, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTrash(items, func(deletedAt *time.Time) bool {
+		return deletedAt != nil
+	}), nil
+}
+
+func (r *DynamoRepository) GetTrashBefore(cutoff time.Time) (*domain.Trash, error) {
+	var items []dynamoItem
+	for _, entityType := range []string{entityProject, entityClient, entityLink} {
+		entityItems, err := r.scanByEntity(context.Background(), entityType)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, entityItems...)
+	}
+	return collectTrash(items, func(deletedAt *time.Time) bool {
+		return deletedAt != nil && deletedAt.Before(cutoff)
+	}), nil
+}
+
+// projectItemByID, clientItemByID and linkItemByID read the stored record
+// whether or not it is in the trash.
+func (r *DynamoRepository) projectItemByID(id, companyID string) (*domain.Project, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), projectSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Project == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Project, nil
+}
+
+func (r *DynamoRepository) clientItemByID(id, companyID string) (*domain.Client, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), clientSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Client == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Client, nil
+}
+
+func (r *DynamoRepository) linkItemByID(id, companyID string) (*domain.Link, error) {
+	item, err := r.getItem(context.Background(), companyPK(companyID), linkSK(id))
+	if err != nil {
+		return nil, err
+	}
+	if item.Link == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return item.Link, nil
+}
+
+// trashedProjectIDs lists the trashed projects of a company, so company-wide
+// queries over project children can skip them.
+func (r *DynamoRepository) trashedProjectIDs(companyID string) (map[string]bool, error) {
+	items, err := r.query(context.Background(),
+		expression.Key("PK").Equal(expression.Value(companyPK(companyID))).And(expression.Key("SK").BeginsWith(projectSK(""))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	trashed := make(map[string]bool)
+	for _, item := range items {
+		if item.Project != nil && item.Project.DeletedAt != nil {
+			trashed[item.Project.ID] = true
+		}
+	}
+	return trashed, nil
+}
+
+func collectTrash(items []dynamoItem, include func(deletedAt *time.Time) bool) *domain.Trash {
+	trash := &domain.Trash{
+		Projects: []domain.Project{},
+		Clients:  []domain.Client{},
+		Links:    []domain.Link{},
+	}
+	for _, item := range items {
+		switch {
+		case item.Project != nil && include(item.Project.DeletedAt):
+			trash.Projects = append(trash.Projects, *item.Project)
+		case item.Client != nil && include(item.Client.DeletedAt):
+			trash.Clients = append(trash.Clients, *item.Client)
+		case item.Link != nil && include(item.Link.DeletedAt):
+			trash.Links = append(trash.Links, *item.Link)
+		}
+	}
+	sort.Slice(trash.Projects, func(i, j int) bool {
+		return trash.Projects[i].DeletedAt.After(*trash.Projects[j].DeletedAt)
+	})
+	sort.Slice(trash.Clients, func(i, j int) bool {
+		return trash.Clients[i].DeletedAt.After(*trash.Clients[j].DeletedAt)
+	})
+	sort.Slice(trash.Links, func(i, j int) bool {
+		return trash.Links[i].DeletedAt.After(*trash.Links[j].DeletedAt)
+	})
+	return trash
+}
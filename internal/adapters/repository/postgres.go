@@ -2,11 +2,13 @@ package repository
 
 import (
 	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
 	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -82,16 +84,21 @@ func (r *PostgresRepository) GetPublicProfile(username string) (*domain.PublicPr
 	}
 
 	return &domain.PublicProfile{
-		ID:        user.ID,
-		Username:  user.Username,
-		Name:      user.Name,
-		Links:     links,
-		Bio:       user.Bio,
-		Avatar:    user.Avatar,
-		CompanyID: user.CompanyID,
+		ID:               user.ID,
+		Username:         user.Username,
+		Name:             user.Name,
+		Links:            links,
+		Bio:              user.Bio,
+		Avatar:           user.Avatar,
+		AvatarThumbnails: user.AvatarThumbnails,
+		CompanyID:        user.CompanyID,
 	}, nil
 }
 
+func (r *PostgresRepository) UpdateAvatar(user *domain.User) error {
+	return r.db.Model(user).Select("Avatar", "AvatarThumbnails", "AvatarKey").Updates(user).Error
+}
+
 func (r *PostgresRepository) UpdateProfile(user *domain.User) error {
 	return r.db.Model(user).Select("Name", "Email", "Phone").Updates(user).Error
 }
@@ -110,13 +117,41 @@ func (r *PostgresRepository) ListUsersByCompanyID(companyID string) ([]domain.Us
 
 // ProjectRepository Implementation
 
-func (r *PostgresRepository) CreateProject(project *domain.Project) error {
-	return r.db.Create(project).Error
+func (r *PostgresRepository) CreateProject(project *domain.Project, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Create(project).Error
+	})
+}
+
+// withProjectAssociations preloads everything returned alongside a project.
+func (r *PostgresRepository) withProjectAssociations() *gorm.DB {
+	return r.db.
+		Preload("Tasks.Subtasks.Assignees").
+		Preload("Tasks.Assignees", taskLevelAssignees).
+		Preload("Tasks.Dependencies").
+		Preload("Milestones", func(db *gorm.DB) *gorm.DB {
+			return db.Order("target_date ASC")
+		}).
+		Preload("Client", "deleted_at IS NULL")
+}
+
+// taskLevelAssignees keeps subtask assignments out of Task.Assignees, since
+// both share the task_id column.
+func taskLevelAssignees(db *gorm.DB) *gorm.DB {
+	return db.Where("subtask_id = ?", "")
+}
+
+// withTaskAssociations preloads everything returned alongside a task.
+func (r *PostgresRepository) withTaskAssociations() *gorm.DB {
+	return r.db.
+		Preload("Subtasks.Assignees").
+		Preload("Assignees", taskLevelAssignees).
+		Preload("Dependencies")
 }
 
 func (r *PostgresRepository) GetAllProjects(companyID string) ([]domain.Project, error) {
 	var projects []domain.Project
-	if err := r.db.Preload("Tasks.Subtasks").Preload("Client").Where("company_id = ?", companyID).Find(&projects).Error; err != nil {
+	if err := r.withProjectAssociations().Where("company_id = ? AND deleted_at IS NULL", companyID).Find(&projects).Error; err != nil {
 		return nil, err
 	}
 	return projects, nil
@@ -124,7 +159,7 @@ func (r *PostgresRepository) GetAllProjects(companyID string) ([]domain.Project,
 
 func (r *PostgresRepository) GetProjectsByClientID(clientID, companyID string) ([]domain.Project, error) {
 	var projects []domain.Project
-	if err := r.db.Preload("Tasks.Subtasks").Preload("Client").Where("client_id = ? AND company_id = ?", clientID, companyID).Find(&projects).Error; err != nil {
+	if err := r.withProjectAssociations().Where("client_id = ? AND company_id = ? AND deleted_at IS NULL", clientID, companyID).Find(&projects).Error; err != nil {
 		return nil, err
 	}
 	return projects, nil
@@ -132,7 +167,7 @@ func (r *PostgresRepository) GetProjectsByClientID(clientID, companyID string) (
 
 func (r *PostgresRepository) GetProjectByID(id, companyID string) (*domain.Project, error) {
 	var project domain.Project
-	if err := r.db.Preload("Tasks.Subtasks").Preload("Client").Where("id = ? AND company_id = ?", id, companyID).First(&project).Error; err != nil {
+	if err := r.withProjectAssociations().Where("id = ? AND company_id = ? AND deleted_at IS NULL", id, companyID).First(&project).Error; err != nil {
 		return nil, err
 	}
 	return &project, nil
@@ -140,47 +175,244 @@ func (r *PostgresRepository) GetProjectByID(id, companyID string) (*domain.Proje
 
 func (r *PostgresRepository) GetPublicProjectByID(id string) (*domain.Project, error) {
 	var project domain.Project
-	if err := r.db.Preload("Tasks.Subtasks").Preload("Client").Where("id = ? AND is_public = true", id).First(&project).Error; err != nil {
+	if err := r.withProjectAssociations().Where("id = ? AND is_public = true AND deleted_at IS NULL", id).First(&project).Error; err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
-func (r *PostgresRepository) UpdateProject(project *domain.Project) error {
-	return r.db.Where("id = ? AND company_id = ?", project.ID, project.CompanyID).Save(project).Error
+func (r *PostgresRepository) UpdateProject(project *domain.Project, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Where("id = ? AND company_id = ?", project.ID, project.CompanyID).Save(project).Error
+	})
+}
+
+func (r *PostgresRepository) UpdateProjectProgress(id, companyID string, progress float64) error {
+	return r.db.Model(&domain.Project{}).Where("id = ? AND company_id = ?", id, companyID).UpdateColumn("progress", progress).Error
 }
 
+// DeleteProject permanently removes a project and everything that belongs to
+// it. Children are deleted explicitly so databases migrated before the cascade
+// constraints existed are cleaned up as well.
 func (r *PostgresRepository) DeleteProject(id, companyID string) error {
-	return r.db.Delete(&domain.Project{}, "id = ? AND company_id = ?", id, companyID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var project domain.Project
+		if err := tx.Select("id").Where("id = ? AND company_id = ?", id, companyID).First(&project).Error; err != nil {
+			return err
+		}
+		taskIDs := tx.Model(&domain.Task{}).Select("id").Where("project_id = ?", id)
+		entryIDs := tx.Model(&domain.DiaryEntry{}).Select("id").Where("project_id = ?", id)
+		if err := tx.Where("project_id = ?", id).Delete(&domain.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&domain.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN (?)", taskIDs).Delete(&domain.Subtask{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&domain.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("diary_entry_id IN (?)", entryIDs).Delete(&domain.DiaryItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&domain.DiaryEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&domain.Milestone{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&domain.StatusTransition{},
+			&domain.DiaryEntryRevision{},
+			&domain.BudgetLine{},
+			&domain.Expense{},
+			&domain.StockMovement{},
+			&domain.MaterialThreshold{},
+			&domain.PurchaseRequest{},
+			&domain.PunchItem{},
+			&domain.Inspection{},
+			&domain.ChangeOrder{},
+			&domain.Invoice{},
+			&domain.ContractItem{},
+			&domain.Measurement{},
+			&domain.Attachment{},
+		} {
+			if err := tx.Where("project_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&domain.Project{}, "id = ? AND company_id = ?", id, companyID).Error
+	})
 }
 
-func (r *PostgresRepository) AddTask(task *domain.Task) error {
-	return r.db.Create(task).Error
+func (r *PostgresRepository) AddTask(task *domain.Task, transitions ...*domain.StatusTransition) error {
+	startVersions(task)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		if err := createTransitions(tx, transitions); err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, taskChanges(task, false)...)
+	})
 }
 
 func (r *PostgresRepository) AddSubtask(subtask *domain.Subtask) error {
-	return r.db.Create(subtask).Error
+	subtask.Version = 1
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		projectID, err := taskProjectID(tx, subtask.TaskID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(subtask).Error; err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, subtaskChange(subtask, projectID, false))
+	})
 }
 
-func (r *PostgresRepository) UpdateTask(task *domain.Task) error {
-	return r.db.Where("company_id = ?", task.CompanyID).Save(task).Error
+func (r *PostgresRepository) UpdateTask(task *domain.Task, transitions ...*domain.StatusTransition) error {
+	read := task.Version
+	task.Version++
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(task).Omit(clause.Associations).
+			Where("company_id = ? AND version = ?", task.CompanyID, read).
+			Select("*").Updates(task)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ports.ErrVersionConflict
+		}
+		if err := tx.Where("task_id = ?", task.ID).Delete(&domain.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		if len(task.Dependencies) > 0 {
+			if err := tx.Create(&task.Dependencies).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("task_id = ? AND subtask_id = ?", task.ID, "").Delete(&domain.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if len(task.Assignees) > 0 {
+			if err := tx.Create(&task.Assignees).Error; err != nil {
+				return err
+			}
+		}
+		if err := createTransitions(tx, transitions); err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, syncChange(task.CompanyID, task.ProjectID, domain.SyncEntityTask, task.ID, task.Version, false))
+	})
+	if err != nil {
+		task.Version = read
+	}
+	return err
 }
 
 func (r *PostgresRepository) UpdateSubtask(subtask *domain.Subtask) error {
-	return r.db.Where("company_id = ?", subtask.CompanyID).Save(subtask).Error
+	read := subtask.Version
+	subtask.Version++
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateSubtaskVersioned(tx, subtask, read); err != nil {
+			return err
+		}
+		if err := tx.Where("subtask_id = ?", subtask.ID).Delete(&domain.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if len(subtask.Assignees) > 0 {
+			if err := tx.Create(&subtask.Assignees).Error; err != nil {
+				return err
+			}
+		}
+		projectID, err := taskProjectID(tx, subtask.TaskID)
+		if err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, subtaskChange(subtask, projectID, false))
+	})
+	if err != nil {
+		subtask.Version = read
+	}
+	return err
 }
 
 func (r *PostgresRepository) DeleteTask(id, companyID string) error {
-	return r.db.Delete(&domain.Task{}, "id = ? AND company_id = ?", id, companyID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var task domain.Task
+		if err := tx.Preload("Subtasks").Where("id = ? AND company_id = ?", id, companyID).First(&task).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("(task_id = ? OR depends_on_task_id = ?) AND company_id = ?", id, id, companyID).Delete(&domain.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ? AND company_id = ?", id, companyID).Delete(&domain.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ? AND company_id = ?", id, companyID).Delete(&domain.Subtask{}).Error; err != nil {
+			return err
+		}
+		// Expenses outlive the task; they just stop being attributed to it.
+		if err := tx.Model(&domain.Expense{}).Where("task_id = ? AND company_id = ?", id, companyID).Update("task_id", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.Task{}, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, taskChanges(&task, true)...)
+	})
 }
 
 func (r *PostgresRepository) DeleteSubtask(id, companyID string) error {
-	return r.db.Delete(&domain.Subtask{}, "id = ? AND company_id = ?", id, companyID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var subtask domain.Subtask
+		if err := tx.Where("id = ? AND company_id = ?", id, companyID).First(&subtask).Error; err != nil {
+			return err
+		}
+		projectID, err := taskProjectID(tx, subtask.TaskID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("subtask_id = ? AND company_id = ?", id, companyID).Delete(&domain.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.Subtask{}, "id = ? AND company_id = ?", id, companyID).Error; err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, subtaskChange(&subtask, projectID, true))
+	})
+}
+
+// updateSubtaskVersioned saves the subtask's own columns if it is still at
+// version read.
+func updateSubtaskVersioned(tx *gorm.DB, subtask *domain.Subtask, read int) error {
+	result := tx.Model(subtask).Omit(clause.Associations).
+		Where("company_id = ? AND version = ?", subtask.CompanyID, read).
+		Select("*").Updates(subtask)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ports.ErrVersionConflict
+	}
+	return nil
+}
+
+func taskProjectID(tx *gorm.DB, taskID string) (string, error) {
+	var task domain.Task
+	if err := tx.Select("project_id").Where("id = ?", taskID).First(&task).Error; err != nil {
+		return "", err
+	}
+	return task.ProjectID, nil
 }
 
 func (r *PostgresRepository) GetTaskByID(id, companyID string) (*domain.Task, error) {
 	var task domain.Task
-	if err := r.db.Preload("Subtasks").Where("id = ? AND company_id = ?", id, companyID).First(&task).Error; err != nil {
+	if err := r.withTaskAssociations().Where("id = ? AND company_id = ?", id, companyID).First(&task).Error; err != nil {
 		return nil, err
 	}
 	return &task, nil
@@ -188,7 +420,7 @@ func (r *PostgresRepository) GetTaskByID(id, companyID string) (*domain.Task, er
 
 func (r *PostgresRepository) GetSubtaskByID(id, companyID string) (*domain.Subtask, error) {
 	var subtask domain.Subtask
-	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&subtask).Error; err != nil {
+	if err := r.db.Preload("Assignees").Where("id = ? AND company_id = ?", id, companyID).First(&subtask).Error; err != nil {
 		return nil, err
 	}
 	return &subtask, nil
@@ -196,21 +428,32 @@ func (r *PostgresRepository) GetSubtaskByID(id, companyID string) (*domain.Subta
 
 func (r *PostgresRepository) GetTasksByProjectID(projectID string) ([]domain.Task, error) {
 	var tasks []domain.Task
-	if err := r.db.Preload("Subtasks").Where("project_id = ?", projectID).Find(&tasks).Error; err != nil {
+	if err := r.withTaskAssociations().Where("project_id = ?", projectID).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *PostgresRepository) GetTasksByCompanyID(companyID string) ([]domain.Task, error) {
+	var tasks []domain.Task
+	if err := r.withTaskAssociations().Where("company_id = ? AND project_id IN (?)", companyID, r.liveProjectIDs()).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
 func (r *PostgresRepository) CreateDiaryEntry(entry *domain.DiaryEntry) error {
+	entry.Version = 1
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Omit("Items").Create(entry).Error; err != nil {
 			return err
 		}
-		if len(entry.Items) == 0 {
-			return nil
+		if len(entry.Items) > 0 {
+			if err := tx.Create(&entry.Items).Error; err != nil {
+				return err
+			}
 		}
-		return tx.Create(&entry.Items).Error
+		return recordSyncChanges(tx, diaryEntryChange(entry, false))
 	})
 }
 
@@ -238,6 +481,7 @@ func (r *PostgresRepository) GetPublicDiaryEntriesByProject(projectID string) ([
 			return db.Where("visibility = ?", "public").Order("sort_order ASC").Order("created_at ASC")
 		}).
 		Where("project_id = ? AND id IN (?)", projectID, publicEntryIDs).
+		Where("status IN ?", []string{domain.DiaryStatusApproved, domain.DiaryStatusAcknowledged}).
 		Order("entry_date DESC").
 		Order("created_at DESC").
 		Find(&entries).Error
@@ -257,38 +501,110 @@ func (r *PostgresRepository) GetDiaryEntryByID(id, projectID, companyID string)
 	return &entry, nil
 }
 
+// UpdateDiaryEntry saves the entry's content in place: items that are gone
+// are deleted and the rest are upserted, so unchanged items keep their rows.
 func (r *PostgresRepository) UpdateDiaryEntry(entry *domain.DiaryEntry) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&domain.DiaryEntry{}).
-			Where("id = ? AND project_id = ? AND company_id = ?", entry.ID, entry.ProjectID, entry.CompanyID).
-			Updates(map[string]interface{}{
-				"entry_date": entry.EntryDate,
-				"title":      entry.Title,
-				"updated_at": entry.UpdatedAt,
-			}).Error; err != nil {
+	read := entry.Version
+	entry.Version++
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateDiaryEntryVersioned(tx, entry, read, map[string]interface{}{
+			"entry_date": entry.EntryDate,
+			"title":      entry.Title,
+			"version":    entry.Version,
+			"updated_at": entry.UpdatedAt,
+		}); err != nil {
 			return err
 		}
 
-		if err := tx.Where("diary_entry_id = ?", entry.ID).Delete(&domain.DiaryItem{}).Error; err != nil {
-			return err
+		if len(entry.Items) == 0 {
+			if err := tx.Where("diary_entry_id = ?", entry.ID).Delete(&domain.DiaryItem{}).Error; err != nil {
+				return err
+			}
+			return recordSyncChanges(tx, diaryEntryChange(entry, false))
 		}
 
-		if len(entry.Items) == 0 {
-			return nil
+		itemIDs := make([]string, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+		if err := tx.Where("diary_entry_id = ? AND id NOT IN ?", entry.ID, itemIDs).Delete(&domain.DiaryItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&entry.Items).Error; err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, diaryEntryChange(entry, false))
+	})
+	if err != nil {
+		entry.Version = read
+	}
+	return err
+}
+
+// UpdateDiaryEntryStatus saves the sign-off fields of an entry, leaving its
+// content untouched.
+func (r *PostgresRepository) UpdateDiaryEntryStatus(entry *domain.DiaryEntry, transitions ...*domain.StatusTransition) error {
+	read := entry.Version
+	entry.Version++
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateDiaryEntryVersioned(tx, entry, read, map[string]interface{}{
+			"status":          entry.Status,
+			"submitted_by":    entry.SubmittedBy,
+			"submitted_at":    entry.SubmittedAt,
+			"approved_by":     entry.ApprovedBy,
+			"approver_name":   entry.ApproverName,
+			"approved_at":     entry.ApprovedAt,
+			"acknowledged_by": entry.AcknowledgedBy,
+			"acknowledged_at": entry.AcknowledgedAt,
+			"amended_by_id":   entry.AmendedByID,
+			"version":         entry.Version,
+			"updated_at":      entry.UpdatedAt,
+		}); err != nil {
+			return err
+		}
+		if err := createTransitions(tx, transitions); err != nil {
+			return err
 		}
-		return tx.Create(&entry.Items).Error
+		return recordSyncChanges(tx, diaryEntryChange(entry, false))
 	})
+	if err != nil {
+		entry.Version = read
+	}
+	return err
 }
 
 func (r *PostgresRepository) DeleteDiaryEntry(id, projectID, companyID string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		var entry domain.DiaryEntry
+		if err := tx.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&entry).Error; err != nil {
+			return err
+		}
 		if err := tx.Where("diary_entry_id = ?", id).Delete(&domain.DiaryItem{}).Error; err != nil {
 			return err
 		}
-		return tx.Delete(&domain.DiaryEntry{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+		if err := tx.Where("entry_id = ? AND project_id = ?", id, projectID).Delete(&domain.DiaryEntryRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.DiaryEntry{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error; err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, diaryEntryChange(&entry, true))
 	})
 }
 
+func updateDiaryEntryVersioned(tx *gorm.DB, entry *domain.DiaryEntry, read int, columns map[string]interface{}) error {
+	result := tx.Model(&domain.DiaryEntry{}).
+		Where("id = ? AND project_id = ? AND company_id = ? AND version = ?", entry.ID, entry.ProjectID, entry.CompanyID, read).
+		Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ports.ErrVersionConflict
+	}
+	return nil
+}
+
 // LinkRepository Implementation
 
 func (r *PostgresRepository) CreateLink(link *domain.Link) error {
@@ -297,7 +613,7 @@ func (r *PostgresRepository) CreateLink(link *domain.Link) error {
 
 func (r *PostgresRepository) GetAllLinks(companyID string) ([]domain.Link, error) {
 	var links []domain.Link
-	if err := r.db.Select("links.*, (SELECT COUNT(*) FROM link_clicks WHERE link_clicks.link_id = links.id) as count").Where("company_id = ?", companyID).Find(&links).Error; err != nil {
+	if err := r.db.Select("links.*, (SELECT COUNT(*) FROM link_clicks WHERE link_clicks.link_id = links.id) as count").Where("company_id = ? AND deleted_at IS NULL", companyID).Find(&links).Error; err != nil {
 		return nil, err
 	}
 	return links, nil
@@ -325,7 +641,7 @@ func (r *PostgresRepository) GetLinkAnalytics(companyID string, startDate, endDa
 			COALESCE(COUNT(link_clicks.id), 0) as clicks
 		`).
 		Joins(joinClause, joinArgs...).
-		Where("links.company_id = ?", companyID)
+		Where("links.company_id = ? AND links.deleted_at IS NULL", companyID)
 
 	err := query.
 		Group("links.id, links.description, links.url").
@@ -343,8 +659,14 @@ func (r *PostgresRepository) UpdateLink(link *domain.Link) error {
 	return r.db.Where("company_id = ?", link.CompanyID).Save(link).Error
 }
 
+// DeleteLink permanently removes a link and its recorded clicks.
 func (r *PostgresRepository) DeleteLink(id, companyID string) error {
-	return r.db.Delete(&domain.Link{}, "id = ? AND company_id = ?", id, companyID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", id).Delete(&domain.LinkClick{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Link{}, "id = ? AND company_id = ?", id, companyID).Error
+	})
 }
 
 func (r *PostgresRepository) RegisterClick(linkID string) error {
@@ -364,7 +686,15 @@ func (r *PostgresRepository) CreateClient(client *domain.Client) error {
 
 func (r *PostgresRepository) GetClientByID(id, companyID string) (*domain.Client, error) {
 	var client domain.Client
-	if err := r.db.Preload("Comments").Where("id = ? AND company_id = ?", id, companyID).First(&client).Error; err != nil {
+	if err := r.db.Preload("Comments").Where("id = ? AND company_id = ? AND deleted_at IS NULL", id, companyID).First(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *PostgresRepository) GetClientWithTrashed(id, companyID string) (*domain.Client, error) {
+	var client domain.Client
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&client).Error; err != nil {
 		return nil, err
 	}
 	return &client, nil
@@ -372,7 +702,7 @@ func (r *PostgresRepository) GetClientByID(id, companyID string) (*domain.Client
 
 func (r *PostgresRepository) GetAllClients(companyID string) ([]domain.Client, error) {
 	var clients []domain.Client
-	err := r.db.Where("company_id = ?", companyID).Find(&clients).Error
+	err := r.db.Where("company_id = ? AND deleted_at IS NULL", companyID).Find(&clients).Error
 	return clients, err
 }
 
@@ -380,16 +710,55 @@ func (r *PostgresRepository) UpdateClient(client *domain.Client) error {
 	return r.db.Where("company_id = ?", client.CompanyID).Save(client).Error
 }
 
+// DeleteClient permanently removes a client and its comments. It refuses while
+// any project, quote or invoice still references the client.
 func (r *PostgresRepository) DeleteClient(id, companyID string) error {
-	return r.db.Delete(&domain.Client{}, "id = ? AND company_id = ?", id, companyID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&domain.Project{}, &domain.Quote{}, &domain.Invoice{}} {
+			var references int64
+			if err := tx.Model(model).Where("client_id = ? AND company_id = ?", id, companyID).Count(&references).Error; err != nil {
+				return err
+			}
+			if references > 0 {
+				return ports.ErrClientInUse
+			}
+		}
+		if err := tx.Where("client_id = ?", id).Delete(&domain.Comment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Client{}, "id = ? AND company_id = ?", id, companyID).Error
+	})
 }
 
 func (r *PostgresRepository) AddComment(comment *domain.Comment) error {
 	return r.db.Create(comment).Error
 }
 
-func (r *PostgresRepository) UpdateSubtaskByTaskID(taskID string) error {
-	return r.db.Model(&domain.Subtask{}).Where("task_id = ?", taskID).Update("status", "Completed").Error
+// UpdateSubtaskByTaskID completes the task's open subtasks, each with its own
+// version.
+func (r *PostgresRepository) UpdateSubtaskByTaskID(taskID, companyID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		projectID, err := taskProjectID(tx, taskID)
+		if err != nil {
+			return err
+		}
+		var subtasks []domain.Subtask
+		if err := tx.Where("task_id = ? AND company_id = ? AND status <> ?", taskID, companyID, domain.SubtaskStatusCompleted).Find(&subtasks).Error; err != nil {
+			return err
+		}
+		changes := make([]domain.SyncChange, 0, len(subtasks))
+		for i := range subtasks {
+			subtask := &subtasks[i]
+			read := subtask.Version
+			subtask.Status = domain.SubtaskStatusCompleted
+			subtask.Version++
+			if err := updateSubtaskVersioned(tx, subtask, read); err != nil {
+				return err
+			}
+			changes = append(changes, subtaskChange(subtask, projectID, false))
+		}
+		return recordSyncChanges(tx, changes...)
+	})
 }
 
 func (r *PostgresRepository) UpdateBio(userID, bio string) error {
@@ -442,14 +811,16 @@ func (r *PostgresRepository) UpdateCompanyPlan(companyID, plan, status, subscrip
 
 func (r *PostgresRepository) CountProjectsByCompany(companyID string) (int64, error) {
 	var count int64
-	err := r.db.Model(&domain.Project{}).Where("company_id = ?", companyID).Count(&count).Error
+	err := r.db.Model(&domain.Project{}).Where("company_id = ? AND deleted_at IS NULL", companyID).Count(&count).Error
 	return count, err
 }
 
 func (r *PostgresRepository) CountProjectsInProgress(companyID string) (int64, error) {
 	var count int64
+	// "Completed" is the status stored before the workflow enums existed.
 	err := r.db.Model(&domain.Project{}).
-		Where("company_id = ? AND (status IS NULL OR status <> ?)", companyID, "Completed").
+		Where("company_id = ? AND deleted_at IS NULL AND (status IS NULL OR status NOT IN ?)", companyID,
+			[]string{domain.ProjectStatusCompleted, domain.ProjectStatusCancelled, "Completed"}).
 		Count(&count).Error
 	return count, err
 }
@@ -457,7 +828,7 @@ func (r *PostgresRepository) CountProjectsInProgress(companyID string) (int64, e
 func (r *PostgresRepository) CountCompletedProjects(companyID string) (int64, error) {
 	var count int64
 	err := r.db.Model(&domain.Project{}).
-		Where("company_id = ? AND status = ?", companyID, "Completed").
+		Where("company_id = ? AND deleted_at IS NULL AND status IN ?", companyID, []string{domain.ProjectStatusCompleted, "Completed"}).
 		Count(&count).Error
 	return count, err
 }
@@ -465,7 +836,7 @@ func (r *PostgresRepository) CountCompletedProjects(companyID string) (int64, er
 func (r *PostgresRepository) CountActiveTasks(companyID string) (int64, error) {
 	var count int64
 	err := r.db.Model(&domain.Task{}).
-		Where("company_id = ? AND (status IS NULL OR status <> ?)", companyID, "Completed").
+		Where("company_id = ? AND project_id IN (?) AND (status IS NULL OR status NOT IN ?)", companyID, r.liveProjectIDs(), []string{domain.TaskStatusDone, "Completed"}).
 		Count(&count).Error
 	return count, err
 }
@@ -474,7 +845,7 @@ func (r *PostgresRepository) CountLinkClicksByCompany(companyID string) (int64,
 	var count int64
 	err := r.db.Table("link_clicks").
 		Joins("JOIN links ON links.id = link_clicks.link_id").
-		Where("links.company_id = ?", companyID).
+		Where("links.company_id = ? AND links.deleted_at IS NULL", companyID).
 		Count(&count).Error
 	return count, err
 }
@@ -482,7 +853,7 @@ func (r *PostgresRepository) CountLinkClicksByCompany(companyID string) (int64,
 func (r *PostgresRepository) CountClientsByCompany(companyID string) (int64, error) {
 	var count int64
 	err := r.db.Model(&domain.Client{}).
-		Where("company_id = ?", companyID).
+		Where("company_id = ? AND deleted_at IS NULL", companyID).
 		Count(&count).Error
 	return count, err
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+)
+
+// AttachmentRepository Implementation
+
+func (r *PostgresRepository) CreateAttachment(attachment *domain.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *PostgresRepository) GetAttachmentsByProject(projectID, companyID string) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *PostgresRepository) GetAttachmentByID(id, projectID, companyID string) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *PostgresRepository) UpdateAttachment(attachment *domain.Attachment) error {
+	return r.db.Where("project_id = ? AND company_id = ?", attachment.ProjectID, attachment.CompanyID).Save(attachment).Error
+}
+
+func (r *PostgresRepository) DeleteAttachment(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Attachment{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ChangeOrderRepository Implementation
+
+func (r *PostgresRepository) CreateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Create(order).Error
+	})
+}
+
+func (r *PostgresRepository) GetChangeOrdersByProject(projectID, companyID string) ([]domain.ChangeOrder, error) {
+	var orders []domain.ChangeOrder
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("number ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *PostgresRepository) GetChangeOrderByID(id, projectID, companyID string) (*domain.ChangeOrder, error) {
+	var order domain.ChangeOrder
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *PostgresRepository) UpdateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Where("project_id = ? AND company_id = ?", order.ProjectID, order.CompanyID).Save(order).Error
+	})
+}
+
+func (r *PostgresRepository) DecideChangeOrder(order *domain.ChangeOrder, previous string, transition *domain.StatusTransition) error {
+	return r.withTransitions([]*domain.StatusTransition{transition}, func(tx *gorm.DB) error {
+		result := tx.Model(order).
+			Where("project_id = ? AND company_id = ? AND status = ?", order.ProjectID, order.CompanyID, previous).
+			Select("*").Updates(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ports.ErrVersionConflict
+		}
+		return nil
+	})
+}
+
+// ShiftChangeOrderWork writes the whole shift in one transaction, claimed by
+// setting the order's ScheduleShiftedAt.
+func (r *PostgresRepository) ShiftChangeOrderWork(order *domain.ChangeOrder, tasks []domain.Task, milestones []domain.Milestone) error {
+	stored := *order
+	now := time.Now()
+	for i := range tasks {
+		order.ShiftedWork = append(order.ShiftedWork, tasks[i].ID)
+	}
+	for i := range milestones {
+		order.ShiftedWork = append(order.ShiftedWork, milestones[i].ID)
+	}
+	order.ScheduleShiftedAt = &now
+	order.UpdatedAt = now
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(order).
+			Where("company_id = ? AND schedule_shifted_at IS NULL", order.CompanyID).
+			Select("shifted_work", "schedule_shifted_at", "updated_at").Updates(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ports.ErrVersionConflict
+		}
+		changes := make([]domain.SyncChange, 0, len(tasks))
+		for i := range tasks {
+			task := &tasks[i]
+			result := tx.Model(&domain.Task{}).
+				Where("id = ? AND company_id = ? AND version = ?", task.ID, task.CompanyID, task.Version).
+				Updates(map[string]interface{}{"start_date": task.StartDate, "due_date": task.DueDate, "version": task.Version + 1})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ports.ErrVersionConflict
+			}
+			changes = append(changes, syncChange(task.CompanyID, task.ProjectID, domain.SyncEntityTask, task.ID, task.Version+1, false))
+		}
+		for i := range milestones {
+			if err := tx.Where("project_id = ? AND company_id = ?", milestones[i].ProjectID, milestones[i].CompanyID).Save(&milestones[i]).Error; err != nil {
+				return err
+			}
+		}
+		return recordSyncChanges(tx, changes...)
+	})
+	if err != nil {
+		*order = stored
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Version++
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteChangeOrder(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.ChangeOrder{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
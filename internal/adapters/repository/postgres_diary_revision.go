@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+)
+
+// Diary revision Implementation
+
+func (r *PostgresRepository) CreateDiaryRevision(revision *domain.DiaryEntryRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *PostgresRepository) GetDiaryRevisions(entryID, projectID, companyID string) ([]domain.DiaryEntryRevision, error) {
+	var revisions []domain.DiaryEntryRevision
+	err := r.db.
+		Where("entry_id = ? AND project_id = ? AND company_id = ?", entryID, projectID, companyID).
+		Order("revision ASC").
+		Find(&revisions).Error
+	return revisions, err
+}
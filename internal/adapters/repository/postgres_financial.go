@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// FinancialRepository Implementation
+
+func (r *PostgresRepository) GetBudgetLines(projectID, companyID string) ([]domain.BudgetLine, error) {
+	var lines []domain.BudgetLine
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("category ASC").
+		Order("created_at ASC").
+		Find(&lines).Error
+	return lines, err
+}
+
+func (r *PostgresRepository) ReplaceBudgetLines(projectID, companyID string, lines []domain.BudgetLine) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ? AND company_id = ?", projectID, companyID).Delete(&domain.BudgetLine{}).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return tx.Create(&lines).Error
+	})
+}
+
+func (r *PostgresRepository) CreateExpense(expense *domain.Expense) error {
+	return r.db.Create(expense).Error
+}
+
+func (r *PostgresRepository) GetExpensesByProject(projectID, companyID string) ([]domain.Expense, error) {
+	var expenses []domain.Expense
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("expense_date DESC").
+		Order("created_at DESC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *PostgresRepository) GetExpenseByID(id, projectID, companyID string) (*domain.Expense, error) {
+	var expense domain.Expense
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&expense).Error; err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *PostgresRepository) UpdateExpense(expense *domain.Expense) error {
+	return r.db.Where("project_id = ? AND company_id = ?", expense.ProjectID, expense.CompanyID).Save(expense).Error
+}
+
+func (r *PostgresRepository) DeleteExpense(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Expense{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
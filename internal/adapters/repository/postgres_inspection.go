@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+)
+
+// InspectionRepository Implementation
+
+func (r *PostgresRepository) CreateInspection(inspection *domain.Inspection) error {
+	return r.db.Create(inspection).Error
+}
+
+func (r *PostgresRepository) GetInspectionsByProject(projectID, companyID string) ([]domain.Inspection, error) {
+	var inspections []domain.Inspection
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("created_at ASC").
+		Find(&inspections).Error
+	return inspections, err
+}
+
+func (r *PostgresRepository) GetInspectionByID(id, projectID, companyID string) (*domain.Inspection, error) {
+	var inspection domain.Inspection
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&inspection).Error; err != nil {
+		return nil, err
+	}
+	return &inspection, nil
+}
+
+func (r *PostgresRepository) UpdateInspection(inspection *domain.Inspection) error {
+	return r.db.Where("project_id = ? AND company_id = ?", inspection.ProjectID, inspection.CompanyID).Save(inspection).Error
+}
+
+func (r *PostgresRepository) DeleteInspection(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Inspection{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"strings"
+)
+
+// InvoiceRepository Implementation
+
+// CreateInvoices inserts the invoices in one statement; a number already taken
+// in the project violates idx_invoices_project_number.
+func (r *PostgresRepository) CreateInvoices(invoices []domain.Invoice) error {
+	err := r.db.Create(&invoices).Error
+	if err != nil && strings.Contains(err.Error(), "idx_invoices_project_number") {
+		return ports.ErrVersionConflict
+	}
+	return err
+}
+
+func (r *PostgresRepository) GetInvoicesByProject(projectID, companyID string) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("due_date ASC, number ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *PostgresRepository) GetInvoicesByCompany(companyID string) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.
+		Where("company_id = ? AND project_id IN (?)", companyID, r.liveProjectIDs()).
+		Order("due_date ASC, number ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *PostgresRepository) GetInvoiceByID(id, projectID, companyID string) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *PostgresRepository) UpdateInvoice(invoice *domain.Invoice) error {
+	read := invoice.Version
+	invoice.Version++
+	result := r.db.Model(invoice).
+		Where("project_id = ? AND company_id = ? AND version = ?", invoice.ProjectID, invoice.CompanyID, read).
+		Select("*").Updates(invoice)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ports.ErrVersionConflict
+	}
+	if result.Error != nil {
+		invoice.Version = read
+	}
+	return result.Error
+}
+
+func (r *PostgresRepository) DeleteInvoice(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Invoice{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaterialRepository Implementation
+
+func (r *PostgresRepository) CreateMaterial(material *domain.Material) error {
+	return r.db.Create(material).Error
+}
+
+func (r *PostgresRepository) GetMaterialsByCompany(companyID string) ([]domain.Material, error) {
+	var materials []domain.Material
+	err := r.db.Where("company_id = ?", companyID).Order("name ASC").Find(&materials).Error
+	return materials, err
+}
+
+func (r *PostgresRepository) GetMaterialByID(id, companyID string) (*domain.Material, error) {
+	var material domain.Material
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&material).Error; err != nil {
+		return nil, err
+	}
+	return &material, nil
+}
+
+func (r *PostgresRepository) UpdateMaterial(material *domain.Material) error {
+	return r.db.Where("company_id = ?", material.CompanyID).Save(material).Error
+}
+
+func (r *PostgresRepository) DeleteMaterial(id, companyID string) error {
+	return r.db.Delete(&domain.Material{}, "id = ? AND company_id = ?", id, companyID).Error
+}
+
+func (r *PostgresRepository) CreateStockMovements(movements []domain.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&movements).Error
+	})
+}
+
+func (r *PostgresRepository) GetStockMovementsByProject(projectID, companyID string) ([]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("movement_date DESC").
+		Order("created_at DESC").
+		Find(&movements).Error
+	return movements, err
+}
+
+func (r *PostgresRepository) SaveMaterialThreshold(threshold *domain.MaterialThreshold) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "material_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_quantity", "updated_at"}),
+	}).Create(threshold).Error
+}
+
+func (r *PostgresRepository) GetMaterialThresholdsByProject(projectID, companyID string) ([]domain.MaterialThreshold, error) {
+	var thresholds []domain.MaterialThreshold
+	err := r.db.Where("project_id = ? AND company_id = ?", projectID, companyID).Find(&thresholds).Error
+	return thresholds, err
+}
+
+func (r *PostgresRepository) CreatePurchaseRequest(request *domain.PurchaseRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *PostgresRepository) GetPurchaseRequestsByProject(projectID, companyID string) ([]domain.PurchaseRequest, error) {
+	var requests []domain.PurchaseRequest
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *PostgresRepository) GetPurchaseRequestByID(id, projectID, companyID string) (*domain.PurchaseRequest, error) {
+	var request domain.PurchaseRequest
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *PostgresRepository) UpdatePurchaseRequest(request *domain.PurchaseRequest) error {
+	return r.db.Where("project_id = ? AND company_id = ?", request.ProjectID, request.CompanyID).Save(request).Error
+}
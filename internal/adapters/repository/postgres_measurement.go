@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+// MeasurementRepository Implementation
+
+func (r *PostgresRepository) CreateContractItem(item *domain.ContractItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *PostgresRepository) GetContractItemsByProject(projectID, companyID string) ([]domain.ContractItem, error) {
+	var items []domain.ContractItem
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *PostgresRepository) GetContractItemByID(id, projectID, companyID string) (*domain.ContractItem, error) {
+	var item domain.ContractItem
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *PostgresRepository) UpdateContractItem(item *domain.ContractItem) error {
+	return r.db.Where("project_id = ? AND company_id = ?", item.ProjectID, item.CompanyID).Save(item).Error
+}
+
+func (r *PostgresRepository) DeleteContractItem(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.ContractItem{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
+
+func (r *PostgresRepository) CreateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Create(measurement).Error
+	})
+}
+
+func (r *PostgresRepository) GetMeasurementsByProject(projectID, companyID string) ([]domain.Measurement, error) {
+	var measurements []domain.Measurement
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("number ASC").
+		Find(&measurements).Error
+	return measurements, err
+}
+
+func (r *PostgresRepository) GetMeasurementByID(id, projectID, companyID string) (*domain.Measurement, error) {
+	var measurement domain.Measurement
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&measurement).Error; err != nil {
+		return nil, err
+	}
+	return &measurement, nil
+}
+
+func (r *PostgresRepository) UpdateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Where("project_id = ? AND company_id = ?", measurement.ProjectID, measurement.CompanyID).Save(measurement).Error
+	})
+}
+
+func (r *PostgresRepository) DecideMeasurement(measurement *domain.Measurement, previous string, transition *domain.StatusTransition) error {
+	return r.withTransitions([]*domain.StatusTransition{transition}, func(tx *gorm.DB) error {
+		result := tx.Model(measurement).
+			Where("project_id = ? AND company_id = ? AND status = ?", measurement.ProjectID, measurement.CompanyID, previous).
+			Select("*").Updates(measurement)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ports.ErrVersionConflict
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) DeleteMeasurement(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Measurement{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
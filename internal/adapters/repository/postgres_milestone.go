@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"time"
+)
+
+// MilestoneRepository Implementation
+
+func (r *PostgresRepository) CreateMilestone(milestone *domain.Milestone) error {
+	return r.db.Create(milestone).Error
+}
+
+func (r *PostgresRepository) GetMilestonesByProject(projectID, companyID string) ([]domain.Milestone, error) {
+	var milestones []domain.Milestone
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("target_date ASC").
+		Order("created_at ASC").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *PostgresRepository) GetMilestoneByID(id, projectID, companyID string) (*domain.Milestone, error) {
+	var milestone domain.Milestone
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&milestone).Error; err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *PostgresRepository) UpdateMilestone(milestone *domain.Milestone) error {
+	return r.db.Where("project_id = ? AND company_id = ?", milestone.ProjectID, milestone.CompanyID).Save(milestone).Error
+}
+
+func (r *PostgresRepository) DeleteMilestone(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.Milestone{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
+
+func (r *PostgresRepository) CountOverdueMilestones(companyID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Milestone{}).
+		Where("company_id = ? AND project_id IN (?) AND completed_at IS NULL AND target_date < ?", companyID, r.liveProjectIDs(), time.Now()).
+		Count(&count).Error
+	return count, err
+}
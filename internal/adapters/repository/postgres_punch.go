@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// PunchListRepository Implementation
+
+func (r *PostgresRepository) CreatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Create(item).Error
+	})
+}
+
+func (r *PostgresRepository) GetPunchItemsByProject(projectID, companyID string) ([]domain.PunchItem, error) {
+	var items []domain.PunchItem
+	err := r.db.
+		Where("project_id = ? AND company_id = ?", projectID, companyID).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *PostgresRepository) GetPunchItemByID(id, projectID, companyID string) (*domain.PunchItem, error) {
+	var item domain.PunchItem
+	if err := r.db.Where("id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *PostgresRepository) UpdatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error {
+	return r.withTransitions(transitions, func(tx *gorm.DB) error {
+		return tx.Where("project_id = ? AND company_id = ?", item.ProjectID, item.CompanyID).Save(item).Error
+	})
+}
+
+func (r *PostgresRepository) DeletePunchItem(id, projectID, companyID string) error {
+	return r.db.Delete(&domain.PunchItem{}, "id = ? AND project_id = ? AND company_id = ?", id, projectID, companyID).Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+// QuoteRepository Implementation
+
+func (r *PostgresRepository) CreateQuote(quote *domain.Quote) error {
+	return r.db.Create(quote).Error
+}
+
+func (r *PostgresRepository) GetQuotesByCompany(companyID string) ([]domain.Quote, error) {
+	var quotes []domain.Quote
+	err := r.db.Where("company_id = ?", companyID).Order("number DESC").Find(&quotes).Error
+	return quotes, err
+}
+
+func (r *PostgresRepository) GetQuoteByID(id, companyID string) (*domain.Quote, error) {
+	var quote domain.Quote
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&quote).Error; err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *PostgresRepository) GetQuoteByPublicToken(token string) (*domain.Quote, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var quote domain.Quote
+	if err := r.db.Where("public_token = ?", token).First(&quote).Error; err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *PostgresRepository) UpdateQuote(quote *domain.Quote) error {
+	return r.db.Where("company_id = ?", quote.CompanyID).Save(quote).Error
+}
+
+// SetQuoteProject saves the quote only while the stored one still points at
+// the project previous, so that two conversions cannot both claim it.
+func (r *PostgresRepository) SetQuoteProject(quote *domain.Quote, previous string) error {
+	result := r.db.Model(&domain.Quote{}).
+		Where("id = ? AND company_id = ? AND project_id = ?", quote.ID, quote.CompanyID, previous).
+		Updates(map[string]interface{}{"project_id": quote.ProjectID, "updated_at": quote.UpdatedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ports.ErrVersionConflict
+	}
+	return nil
+}
+
+// DeleteQuote removes the quote together with its sent versions.
+func (r *PostgresRepository) DeleteQuote(id, companyID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("quote_id = ? AND company_id = ?", id, companyID).Delete(&domain.QuoteVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Quote{}, "id = ? AND company_id = ?", id, companyID).Error
+	})
+}
+
+func (r *PostgresRepository) CreateQuoteVersion(version *domain.QuoteVersion) error {
+	return r.db.Create(version).Error
+}
+
+func (r *PostgresRepository) GetQuoteVersions(quoteID, companyID string) ([]domain.QuoteVersion, error) {
+	var versions []domain.QuoteVersion
+	err := r.db.Where("quote_id = ? AND company_id = ?", quoteID, companyID).Order("version ASC").Find(&versions).Error
+	return versions, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitRepository Implementation
+
+func (r *PostgresRepository) HitRateLimit(key string, expiresAt time.Time) (int, error) {
+	hit := domain.RateLimitHit{Key: key, Count: 1, ExpiresAt: expiresAt}
+	err := r.db.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("rate_limit_hits.count + 1")}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "count"}}},
+	).Create(&hit).Error
+	return hit.Count, err
+}
+
+func (r *PostgresRepository) GetRateLimit(key string) (int, error) {
+	var hit domain.RateLimitHit
+	err := r.db.Where("key = ?", key).First(&hit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return hit.Count, err
+}
+
+func (r *PostgresRepository) PurgeRateLimits(before time.Time) (int, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.RateLimitHit{})
+	return int(result.RowsAffected), result.Error
+}
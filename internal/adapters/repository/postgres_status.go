@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// StatusHistoryRepository Implementation
+
+func (r *PostgresRepository) CreateStatusTransition(transition *domain.StatusTransition) error {
+	return r.db.Create(transition).Error
+}
+
+// withTransitions runs write in a transaction together with the status history
+// entries recording what it does.
+func (r *PostgresRepository) withTransitions(transitions []*domain.StatusTransition, write func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		return createTransitions(tx, transitions)
+	})
+}
+
+func createTransitions(tx *gorm.DB, transitions []*domain.StatusTransition) error {
+	for _, transition := range transitions {
+		if err := tx.Create(transition).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetStatusTransitions(projectID, entityType, entityID, companyID string) ([]domain.StatusTransition, error) {
+	var transitions []domain.StatusTransition
+	err := r.db.
+		Where("project_id = ? AND entity_type = ? AND entity_id = ? AND company_id = ?", projectID, entityType, entityID, companyID).
+		Order("created_at ASC").
+		Find(&transitions).Error
+	return transitions, err
+}
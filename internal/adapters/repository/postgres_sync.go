@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncRepository Implementation
+
+// recordSyncChanges numbers changes from the company's sequence and stores
+// them as part of tx. The counter row stays locked until tx ends, so cursors
+// are handed out in commit order and a reader never skips a change that
+// commits late.
+func recordSyncChanges(tx *gorm.DB, changes ...domain.SyncChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	counter := domain.SyncCounter{CompanyID: changes[0].CompanyID, Sequence: int64(len(changes))}
+	if err := tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "company_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"sequence": gorm.Expr("sync_counters.sequence + ?", len(changes))}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "sequence"}}},
+	).Create(&counter).Error; err != nil {
+		return err
+	}
+
+	first := counter.Sequence - int64(len(changes))
+	for i := range changes {
+		changes[i].Cursor = syncCursor(first + int64(i) + 1)
+	}
+	return tx.Create(&changes).Error
+}
+
+func (r *PostgresRepository) GetSyncChanges(companyID, cursor string, limit int) ([]domain.SyncChange, error) {
+	var changes []domain.SyncChange
+	err := r.db.
+		Where("company_id = ? AND cursor > ?", companyID, cursor).
+		Order("cursor ASC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
+
+func (r *PostgresRepository) GetLatestSyncCursor(companyID string) (string, error) {
+	var counter domain.SyncCounter
+	err := r.db.Where("company_id = ?", companyID).First(&counter).Error
+	if err == nil {
+		return syncCursor(counter.Sequence), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	// Companies whose feed predates the counter.
+	var changes []domain.SyncChange
+	if err := r.db.Where("company_id = ?", companyID).Order("cursor DESC").Limit(1).Find(&changes).Error; err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+	return changes[0].Cursor, nil
+}
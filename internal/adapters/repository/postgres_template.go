@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TemplateRepository Implementation
+
+func (r *PostgresRepository) CreateTemplate(template *domain.ProjectTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *PostgresRepository) GetTemplatesByCompany(companyID string) ([]domain.ProjectTemplate, error) {
+	var templates []domain.ProjectTemplate
+	err := r.db.Where("company_id = ?", companyID).Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *PostgresRepository) GetTemplateByID(id, companyID string) (*domain.ProjectTemplate, error) {
+	var template domain.ProjectTemplate
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *PostgresRepository) UpdateTemplate(template *domain.ProjectTemplate) error {
+	return r.db.Where("company_id = ?", template.CompanyID).Save(template).Error
+}
+
+func (r *PostgresRepository) DeleteTemplate(id, companyID string) error {
+	return r.db.Delete(&domain.ProjectTemplate{}, "id = ? AND company_id = ?", id, companyID).Error
+}
+
+func (r *PostgresRepository) CreateDiaryTemplate(template *domain.DiaryTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *PostgresRepository) GetDiaryTemplatesByCompany(companyID string) ([]domain.DiaryTemplate, error) {
+	var templates []domain.DiaryTemplate
+	err := r.db.Where("company_id = ?", companyID).Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *PostgresRepository) GetDiaryTemplateByID(id, companyID string) (*domain.DiaryTemplate, error) {
+	var template domain.DiaryTemplate
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *PostgresRepository) UpdateDiaryTemplate(template *domain.DiaryTemplate) error {
+	return r.db.Where("company_id = ?", template.CompanyID).Save(template).Error
+}
+
+func (r *PostgresRepository) DeleteDiaryTemplate(id, companyID string) error {
+	return r.db.Delete(&domain.DiaryTemplate{}, "id = ? AND company_id = ?", id, companyID).Error
+}
+
+// CreateProjectBundle relies on GORM saving the task associations (subtasks,
+// assignees and dependencies) together with the tasks.
+func (r *PostgresRepository) CreateProjectBundle(bundle *domain.ProjectBundle) error {
+	for i := range bundle.Tasks {
+		startVersions(&bundle.Tasks[i])
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(bundle.Project).Error; err != nil {
+			return err
+		}
+		if len(bundle.Milestones) > 0 {
+			if err := tx.Create(&bundle.Milestones).Error; err != nil {
+				return err
+			}
+		}
+		if len(bundle.Tasks) > 0 {
+			if err := tx.Create(&bundle.Tasks).Error; err != nil {
+				return err
+			}
+		}
+		if len(bundle.BudgetLines) > 0 {
+			if err := tx.Create(&bundle.BudgetLines).Error; err != nil {
+				return err
+			}
+		}
+		if len(bundle.Transitions) > 0 {
+			if err := tx.Create(&bundle.Transitions).Error; err != nil {
+				return err
+			}
+		}
+		return recordSyncChanges(tx, projectChange(bundle.Project.ID, bundle.Project.CompanyID, false))
+	})
+}
+
+func (r *PostgresRepository) CreateInspectionTemplate(template *domain.InspectionTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *PostgresRepository) GetInspectionTemplatesByCompany(companyID string) ([]domain.InspectionTemplate, error) {
+	var templates []domain.InspectionTemplate
+	err := r.db.Where("company_id = ?", companyID).Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *PostgresRepository) GetInspectionTemplateByID(id, companyID string) (*domain.InspectionTemplate, error) {
+	var template domain.InspectionTemplate
+	if err := r.db.Where("id = ? AND company_id = ?", id, companyID).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *PostgresRepository) UpdateInspectionTemplate(template *domain.InspectionTemplate) error {
+	return r.db.Where("company_id = ?", template.CompanyID).Save(template).Error
+}
+
+func (r *PostgresRepository) DeleteInspectionTemplate(id, companyID string) error {
+	return r.db.Delete(&domain.InspectionTemplate{}, "id = ? AND company_id = ?", id, companyID).Error
+}
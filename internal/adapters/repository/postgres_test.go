@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestPostgres connects to the database in TEST_POSTGRES_DSN, which the
+// tests write to; they are skipped when it is not set.
+func newTestPostgres(t *testing.T) (*PostgresRepository, *gorm.DB) {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("connect to Postgres: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.User{}, &domain.Project{}, &domain.Client{}, &domain.Comment{}, &domain.Task{}, &domain.Subtask{}, &domain.TaskDependency{}, &domain.TaskAssignee{}, &domain.Company{}, &domain.DiaryEntry{}, &domain.DiaryItem{}, &domain.DiaryEntryRevision{}, &domain.BudgetLine{}, &domain.Expense{}, &domain.Material{}, &domain.StockMovement{}, &domain.MaterialThreshold{}, &domain.PurchaseRequest{}, &domain.Milestone{}, &domain.StatusTransition{}, &domain.Attachment{}, &domain.SyncChange{}, &domain.SyncCounter{}, &domain.PunchItem{}, &domain.Inspection{}, &domain.ChangeOrder{}, &domain.Invoice{}, &domain.ContractItem{}, &domain.Measurement{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewPostgresRepository(db), db
+}
+
+func seedPostgresProject(t *testing.T, repo *PostgresRepository, db *gorm.DB, fixture *projectFixture) {
+	t.Helper()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("seed project: %v", err)
+		}
+	}
+
+	must(repo.CreateProject(fixture.Project))
+	for i := range fixture.Tasks {
+		must(repo.AddTask(&fixture.Tasks[i]))
+	}
+	if fixture.Dependency.ID != "" {
+		must(db.Create(&fixture.Dependency).Error)
+	}
+	must(repo.CreateDiaryEntry(&fixture.DiaryEntry))
+	for _, record := range []interface{}{
+		&fixture.Revision,
+		&fixture.BudgetLine,
+		&fixture.Expense,
+		&fixture.Milestone,
+		&fixture.Transition,
+		&fixture.Movement,
+		&fixture.Threshold,
+		&fixture.Request,
+		&fixture.PunchItem,
+		&fixture.Inspection,
+		&fixture.ChangeOrder,
+		&fixture.Invoice,
+		&fixture.ContractItem,
+		&fixture.Measurement,
+		&fixture.Attachment,
+	} {
+		must(db.Create(record).Error)
+	}
+}
+
+// projectRowCounts counts the rows that belong to the fixture's project, by
+// table.
+func projectRowCounts(t *testing.T, db *gorm.DB, fixture *projectFixture) map[string]int64 {
+	t.Helper()
+	projectID := fixture.Project.ID
+	counts := make(map[string]int64)
+	count := func(name string, model interface{}, query string, args ...interface{}) {
+		t.Helper()
+		var n int64
+		if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatalf("count %s: %v", name, err)
+		}
+		counts[name] = n
+	}
+
+	count("projects", &domain.Project{}, "id = ?", projectID)
+	count("tasks", &domain.Task{}, "project_id = ?", projectID)
+	count("subtasks", &domain.Subtask{}, "task_id IN ?", fixture.taskIDs())
+	count("task_assignees", &domain.TaskAssignee{}, "project_id = ?", projectID)
+	count("task_dependencies", &domain.TaskDependency{}, "project_id = ?", projectID)
+	count("diary_entries", &domain.DiaryEntry{}, "project_id = ?", projectID)
+	count("diary_items", &domain.DiaryItem{}, "diary_entry_id = ?", fixture.DiaryEntry.ID)
+	for name, model := range map[string]interface{}{
+		"diary_entry_revisions": &domain.DiaryEntryRevision{},
+		"budget_lines":          &domain.BudgetLine{},
+		"expenses":              &domain.Expense{},
+		"milestones":            &domain.Milestone{},
+		"status_transitions":    &domain.StatusTransition{},
+		"stock_movements":       &domain.StockMovement{},
+		"material_thresholds":   &domain.MaterialThreshold{},
+		"purchase_requests":     &domain.PurchaseRequest{},
+		"punch_items":           &domain.PunchItem{},
+		"inspections":           &domain.Inspection{},
+		"change_orders":         &domain.ChangeOrder{},
+		"invoices":              &domain.Invoice{},
+		"contract_items":        &domain.ContractItem{},
+		"measurements":          &domain.Measurement{},
+		"attachments":           &domain.Attachment{},
+	} {
+		count(name, model, "project_id = ?", projectID)
+	}
+	return counts
+}
+
+func TestPostgresDeleteProjectRemovesEveryChild(t *testing.T) {
+	repo, db := newTestPostgres(t)
+	companyID := uuid.New().String()
+	client := &domain.Client{ID: uuid.New().String(), Name: "Fixture client", CompanyID: companyID}
+	if err := repo.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+
+	fixture := newProjectFixture(companyID, client.ID, 3)
+	other := newProjectFixture(companyID, client.ID, 2)
+	seedPostgresProject(t, repo, db, fixture)
+	seedPostgresProject(t, repo, db, other)
+
+	for table, n := range projectRowCounts(t, db, fixture) {
+		if n == 0 {
+			t.Fatalf("fixture seeded no %s", table)
+		}
+	}
+	otherBefore := projectRowCounts(t, db, other)
+
+	if err := repo.DeleteProject(fixture.Project.ID, companyID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+
+	for table, n := range projectRowCounts(t, db, fixture) {
+		if n != 0 {
+			t.Errorf("%d %s left after DeleteProject", n, table)
+		}
+	}
+	for table, n := range projectRowCounts(t, db, other) {
+		if n != otherBefore[table] {
+			t.Errorf("other project has %d %s, want %d", n, table, otherBefore[table])
+		}
+	}
+}
+
+func TestPostgresDeleteProjectChecksCompany(t *testing.T) {
+	repo, db := newTestPostgres(t)
+	companyID := uuid.New().String()
+	client := &domain.Client{ID: uuid.New().String(), Name: "Fixture client", CompanyID: companyID}
+	if err := repo.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	fixture := newProjectFixture(companyID, client.ID, 1)
+	seedPostgresProject(t, repo, db, fixture)
+
+	if err := repo.DeleteProject(fixture.Project.ID, uuid.New().String()); err == nil {
+		t.Fatal("DeleteProject removed another company's project")
+	}
+	if counts := projectRowCounts(t, db, fixture); counts["tasks"] != 1 || counts["projects"] != 1 {
+		t.Fatalf("project or tasks removed: %v", counts)
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashRepository Implementation
+
+// TrashProject and RestoreProject also tell offline clients that the
+// project's tasks, subtasks and diary entries went away or came back.
+func (r *PostgresRepository) TrashProject(id, companyID string, deletedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := setDeletedAt(tx, &domain.Project{}, id, companyID, &deletedAt); err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, projectChange(id, companyID, true))
+	})
+}
+
+func (r *PostgresRepository) RestoreProject(id, companyID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := setDeletedAt(tx, &domain.Project{}, id, companyID, nil); err != nil {
+			return err
+		}
+		return recordSyncChanges(tx, projectChange(id, companyID, false))
+	})
+}
+
+func (r *PostgresRepository) TrashClient(id, companyID string, deletedAt time.Time) error {
+	return r.setDeletedAt(&domain.Client{}, id, companyID, &deletedAt)
+}
+
+func (r *PostgresRepository) RestoreClient(id, companyID string) error {
+	return r.setDeletedAt(&domain.Client{}, id, companyID, nil)
+}
+
+func (r *PostgresRepository) TrashLink(id, companyID string, deletedAt time.Time) error {
+	return r.setDeletedAt(&domain.Link{}, id, companyID, &deletedAt)
+}
+
+func (r *PostgresRepository) RestoreLink(id, companyID string) error {
+	return r.setDeletedAt(&domain.Link{}, id, companyID, nil)
+}
+
+func (r *PostgresRepository) GetTrash(companyID string) (*domain.Trash, error) {
+	return r.trash(func(db *gorm.DB) *gorm.DB {
+		return db.Where("company_id = ? AND deleted_at IS NOT NULL", companyID)
+	})
+}
+
+func (r *PostgresRepository) GetTrashBefore(cutoff time.Time) (*domain.Trash, error) {
+	return r.trash(func(db *gorm.DB) *gorm.DB {
+		return db.Where("deleted_at < ?", cutoff)
+	})
+}
+
+func (r *PostgresRepository) trash(scope func(*gorm.DB) *gorm.DB) (*domain.Trash, error) {
+	trash := &domain.Trash{
+		Projects: []domain.Project{},
+		Clients:  []domain.Client{},
+		Links:    []domain.Link{},
+	}
+	if err := r.db.Scopes(scope).Order("deleted_at DESC").Find(&trash.Projects).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Scopes(scope).Order("deleted_at DESC").Find(&trash.Clients).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Scopes(scope).Order("deleted_at DESC").Find(&trash.Links).Error; err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
+
+// setDeletedAt moves a record into the trash (deletedAt set) or back out of it
+// (deletedAt nil). It reports gorm.ErrRecordNotFound when the record is not in
+// the opposite state.
+func (r *PostgresRepository) setDeletedAt(model interface{}, id, companyID string, deletedAt *time.Time) error {
+	return setDeletedAt(r.db, model, id, companyID, deletedAt)
+}
+
+func setDeletedAt(db *gorm.DB, model interface{}, id, companyID string, deletedAt *time.Time) error {
+	condition := "deleted_at IS NULL"
+	if deletedAt == nil {
+		condition = "deleted_at IS NOT NULL"
+	}
+	result := db.Model(model).
+		Where("id = ? AND company_id = ? AND "+condition, id, companyID).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// liveProjectIDs selects the projects that are not in the trash, for queries
+// over project children.
+func (r *PostgresRepository) liveProjectIDs() *gorm.DB {
+	return r.db.Model(&domain.Project{}).Select("id").Where("deleted_at IS NULL")
+}
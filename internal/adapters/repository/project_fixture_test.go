@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// projectFixture is a project with one of every kind of child record, used to
+// check that a permanent delete leaves nothing behind.
+type projectFixture struct {
+	Project      *domain.Project
+	Tasks        []domain.Task
+	Dependency   domain.TaskDependency
+	DiaryEntry   domain.DiaryEntry
+	Revision     domain.DiaryEntryRevision
+	BudgetLine   domain.BudgetLine
+	Expense      domain.Expense
+	Milestone    domain.Milestone
+	Transition   domain.StatusTransition
+	Movement     domain.StockMovement
+	Threshold    domain.MaterialThreshold
+	Request      domain.PurchaseRequest
+	PunchItem    domain.PunchItem
+	Inspection   domain.Inspection
+	ChangeOrder  domain.ChangeOrder
+	Invoice      domain.Invoice
+	ContractItem domain.ContractItem
+	Measurement  domain.Measurement
+	Attachment   domain.Attachment
+}
+
+// newProjectFixture builds a project of companyID with taskCount tasks, each
+// with a subtask and an assignee.
+func newProjectFixture(companyID, clientID string, taskCount int) *projectFixture {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	projectID := uuid.New().String()
+	fixture := &projectFixture{
+		Project: &domain.Project{
+			ID:        projectID,
+			Name:      "Fixture project",
+			ClientID:  clientID,
+			Status:    domain.ProjectStatusInProgress,
+			CompanyID: companyID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	for i := 0; i < taskCount; i++ {
+		taskID := uuid.New().String()
+		fixture.Tasks = append(fixture.Tasks, domain.Task{
+			ID:        taskID,
+			ProjectID: projectID,
+			Name:      "Task",
+			Status:    domain.TaskStatusTodo,
+			CompanyID: companyID,
+			Assignees: []domain.TaskAssignee{{
+				ID:        uuid.New().String(),
+				TaskID:    taskID,
+				ProjectID: projectID,
+				CompanyID: companyID,
+				UserID:    uuid.New().String(),
+				CreatedAt: now,
+			}},
+			Subtasks: []domain.Subtask{{
+				ID:        uuid.New().String(),
+				TaskID:    taskID,
+				Name:      "Subtask",
+				Status:    domain.SubtaskStatusPending,
+				CompanyID: companyID,
+				CreatedAt: now,
+			}},
+			CreatedAt: now,
+		})
+	}
+	if len(fixture.Tasks) > 1 {
+		fixture.Dependency = domain.TaskDependency{
+			ID:              uuid.New().String(),
+			TaskID:          fixture.Tasks[1].ID,
+			DependsOnTaskID: fixture.Tasks[0].ID,
+			ProjectID:       projectID,
+			CompanyID:       companyID,
+			Type:            domain.DependencyFinishToStart,
+			CreatedAt:       now,
+		}
+	}
+
+	entryID := uuid.New().String()
+	fixture.DiaryEntry = domain.DiaryEntry{
+		ID:        entryID,
+		ProjectID: projectID,
+		CompanyID: companyID,
+		EntryDate: now,
+		Title:     "Day one",
+		Items: []domain.DiaryItem{{
+			ID:           uuid.New().String(),
+			DiaryEntryID: entryID,
+			Type:         "note",
+			Content:      "Poured the slab",
+			Visibility:   "internal",
+			CreatedAt:    now,
+		}},
+		CreatedAt: now,
+	}
+	fixture.Revision = domain.DiaryEntryRevision{ID: uuid.New().String(), EntryID: entryID, ProjectID: projectID, CompanyID: companyID, Revision: 1, CreatedAt: now}
+
+	materialID := uuid.New().String()
+	fixture.BudgetLine = domain.BudgetLine{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID, CreatedAt: now}
+	fixture.Expense = domain.Expense{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID, CreatedAt: now}
+	fixture.Milestone = domain.Milestone{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.Transition = domain.StatusTransition{ID: uuid.New().String(), EntityType: domain.StatusEntityProject, EntityID: projectID, ProjectID: projectID, CompanyID: companyID, CreatedAt: now}
+	fixture.Movement = domain.StockMovement{ID: uuid.New().String(), CompanyID: companyID, ProjectID: projectID, MaterialID: materialID, CreatedAt: now}
+	fixture.Threshold = domain.MaterialThreshold{ID: uuid.New().String(), CompanyID: companyID, ProjectID: projectID, MaterialID: materialID}
+	fixture.Request = domain.PurchaseRequest{ID: uuid.New().String(), CompanyID: companyID, ProjectID: projectID, MaterialID: materialID}
+	fixture.PunchItem = domain.PunchItem{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.Inspection = domain.Inspection{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.ChangeOrder = domain.ChangeOrder{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.Invoice = domain.Invoice{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.ContractItem = domain.ContractItem{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.Measurement = domain.Measurement{ID: uuid.New().String(), ProjectID: projectID, CompanyID: companyID}
+	fixture.Attachment = domain.Attachment{ID: uuid.New().String(), CompanyID: companyID, ProjectID: projectID, EntityType: "project", EntityID: projectID, CreatedAt: now}
+	return fixture
+}
+
+func (f *projectFixture) taskIDs() []string {
+	ids := make([]string, 0, len(f.Tasks))
+	for _, task := range f.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// syncCursorPrefix sets sequence cursors apart from the time-based cursors of
+// earlier releases, which start with the year and so sort before them: clients
+// holding an old cursor still get every change made since.
+const syncCursorPrefix = "SEQ#"
+
+// syncCursor formats a change sequence number at fixed width so cursors sort
+// as strings.
+func syncCursor(sequence int64) string {
+	return fmt.Sprintf("%s%020d", syncCursorPrefix, sequence)
+}
+
+// syncChange builds a change feed entry; the cursor is assigned when it is
+// stored.
+func syncChange(companyID, projectID, entityType, entityID string, version int, deleted bool) domain.SyncChange {
+	return domain.SyncChange{
+		ID:         uuid.New().String(),
+		CompanyID:  companyID,
+		ProjectID:  projectID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Version:    version,
+		Deleted:    deleted,
+		ChangedAt:  time.Now().UTC(),
+	}
+}
+
+func taskChanges(task *domain.Task, deleted bool) []domain.SyncChange {
+	changes := []domain.SyncChange{
+		syncChange(task.CompanyID, task.ProjectID, domain.SyncEntityTask, task.ID, task.Version, deleted),
+	}
+	for _, subtask := range task.Subtasks {
+		changes = append(changes, subtaskChange(&subtask, task.ProjectID, deleted))
+	}
+	return changes
+}
+
+func subtaskChange(subtask *domain.Subtask, projectID string, deleted bool) domain.SyncChange {
+	return syncChange(subtask.CompanyID, projectID, domain.SyncEntitySubtask, subtask.ID, subtask.Version, deleted)
+}
+
+func diaryEntryChange(entry *domain.DiaryEntry, deleted bool) domain.SyncChange {
+	return syncChange(entry.CompanyID, entry.ProjectID, domain.SyncEntityDiaryEntry, entry.ID, entry.Version, deleted)
+}
+
+func projectChange(projectID, companyID string, deleted bool) domain.SyncChange {
+	return syncChange(companyID, projectID, domain.SyncEntityProject, projectID, 0, deleted)
+}
+
+// startVersions sets a new task and its subtasks at version 1.
+func startVersions(task *domain.Task) {
+	task.Version = 1
+	for i := range task.Subtasks {
+		task.Subtasks[i].Version = 1
+	}
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"bytes"
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps files on the local filesystem. Its presigned URLs point
+// at the API's /storage routes and are authenticated with an HMAC signature,
+// so clients use them exactly like S3 presigned URLs.
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+	secret  []byte
+}
+
+func NewLocalStorage(baseDir, baseURL, secret string) *LocalStorage {
+	return &LocalStorage{
+		baseDir: baseDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}
+}
+
+func (s *LocalStorage) PresignUpload(key, contentType string, expires time.Duration) (*domain.PresignedUpload, error) {
+	expiresAt := time.Now().Add(expires)
+	return &domain.PresignedUpload{
+		URL:       s.signedURL(http.MethodPut, key, expiresAt, nil),
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *LocalStorage) PresignDownload(key, fileName string, expires time.Duration) (string, error) {
+	return s.signedURL(http.MethodGet, key, time.Now().Add(expires), url.Values{"name": {fileName}}), nil
+}
+
+func (s *LocalStorage) Stat(key string) (*ports.StoredFile, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	return &ports.StoredFile{
+		Size:        info.Size(),
+		ContentType: http.DetectContentType(head[:n]),
+	}, nil
+}
+
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalStorage) Put(key, contentType string, data []byte) error {
+	return s.Write(key, bytes.NewReader(data))
+}
+
+func (s *LocalStorage) PublicURL(key string) string {
+	return s.baseURL + "/storage/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// VerifySignature checks a signature produced by PresignUpload or
+// PresignDownload for the given method and key.
+func (s *LocalStorage) VerifySignature(method, key string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("signature expired")
+	}
+	expected := s.sign(method, key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func (s *LocalStorage) Write(key string, body io.Reader) error {
+	path, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Path resolves a storage key to a file under the base directory, rejecting
+// keys that would escape it.
+func (s *LocalStorage) Path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid storage key")
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(clean)), nil
+}
+
+func (s *LocalStorage) signedURL(method, key string, expiresAt time.Time, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	expires := expiresAt.Unix()
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(method, key, expires))
+	return s.PublicURL(key) + "?" + query.Encode()
+}
+
+func (s *LocalStorage) sign(method, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%d", method, key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"bytes"
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Storage keeps files in an S3-compatible bucket. Setting an endpoint with
+// path-style addressing makes it work against MinIO.
+type S3Storage struct {
+	client    *s3.Client
+	presigner *s3.PresignClient
+	bucket    string
+	publicURL string
+}
+
+// NewS3Storage connects to the bucket. publicURL is where objects under the
+// public prefix can be read without a signature (a CDN, or the bucket itself
+// with a public-read policy on that prefix); it defaults to the bucket URL.
+func NewS3Storage(ctx context.Context, bucket, endpoint, publicURL string, usePathStyle bool) (*S3Storage, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("S3_BUCKET is required when STORAGE_DRIVER=s3")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		o.UsePathStyle = usePathStyle
+	})
+
+	if publicURL == "" {
+		switch {
+		case endpoint != "" && usePathStyle:
+			publicURL = strings.TrimRight(endpoint, "/") + "/" + bucket
+		case endpoint != "":
+			publicURL = strings.Replace(strings.TrimRight(endpoint, "/"), "://", "://"+bucket+".", 1)
+		default:
+			publicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, cfg.Region)
+		}
+	}
+
+	return &S3Storage{
+		client:    client,
+		presigner: s3.NewPresignClient(client),
+		bucket:    bucket,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}, nil
+}
+
+func (s *S3Storage) PresignUpload(key, contentType string, expires time.Duration) (*domain.PresignedUpload, error) {
+	req, err := s.presigner.PresignPutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name, values := range req.SignedHeader {
+		if name == "Host" || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return &domain.PresignedUpload{
+		URL:       req.URL,
+		Method:    req.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expires),
+	}, nil
+}
+
+func (s *S3Storage) PresignDownload(key, fileName string, expires time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(context.Background(), &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(mime.FormatMediaType("inline", map[string]string{"filename": fileName})),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+// Stat reports the object size and sniffs its content type from the first
+// bytes, since the Content-Type stored with the object is client supplied.
+func (s *S3Storage) Stat(key string) (*ports.StoredFile, error) {
+	ctx := context.Background()
+	head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String("bytes=0-511"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	sniff, err := io.ReadAll(io.LimitReader(out.Body, 512))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.StoredFile{
+		Size:        aws.ToInt64(head.ContentLength),
+		ContentType: http.DetectContentType(sniff),
+	}, nil
+}
+
+func (s *S3Storage) Open(key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *S3Storage) Put(key, contentType string, data []byte) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *S3Storage) PublicURL(key string) string {
+	return s.publicURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func (s *S3Storage) Delete(key string) error {
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
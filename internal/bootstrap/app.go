@@ -1,8 +1,11 @@
 package bootstrap
 
 import (
+	"construct-backend/internal/adapters/captcha"
 	"construct-backend/internal/adapters/handler"
+	"construct-backend/internal/adapters/notification"
 	"construct-backend/internal/adapters/payment"
+	"construct-backend/internal/adapters/report"
 	"construct-backend/internal/adapters/repository"
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
@@ -11,6 +14,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -26,21 +30,31 @@ func NewRouter() (*gin.Engine, error) {
 	}
 
 	var (
-		userRepo      ports.UserRepository
-		projectRepo   ports.ProjectRepository
-		linkRepo      ports.LinkRepository
-		companyRepo   ports.CompanyRepository
-		subRepo       ports.SubscriptionRepository
-		dashboardRepo ports.DashboardRepository
-		clientRepo    ports.ClientRepository
+		userRepo        ports.UserRepository
+		projectRepo     ports.ProjectRepository
+		linkRepo        ports.LinkRepository
+		companyRepo     ports.CompanyRepository
+		subRepo         ports.SubscriptionRepository
+		dashboardRepo   ports.DashboardRepository
+		clientRepo      ports.ClientRepository
+		financialRepo   ports.FinancialRepository
+		materialRepo    ports.MaterialRepository
+		milestoneRepo   ports.MilestoneRepository
+		statusRepo      ports.StatusHistoryRepository
+		templateRepo    ports.TemplateRepository
+		trashRepo       ports.TrashRepository
+		attachmentRepo  ports.AttachmentRepository
+		syncRepo        ports.SyncRepository
+		punchRepo       ports.PunchListRepository
+		inspectionRepo  ports.InspectionRepository
+		changeOrderRepo ports.ChangeOrderRepository
+		quoteRepo       ports.QuoteRepository
+		invoiceRepo     ports.InvoiceRepository
+		measurementRepo ports.MeasurementRepository
+		rateLimitRepo   ports.RateLimitRepository
 	)
 
-	repositoryDriver := os.Getenv("REPOSITORY_DRIVER")
-	if repositoryDriver == "" {
-		repositoryDriver = "dynamodb"
-	}
-
-	switch repositoryDriver {
+	switch driver := repositoryDriver(); driver {
 	case "postgres":
 		pgRepo, err := newPostgresRepository()
 		if err != nil {
@@ -54,6 +68,21 @@ func NewRouter() (*gin.Engine, error) {
 		subRepo = pgRepo
 		dashboardRepo = pgRepo
 		clientRepo = pgRepo
+		financialRepo = pgRepo
+		materialRepo = pgRepo
+		milestoneRepo = pgRepo
+		statusRepo = pgRepo
+		templateRepo = pgRepo
+		trashRepo = pgRepo
+		attachmentRepo = pgRepo
+		syncRepo = pgRepo
+		punchRepo = pgRepo
+		inspectionRepo = pgRepo
+		changeOrderRepo = pgRepo
+		quoteRepo = pgRepo
+		invoiceRepo = pgRepo
+		measurementRepo = pgRepo
+		rateLimitRepo = pgRepo
 	case "dynamodb":
 		dynamoRepo, err := repository.NewDynamoRepositoryFromEnv(context.Background())
 		if err != nil {
@@ -67,17 +96,51 @@ func NewRouter() (*gin.Engine, error) {
 		subRepo = dynamoRepo
 		dashboardRepo = dynamoRepo
 		clientRepo = dynamoRepo
+		financialRepo = dynamoRepo
+		materialRepo = dynamoRepo
+		milestoneRepo = dynamoRepo
+		statusRepo = dynamoRepo
+		templateRepo = dynamoRepo
+		trashRepo = dynamoRepo
+		attachmentRepo = dynamoRepo
+		syncRepo = dynamoRepo
+		punchRepo = dynamoRepo
+		inspectionRepo = dynamoRepo
+		changeOrderRepo = dynamoRepo
+		quoteRepo = dynamoRepo
+		invoiceRepo = dynamoRepo
+		measurementRepo = dynamoRepo
+		rateLimitRepo = dynamoRepo
 	default:
-		return nil, fmt.Errorf("unsupported repository driver %q", repositoryDriver)
+		return nil, fmt.Errorf("unsupported repository driver %q", driver)
+	}
+
+	fileStorage, storageHandler, err := newFileStorage(context.Background(), jwtSecret)
+	if err != nil {
+		return nil, err
 	}
 
 	authService := services.NewAuthService(userRepo, companyRepo, jwtSecret)
-	projectService := services.NewProjectService(projectRepo)
+	projectService := services.NewProjectService(projectRepo, milestoneRepo, statusRepo, userRepo, templateRepo)
 	linkService := services.NewLinkService(linkRepo)
-	userService := services.NewUserService(userRepo, linkRepo)
-	clientService := services.NewClientService(clientRepo)
-	companyService := services.NewCompanyService(companyRepo, linkRepo)
-	dashboardService := services.NewDashboardService(dashboardRepo)
+	userService := services.NewUserService(userRepo, linkRepo, fileStorage)
+	clientService := services.NewClientService(clientRepo, invoiceRepo, companyRepo)
+	companyService := services.NewCompanyService(companyRepo, linkRepo, fileStorage)
+	dashboardService := services.NewDashboardService(dashboardRepo, invoiceRepo, clientRepo, companyRepo)
+	financialService := services.NewFinancialService(financialRepo, projectRepo, changeOrderRepo)
+	materialService := services.NewMaterialService(materialRepo, projectRepo)
+	milestoneService := services.NewMilestoneService(projectRepo, milestoneRepo)
+	templateService := services.NewTemplateService(templateRepo, projectRepo, milestoneRepo)
+	trashService := services.NewTrashService(trashRepo, projectRepo, clientRepo, linkRepo, attachmentRepo, fileStorage)
+	attachmentService := services.NewAttachmentService(attachmentRepo, projectRepo, punchRepo, changeOrderRepo, fileStorage)
+	syncService := services.NewSyncService(projectService, syncRepo)
+	punchListService := services.NewPunchListService(punchRepo, projectRepo, statusRepo, companyRepo, attachmentRepo, fileStorage, report.NewPunchListPDFRenderer())
+	inspectionService := services.NewInspectionService(inspectionRepo, templateRepo, projectRepo, projectService)
+	changeOrderService := services.NewChangeOrderService(changeOrderRepo, projectRepo, milestoneRepo, statusRepo)
+	invoiceService := services.NewInvoiceService(invoiceRepo, projectRepo, milestoneRepo)
+	measurementService := services.NewMeasurementService(measurementRepo, projectRepo, statusRepo, companyRepo, invoiceService, fileStorage, report.NewMeasurementPDFRenderer(), report.NewMeasurementCSVRenderer())
+	quoteService := services.NewQuoteService(quoteRepo, clientRepo, companyRepo, projectRepo, fileStorage, report.NewQuotePDFRenderer())
+	diaryReportService := services.NewDiaryReportService(projectRepo, companyRepo, attachmentRepo, fileStorage, report.NewDiaryPDFRenderer())
 
 	mpToken := os.Getenv("MP_ACCESS_TOKEN")
 	mpSuccessURL := os.Getenv("MP_SUCCESS_URL")
@@ -92,16 +155,69 @@ func NewRouter() (*gin.Engine, error) {
 	gateway := payment.NewMercadoPagoAdapter(mpToken, mpSuccessURL, mpFailureURL)
 	subscriptionService := services.NewSubscriptionService(gateway, companyRepo, subRepo, mpSuccessURL, mpFailureURL)
 
+	// Leads from the public company page are checked against a captcha
+	// provider only when one is configured.
+	var captchaVerifier ports.CaptchaVerifier
+	if captchaSecret := os.Getenv("CAPTCHA_SECRET"); captchaSecret != "" {
+		captchaVerifyURL := os.Getenv("CAPTCHA_VERIFY_URL")
+		if captchaVerifyURL == "" {
+			return nil, fmt.Errorf("CAPTCHA_VERIFY_URL is required when CAPTCHA_SECRET is set")
+		}
+		captchaVerifier = captcha.NewSiteVerifyAdapter(captchaSecret, captchaVerifyURL)
+	}
+	leadService := services.NewLeadService(clientRepo, companyRepo, userRepo, captchaVerifier, notification.NewLogNotifier())
+
 	authHandler := handler.NewAuthHandler(authService)
 	dashboardHandler := handler.NewDashboardHandler(dashboardService)
-	projectHandler := handler.NewProjectHandler(projectService, subscriptionService)
+	projectHandler := handler.NewProjectHandler(projectService, subscriptionService, attachmentService)
 	linkHandler := handler.NewLinkHandler(linkService)
 	userHandler := handler.NewUserHandler(userService)
 	clientHandler := handler.NewClientHandler(clientService)
 	companyHandler := handler.NewCompanyHandler(companyService, userService)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
+	financialHandler := handler.NewFinancialHandler(financialService)
+	materialHandler := handler.NewMaterialHandler(materialService)
+	milestoneHandler := handler.NewMilestoneHandler(milestoneService)
+	templateHandler := handler.NewTemplateHandler(templateService, subscriptionService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	syncHandler := handler.NewSyncHandler(syncService)
+	punchListHandler := handler.NewPunchListHandler(punchListService, attachmentService)
+	inspectionHandler := handler.NewInspectionHandler(inspectionService)
+	changeOrderHandler := handler.NewChangeOrderHandler(changeOrderService, attachmentService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+	measurementHandler := handler.NewMeasurementHandler(measurementService)
+	quoteHandler := handler.NewQuoteHandler(quoteService, subscriptionService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	reportHandler := handler.NewReportHandler(diaryReportService)
+	leadHandler := handler.NewLeadHandler(leadService)
+
+	router := handler.SetupRouter(authHandler, userHandler, dashboardHandler, projectHandler, linkHandler, clientHandler, companyHandler, subscriptionHandler, financialHandler, materialHandler, milestoneHandler, templateHandler, trashHandler, punchListHandler, inspectionHandler, changeOrderHandler, quoteHandler, invoiceHandler, measurementHandler, leadHandler, syncHandler, attachmentHandler, storageHandler, reportHandler, rateLimitRepo, jwtSecret)
+
+	// Client IPs, which rate limits are keyed on, are only taken from
+	// X-Forwarded-For when the request comes from one of TRUSTED_PROXIES
+	// (comma separated IPs or CIDRs). By default no proxy is trusted.
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	return router, nil
+}
+
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
 
-	return handler.SetupRouter(authHandler, userHandler, dashboardHandler, projectHandler, linkHandler, clientHandler, companyHandler, subscriptionHandler, jwtSecret), nil
+func repositoryDriver() string {
+	if driver := os.Getenv("REPOSITORY_DRIVER"); driver != "" {
+		return driver
+	}
+	return "dynamodb"
 }
 
 func newPostgresRepository() (*repository.PostgresRepository, error) {
@@ -118,7 +234,7 @@ func newPostgresRepository() (*repository.PostgresRepository, error) {
 	}
 
 	if os.Getenv("AUTO_MIGRATE") == "true" {
-		if err := db.AutoMigrate(&domain.User{}, &domain.Project{}, &domain.Link{}, &domain.Client{}, &domain.Comment{}, &domain.Task{}, &domain.Subtask{}, &domain.LinkClick{}, &domain.Company{}, &domain.DiaryEntry{}, &domain.DiaryItem{}); err != nil {
+		if err := db.AutoMigrate(&domain.User{}, &domain.Project{}, &domain.Link{}, &domain.Client{}, &domain.Comment{}, &domain.Task{}, &domain.Subtask{}, &domain.TaskDependency{}, &domain.TaskAssignee{}, &domain.LinkClick{}, &domain.Company{}, &domain.DiaryEntry{}, &domain.DiaryItem{}, &domain.DiaryEntryRevision{}, &domain.BudgetLine{}, &domain.Expense{}, &domain.Material{}, &domain.StockMovement{}, &domain.MaterialThreshold{}, &domain.PurchaseRequest{}, &domain.Milestone{}, &domain.StatusTransition{}, &domain.ProjectTemplate{}, &domain.DiaryTemplate{}, &domain.Attachment{}, &domain.SyncChange{}, &domain.SyncCounter{}, &domain.PunchItem{}, &domain.InspectionTemplate{}, &domain.Inspection{}, &domain.ChangeOrder{}, &domain.Quote{}, &domain.QuoteVersion{}, &domain.Invoice{}, &domain.ContractItem{}, &domain.Measurement{}, &domain.RateLimitHit{}); err != nil {
 			return nil, fmt.Errorf("auto migrate Postgres: %w", err)
 		}
 		log.Println("Postgres auto migration completed")
@@ -0,0 +1,76 @@
+package bootstrap
+
+import (
+	"construct-backend/internal/adapters/repository"
+	"construct-backend/internal/core/ports"
+	"construct-backend/internal/core/services"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultTrashRetentionDays = 30
+
+// PurgeTrash permanently deletes projects, clients and links that have been in
+// the trash for longer than TRASH_RETENTION_DAYS (30 by default), along with
+// the stored files of the projects' attachments.
+func PurgeTrash(ctx context.Context) (int, error) {
+	retentionDays := defaultTrashRetentionDays
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid TRASH_RETENTION_DAYS %q", value)
+		}
+		retentionDays = days
+	}
+
+	fileStorage, _, err := newFileStorage(ctx, os.Getenv("JWT_SECRET"))
+	if err != nil {
+		return 0, err
+	}
+
+	var trashService *services.TrashService
+	switch driver := repositoryDriver(); driver {
+	case "postgres":
+		pgRepo, err := newPostgresRepository()
+		if err != nil {
+			return 0, err
+		}
+		trashService = services.NewTrashService(pgRepo, pgRepo, pgRepo, pgRepo, pgRepo, fileStorage)
+	case "dynamodb":
+		dynamoRepo, err := repository.NewDynamoRepositoryFromEnv(ctx)
+		if err != nil {
+			return 0, err
+		}
+		trashService = services.NewTrashService(dynamoRepo, dynamoRepo, dynamoRepo, dynamoRepo, dynamoRepo, fileStorage)
+	default:
+		return 0, fmt.Errorf("unsupported repository driver %q", driver)
+	}
+
+	return trashService.PurgeExpired(time.Duration(retentionDays) * 24 * time.Hour)
+}
+
+// PurgeRateLimits deletes the rate limit counts whose window has ended.
+func PurgeRateLimits(ctx context.Context) (int, error) {
+	var rateLimitRepo ports.RateLimitRepository
+	switch driver := repositoryDriver(); driver {
+	case "postgres":
+		pgRepo, err := newPostgresRepository()
+		if err != nil {
+			return 0, err
+		}
+		rateLimitRepo = pgRepo
+	case "dynamodb":
+		dynamoRepo, err := repository.NewDynamoRepositoryFromEnv(ctx)
+		if err != nil {
+			return 0, err
+		}
+		rateLimitRepo = dynamoRepo
+	default:
+		return 0, fmt.Errorf("unsupported repository driver %q", driver)
+	}
+
+	return rateLimitRepo.PurgeRateLimits(time.Now())
+}
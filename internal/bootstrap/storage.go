@@ -0,0 +1,49 @@
+package bootstrap
+
+import (
+	"construct-backend/internal/adapters/handler"
+	"construct-backend/internal/adapters/storage"
+	"construct-backend/internal/core/ports"
+	"context"
+	"fmt"
+	"os"
+)
+
+// newFileStorage builds the attachment storage selected by STORAGE_DRIVER
+// ("local" by default, or "s3"). The local driver stores files under
+// STORAGE_LOCAL_DIR, which must be set, and also returns the handler
+// serving its presigned URLs; its signatures use STORAGE_SIGNING_KEY, falling
+// back to signingSecret.
+func newFileStorage(ctx context.Context, signingSecret string) (ports.FileStorage, *handler.StorageHandler, error) {
+	driver := os.Getenv("STORAGE_DRIVER")
+	if driver == "" {
+		driver = "local"
+	}
+
+	switch driver {
+	case "local":
+		// No default directory: the working directory is read-only where the
+		// API runs on Lambda, so local storage must be chosen deliberately.
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			return nil, nil, fmt.Errorf("STORAGE_LOCAL_DIR is required when STORAGE_DRIVER=local")
+		}
+		publicURL := os.Getenv("STORAGE_PUBLIC_URL")
+		if publicURL == "" {
+			publicURL = "http://localhost:8080"
+		}
+		if key := os.Getenv("STORAGE_SIGNING_KEY"); key != "" {
+			signingSecret = key
+		}
+		local := storage.NewLocalStorage(dir, publicURL, signingSecret)
+		return local, handler.NewStorageHandler(local), nil
+	case "s3":
+		s3Storage, err := storage.NewS3Storage(ctx, os.Getenv("S3_BUCKET"), os.Getenv("S3_ENDPOINT"), os.Getenv("S3_PUBLIC_URL"), os.Getenv("S3_FORCE_PATH_STYLE") == "true")
+		if err != nil {
+			return nil, nil, err
+		}
+		return s3Storage, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage driver %q", driver)
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	AttachmentEntityProject     = "project"
+	AttachmentEntityTask        = "task"
+	AttachmentEntityDiaryItem   = "diary_item"
+	AttachmentEntityPunchItem   = "punch_item"
+	AttachmentEntityChangeOrder = "change_order"
+
+	AttachmentStatusPending  = "pending"
+	AttachmentStatusUploaded = "uploaded"
+
+	// MaxAttachmentSize is the largest file accepted for an attachment.
+	MaxAttachmentSize int64 = 25 << 20
+)
+
+// attachmentContentTypes lists the accepted MIME types. They are all types
+// http.DetectContentType recognises, so uploads can be checked against their
+// actual content once they land in storage.
+var attachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"video/mp4":       true,
+}
+
+// Attachment is a file stored for a project, one of its tasks, a diary item, a
+// punch item or a change order.
+// Files are uploaded straight to storage through a presigned URL; the record
+// stays pending until the upload is confirmed.
+type Attachment struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	ProjectID   string    `json:"project_id" gorm:"index"`
+	EntityType  string    `json:"entity_type" gorm:"index:idx_attachment_entity"`
+	EntityID    string    `json:"entity_id" gorm:"index:idx_attachment_entity"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	Status      string    `json:"status"`
+	UserID      string    `json:"uploaded_by"`
+	URL         string    `json:"url,omitempty" gorm:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PresignedUpload tells the client how to send a file straight to storage.
+type PresignedUpload struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type AttachmentUpload struct {
+	Attachment *Attachment      `json:"attachment"`
+	Upload     *PresignedUpload `json:"upload"`
+}
+
+func IsAllowedAttachmentType(contentType string) bool {
+	return attachmentContentTypes[contentType]
+}
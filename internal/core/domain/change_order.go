@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	ChangeOrderStatusDraft    = "draft"
+	ChangeOrderStatusSent     = "sent"
+	ChangeOrderStatusApproved = "approved"
+	ChangeOrderStatusRejected = "rejected"
+)
+
+// changeOrderStatusTransitions are the moves the company makes: sending an
+// order to the client, withdrawing it to revise it, and reworking a rejected
+// one. Approval and rejection are the client's, through the public link, and
+// an approved order is final.
+var changeOrderStatusTransitions = map[string][]string{
+	ChangeOrderStatusDraft:    {ChangeOrderStatusSent},
+	ChangeOrderStatusSent:     {ChangeOrderStatusDraft},
+	ChangeOrderStatusApproved: {},
+	ChangeOrderStatusRejected: {ChangeOrderStatusDraft},
+}
+
+// ChangeOrder is a change of scope agreed with the client ("aditivo"). Once
+// approved, CostImpact is added to the budget of its cost category and open
+// tasks and milestones are pushed back by ScheduleImpactDays. Both impacts may
+// be negative when scope is removed. Supporting documents are attachments with
+// the change order entity type. DecisionToken is issued each time the order is
+// sent; the client's decision link carries it on top of the project PIN.
+// ShiftedWork lists the tasks and milestones already pushed back, and
+// ScheduleShiftedAt is set once all of them are, so an interrupted shift
+// resumes without moving anything twice.
+type ChangeOrder struct {
+	ID                 string       `json:"id" gorm:"primaryKey"`
+	ProjectID          string       `json:"project_id" gorm:"index"`
+	CompanyID          string       `json:"company_id" gorm:"index"`
+	Number             int          `json:"number"`
+	Title              string       `json:"title"`
+	Description        string       `json:"description"`
+	Category           string       `json:"category"`
+	CostImpact         float64      `json:"cost_impact"`
+	ScheduleImpactDays int          `json:"schedule_impact_days"`
+	Status             string       `json:"status" gorm:"index"`
+	UserID             string       `json:"created_by"`
+	SentAt             *time.Time   `json:"sent_at,omitempty"`
+	DecisionToken      string       `json:"decision_token,omitempty"`
+	DecidedBy          string       `json:"decided_by,omitempty"`
+	DecidedAt          *time.Time   `json:"decided_at,omitempty"`
+	DecisionNote       string       `json:"decision_note,omitempty"`
+	ShiftedWork        []string     `json:"-" gorm:"serializer:json"`
+	ScheduleShiftedAt  *time.Time   `json:"schedule_shifted_at,omitempty"`
+	Attachments        []Attachment `json:"attachments,omitempty" gorm:"-"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+}
+
+// Locked reports whether the order is out of the company's hands, either
+// waiting on the client or decided.
+func (o *ChangeOrder) Locked() bool {
+	return o.Status != ChangeOrderStatusDraft
+}
+
+// SchedulePending reports whether the order is approved but has not finished
+// pushing back the project's open work.
+func (o *ChangeOrder) SchedulePending() bool {
+	return o.Status == ChangeOrderStatusApproved && o.ScheduleImpactDays != 0 && o.ScheduleShiftedAt == nil
+}
+
+func IsValidChangeOrderStatus(status string) bool {
+	_, ok := changeOrderStatusTransitions[status]
+	return ok
+}
+
+func CanTransitionChangeOrder(from, to string) bool {
+	return containsStatus(changeOrderStatusTransitions[from], to)
+}
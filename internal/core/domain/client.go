@@ -5,17 +5,32 @@ import (
 )
 
 type Client struct {
-	ID         string    `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
-	UserID     string    `bson:"user_id" json:"user_id" datastore:"user_id"`
-	CompanyID  string    `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
-	Name       string    `bson:"name" json:"name" datastore:"name"`
-	Phone      string    `bson:"phone" json:"phone" datastore:"phone"`
-	Address    string    `bson:"address" json:"address" datastore:"address"`
-	Summary    string    `bson:"summary" json:"summary" datastore:"summary"`
-	Comments   []Comment `bson:"comments" json:"comments" datastore:"comments" gorm:"foreignKey:ClientID"`
-	CreatedAt  time.Time `bson:"created_at" json:"created_at" datastore:"created_at"`
-	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at" datastore:"updated_at"`
-	ClickCount int       `bson:"click_count" json:"click_count" datastore:"click_count"`
+	ID         string     `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
+	UserID     string     `bson:"user_id" json:"user_id" datastore:"user_id"`
+	CompanyID  string     `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
+	Name       string     `bson:"name" json:"name" datastore:"name"`
+	Phone      string     `bson:"phone" json:"phone" datastore:"phone"`
+	Address    string     `bson:"address" json:"address" datastore:"address"`
+	Summary    string     `bson:"summary" json:"summary" datastore:"summary"`
+	Comments   []Comment  `bson:"comments" json:"comments" datastore:"comments" gorm:"foreignKey:ClientID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at" datastore:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at" json:"updated_at" datastore:"updated_at"`
+	ClickCount int        `bson:"click_count" json:"click_count" datastore:"click_count"`
+	ArchivedAt *time.Time `bson:"archived_at" json:"archived_at,omitempty" datastore:"archived_at" gorm:"index"`
+	DeletedAt  *time.Time `bson:"deleted_at" json:"deleted_at,omitempty" datastore:"deleted_at" gorm:"index"`
+	// Sales pipeline. Clients without a PipelineStage are plain contacts and
+	// stay out of the pipeline. LostFromStage is the open stage a lost client
+	// was in when it was lost.
+	PipelineStage     string     `bson:"pipeline_stage" json:"pipeline_stage,omitempty" datastore:"pipeline_stage" gorm:"index"`
+	LeadSource        string     `bson:"lead_source" json:"lead_source,omitempty" datastore:"lead_source"`
+	EstimatedValue    float64    `bson:"estimated_value" json:"estimated_value" datastore:"estimated_value"`
+	ExpectedCloseDate *time.Time `bson:"expected_close_date" json:"expected_close_date,omitempty" datastore:"expected_close_date"`
+	LostReason        string     `bson:"lost_reason" json:"lost_reason,omitempty" datastore:"lost_reason"`
+	LostFromStage     string     `bson:"lost_from_stage" json:"lost_from_stage,omitempty" datastore:"lost_from_stage"`
+	StageChangedAt    *time.Time `bson:"stage_changed_at" json:"stage_changed_at,omitempty" datastore:"stage_changed_at"`
+	// Receivables totals the client's invoices across projects. It is only
+	// filled in when a single client is fetched.
+	Receivables *ReceivableTotals `bson:"-" json:"receivables,omitempty" datastore:"-" gorm:"-" dynamodbav:"-"`
 }
 
 type Comment struct {
@@ -5,16 +5,21 @@ import (
 )
 
 type Company struct {
-	ID           string `json:"id" datastore:"-" gorm:"primaryKey"`
-	Name         string `json:"name" datastore:"name"`
-	CNPJ         string `json:"cnpj" datastore:"cnpj" gorm:"uniqueIndex"`
-	Email        string `json:"email" datastore:"email"`
-	Phone        string `json:"phone" datastore:"phone"`
-	Address      string `json:"address" datastore:"address"`
-	Slug         string `json:"slug" datastore:"slug" gorm:"uniqueIndex"`
-	PublicName   string `json:"public_name" datastore:"public_name"`
-	PublicBio    string `json:"public_bio" datastore:"public_bio"`
-	PublicAvatar string `json:"public_avatar" datastore:"public_avatar"`
+	ID                     string            `json:"id" datastore:"-" gorm:"primaryKey"`
+	Name                   string            `json:"name" datastore:"name"`
+	CNPJ                   string            `json:"cnpj" datastore:"cnpj" gorm:"uniqueIndex"`
+	Email                  string            `json:"email" datastore:"email"`
+	Phone                  string            `json:"phone" datastore:"phone"`
+	Address                string            `json:"address" datastore:"address"`
+	Slug                   string            `json:"slug" datastore:"slug" gorm:"uniqueIndex"`
+	PublicName             string            `json:"public_name" datastore:"public_name"`
+	PublicBio              string            `json:"public_bio" datastore:"public_bio"`
+	PublicAvatar           string            `json:"public_avatar" datastore:"public_avatar"`
+	PublicAvatarThumbnails map[string]string `json:"public_avatar_thumbnails" datastore:"public_avatar_thumbnails" gorm:"serializer:json"`
+	PublicAvatarKey        string            `json:"-" datastore:"public_avatar_key"`
+	// PipelineStages are the company's sales pipeline stages; empty means the
+	// defaults.
+	PipelineStages []PipelineStage `json:"pipeline_stages,omitempty" datastore:"pipeline_stages" gorm:"serializer:json"`
 	// Subscription fields
 	Plan           string     `json:"plan" gorm:"default:free"`          // free | pro | enterprise
 	PlanStatus     string     `json:"plan_status" gorm:"default:active"` // active | inactive
@@ -25,10 +30,11 @@ type Company struct {
 }
 
 type PublicCompanyProfile struct {
-	CompanyID  string `json:"company_id"`
-	Slug       string `json:"slug"`
-	PublicName string `json:"public_name"`
-	Bio        string `json:"bio"`
-	Avatar     string `json:"avatar"`
-	Links      []Link `json:"links"`
+	CompanyID        string            `json:"company_id"`
+	Slug             string            `json:"slug"`
+	PublicName       string            `json:"public_name"`
+	Bio              string            `json:"bio"`
+	Avatar           string            `json:"avatar"`
+	AvatarThumbnails map[string]string `json:"avatar_thumbnails"`
+	Links            []Link            `json:"links"`
 }
@@ -1,9 +1,16 @@
 package domain
 
+// DashboardMetrics are the company's headline numbers. AccountsReceivable is
+// what clients still owe on invoices, OverdueReceivables the part of it past
+// due. Pipeline measures how the sales pipeline converts.
 type DashboardMetrics struct {
-	ProjectsInProgress int64 `json:"projects_in_progress"`
-	CompletedProjects  int64 `json:"completed_projects"`
-	ActiveTasks        int64 `json:"active_tasks"`
-	LinkClicks         int64 `json:"link_clicks"`
-	ClientsCount       int64 `json:"clients_count"`
+	ProjectsInProgress int64           `json:"projects_in_progress"`
+	CompletedProjects  int64           `json:"completed_projects"`
+	ActiveTasks        int64           `json:"active_tasks"`
+	LinkClicks         int64           `json:"link_clicks"`
+	ClientsCount       int64           `json:"clients_count"`
+	OverdueMilestones  int64           `json:"overdue_milestones"`
+	AccountsReceivable float64         `json:"accounts_receivable"`
+	OverdueReceivables float64         `json:"overdue_receivables"`
+	Pipeline           PipelineMetrics `json:"pipeline"`
 }
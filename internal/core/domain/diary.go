@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DiaryItemText       = "text"
+	DiaryItemField      = "field"
+	DiaryItemAttachment = "attachment"
+	DiaryItemWeather    = "weather"
+	DiaryItemWorkforce  = "workforce"
+	DiaryItemEquipment  = "equipment"
+	DiaryItemOccurrence = "occurrence"
+	DiaryItemService    = "service"
+
+	WeatherClear  = "clear"
+	WeatherCloudy = "cloudy"
+	WeatherRain   = "rain"
+	WeatherStorm  = "storm"
+
+	OccurrenceIncident = "incident"
+	OccurrenceAccident = "accident"
+	OccurrenceDelay    = "delay"
+	OccurrenceVisit    = "visit"
+	OccurrenceOther    = "other"
+
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+
+	DiaryStatusDraft        = "draft"
+	DiaryStatusSubmitted    = "submitted"
+	DiaryStatusApproved     = "approved"
+	DiaryStatusAcknowledged = "acknowledged"
+)
+
+// diaryStatusTransitions is the sign-off workflow of a diary entry. A
+// submitted entry may be sent back to draft; once approved it is locked and
+// can only be corrected by an amendment.
+var diaryStatusTransitions = map[string][]string{
+	DiaryStatusDraft:        {DiaryStatusSubmitted},
+	DiaryStatusSubmitted:    {DiaryStatusApproved, DiaryStatusDraft},
+	DiaryStatusApproved:     {DiaryStatusAcknowledged},
+	DiaryStatusAcknowledged: {},
+}
+
+var diaryItemTypes = map[string]bool{
+	DiaryItemText:       true,
+	DiaryItemField:      true,
+	DiaryItemAttachment: true,
+	DiaryItemWeather:    true,
+	DiaryItemWorkforce:  true,
+	DiaryItemEquipment:  true,
+	DiaryItemOccurrence: true,
+	DiaryItemService:    true,
+}
+
+var weatherConditions = map[string]bool{
+	WeatherClear:  true,
+	WeatherCloudy: true,
+	WeatherRain:   true,
+	WeatherStorm:  true,
+}
+
+var occurrenceCategories = map[string]bool{
+	OccurrenceIncident: true,
+	OccurrenceAccident: true,
+	OccurrenceDelay:    true,
+	OccurrenceVisit:    true,
+	OccurrenceOther:    true,
+}
+
+var occurrenceSeverities = map[string]bool{
+	SeverityLow:    true,
+	SeverityMedium: true,
+	SeverityHigh:   true,
+}
+
+// DiaryWeather records the weather of the day. Stoppage hours are the time
+// work was halted by rain.
+type DiaryWeather struct {
+	Morning       string  `json:"morning"`
+	Afternoon     string  `json:"afternoon"`
+	RainStoppage  bool    `json:"rain_stoppage"`
+	StoppageHours float64 `json:"stoppage_hours"`
+}
+
+// Rainy reports whether it rained in either period of the day.
+func (w *DiaryWeather) Rainy() bool {
+	return isRain(w.Morning) || isRain(w.Afternoon)
+}
+
+type DiaryWorkforce struct {
+	Trade     string `json:"trade"`
+	Headcount int    `json:"headcount"`
+}
+
+type DiaryEquipment struct {
+	Name     string  `json:"name"`
+	Quantity int     `json:"quantity"`
+	Hours    float64 `json:"hours"`
+}
+
+// DiaryOccurrence classifies an occurrence item; its description is the
+// item's content.
+type DiaryOccurrence struct {
+	Category string `json:"category"`
+	Severity string `json:"severity"`
+}
+
+// DiaryExecutedService is a service carried out on the day, optionally linked
+// to the project task it advances.
+type DiaryExecutedService struct {
+	TaskID      string `json:"task_id,omitempty"`
+	Description string `json:"description"`
+}
+
+// DiarySummary aggregates the structured diary items of a project over a
+// period.
+type DiarySummary struct {
+	ProjectID             string             `json:"project_id"`
+	From                  time.Time          `json:"from"`
+	To                    time.Time          `json:"to"`
+	EntryDays             int                `json:"entry_days"`
+	RainDays              int                `json:"rain_days"`
+	RainStoppageDays      int                `json:"rain_stoppage_days"`
+	StoppageHours         float64            `json:"stoppage_hours"`
+	WorkforceDays         map[string]int     `json:"workforce_days"`
+	AverageHeadcount      float64            `json:"average_headcount"`
+	EquipmentHours        map[string]float64 `json:"equipment_hours"`
+	Occurrences           int                `json:"occurrences"`
+	OccurrencesByCategory map[string]int     `json:"occurrences_by_category"`
+	ServicesExecuted      int                `json:"services_executed"`
+	TasksWorked           []string           `json:"tasks_worked"`
+}
+
+func CanTransitionDiaryEntry(from, to string) bool {
+	return containsStatus(diaryStatusTransitions[from], to)
+}
+
+// NormalizeDiaryStatus treats entries written before the sign-off workflow
+// existed as drafts.
+func NormalizeDiaryStatus(status string) string {
+	if status == "" {
+		return DiaryStatusDraft
+	}
+	return status
+}
+
+// Locked reports whether the entry can no longer be edited or deleted.
+func (e *DiaryEntry) Locked() bool {
+	return NormalizeDiaryStatus(e.Status) != DiaryStatusDraft
+}
+
+// Published reports whether the entry has been approved and may be shown to
+// the client.
+func (e *DiaryEntry) Published() bool {
+	status := NormalizeDiaryStatus(e.Status)
+	return status == DiaryStatusApproved || status == DiaryStatusAcknowledged
+}
+
+func IsDiaryItemType(itemType string) bool {
+	return diaryItemTypes[itemType]
+}
+
+func IsWeatherCondition(condition string) bool {
+	return weatherConditions[condition]
+}
+
+func IsOccurrenceCategory(category string) bool {
+	return occurrenceCategories[category]
+}
+
+func IsOccurrenceSeverity(severity string) bool {
+	return occurrenceSeverities[severity]
+}
+
+func isRain(condition string) bool {
+	return condition == WeatherRain || condition == WeatherStorm
+}
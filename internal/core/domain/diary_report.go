@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DiaryVisibilityAll      = "all"
+	DiaryVisibilityPublic   = "public"
+	DiaryVisibilityInternal = "internal"
+)
+
+// DiaryReport is the data of a printed daily work report (RDO) for a period
+// of a project's diary.
+type DiaryReport struct {
+	Company     *Company
+	Logo        *ReportImage
+	Project     *Project
+	From        *time.Time
+	To          *time.Time
+	Visibility  string
+	Entries     []DiaryEntry
+	Photos      map[string][]ReportImage // by diary item ID
+	Files       map[string][]string      // non-image attachment names, by diary item ID
+	GeneratedAt time.Time
+}
+
+// ReportImage is an image prepared for a report, always JPEG encoded.
+type ReportImage struct {
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
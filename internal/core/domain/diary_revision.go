@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// DiaryEntryRevision is an immutable snapshot of a diary entry's content,
+// written each time the entry is created or edited. Revisions are numbered
+// from 1 per entry.
+type DiaryEntryRevision struct {
+	ID        string      `json:"id" gorm:"primaryKey"`
+	EntryID   string      `json:"entry_id" gorm:"index"`
+	ProjectID string      `json:"project_id" gorm:"index"`
+	CompanyID string      `json:"company_id" gorm:"index"`
+	Revision  int         `json:"revision"`
+	UserID    string      `json:"author_id"`
+	EntryDate time.Time   `json:"entry_date"`
+	Title     string      `json:"title"`
+	Items     []DiaryItem `json:"items" gorm:"serializer:json"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// DiaryRevisionDiff lists what changed between two revisions of an entry.
+// Items are matched by ID.
+type DiaryRevisionDiff struct {
+	EntryID          string            `json:"entry_id"`
+	FromRevision     int               `json:"from_revision"`
+	ToRevision       int               `json:"to_revision"`
+	EntryDateChanged bool              `json:"entry_date_changed"`
+	TitleChanged     bool              `json:"title_changed"`
+	Added            []DiaryItem       `json:"added"`
+	Removed          []DiaryItem       `json:"removed"`
+	Changed          []DiaryItemChange `json:"changed"`
+}
+
+// DiaryItemChange is an item present in both revisions whose content or
+// position differs. Fields names the JSON fields that changed.
+type DiaryItemChange struct {
+	ItemID string    `json:"item_id"`
+	Fields []string  `json:"fields"`
+	Before DiaryItem `json:"before"`
+	After  DiaryItem `json:"after"`
+}
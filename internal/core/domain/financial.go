@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	CostCategoryLabor          = "labor"
+	CostCategoryMaterials      = "materials"
+	CostCategoryEquipment      = "equipment"
+	CostCategorySubcontractors = "subcontractors"
+)
+
+// CostCategories lists the categories accepted for budget lines and expenses,
+// in the order they are reported.
+var CostCategories = []string{
+	CostCategoryLabor,
+	CostCategoryMaterials,
+	CostCategoryEquipment,
+	CostCategorySubcontractors,
+}
+
+func IsValidCostCategory(category string) bool {
+	for _, candidate := range CostCategories {
+		if candidate == category {
+			return true
+		}
+	}
+	return false
+}
+
+type BudgetLine struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	ProjectID   string    `json:"project_id" gorm:"index"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Expense struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	ProjectID   string    `json:"project_id" gorm:"index"`
+	TaskID      string    `json:"task_id,omitempty" gorm:"index"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	UserID      string    `json:"created_by" gorm:"index"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Supplier    string    `json:"supplier"`
+	Amount      float64   `json:"amount"`
+	ExpenseDate time.Time `json:"expense_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CategoryFinancials struct {
+	Category        string  `json:"category"`
+	Budgeted        float64 `json:"budgeted"`
+	Actual          float64 `json:"actual"`
+	Variance        float64 `json:"variance"`
+	ConsumedPercent float64 `json:"consumed_percent"`
+}
+
+type TaskCost struct {
+	TaskID string  `json:"task_id"`
+	Actual float64 `json:"actual"`
+}
+
+// ProjectFinancials compares budget and spending. Budgeted includes the cost
+// impact of approved change orders, which ApprovedChangeOrders totals.
+type ProjectFinancials struct {
+	ProjectID            string               `json:"project_id"`
+	Budgeted             float64              `json:"budgeted"`
+	ApprovedChangeOrders float64              `json:"approved_change_orders"`
+	Actual               float64              `json:"actual"`
+	Variance             float64              `json:"variance"`
+	ConsumedPercent      float64              `json:"consumed_percent"`
+	Categories           []CategoryFinancials `json:"categories"`
+	Tasks                []TaskCost           `json:"tasks"`
+	ExpensesCount        int                  `json:"expenses_count"`
+}
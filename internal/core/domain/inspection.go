@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	InspectionResultPass = "pass"
+	InspectionResultFail = "fail"
+	InspectionResultNA   = "na"
+
+	InspectionStatusInProgress = "in_progress"
+	InspectionStatusCompleted  = "completed"
+)
+
+// InspectionTemplate is a company's checklist for a kind of quality
+// inspection ("concretagem", "impermeabilização", "instalações elétricas").
+type InspectionTemplate struct {
+	ID          string                   `json:"id" gorm:"primaryKey"`
+	CompanyID   string                   `json:"company_id" gorm:"index"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Items       []InspectionTemplateItem `json:"items" gorm:"serializer:json"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+// InspectionTemplateItem is a single check. Guidance tells the inspector what
+// to look for ("cobrimento mínimo de 3 cm").
+type InspectionTemplateItem struct {
+	Label    string `json:"label"`
+	Guidance string `json:"guidance"`
+}
+
+// Inspection is one run of a checklist on a project, optionally about a
+// specific task. The items are copied from the template when the inspection
+// starts, so later changes to the template leave it untouched. The first
+// signature completes the inspection and locks its results.
+type Inspection struct {
+	ID          string                `json:"id" gorm:"primaryKey"`
+	ProjectID   string                `json:"project_id" gorm:"index"`
+	CompanyID   string                `json:"company_id" gorm:"index"`
+	TaskID      string                `json:"task_id,omitempty" gorm:"index"`
+	TemplateID  string                `json:"template_id"`
+	Name        string                `json:"name"`
+	Status      string                `json:"status"`
+	Items       []InspectionItem      `json:"items" gorm:"serializer:json"`
+	Notes       string                `json:"notes"`
+	Signatures  []InspectionSignature `json:"signatures" gorm:"serializer:json"`
+	UserID      string                `json:"created_by"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// InspectionItem is a check of an inspection and its result. An empty result
+// means it has not been checked yet. FollowUpTaskID is the task created to
+// correct the item when it failed.
+type InspectionItem struct {
+	Label          string `json:"label"`
+	Guidance       string `json:"guidance"`
+	Result         string `json:"result"`
+	Notes          string `json:"notes"`
+	FollowUpTaskID string `json:"follow_up_task_id,omitempty"`
+}
+
+type InspectionSignature struct {
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	UserID   string    `json:"user_id"`
+	SignedAt time.Time `json:"signed_at"`
+}
+
+// QualitySummary gathers the inspection results of a project.
+type QualitySummary struct {
+	ProjectID           string                   `json:"project_id"`
+	Inspections         int                      `json:"inspections"`
+	Completed           int                      `json:"completed"`
+	InProgress          int                      `json:"in_progress"`
+	Passed              int                      `json:"passed"`
+	Failed              int                      `json:"failed"`
+	NotApplicable       int                      `json:"not_applicable"`
+	Pending             int                      `json:"pending"`
+	PassPercent         float64                  `json:"pass_percent"`
+	FollowUpTasks       int                      `json:"follow_up_tasks"`
+	OpenFollowUpTasks   int                      `json:"open_follow_up_tasks"`
+	ByTemplate          []QualityTemplateSummary `json:"by_template"`
+	OutstandingFailures []QualityFailure         `json:"outstanding_failures"`
+}
+
+type QualityTemplateSummary struct {
+	TemplateID  string  `json:"template_id"`
+	Name        string  `json:"name"`
+	Inspections int     `json:"inspections"`
+	Passed      int     `json:"passed"`
+	Failed      int     `json:"failed"`
+	PassPercent float64 `json:"pass_percent"`
+}
+
+// QualityFailure is a failed item whose follow-up task is not done yet.
+type QualityFailure struct {
+	InspectionID   string    `json:"inspection_id"`
+	InspectionName string    `json:"inspection_name"`
+	Label          string    `json:"label"`
+	Notes          string    `json:"notes"`
+	FollowUpTaskID string    `json:"follow_up_task_id"`
+	FollowUpStatus string    `json:"follow_up_status"`
+	InspectedAt    time.Time `json:"inspected_at"`
+}
+
+func IsValidInspectionResult(result string) bool {
+	switch result {
+	case InspectionResultPass, InspectionResultFail, InspectionResultNA:
+		return true
+	}
+	return false
+}
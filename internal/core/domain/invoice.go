@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	InvoiceStatusOpen          = "open"
+	InvoiceStatusPartiallyPaid = "partially_paid"
+	InvoiceStatusPaid          = "paid"
+	InvoiceStatusCancelled     = "cancelled"
+)
+
+// Invoice is an installment the client owes on a project ("parcela"). The
+// payment schedule of a project is its invoices in due date order; an
+// installment billing a measurement milestone keeps it in MilestoneID.
+// Payments may be partial, and Status follows AmountPaid. Overdue is not
+// stored: it is set whenever invoices are read, for unpaid invoices past the
+// end of their due date. ClientID is the client billed, taken from the project
+// when the invoice is created. Number is unique within the project, and
+// Version counts the saves of the invoice so a payment recorded against a
+// stale read is refused.
+type Invoice struct {
+	ID          string           `json:"id" gorm:"primaryKey"`
+	ProjectID   string           `json:"project_id" gorm:"index;uniqueIndex:idx_invoices_project_number"`
+	CompanyID   string           `json:"company_id" gorm:"index"`
+	ClientID    string           `json:"client_id" gorm:"index"`
+	MilestoneID string           `json:"milestone_id,omitempty"`
+	Number      int              `json:"number" gorm:"uniqueIndex:idx_invoices_project_number"`
+	Description string           `json:"description"`
+	Amount      float64          `json:"amount"`
+	AmountPaid  float64          `json:"amount_paid"`
+	DueDate     time.Time        `json:"due_date" gorm:"index"`
+	Status      string           `json:"status" gorm:"index"`
+	Payments    []InvoicePayment `json:"payments" gorm:"serializer:json"`
+	Notes       string           `json:"notes"`
+	PaidAt      *time.Time       `json:"paid_at,omitempty"`
+	CancelledAt *time.Time       `json:"cancelled_at,omitempty"`
+	Overdue     bool             `json:"overdue" gorm:"-" dynamodbav:"-"`
+	DaysOverdue int              `json:"days_overdue,omitempty" gorm:"-" dynamodbav:"-"`
+	UserID      string           `json:"created_by"`
+	Version     int              `json:"version" gorm:"not null;default:0"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// InvoicePayment is money received against an invoice.
+type InvoicePayment struct {
+	ID        string    `json:"id"`
+	Amount    float64   `json:"amount"`
+	PaidAt    time.Time `json:"paid_at"`
+	Method    string    `json:"method"`
+	Notes     string    `json:"notes"`
+	UserID    string    `json:"recorded_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReceivableTotals sums invoices. Cancelled invoices are left out; Overdue is
+// the outstanding amount of the overdue ones.
+type ReceivableTotals struct {
+	Invoiced        float64 `json:"invoiced"`
+	Received        float64 `json:"received"`
+	Outstanding     float64 `json:"outstanding"`
+	Overdue         float64 `json:"overdue"`
+	OverdueInvoices int     `json:"overdue_invoices"`
+}
+
+// PaymentSchedule is a project's installments with their totals.
+type PaymentSchedule struct {
+	ProjectID string           `json:"project_id"`
+	Invoices  []Invoice        `json:"invoices"`
+	Totals    ReceivableTotals `json:"totals"`
+}
+
+// Outstanding is what is still owed on the invoice.
+func (i *Invoice) Outstanding() float64 {
+	if i.Status == InvoiceStatusCancelled {
+		return 0
+	}
+	return i.Amount - i.AmountPaid
+}
+
+// IsOverdue reports whether the invoice is unpaid after its due date.
+func (i *Invoice) IsOverdue(now time.Time) bool {
+	return (i.Status == InvoiceStatusOpen || i.Status == InvoiceStatusPartiallyPaid) &&
+		now.After(i.DueDate.AddDate(0, 0, 1))
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	StockMovementReceived    = "received"
+	StockMovementConsumed    = "consumed"
+	StockMovementTransferIn  = "transfer_in"
+	StockMovementTransferOut = "transfer_out"
+
+	PurchaseRequestPending  = "pending"
+	PurchaseRequestApproved = "approved"
+	PurchaseRequestRejected = "rejected"
+	PurchaseRequestReceived = "received"
+)
+
+type Material struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	Name        string    `json:"name"`
+	Unit        string    `json:"unit"`
+	Description string    `json:"description"`
+	MinStock    float64   `json:"min_stock"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type StockMovement struct {
+	ID                   string    `json:"id" gorm:"primaryKey"`
+	CompanyID            string    `json:"company_id" gorm:"index"`
+	ProjectID            string    `json:"project_id" gorm:"index"`
+	MaterialID           string    `json:"material_id" gorm:"index"`
+	Type                 string    `json:"type"`
+	Quantity             float64   `json:"quantity"`
+	TransferID           string    `json:"transfer_id,omitempty"`
+	CounterpartProjectID string    `json:"counterpart_project_id,omitempty"`
+	PurchaseRequestID    string    `json:"purchase_request_id,omitempty"`
+	Notes                string    `json:"notes"`
+	UserID               string    `json:"created_by"`
+	MovementDate         time.Time `json:"movement_date"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// MaterialThreshold overrides the catalog MinStock of a material for one project.
+type MaterialThreshold struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	ProjectID   string    `json:"project_id" gorm:"uniqueIndex:idx_material_thresholds_project_material"`
+	MaterialID  string    `json:"material_id" gorm:"uniqueIndex:idx_material_thresholds_project_material"`
+	MinQuantity float64   `json:"min_quantity"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ProjectMaterialStock struct {
+	MaterialID     string  `json:"material_id"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	Received       float64 `json:"received"`
+	Consumed       float64 `json:"consumed"`
+	TransferredIn  float64 `json:"transferred_in"`
+	TransferredOut float64 `json:"transferred_out"`
+	Balance        float64 `json:"balance"`
+	MinQuantity    float64 `json:"min_quantity"`
+	LowStock       bool    `json:"low_stock"`
+}
+
+type PurchaseRequest struct {
+	ID           string     `json:"id" gorm:"primaryKey"`
+	CompanyID    string     `json:"company_id" gorm:"index"`
+	ProjectID    string     `json:"project_id" gorm:"index"`
+	MaterialID   string     `json:"material_id" gorm:"index"`
+	Quantity     float64    `json:"quantity"`
+	Status       string     `json:"status" gorm:"index"`
+	Notes        string     `json:"notes"`
+	RequestedBy  string     `json:"requested_by"`
+	DecidedBy    string     `json:"decided_by,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	ReceivedAt   *time.Time `json:"received_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	MeasurementStatusDraft     = "draft"
+	MeasurementStatusSubmitted = "submitted"
+	MeasurementStatusApproved  = "approved"
+	MeasurementStatusRejected  = "rejected"
+)
+
+// measurementStatusTransitions are the moves the company makes: submitting a
+// measurement to the client, withdrawing it, and reworking a rejected one.
+// Approval and rejection are the client's, through the public link, and an
+// approved measurement is final.
+var measurementStatusTransitions = map[string][]string{
+	MeasurementStatusDraft:     {MeasurementStatusSubmitted},
+	MeasurementStatusSubmitted: {MeasurementStatusDraft},
+	MeasurementStatusApproved:  {},
+	MeasurementStatusRejected:  {MeasurementStatusDraft},
+}
+
+// ContractItem is a service of the project's contract, measured and paid by
+// quantity ("item da planilha contratual").
+type ContractItem struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	ProjectID   string    `json:"project_id" gorm:"index"`
+	CompanyID   string    `json:"company_id" gorm:"index"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Unit        string    `json:"unit"`
+	Quantity    float64   `json:"quantity"`
+	UnitPrice   float64   `json:"unit_price"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Measurement is a periodic record of executed work ("medição"). Measurements
+// of a project are sequential: a new one starts only once the previous ones
+// are approved, and each lists every contract item with what was executed in
+// the period on top of what earlier measurements approved. Amount is what the
+// period bills; once approved it can be billed as an invoice, kept in
+// InvoiceID. DecisionToken is issued each time the measurement is submitted;
+// the client's decision link carries it on top of the project PIN.
+type Measurement struct {
+	ID                 string            `json:"id" gorm:"primaryKey"`
+	ProjectID          string            `json:"project_id" gorm:"index"`
+	CompanyID          string            `json:"company_id" gorm:"index"`
+	Number             int               `json:"number"`
+	PeriodStart        time.Time         `json:"period_start"`
+	PeriodEnd          time.Time         `json:"period_end"`
+	Items              []MeasurementItem `json:"items" gorm:"serializer:json"`
+	Amount             float64           `json:"amount"`
+	AccumulatedAmount  float64           `json:"accumulated_amount"`
+	ContractTotal      float64           `json:"contract_total"`
+	AccumulatedPercent float64           `json:"accumulated_percent"`
+	Notes              string            `json:"notes"`
+	Status             string            `json:"status" gorm:"index"`
+	SubmittedAt        *time.Time        `json:"submitted_at,omitempty"`
+	DecisionToken      string            `json:"decision_token,omitempty"`
+	DecidedBy          string            `json:"decided_by,omitempty"`
+	DecidedAt          *time.Time        `json:"decided_at,omitempty"`
+	DecisionNote       string            `json:"decision_note,omitempty"`
+	InvoiceID          string            `json:"invoice_id,omitempty"`
+	UserID             string            `json:"created_by"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// MeasurementItem is a contract item as measured: planned quantity, what
+// earlier measurements approved, what was executed in the period, and the
+// accumulated total. Amount bills the period's quantity.
+type MeasurementItem struct {
+	ContractItemID      string  `json:"contract_item_id"`
+	Code                string  `json:"code"`
+	Description         string  `json:"description"`
+	Unit                string  `json:"unit"`
+	UnitPrice           float64 `json:"unit_price"`
+	PlannedQuantity     float64 `json:"planned_quantity"`
+	PreviousQuantity    float64 `json:"previous_quantity"`
+	ExecutedQuantity    float64 `json:"executed_quantity"`
+	AccumulatedQuantity float64 `json:"accumulated_quantity"`
+	AccumulatedPercent  float64 `json:"accumulated_percent"`
+	Amount              float64 `json:"amount"`
+}
+
+// MeasurementReport is the data of a printed measurement.
+type MeasurementReport struct {
+	Company     *Company
+	Logo        *ReportImage
+	Project     *Project
+	Measurement *Measurement
+	GeneratedAt time.Time
+}
+
+// Locked reports whether the measurement is out of the company's hands,
+// either waiting on the client or decided.
+func (m *Measurement) Locked() bool {
+	return m.Status != MeasurementStatusDraft
+}
+
+func IsValidMeasurementStatus(status string) bool {
+	_, ok := measurementStatusTransitions[status]
+	return ok
+}
+
+func CanTransitionMeasurement(from, to string) bool {
+	return containsStatus(measurementStatusTransitions[from], to)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// Milestone groups tasks of a project. Progress is a weighted roll-up of its
+// tasks and is persisted whenever those tasks change.
+type Milestone struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	ProjectID   string     `json:"project_id" gorm:"index"`
+	CompanyID   string     `json:"company_id" gorm:"index"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TargetDate  time.Time  `json:"target_date" gorm:"index"`
+	Weight      float64    `json:"weight"`
+	Progress    float64    `json:"progress"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,105 @@
+package domain
+
+const (
+	PipelineOutcomeOpen = "open"
+	PipelineOutcomeWon  = "won"
+	PipelineOutcomeLost = "lost"
+
+	LeadSourceReferral    = "referral"
+	LeadSourcePublicPage  = "public_page"
+	LeadSourceWebsite     = "website"
+	LeadSourceSocialMedia = "social_media"
+	LeadSourcePhone       = "phone"
+	LeadSourceOther       = "other"
+
+	// LeadSourceUnknown groups clients without a source in the metrics.
+	LeadSourceUnknown = "unknown"
+)
+
+var leadSources = []string{
+	LeadSourceReferral,
+	LeadSourcePublicPage,
+	LeadSourceWebsite,
+	LeadSourceSocialMedia,
+	LeadSourcePhone,
+	LeadSourceOther,
+}
+
+// PipelineStage is a column of a company's sales pipeline. Open stages are
+// worked through in order; a client ends in the won or the lost stage.
+type PipelineStage struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+}
+
+// Pipeline is the kanban view of the clients in the sales pipeline, one
+// column per stage in the company's order.
+type Pipeline struct {
+	Columns []PipelineColumn `json:"columns"`
+}
+
+// PipelineColumn lists the clients in a stage with their estimated value.
+type PipelineColumn struct {
+	Stage   PipelineStage `json:"stage"`
+	Clients []Client      `json:"clients"`
+	Count   int           `json:"count"`
+	Value   float64       `json:"value"`
+}
+
+// PipelineMetrics measure how leads convert. WinRate is the share of closed
+// leads that were won, 0 to 100.
+type PipelineMetrics struct {
+	OpenLeads int                     `json:"open_leads"`
+	OpenValue float64                 `json:"open_value"`
+	Won       int                     `json:"won"`
+	WonValue  float64                 `json:"won_value"`
+	Lost      int                     `json:"lost"`
+	WinRate   float64                 `json:"win_rate"`
+	BySource  []PipelineSourceMetrics `json:"by_source"`
+	ByStage   []PipelineStageMetrics  `json:"by_stage"`
+}
+
+type PipelineSourceMetrics struct {
+	Source   string  `json:"source"`
+	Leads    int     `json:"leads"`
+	Open     int     `json:"open"`
+	Won      int     `json:"won"`
+	Lost     int     `json:"lost"`
+	WonValue float64 `json:"won_value"`
+	WinRate  float64 `json:"win_rate"`
+}
+
+// PipelineStageMetrics is the funnel at a stage: Current clients are in it
+// now, Reached got at least this far, and ConversionRate is the share of
+// those that reached the next stage, 0 to 100. Won clients reached every open
+// stage; lost clients reached the stage they were lost from.
+type PipelineStageMetrics struct {
+	StageID        string  `json:"stage_id"`
+	Name           string  `json:"name"`
+	Outcome        string  `json:"outcome"`
+	Current        int     `json:"current"`
+	Value          float64 `json:"value"`
+	Reached        int     `json:"reached"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// DefaultPipelineStages are used by companies that have not configured their
+// own.
+func DefaultPipelineStages() []PipelineStage {
+	return []PipelineStage{
+		{ID: "lead", Name: "Lead", Outcome: PipelineOutcomeOpen},
+		{ID: "proposal", Name: "Proposta", Outcome: PipelineOutcomeOpen},
+		{ID: "won", Name: "Ganho", Outcome: PipelineOutcomeWon},
+		{ID: "lost", Name: "Perdido", Outcome: PipelineOutcomeLost},
+	}
+}
+
+func IsValidLeadSource(source string) bool {
+	for _, candidate := range leadSources {
+		if candidate == source {
+			return true
+		}
+	}
+	return false
+}
@@ -5,20 +5,25 @@ import (
 )
 
 type Project struct {
-	ID        string    `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
-	Name      string    `bson:"name" json:"name" datastore:"name"`
-	ClientID  string    `bson:"client_id" json:"client_id" datastore:"client_id"`
-	Client    *Client   `bson:"client,omitempty" json:"client,omitempty" datastore:"-" gorm:"foreignKey:ClientID"`
-	StartDate time.Time `bson:"start_date" json:"start_date" datastore:"start_date"`
-	Address   string    `bson:"address" json:"address" datastore:"address"`
-	Summary   string    `bson:"summary" json:"summary" datastore:"summary"`
-	Status    string    `bson:"status" json:"status" datastore:"status"`
-	UserID    string    `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
-	CompanyID string    `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
-	Tasks     []Task    `bson:"tasks" json:"tasks" datastore:"tasks" gorm:"foreignKey:ProjectID"`
-	CreatedAt time.Time `bson:"created_at" json:"created_at" datastore:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at" json:"updated_at" datastore:"updated_at"`
-	IsPublic  bool      `bson:"is_public" json:"is_public" datastore:"is_public"`
+	ID                string             `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
+	Name              string             `bson:"name" json:"name" datastore:"name"`
+	ClientID          string             `bson:"client_id" json:"client_id" datastore:"client_id"`
+	Client            *Client            `bson:"client,omitempty" json:"client,omitempty" datastore:"-" gorm:"foreignKey:ClientID"`
+	StartDate         time.Time          `bson:"start_date" json:"start_date" datastore:"start_date"`
+	Address           string             `bson:"address" json:"address" datastore:"address"`
+	Summary           string             `bson:"summary" json:"summary" datastore:"summary"`
+	Status            string             `bson:"status" json:"status" datastore:"status"`
+	UserID            string             `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
+	CompanyID         string             `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
+	Progress          float64            `bson:"progress" json:"progress" datastore:"progress"`
+	Tasks             []Task             `bson:"tasks" json:"tasks" datastore:"tasks" gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+	Milestones        []Milestone        `bson:"milestones" json:"milestones" datastore:"milestones" gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+	DiaryFieldPresets []DiaryFieldPreset `bson:"diary_field_presets" json:"diary_field_presets" datastore:"diary_field_presets" gorm:"serializer:json"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at" datastore:"created_at"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at" datastore:"updated_at"`
+	IsPublic          bool               `bson:"is_public" json:"is_public" datastore:"is_public"`
+	ArchivedAt        *time.Time         `bson:"archived_at" json:"archived_at,omitempty" datastore:"archived_at" gorm:"index"`
+	DeletedAt         *time.Time         `bson:"deleted_at" json:"deleted_at,omitempty" datastore:"deleted_at" gorm:"index"`
 }
 
 type DiaryEntry struct {
@@ -28,41 +33,118 @@ type DiaryEntry struct {
 	CompanyID string      `json:"company_id" gorm:"index"`
 	EntryDate time.Time   `json:"entry_date"`
 	Title     string      `json:"title"`
-	Items     []DiaryItem `json:"items" gorm:"foreignKey:DiaryEntryID"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	Items     []DiaryItem `json:"items" gorm:"foreignKey:DiaryEntryID;constraint:OnDelete:CASCADE"`
+	// TemplateID is the diary template the entry was created from, whose
+	// required items it must keep.
+	TemplateID string `json:"template_id,omitempty"`
+	// Status follows the sign-off workflow: draft, submitted, approved by an
+	// admin, then acknowledged by the client through the public link.
+	Status         string     `json:"status" gorm:"index"`
+	SubmittedBy    string     `json:"submitted_by,omitempty"`
+	SubmittedAt    *time.Time `json:"submitted_at,omitempty"`
+	ApprovedBy     string     `json:"approved_by,omitempty"`
+	ApproverName   string     `json:"approver_name,omitempty"`
+	ApprovedAt     *time.Time `json:"approved_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	// AmendsEntryID links an amendment to the approved entry it corrects; the
+	// original keeps its content and points back through AmendedByID once the
+	// amendment is approved.
+	AmendsEntryID string    `json:"amends_entry_id,omitempty" gorm:"index"`
+	AmendedByID   string    `json:"amended_by_id,omitempty"`
+	Version       int       `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type DiaryItem struct {
-	ID           string    `json:"id" gorm:"primaryKey"`
-	DiaryEntryID string    `json:"diary_entry_id" gorm:"index"`
-	Type         string    `json:"type"`
-	Label        string    `json:"label"`
-	Content      string    `json:"content"`
-	Visibility   string    `json:"visibility" gorm:"index"`
-	SortOrder    int       `json:"sort_order"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string                 `json:"id" gorm:"primaryKey"`
+	DiaryEntryID string                 `json:"diary_entry_id" gorm:"index"`
+	Type         string                 `json:"type"`
+	Label        string                 `json:"label"`
+	Content      string                 `json:"content"`
+	Visibility   string                 `json:"visibility" gorm:"index"`
+	SortOrder    int                    `json:"sort_order"`
+	Weather      *DiaryWeather          `json:"weather,omitempty" gorm:"serializer:json"`
+	Workforce    []DiaryWorkforce       `json:"workforce,omitempty" gorm:"serializer:json"`
+	Equipment    []DiaryEquipment       `json:"equipment,omitempty" gorm:"serializer:json"`
+	Occurrence   *DiaryOccurrence       `json:"occurrence,omitempty" gorm:"serializer:json"`
+	Services     []DiaryExecutedService `json:"services,omitempty" gorm:"serializer:json"`
+	Attachments  []Attachment           `json:"attachments,omitempty" gorm:"-" dynamodbav:"-"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
 type Task struct {
-	ID        string    `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
-	ProjectID string    `bson:"project_id" json:"project_id" datastore:"project_id"`
-	Name      string    `bson:"name" json:"name" datastore:"name"`
-	DueDate   time.Time `bson:"due_date" json:"due_date" datastore:"due_date"`
-	Status    string    `bson:"status" json:"status" datastore:"status"`
-	UserID    string    `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
-	CompanyID string    `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
-	Subtasks  []Subtask `bson:"subtasks" json:"subtasks" datastore:"subtasks" gorm:"foreignKey:TaskID"`
-	CreatedAt time.Time `bson:"created_at" json:"created_at" datastore:"created_at"`
+	ID           string           `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
+	ProjectID    string           `bson:"project_id" json:"project_id" datastore:"project_id"`
+	Name         string           `bson:"name" json:"name" datastore:"name"`
+	MilestoneID  string           `bson:"milestone_id" json:"milestone_id" datastore:"milestone_id" gorm:"index"`
+	Weight       float64          `bson:"weight" json:"weight" datastore:"weight"`
+	StartDate    time.Time        `bson:"start_date" json:"start_date" datastore:"start_date"`
+	Duration     int              `bson:"duration" json:"duration" datastore:"duration"`
+	DueDate      time.Time        `bson:"due_date" json:"due_date" datastore:"due_date"`
+	Status       string           `bson:"status" json:"status" datastore:"status"`
+	UserID       string           `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
+	CompanyID    string           `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
+	Assignees    []TaskAssignee   `bson:"assignees" json:"assignees" datastore:"assignees" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	Crew         string           `bson:"crew" json:"crew" datastore:"crew"`
+	Subtasks     []Subtask        `bson:"subtasks" json:"subtasks" datastore:"subtasks" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	Dependencies []TaskDependency `bson:"dependencies" json:"dependencies" datastore:"dependencies" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	Version      int              `bson:"version" json:"version" datastore:"version"`
+	CreatedAt    time.Time        `bson:"created_at" json:"created_at" datastore:"created_at"`
+}
+
+const (
+	DependencyFinishToStart = "FS"
+	DependencyStartToStart  = "SS"
+)
+
+// TaskDependency links a task (TaskID) to the predecessor it waits on.
+// LagDays may be negative to allow overlap.
+type TaskDependency struct {
+	ID              string    `json:"id" gorm:"primaryKey"`
+	TaskID          string    `json:"task_id" gorm:"index"`
+	DependsOnTaskID string    `json:"depends_on_task_id" gorm:"index"`
+	ProjectID       string    `json:"project_id" gorm:"index"`
+	CompanyID       string    `json:"company_id" gorm:"index"`
+	Type            string    `json:"type"`
+	LagDays         int       `json:"lag_days"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 type Subtask struct {
-	ID        string    `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
-	TaskID    string    `bson:"task_id" json:"task_id" datastore:"task_id"`
-	Name      string    `bson:"name" json:"name" datastore:"name"`
-	Status    string    `bson:"status" json:"status" datastore:"status"`
-	UserID    string    `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
-	CompanyID string    `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
-	CreatedAt time.Time `bson:"created_at" json:"created_at" datastore:"created_at"`
+	ID        string         `bson:"_id" json:"id" datastore:"-" gorm:"primaryKey"`
+	TaskID    string         `bson:"task_id" json:"task_id" datastore:"task_id"`
+	Name      string         `bson:"name" json:"name" datastore:"name"`
+	Status    string         `bson:"status" json:"status" datastore:"status"`
+	UserID    string         `bson:"user_id" json:"user_id" datastore:"user_id" gorm:"index"`
+	CompanyID string         `bson:"company_id" json:"company_id" datastore:"company_id" gorm:"index"`
+	Assignees []TaskAssignee `bson:"assignees" json:"assignees" datastore:"assignees" gorm:"foreignKey:SubtaskID;constraint:-"`
+	Crew      string         `bson:"crew" json:"crew" datastore:"crew"`
+	Version   int            `bson:"version" json:"version" datastore:"version"`
+	CreatedAt time.Time      `bson:"created_at" json:"created_at" datastore:"created_at"`
+}
+
+// TaskAssignee makes a company member responsible for a task, or for one of
+// its subtasks when SubtaskID is set. SubtaskID is empty for task-level rows,
+// so it carries no foreign key; the TaskID cascade removes both kinds.
+type TaskAssignee struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	TaskID    string    `json:"task_id" gorm:"index"`
+	SubtaskID string    `json:"subtask_id,omitempty" gorm:"index"`
+	ProjectID string    `json:"project_id" gorm:"index"`
+	CompanyID string    `json:"company_id" gorm:"index"`
+	UserID    string    `json:"user_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemberWorkload summarises the open work assigned to a company member.
+type MemberWorkload struct {
+	UserID       string `json:"user_id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	OpenTasks    int    `json:"open_tasks"`
+	OverdueTasks int    `json:"overdue_tasks"`
+	OpenSubtasks int    `json:"open_subtasks"`
 }
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	PunchStatusOpen     = "open"
+	PunchStatusFixed    = "fixed"
+	PunchStatusVerified = "verified"
+
+	PunchPriorityLow    = "low"
+	PunchPriorityMedium = "medium"
+	PunchPriorityHigh   = "high"
+)
+
+// punchStatusTransitions is the life of a punch item: the responsible trade
+// fixes it, the site team verifies the fix, and anything found lacking goes
+// back to open.
+var punchStatusTransitions = map[string][]string{
+	PunchStatusOpen:     {PunchStatusFixed},
+	PunchStatusFixed:    {PunchStatusVerified, PunchStatusOpen},
+	PunchStatusVerified: {PunchStatusOpen},
+}
+
+var punchPriorities = map[string]bool{
+	PunchPriorityLow:    true,
+	PunchPriorityMedium: true,
+	PunchPriorityHigh:   true,
+}
+
+// PunchItem is a defect or pending finish found near handover ("tomada sem
+// espelho na suíte"). Photos are attachments with the punch item entity type.
+// Once verified, the client may approve it through the public project access.
+type PunchItem struct {
+	ID               string       `json:"id" gorm:"primaryKey"`
+	ProjectID        string       `json:"project_id" gorm:"index"`
+	CompanyID        string       `json:"company_id" gorm:"index"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	Floor            string       `json:"floor"`
+	Room             string       `json:"room"`
+	Trade            string       `json:"trade"`
+	Priority         string       `json:"priority"`
+	DueDate          *time.Time   `json:"due_date"`
+	Status           string       `json:"status" gorm:"index"`
+	UserID           string       `json:"created_by"`
+	FixedBy          string       `json:"fixed_by,omitempty"`
+	FixedAt          *time.Time   `json:"fixed_at,omitempty"`
+	VerifiedBy       string       `json:"verified_by,omitempty"`
+	VerifiedAt       *time.Time   `json:"verified_at,omitempty"`
+	ClientApprovedBy string       `json:"client_approved_by,omitempty"`
+	ClientApprovedAt *time.Time   `json:"client_approved_at,omitempty"`
+	Attachments      []Attachment `json:"attachments,omitempty" gorm:"-"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// PunchListReport is the data of a printed punch list for a project.
+type PunchListReport struct {
+	Company     *Company
+	Logo        *ReportImage
+	Project     *Project
+	Status      string
+	Items       []PunchItem
+	Photos      map[string][]ReportImage // by punch item ID
+	Files       map[string][]string      // non-image attachment names, by punch item ID
+	GeneratedAt time.Time
+}
+
+func IsValidPunchStatus(status string) bool {
+	_, ok := punchStatusTransitions[status]
+	return ok
+}
+
+func CanTransitionPunchItem(from, to string) bool {
+	return containsStatus(punchStatusTransitions[from], to)
+}
+
+func IsValidPunchPriority(priority string) bool {
+	return punchPriorities[priority]
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	QuoteStatusDraft    = "draft"
+	QuoteStatusSent     = "sent"
+	QuoteStatusAccepted = "accepted"
+	QuoteStatusRejected = "rejected"
+)
+
+// Quote is a priced proposal for a client ("orçamento"). Items are priced at
+// cost, less their own discounts, and Subtotal adds them up; BDIPercent is the
+// markup covering overhead and profit, and DiscountPercent comes off the
+// marked-up price:
+//
+//	Total = Subtotal + BDIAmount - DiscountAmount
+//
+// Editing a quote the client has already seen starts a new Version; each
+// version is kept as a QuoteVersion when it is sent. The client answers
+// through the public link carrying PublicToken, and an accepted quote can be
+// converted once into a project, whose ID is then kept in ProjectID.
+type Quote struct {
+	ID              string      `json:"id" gorm:"primaryKey"`
+	CompanyID       string      `json:"company_id" gorm:"index"`
+	ClientID        string      `json:"client_id" gorm:"index"`
+	Number          int         `json:"number"`
+	Title           string      `json:"title"`
+	Address         string      `json:"address"`
+	Notes           string      `json:"notes"`
+	ValidUntil      *time.Time  `json:"valid_until"`
+	Items           []QuoteItem `json:"items" gorm:"serializer:json"`
+	BDIPercent      float64     `json:"bdi_percent"`
+	DiscountPercent float64     `json:"discount_percent"`
+	Subtotal        float64     `json:"subtotal"`
+	BDIAmount       float64     `json:"bdi_amount"`
+	DiscountAmount  float64     `json:"discount_amount"`
+	Total           float64     `json:"total"`
+	Version         int         `json:"version"`
+	Status          string      `json:"status" gorm:"index"`
+	PublicToken     string      `json:"public_token,omitempty" gorm:"index"`
+	SentAt          *time.Time  `json:"sent_at,omitempty"`
+	DecidedBy       string      `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time  `json:"decided_at,omitempty"`
+	DecisionNote    string      `json:"decision_note,omitempty"`
+	ProjectID       string      `json:"project_id,omitempty"`
+	UserID          string      `json:"created_by"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// QuoteItem is a priced line. Stage groups lines into the tasks of the
+// project the quote converts into, and Category is the cost category its
+// budget line goes to. DiscountPercent comes off the line's price:
+//
+//	Total = Quantity × UnitPrice - DiscountAmount
+type QuoteItem struct {
+	Stage           string  `json:"stage"`
+	Description     string  `json:"description"`
+	Category        string  `json:"category"`
+	Quantity        float64 `json:"quantity"`
+	Unit            string  `json:"unit"`
+	UnitPrice       float64 `json:"unit_price"`
+	DiscountPercent float64 `json:"discount_percent"`
+	DiscountAmount  float64 `json:"discount_amount"`
+	Total           float64 `json:"total"`
+}
+
+// Cost is the line's direct cost, before its discount.
+func (i QuoteItem) Cost() float64 {
+	return i.Quantity * i.UnitPrice
+}
+
+// QuoteVersion is the content of a quote as it was sent to the client.
+type QuoteVersion struct {
+	ID              string      `json:"id" gorm:"primaryKey"`
+	QuoteID         string      `json:"quote_id" gorm:"index"`
+	CompanyID       string      `json:"company_id" gorm:"index"`
+	Version         int         `json:"version"`
+	Title           string      `json:"title"`
+	Address         string      `json:"address"`
+	Notes           string      `json:"notes"`
+	ValidUntil      *time.Time  `json:"valid_until"`
+	Items           []QuoteItem `json:"items" gorm:"serializer:json"`
+	BDIPercent      float64     `json:"bdi_percent"`
+	DiscountPercent float64     `json:"discount_percent"`
+	Subtotal        float64     `json:"subtotal"`
+	BDIAmount       float64     `json:"bdi_amount"`
+	DiscountAmount  float64     `json:"discount_amount"`
+	Total           float64     `json:"total"`
+	UserID          string      `json:"sent_by"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// QuoteReport is the data of a printed quote.
+type QuoteReport struct {
+	Company     *Company
+	Logo        *ReportImage
+	Client      *Client
+	Quote       *Quote
+	GeneratedAt time.Time
+}
+
+// Expired reports whether the quote's validity has run out by now.
+func (q *Quote) Expired(now time.Time) bool {
+	return q.ValidUntil != nil && now.After(q.ValidUntil.AddDate(0, 0, 1))
+}
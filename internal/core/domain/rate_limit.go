@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+)
+
+// RateLimitHit counts the requests one caller made to a rate limited route in
+// one fixed window. Key names the route, the caller and the window, so a new
+// window starts a new count; ExpiresAt is the end of the window.
+type RateLimitHit struct {
+	Key       string `gorm:"primaryKey"`
+	Count     int
+	ExpiresAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+)
+
+type ScheduledTask struct {
+	TaskID      string    `json:"task_id"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Duration    int       `json:"duration"`
+	EarlyStart  time.Time `json:"early_start"`
+	EarlyFinish time.Time `json:"early_finish"`
+	LateStart   time.Time `json:"late_start"`
+	LateFinish  time.Time `json:"late_finish"`
+	TotalFloat  int       `json:"total_float"`
+	Critical    bool      `json:"critical"`
+	DependsOn   []string  `json:"depends_on"`
+}
+
+type ProjectSchedule struct {
+	ProjectID    string          `json:"project_id"`
+	StartDate    time.Time       `json:"start_date"`
+	FinishDate   time.Time       `json:"finish_date"`
+	Duration     int             `json:"duration"`
+	Tasks        []ScheduledTask `json:"tasks"`
+	CriticalPath []string        `json:"critical_path"`
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ProjectStatusPlanning   = "planning"
+	ProjectStatusInProgress = "in_progress"
+	ProjectStatusPaused     = "paused"
+	ProjectStatusCompleted  = "completed"
+	ProjectStatusCancelled  = "cancelled"
+
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusBlocked    = "blocked"
+	TaskStatusDone       = "done"
+
+	StatusEntityProject     = "project"
+	StatusEntityTask        = "task"
+	StatusEntityDiaryEntry  = "diary_entry"
+	StatusEntityPunchItem   = "punch_item"
+	StatusEntityChangeOrder = "change_order"
+	StatusEntityMeasurement = "measurement"
+)
+
+// Subtasks are a checklist inside their task rather than a workflow of their
+// own: they are only ticked and unticked, have no transition rules and record
+// no status history. The task's status and history carry the workflow.
+const (
+	SubtaskStatusPending   = "Pending"
+	SubtaskStatusCompleted = "Completed"
+)
+
+var projectStatusTransitions = map[string][]string{
+	ProjectStatusPlanning:   {ProjectStatusInProgress, ProjectStatusCancelled},
+	ProjectStatusInProgress: {ProjectStatusPaused, ProjectStatusCompleted, ProjectStatusCancelled},
+	ProjectStatusPaused:     {ProjectStatusInProgress, ProjectStatusCancelled},
+	ProjectStatusCompleted:  {ProjectStatusInProgress},
+	ProjectStatusCancelled:  {ProjectStatusPlanning},
+}
+
+var taskStatusTransitions = map[string][]string{
+	TaskStatusTodo:       {TaskStatusInProgress, TaskStatusBlocked, TaskStatusDone},
+	TaskStatusInProgress: {TaskStatusTodo, TaskStatusBlocked, TaskStatusDone},
+	TaskStatusBlocked:    {TaskStatusTodo, TaskStatusInProgress},
+	TaskStatusDone:       {TaskStatusInProgress},
+}
+
+// StatusTransition records a status change of a project, task, diary entry,
+// punch item, change order or measurement.
+type StatusTransition struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"index:idx_status_transition_entity"`
+	EntityID   string    `json:"entity_id" gorm:"index:idx_status_transition_entity"`
+	ProjectID  string    `json:"project_id" gorm:"index"`
+	CompanyID  string    `json:"company_id" gorm:"index"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	UserID     string    `json:"changed_by"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func IsValidProjectStatus(status string) bool {
+	_, ok := projectStatusTransitions[status]
+	return ok
+}
+
+func IsValidTaskStatus(status string) bool {
+	_, ok := taskStatusTransitions[status]
+	return ok
+}
+
+func CanTransitionProject(from, to string) bool {
+	return containsStatus(projectStatusTransitions[from], to)
+}
+
+func CanTransitionTask(from, to string) bool {
+	return containsStatus(taskStatusTransitions[from], to)
+}
+
+// NormalizeProjectStatus maps values stored before the status workflow
+// existed onto the enum. Unknown values are returned unchanged.
+func NormalizeProjectStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "":
+		return ProjectStatusPlanning
+	case "completed":
+		return ProjectStatusCompleted
+	case "in progress":
+		return ProjectStatusInProgress
+	}
+	return status
+}
+
+// NormalizeTaskStatus maps the legacy "Pending" and "Completed" task statuses
+// onto the enum. Unknown values are returned unchanged.
+func NormalizeTaskStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "", "pending":
+		return TaskStatusTodo
+	case "completed":
+		return TaskStatusDone
+	case "in progress":
+		return TaskStatusInProgress
+	}
+	return status
+}
+
+// ValidSubtaskStatus reports whether status is one a subtask can be in.
+func ValidSubtaskStatus(status string) bool {
+	return status == SubtaskStatusPending || status == SubtaskStatusCompleted
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	SyncEntityDiaryEntry = "diary_entry"
+	SyncEntityTask       = "task"
+	SyncEntitySubtask    = "subtask"
+	// SyncEntityProject marks a change to every synced record of a project at
+	// once, such as moving it to the trash or creating it from a template.
+	SyncEntityProject = "project"
+
+	SyncOperationUpsert = "upsert"
+	SyncOperationDelete = "delete"
+
+	SyncResultApplied  = "applied"
+	SyncResultConflict = "conflict"
+	SyncResultRejected = "rejected"
+)
+
+// SyncChange is one entry of a company's change feed, written whenever a
+// synced record is created, updated or deleted, in the same transaction as the
+// record. Deletes stay in the feed as tombstones. Cursor orders the feed: the
+// company's change sequence number at fixed width.
+type SyncChange struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	CompanyID  string    `json:"company_id" gorm:"index:idx_sync_change_cursor"`
+	Cursor     string    `json:"cursor" gorm:"index:idx_sync_change_cursor"`
+	ProjectID  string    `json:"project_id" gorm:"index"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Version    int       `json:"version"`
+	Deleted    bool      `json:"deleted"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// SyncCounter is the last change sequence number handed out to a company.
+type SyncCounter struct {
+	CompanyID string `gorm:"primaryKey"`
+	Sequence  int64
+}
+
+// SyncResult tells the client what became of one of its changes. Record is
+// the server's copy of the record; on a conflict it is nil when the server
+// deleted it.
+type SyncResult struct {
+	EntityType string `json:"entity_type"`
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	Version    int    `json:"version,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Record     any    `json:"record,omitempty"`
+}
+
+// SyncRecord is the current state of a record changed on the server.
+type SyncRecord struct {
+	EntityType string `json:"entity_type"`
+	ID         string `json:"id"`
+	ProjectID  string `json:"project_id"`
+	Version    int    `json:"version"`
+	Deleted    bool   `json:"deleted"`
+	Record     any    `json:"record,omitempty"`
+}
+
+type SyncResponse struct {
+	Results []SyncResult `json:"results"`
+	Changes []SyncRecord `json:"changes"`
+	Cursor  string       `json:"cursor"`
+	HasMore bool         `json:"has_more"`
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectTemplate is a reusable task plan. Dates are stored as day offsets
+// from the start date of the project instantiated from it.
+type ProjectTemplate struct {
+	ID                string             `json:"id" gorm:"primaryKey"`
+	CompanyID         string             `json:"company_id" gorm:"index"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Tasks             []TemplateTask     `json:"tasks" gorm:"serializer:json"`
+	DiaryFieldPresets []DiaryFieldPreset `json:"diary_field_presets" gorm:"serializer:json"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// TemplateTask is a task of the plan. Tasks without a Weight are created with
+// the default weight.
+type TemplateTask struct {
+	Name            string               `json:"name"`
+	StartOffsetDays int                  `json:"start_offset_days"`
+	DueOffsetDays   int                  `json:"due_offset_days"`
+	Duration        int                  `json:"duration"`
+	Weight          *float64             `json:"weight,omitempty"`
+	Crew            string               `json:"crew"`
+	Subtasks        []TemplateSubtask    `json:"subtasks"`
+	Dependencies    []TemplateDependency `json:"dependencies"`
+}
+
+type TemplateSubtask struct {
+	Name string `json:"name"`
+	Crew string `json:"crew"`
+}
+
+// TemplateDependency points at another task of the same template by its
+// position in ProjectTemplate.Tasks.
+type TemplateDependency struct {
+	TaskIndex int    `json:"task_index"`
+	Type      string `json:"type"`
+	LagDays   int    `json:"lag_days"`
+}
+
+// DiaryFieldPreset is a diary field offered by default when writing entries
+// for a project.
+type DiaryFieldPreset struct {
+	Label      string `json:"label"`
+	Content    string `json:"content"`
+	Visibility string `json:"visibility"`
+}
+
+// DiaryTemplate is a company's standard layout for diary entries. Entries
+// created from it start with its items, in order, and cannot be saved while a
+// required item is missing or empty.
+type DiaryTemplate struct {
+	ID          string              `json:"id" gorm:"primaryKey"`
+	CompanyID   string              `json:"company_id" gorm:"index"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Items       []DiaryTemplateItem `json:"items" gorm:"serializer:json"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// DiaryTemplateItem is matched to the items of an entry by type and label.
+// Content is the default text of text and field items.
+type DiaryTemplateItem struct {
+	Type       string `json:"type"`
+	Label      string `json:"label"`
+	Content    string `json:"content"`
+	Visibility string `json:"visibility"`
+	Required   bool   `json:"required"`
+}
+
+// ProjectBundle is a project with its children, created in one go when a
+// project is cloned, instantiated from a template or converted from a quote.
+// Transitions records the initial statuses.
+type ProjectBundle struct {
+	Project     *Project
+	Tasks       []Task
+	Milestones  []Milestone
+	BudgetLines []BudgetLine
+	Transitions []StatusTransition
+}
@@ -0,0 +1,9 @@
+package domain
+
+// Trash groups the soft-deleted records of a company. Items stay here until
+// they are restored or purged by the retention job.
+type Trash struct {
+	Projects []Project `json:"projects"`
+	Clients  []Client  `json:"clients"`
+	Links    []Link    `json:"links"`
+}
@@ -0,0 +1,28 @@
+package ports
+
+import "construct-backend/internal/core/domain"
+
+// CaptchaVerifier checks the captcha token a prospect solved on the public
+// company page. Implementations talk to a specific provider.
+type CaptchaVerifier interface {
+	Verify(token, remoteIP string) (bool, error)
+}
+
+// LeadNotifier tells the company's admins about a lead captured on the public
+// page. Delivery is best effort: the lead is kept even when it fails.
+type LeadNotifier interface {
+	NotifyNewLead(company *domain.Company, admins []domain.User, lead *domain.Client, message string) error
+}
+
+// PublicLeadInput is what a prospect submits on the public company page.
+type PublicLeadInput struct {
+	Name         string
+	Phone        string
+	Message      string
+	CaptchaToken string
+	RemoteIP     string
+}
+
+type LeadService interface {
+	CreatePublicLead(slug string, input PublicLeadInput) (*domain.Client, error)
+}
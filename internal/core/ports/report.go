@@ -0,0 +1,23 @@
+package ports
+
+import "construct-backend/internal/core/domain"
+
+// DiaryReportRenderer turns a diary report into a printable document.
+type DiaryReportRenderer interface {
+	RenderDiaryReport(report *domain.DiaryReport) ([]byte, error)
+}
+
+// PunchListReportRenderer turns a punch list report into a printable document.
+type PunchListReportRenderer interface {
+	RenderPunchListReport(report *domain.PunchListReport) ([]byte, error)
+}
+
+// QuoteReportRenderer turns a quote into a printable proposal.
+type QuoteReportRenderer interface {
+	RenderQuoteReport(report *domain.QuoteReport) ([]byte, error)
+}
+
+// MeasurementReportRenderer turns a measurement into an exported document.
+type MeasurementReportRenderer interface {
+	RenderMeasurementReport(report *domain.MeasurementReport) ([]byte, error)
+}
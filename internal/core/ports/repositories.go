@@ -2,9 +2,18 @@ package ports
 
 import (
 	"construct-backend/internal/core/domain"
+	"errors"
 	"time"
 )
 
+// ErrClientInUse is returned when permanently deleting a client that projects,
+// including trashed ones, quotes or invoices still reference.
+var ErrClientInUse = errors.New("client in use")
+
+// ErrVersionConflict is returned when writing a versioned record that was
+// changed by someone else since it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
 type UserRepository interface {
 	CreateUser(user *domain.User) error
 	GetUserByEmail(email string) (*domain.User, error)
@@ -15,34 +24,54 @@ type UserRepository interface {
 	GetUsername(userID string) (string, error)
 	GetPublicProfile(username string) (*domain.PublicProfile, error)
 	UpdateBio(userID, bio string) error
+	UpdateAvatar(user *domain.User) error
 	UpdateProfile(user *domain.User) error
 	UpdatePassword(userID, password string) error
 	ListUsersByCompanyID(companyID string) ([]domain.User, error)
 }
 
+// ProjectRepository stores projects and their tasks, subtasks and diary
+// entries. The last three are synced offline: each write bumps the record's
+// Version, fails with ErrVersionConflict when the stored record is no longer
+// at the version it was read at, and lands in the company's change feed in the
+// same transaction.
+//
+// Writes that create a record or change its status take the StatusTransitions
+// recording that, and store them in the same transaction as the record, here
+// and in the other repositories of records with a status history.
 type ProjectRepository interface {
-	CreateProject(project *domain.Project) error
+	CreateProject(project *domain.Project, transitions ...*domain.StatusTransition) error
 	GetAllProjects(companyID string) ([]domain.Project, error)
 	GetProjectsByClientID(clientID, companyID string) ([]domain.Project, error)
 	GetProjectByID(id, companyID string) (*domain.Project, error)
 	GetPublicProjectByID(id string) (*domain.Project, error)
-	UpdateProject(project *domain.Project) error
+	UpdateProject(project *domain.Project, transitions ...*domain.StatusTransition) error
+	// UpdateProjectProgress sets only the project's Progress, leaving edits
+	// made since it was read in place.
+	UpdateProjectProgress(id, companyID string, progress float64) error
 	DeleteProject(id, companyID string) error
-	AddTask(task *domain.Task) error
+	TrashProject(id, companyID string, deletedAt time.Time) error
+	RestoreProject(id, companyID string) error
+	CreateProjectBundle(bundle *domain.ProjectBundle) error
+	AddTask(task *domain.Task, transitions ...*domain.StatusTransition) error
 	AddSubtask(subtask *domain.Subtask) error
-	UpdateTask(task *domain.Task) error
+	UpdateTask(task *domain.Task, transitions ...*domain.StatusTransition) error
 	UpdateSubtask(subtask *domain.Subtask) error
-	UpdateSubtaskByTaskID(taskID string) error
+	UpdateSubtaskByTaskID(taskID, companyID string) error
 	DeleteTask(id, companyID string) error
 	DeleteSubtask(id, companyID string) error
 	GetTaskByID(id, companyID string) (*domain.Task, error)
 	GetSubtaskByID(id, companyID string) (*domain.Subtask, error)
 	GetTasksByProjectID(projectID string) ([]domain.Task, error)
+	GetTasksByCompanyID(companyID string) ([]domain.Task, error)
 	CreateDiaryEntry(entry *domain.DiaryEntry) error
 	GetDiaryEntriesByProject(projectID, companyID string) ([]domain.DiaryEntry, error)
 	GetPublicDiaryEntriesByProject(projectID string) ([]domain.DiaryEntry, error)
 	GetDiaryEntryByID(id, projectID, companyID string) (*domain.DiaryEntry, error)
 	UpdateDiaryEntry(entry *domain.DiaryEntry) error
+	UpdateDiaryEntryStatus(entry *domain.DiaryEntry, transitions ...*domain.StatusTransition) error
+	CreateDiaryRevision(revision *domain.DiaryEntryRevision) error
+	GetDiaryRevisions(entryID, projectID, companyID string) ([]domain.DiaryEntryRevision, error)
 	DeleteDiaryEntry(id, projectID, companyID string) error
 }
 
@@ -52,18 +81,32 @@ type LinkRepository interface {
 	GetLinkAnalytics(companyID string, startDate, endDate *time.Time) ([]domain.LinkAnalyticsItem, error)
 	UpdateLink(link *domain.Link) error
 	DeleteLink(id, companyID string) error
+	TrashLink(id, companyID string, deletedAt time.Time) error
+	RestoreLink(id, companyID string) error
 	RegisterClick(linkID string) error
 }
 
 type ClientRepository interface {
 	CreateClient(client *domain.Client) error
 	GetClientByID(id, companyID string) (*domain.Client, error)
+	// GetClientWithTrashed also finds a client in the trash, for records such
+	// as quotes that keep naming it.
+	GetClientWithTrashed(id, companyID string) (*domain.Client, error)
 	GetAllClients(companyID string) ([]domain.Client, error)
 	UpdateClient(client *domain.Client) error
 	DeleteClient(id, companyID string) error
+	TrashClient(id, companyID string, deletedAt time.Time) error
+	RestoreClient(id, companyID string) error
 	AddComment(comment *domain.Comment) error
 }
 
+// TrashRepository lists soft-deleted projects, clients and links, either for
+// one company or, for the retention job, across all companies.
+type TrashRepository interface {
+	GetTrash(companyID string) (*domain.Trash, error)
+	GetTrashBefore(cutoff time.Time) (*domain.Trash, error)
+}
+
 type CompanyRepository interface {
 	CreateCompany(company *domain.Company) error
 	GetCompanyByID(id string) (*domain.Company, error)
@@ -76,10 +119,178 @@ type SubscriptionRepository interface {
 	CountProjectsByCompany(companyID string) (int64, error)
 }
 
+type FinancialRepository interface {
+	GetBudgetLines(projectID, companyID string) ([]domain.BudgetLine, error)
+	ReplaceBudgetLines(projectID, companyID string, lines []domain.BudgetLine) error
+	CreateExpense(expense *domain.Expense) error
+	GetExpensesByProject(projectID, companyID string) ([]domain.Expense, error)
+	GetExpenseByID(id, projectID, companyID string) (*domain.Expense, error)
+	UpdateExpense(expense *domain.Expense) error
+	DeleteExpense(id, projectID, companyID string) error
+}
+
+type MaterialRepository interface {
+	CreateMaterial(material *domain.Material) error
+	GetMaterialsByCompany(companyID string) ([]domain.Material, error)
+	GetMaterialByID(id, companyID string) (*domain.Material, error)
+	UpdateMaterial(material *domain.Material) error
+	DeleteMaterial(id, companyID string) error
+	CreateStockMovements(movements []domain.StockMovement) error
+	GetStockMovementsByProject(projectID, companyID string) ([]domain.StockMovement, error)
+	SaveMaterialThreshold(threshold *domain.MaterialThreshold) error
+	GetMaterialThresholdsByProject(projectID, companyID string) ([]domain.MaterialThreshold, error)
+	CreatePurchaseRequest(request *domain.PurchaseRequest) error
+	GetPurchaseRequestsByProject(projectID, companyID string) ([]domain.PurchaseRequest, error)
+	GetPurchaseRequestByID(id, projectID, companyID string) (*domain.PurchaseRequest, error)
+	UpdatePurchaseRequest(request *domain.PurchaseRequest) error
+}
+
+type MilestoneRepository interface {
+	CreateMilestone(milestone *domain.Milestone) error
+	GetMilestonesByProject(projectID, companyID string) ([]domain.Milestone, error)
+	GetMilestoneByID(id, projectID, companyID string) (*domain.Milestone, error)
+	UpdateMilestone(milestone *domain.Milestone) error
+	DeleteMilestone(id, projectID, companyID string) error
+}
+
+type PunchListRepository interface {
+	CreatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error
+	GetPunchItemsByProject(projectID, companyID string) ([]domain.PunchItem, error)
+	GetPunchItemByID(id, projectID, companyID string) (*domain.PunchItem, error)
+	UpdatePunchItem(item *domain.PunchItem, transitions ...*domain.StatusTransition) error
+	DeletePunchItem(id, projectID, companyID string) error
+}
+
+type QuoteRepository interface {
+	CreateQuote(quote *domain.Quote) error
+	GetQuotesByCompany(companyID string) ([]domain.Quote, error)
+	GetQuoteByID(id, companyID string) (*domain.Quote, error)
+	GetQuoteByPublicToken(token string) (*domain.Quote, error)
+	UpdateQuote(quote *domain.Quote) error
+	// SetQuoteProject saves the quote's ProjectID, failing with
+	// ErrVersionConflict unless the stored quote still has previous.
+	SetQuoteProject(quote *domain.Quote, previous string) error
+	DeleteQuote(id, companyID string) error
+	CreateQuoteVersion(version *domain.QuoteVersion) error
+	GetQuoteVersions(quoteID, companyID string) ([]domain.QuoteVersion, error)
+}
+
+type ChangeOrderRepository interface {
+	CreateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error
+	GetChangeOrdersByProject(projectID, companyID string) ([]domain.ChangeOrder, error)
+	GetChangeOrderByID(id, projectID, companyID string) (*domain.ChangeOrder, error)
+	UpdateChangeOrder(order *domain.ChangeOrder, transitions ...*domain.StatusTransition) error
+	// DecideChangeOrder saves the client's decision with its transition,
+	// failing with ErrVersionConflict unless the stored order is still at
+	// status previous.
+	DecideChangeOrder(order *domain.ChangeOrder, previous string, transition *domain.StatusTransition) error
+	// ShiftChangeOrderWork saves the tasks and milestones an approved order
+	// pushed back, adds them to its ShiftedWork and sets its
+	// ScheduleShiftedAt. Stores that cannot write it all in one transaction
+	// write it in parts, each with the order, so a failed shift is resumed
+	// from ShiftedWork. A concurrent shift fails with ErrVersionConflict.
+	ShiftChangeOrderWork(order *domain.ChangeOrder, tasks []domain.Task, milestones []domain.Milestone) error
+	DeleteChangeOrder(id, projectID, companyID string) error
+}
+
+type MeasurementRepository interface {
+	CreateContractItem(item *domain.ContractItem) error
+	GetContractItemsByProject(projectID, companyID string) ([]domain.ContractItem, error)
+	GetContractItemByID(id, projectID, companyID string) (*domain.ContractItem, error)
+	UpdateContractItem(item *domain.ContractItem) error
+	DeleteContractItem(id, projectID, companyID string) error
+	CreateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error
+	GetMeasurementsByProject(projectID, companyID string) ([]domain.Measurement, error)
+	GetMeasurementByID(id, projectID, companyID string) (*domain.Measurement, error)
+	UpdateMeasurement(measurement *domain.Measurement, transitions ...*domain.StatusTransition) error
+	// DecideMeasurement saves the client's decision with its transition,
+	// failing with ErrVersionConflict unless the stored measurement is still
+	// at status previous.
+	DecideMeasurement(measurement *domain.Measurement, previous string, transition *domain.StatusTransition) error
+	DeleteMeasurement(id, projectID, companyID string) error
+}
+
+// InvoiceRepository stores project receivables. GetInvoicesByCompany leaves
+// out the invoices of trashed projects. CreateInvoices stores all the invoices
+// or none, failing with ErrVersionConflict when a number is already taken in
+// the project; UpdateInvoice bumps the invoice's Version and fails with
+// ErrVersionConflict when the stored invoice is no longer at the version read.
+type InvoiceRepository interface {
+	CreateInvoices(invoices []domain.Invoice) error
+	GetInvoicesByProject(projectID, companyID string) ([]domain.Invoice, error)
+	GetInvoicesByCompany(companyID string) ([]domain.Invoice, error)
+	GetInvoiceByID(id, projectID, companyID string) (*domain.Invoice, error)
+	UpdateInvoice(invoice *domain.Invoice) error
+	DeleteInvoice(id, projectID, companyID string) error
+}
+
+type InspectionRepository interface {
+	CreateInspection(inspection *domain.Inspection) error
+	GetInspectionsByProject(projectID, companyID string) ([]domain.Inspection, error)
+	GetInspectionByID(id, projectID, companyID string) (*domain.Inspection, error)
+	UpdateInspection(inspection *domain.Inspection) error
+	DeleteInspection(id, projectID, companyID string) error
+}
+
+type TemplateRepository interface {
+	CreateTemplate(template *domain.ProjectTemplate) error
+	GetTemplatesByCompany(companyID string) ([]domain.ProjectTemplate, error)
+	GetTemplateByID(id, companyID string) (*domain.ProjectTemplate, error)
+	UpdateTemplate(template *domain.ProjectTemplate) error
+	DeleteTemplate(id, companyID string) error
+	CreateDiaryTemplate(template *domain.DiaryTemplate) error
+	GetDiaryTemplatesByCompany(companyID string) ([]domain.DiaryTemplate, error)
+	GetDiaryTemplateByID(id, companyID string) (*domain.DiaryTemplate, error)
+	UpdateDiaryTemplate(template *domain.DiaryTemplate) error
+	DeleteDiaryTemplate(id, companyID string) error
+	CreateInspectionTemplate(template *domain.InspectionTemplate) error
+	GetInspectionTemplatesByCompany(companyID string) ([]domain.InspectionTemplate, error)
+	GetInspectionTemplateByID(id, companyID string) (*domain.InspectionTemplate, error)
+	UpdateInspectionTemplate(template *domain.InspectionTemplate) error
+	DeleteInspectionTemplate(id, companyID string) error
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *domain.Attachment) error
+	GetAttachmentsByProject(projectID, companyID string) ([]domain.Attachment, error)
+	GetAttachmentByID(id, projectID, companyID string) (*domain.Attachment, error)
+	UpdateAttachment(attachment *domain.Attachment) error
+	DeleteAttachment(id, projectID, companyID string) error
+}
+
+type StatusHistoryRepository interface {
+	CreateStatusTransition(transition *domain.StatusTransition) error
+	GetStatusTransitions(projectID, entityType, entityID, companyID string) ([]domain.StatusTransition, error)
+}
+
+// RateLimitRepository counts requests for rate limiting in a store shared by
+// every instance of the API.
+type RateLimitRepository interface {
+	// HitRateLimit counts one request against key, which expires at expiresAt,
+	// and returns the requests counted so far.
+	HitRateLimit(key string, expiresAt time.Time) (int, error)
+	// GetRateLimit returns the requests counted against key, without counting
+	// one more. A key never hit has a count of 0.
+	GetRateLimit(key string) (int, error)
+	// PurgeRateLimits deletes the counts that expired before the given time.
+	PurgeRateLimits(before time.Time) (int, error)
+}
+
 type DashboardRepository interface {
 	CountProjectsInProgress(companyID string) (int64, error)
 	CountCompletedProjects(companyID string) (int64, error)
 	CountActiveTasks(companyID string) (int64, error)
 	CountLinkClicksByCompany(companyID string) (int64, error)
 	CountClientsByCompany(companyID string) (int64, error)
+	CountOverdueMilestones(companyID string) (int64, error)
+}
+
+// SyncRepository reads the change feed. Changes are written by the project
+// repository itself, in the same transaction as the synced records.
+type SyncRepository interface {
+	// GetSyncChanges returns up to limit changes of the company after cursor,
+	// in feed order.
+	GetSyncChanges(companyID, cursor string, limit int) ([]domain.SyncChange, error)
+	// GetLatestSyncCursor returns the cursor of the company's last change.
+	GetLatestSyncCursor(companyID string) (string, error)
 }
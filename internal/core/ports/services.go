@@ -1,6 +1,9 @@
 package ports
 
-import "construct-backend/internal/core/domain"
+import (
+	"construct-backend/internal/core/domain"
+	"time"
+)
 
 type AuthService interface {
 	Signup(email, password, name, companyName, cnpj string) (string, error)
@@ -10,29 +13,260 @@ type AuthService interface {
 	VerifyToken(token string) error
 }
 
+// TaskScheduleInput carries the planning fields of a task. Empty dates and nil
+// pointers leave the current values untouched on update, and a nil
+// Dependencies slice keeps the existing dependencies.
+type TaskScheduleInput struct {
+	StartDate    string
+	DueDate      string
+	Duration     *int
+	Dependencies []domain.TaskDependency
+	MilestoneID  *string
+	Weight       *float64
+}
+
+// TaskAssignmentInput carries who is responsible for a task or subtask. A nil
+// AssigneeIDs slice or Crew pointer leaves the current value untouched.
+type TaskAssignmentInput struct {
+	AssigneeIDs []string
+	Crew        *string
+}
+
 type ProjectService interface {
 	CreateProject(companyID, userID, name, clientID, address, summary string, startDate string) (*domain.Project, error)
-	ListProjects(companyID string) ([]domain.Project, error)
-	ListProjectsByClient(clientID, companyID string) ([]domain.Project, error)
+	ListProjects(companyID string, archived bool) ([]domain.Project, error)
+	ListProjectsByClient(clientID, companyID string, archived bool) ([]domain.Project, error)
 	GetProject(id, companyID string) (*domain.Project, error)
 	GetPublicProject(id, pin string) (*domain.Project, error)
 	VerifyPublicProjectPin(id, pin string) error
 	UpdateProject(id, name, clientID, address, summary, startDate string, isPublic bool, companyID string) (*domain.Project, error)
 	DeleteProject(id, companyID string) error
-	AddTask(projectID, name, status, dueDate, companyID, userID string) (*domain.Task, error)
-	AddSubtask(taskID, name, status, companyID, userID string) (*domain.Subtask, error)
-	UpdateTask(id, companyID, status string) (*domain.Task, error)
+	RestoreProject(id, companyID string) error
+	SetProjectArchived(id, companyID string, archived bool) (*domain.Project, error)
+	AddTask(projectID, name, status, companyID, userID string, schedule TaskScheduleInput, assignment TaskAssignmentInput) (*domain.Task, error)
+	AddSubtask(taskID, name, status, companyID, userID string, assignment TaskAssignmentInput) (*domain.Subtask, error)
+	AssignTask(id, companyID string, assignment TaskAssignmentInput) (*domain.Task, error)
+	AssignSubtask(id, companyID string, assignment TaskAssignmentInput) (*domain.Subtask, error)
+	ListMyTasks(userID, companyID, status, dueFrom, dueTo string) ([]domain.Task, error)
+	GetWorkload(companyID string) ([]domain.MemberWorkload, error)
+	UpdateTask(id, companyID, userID, status string, schedule TaskScheduleInput) (*domain.Task, error)
+	ChangeProjectStatus(id, companyID, userID, status, note string) (*domain.Project, error)
+	ChangeTaskStatus(id, companyID, userID, status, note string) (*domain.Task, error)
+	GetProjectStatusHistory(id, companyID string) ([]domain.StatusTransition, error)
+	GetTaskStatusHistory(id, companyID string) ([]domain.StatusTransition, error)
 	UpdateSubtask(id, companyID string) (*domain.Subtask, error)
 	DeleteTask(id, companyID string) error
 	DeleteSubtask(id, companyID string) error
 	GetTask(id, companyID string) (*domain.Task, error)
 	GetSubtask(id, companyID string) (*domain.Subtask, error)
 	ListTasks(projectID string) ([]domain.Task, error)
-	CreateDiaryEntry(projectID, companyID, userID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error)
+	GetSchedule(projectID, companyID string) (*domain.ProjectSchedule, error)
+	CreateDiaryEntry(projectID, companyID, userID, entryDate, title, templateID string, items []domain.DiaryItem) (*domain.DiaryEntry, error)
 	ListDiaryEntries(projectID, companyID string) ([]domain.DiaryEntry, error)
 	ListPublicDiaryEntries(projectID, pin string) ([]domain.DiaryEntry, error)
-	UpdateDiaryEntry(entryID, projectID, companyID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error)
+	UpdateDiaryEntry(entryID, projectID, companyID, userID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error)
 	DeleteDiaryEntry(entryID, projectID, companyID string) error
+	GetDiarySummary(projectID, companyID, month, from, to string) (*domain.DiarySummary, error)
+	SubmitDiaryEntry(entryID, projectID, companyID, userID string) (*domain.DiaryEntry, error)
+	ApproveDiaryEntry(entryID, projectID, companyID, userID string) (*domain.DiaryEntry, error)
+	RejectDiaryEntry(entryID, projectID, companyID, userID, note string) (*domain.DiaryEntry, error)
+	AcknowledgePublicDiaryEntry(projectID, entryID, pin, name string) (*domain.DiaryEntry, error)
+	AmendDiaryEntry(entryID, projectID, companyID, userID, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error)
+	GetDiaryEntryHistory(entryID, projectID, companyID string) ([]domain.StatusTransition, error)
+	ListDiaryRevisions(entryID, projectID, companyID string) ([]domain.DiaryEntryRevision, error)
+	DiffDiaryRevisions(entryID, projectID, companyID string, from, to int) (*domain.DiaryRevisionDiff, error)
+}
+
+type MilestoneService interface {
+	CreateMilestone(projectID, companyID, name, description, targetDate string, weight *float64) (*domain.Milestone, error)
+	ListMilestones(projectID, companyID string) ([]domain.Milestone, error)
+	UpdateMilestone(id, projectID, companyID, name, description, targetDate string, weight *float64) (*domain.Milestone, error)
+	DeleteMilestone(id, projectID, companyID string) error
+}
+
+type TemplateService interface {
+	CreateTemplate(companyID, name, description string, tasks []domain.TemplateTask, presets []domain.DiaryFieldPreset) (*domain.ProjectTemplate, error)
+	ListTemplates(companyID string) ([]domain.ProjectTemplate, error)
+	GetTemplate(id, companyID string) (*domain.ProjectTemplate, error)
+	UpdateTemplate(id, companyID, name, description string, tasks []domain.TemplateTask, presets []domain.DiaryFieldPreset) (*domain.ProjectTemplate, error)
+	DeleteTemplate(id, companyID string) error
+	CloneProject(projectID, companyID, userID, name, startDate string) (*domain.Project, error)
+	CreateProjectFromTemplate(templateID, companyID, userID, name, clientID, address, summary, startDate string) (*domain.Project, error)
+	CreateDiaryTemplate(companyID, name, description string, items []domain.DiaryTemplateItem) (*domain.DiaryTemplate, error)
+	ListDiaryTemplates(companyID string) ([]domain.DiaryTemplate, error)
+	GetDiaryTemplate(id, companyID string) (*domain.DiaryTemplate, error)
+	UpdateDiaryTemplate(id, companyID, name, description string, items []domain.DiaryTemplateItem) (*domain.DiaryTemplate, error)
+	DeleteDiaryTemplate(id, companyID string) error
+	CreateInspectionTemplate(companyID, name, description string, items []domain.InspectionTemplateItem) (*domain.InspectionTemplate, error)
+	ListInspectionTemplates(companyID string) ([]domain.InspectionTemplate, error)
+	GetInspectionTemplate(id, companyID string) (*domain.InspectionTemplate, error)
+	UpdateInspectionTemplate(id, companyID, name, description string, items []domain.InspectionTemplateItem) (*domain.InspectionTemplate, error)
+	DeleteInspectionTemplate(id, companyID string) error
+}
+
+type AttachmentService interface {
+	RequestUpload(projectID, companyID, userID, entityType, entityID, fileName, contentType string, size int64) (*domain.AttachmentUpload, error)
+	CompleteUpload(id, projectID, companyID string) (*domain.Attachment, error)
+	ListAttachments(projectID, companyID, entityType, entityID string) ([]domain.Attachment, error)
+	GetAttachment(id, projectID, companyID string) (*domain.Attachment, error)
+	DeleteAttachment(id, projectID, companyID string) error
+	AttachToDiaryEntries(entries []domain.DiaryEntry) error
+	AttachToPunchItems(items []domain.PunchItem) error
+	AttachToChangeOrders(orders []domain.ChangeOrder) error
+}
+
+type PunchListService interface {
+	CreatePunchItem(projectID, companyID, userID, title, description, floor, room, trade, priority, dueDate string) (*domain.PunchItem, error)
+	ListPunchItems(projectID, companyID, status, trade, floor string) ([]domain.PunchItem, error)
+	GetPunchItem(id, projectID, companyID string) (*domain.PunchItem, error)
+	UpdatePunchItem(id, projectID, companyID, title, description, floor, room, trade, priority, dueDate string) (*domain.PunchItem, error)
+	ChangePunchItemStatus(id, projectID, companyID, userID, status, note string) (*domain.PunchItem, error)
+	GetPunchItemHistory(id, projectID, companyID string) ([]domain.StatusTransition, error)
+	DeletePunchItem(id, projectID, companyID string) error
+	ListPublicPunchItems(projectID, pin string) ([]domain.PunchItem, error)
+	ApprovePublicPunchItem(projectID, itemID, pin, name string) (*domain.PunchItem, error)
+	ExportPunchList(projectID, companyID, status string) ([]byte, error)
+	ExportPublicPunchList(projectID, pin string) ([]byte, error)
+}
+
+// QuoteInput carries the editable fields of a quote. Item totals are computed
+// by the service.
+type QuoteInput struct {
+	ClientID        string
+	Title           string
+	Address         string
+	Notes           string
+	ValidUntil      string
+	Items           []domain.QuoteItem
+	BDIPercent      float64
+	DiscountPercent float64
+}
+
+type QuoteService interface {
+	CreateQuote(companyID, userID string, input QuoteInput) (*domain.Quote, error)
+	ListQuotes(companyID, status, clientID string) ([]domain.Quote, error)
+	GetQuote(id, companyID string) (*domain.Quote, error)
+	UpdateQuote(id, companyID string, input QuoteInput) (*domain.Quote, error)
+	SendQuote(id, companyID, userID string) (*domain.Quote, error)
+	ListQuoteVersions(id, companyID string) ([]domain.QuoteVersion, error)
+	DeleteQuote(id, companyID string) error
+	ExportQuote(id, companyID string) ([]byte, error)
+	ConvertQuote(id, companyID, userID, name, startDate string) (*domain.Project, error)
+	GetPublicQuote(token string) (*domain.Quote, error)
+	ExportPublicQuote(token string) ([]byte, error)
+	DecidePublicQuote(token, name, decision, note string) (*domain.Quote, error)
+}
+
+// ChangeOrderInput carries the editable fields of a change order.
+type ChangeOrderInput struct {
+	Title              string
+	Description        string
+	Category           string
+	CostImpact         float64
+	ScheduleImpactDays int
+}
+
+type ChangeOrderService interface {
+	CreateChangeOrder(projectID, companyID, userID string, input ChangeOrderInput) (*domain.ChangeOrder, error)
+	ListChangeOrders(projectID, companyID, status string) ([]domain.ChangeOrder, error)
+	GetChangeOrder(id, projectID, companyID string) (*domain.ChangeOrder, error)
+	UpdateChangeOrder(id, projectID, companyID string, input ChangeOrderInput) (*domain.ChangeOrder, error)
+	ChangeChangeOrderStatus(id, projectID, companyID, userID, status, note string) (*domain.ChangeOrder, error)
+	GetChangeOrderHistory(id, projectID, companyID string) ([]domain.StatusTransition, error)
+	DeleteChangeOrder(id, projectID, companyID string) error
+	ListPublicChangeOrders(projectID, pin string) ([]domain.ChangeOrder, error)
+	DecidePublicChangeOrder(projectID, orderID, pin, token, name, decision, note string) (*domain.ChangeOrder, error)
+}
+
+// InvoiceInput carries the editable fields of an invoice.
+type InvoiceInput struct {
+	Description string
+	Amount      float64
+	DueDate     string
+	MilestoneID string
+	Notes       string
+}
+
+// PaymentInput is a payment received against an invoice. PaidAt defaults to
+// today.
+type PaymentInput struct {
+	Amount float64
+	PaidAt string
+	Method string
+	Notes  string
+}
+
+type InvoiceService interface {
+	GetPaymentSchedule(projectID, companyID string) (*domain.PaymentSchedule, error)
+	CreatePaymentSchedule(projectID, companyID, userID string, installments []InvoiceInput) ([]domain.Invoice, error)
+	CreateInvoice(projectID, companyID, userID string, input InvoiceInput) (*domain.Invoice, error)
+	GetInvoice(id, projectID, companyID string) (*domain.Invoice, error)
+	UpdateInvoice(id, projectID, companyID string, input InvoiceInput) (*domain.Invoice, error)
+	CancelInvoice(id, projectID, companyID string) (*domain.Invoice, error)
+	DeleteInvoice(id, projectID, companyID string) error
+	RecordPayment(id, projectID, companyID, userID string, input PaymentInput) (*domain.Invoice, error)
+	DeletePayment(id, projectID, companyID, paymentID string) (*domain.Invoice, error)
+	ListOverdueInvoices(companyID string) ([]domain.Invoice, error)
+}
+
+// ContractItemInput carries the editable fields of a contract item.
+type ContractItemInput struct {
+	Code        string
+	Description string
+	Unit        string
+	Quantity    float64
+	UnitPrice   float64
+}
+
+// MeasurementInput carries the editable fields of a measurement. Quantities
+// are the quantities executed in the period by contract item ID; items left
+// out were not worked on.
+type MeasurementInput struct {
+	PeriodStart string
+	PeriodEnd   string
+	Notes       string
+	Quantities  map[string]float64
+}
+
+type MeasurementService interface {
+	CreateContractItem(projectID, companyID string, input ContractItemInput) (*domain.ContractItem, error)
+	ListContractItems(projectID, companyID string) ([]domain.ContractItem, error)
+	UpdateContractItem(id, projectID, companyID string, input ContractItemInput) (*domain.ContractItem, error)
+	DeleteContractItem(id, projectID, companyID string) error
+	CreateMeasurement(projectID, companyID, userID string, input MeasurementInput) (*domain.Measurement, error)
+	ListMeasurements(projectID, companyID, status string) ([]domain.Measurement, error)
+	GetMeasurement(id, projectID, companyID string) (*domain.Measurement, error)
+	UpdateMeasurement(id, projectID, companyID string, input MeasurementInput) (*domain.Measurement, error)
+	ChangeMeasurementStatus(id, projectID, companyID, userID, status, note string) (*domain.Measurement, error)
+	GetMeasurementHistory(id, projectID, companyID string) ([]domain.StatusTransition, error)
+	DeleteMeasurement(id, projectID, companyID string) error
+	BillMeasurement(id, projectID, companyID, userID, dueDate string) (*domain.Invoice, error)
+	ExportMeasurement(id, projectID, companyID, format string) ([]byte, error)
+	ListPublicMeasurements(projectID, pin string) ([]domain.Measurement, error)
+	ExportPublicMeasurement(projectID, measurementID, pin, format string) ([]byte, error)
+	DecidePublicMeasurement(projectID, measurementID, pin, token, name, decision, note string) (*domain.Measurement, error)
+}
+
+// InspectionResultInput is the result of one checklist item, matched to the
+// inspection's items by position.
+type InspectionResultInput struct {
+	Result string
+	Notes  string
+}
+
+type InspectionService interface {
+	StartInspection(projectID, companyID, userID, templateID, taskID string) (*domain.Inspection, error)
+	ListInspections(projectID, companyID, taskID string) ([]domain.Inspection, error)
+	GetInspection(id, projectID, companyID string) (*domain.Inspection, error)
+	RecordResults(id, projectID, companyID, userID, notes string, results []InspectionResultInput) (*domain.Inspection, error)
+	SignInspection(id, projectID, companyID, userID, name, role string) (*domain.Inspection, error)
+	DeleteInspection(id, projectID, companyID string) error
+	GetQualitySummary(projectID, companyID string) (*domain.QualitySummary, error)
+}
+
+type DiaryReportService interface {
+	ExportDiary(projectID, companyID, from, to, visibility string) ([]byte, error)
+	ExportPublicDiary(projectID, pin, from, to string) ([]byte, error)
 }
 
 type LinkService interface {
@@ -41,15 +275,22 @@ type LinkService interface {
 	ListLinks(companyID string) ([]domain.Link, error)
 	GetLinkAnalytics(companyID, startDate, endDate string) (*domain.LinkAnalyticsResponse, error)
 	DeleteLink(id, companyID string) error
+	RestoreLink(id, companyID string) error
 	TrackLinkClick(id string) error
 }
 
+type TrashService interface {
+	ListTrash(companyID string) (*domain.Trash, error)
+	PurgeExpired(retention time.Duration) (int, error)
+}
+
 type UserService interface {
 	VerifyUserName(username string) error
 	UpdateUsername(userID, username string) error
 	GetUsername(userID string) (string, error)
 	GetPublicProfile(username string) (*domain.PublicProfile, error)
 	UpdateBio(userID, bio string) error
+	UploadAvatar(userID string, data []byte) (*domain.User, error)
 	GetProfile(userID string) (*domain.User, error)
 	UpdateProfile(userID, name, email, phone string) error
 	UpdatePassword(userID, oldPassword, newPassword string) error
@@ -60,10 +301,26 @@ type UserService interface {
 type ClientService interface {
 	CreateClient(companyID, userID, name, phone, address, summary string) (*domain.Client, error)
 	GetClient(id, companyID string) (*domain.Client, error)
-	ListClients(companyID string) ([]domain.Client, error)
+	ListClients(companyID string, archived bool) ([]domain.Client, error)
 	UpdateClient(id, name, phone, address, summary, companyID string) (*domain.Client, error)
 	DeleteClient(id, companyID string) error
+	RestoreClient(id, companyID string) error
+	SetClientArchived(id, companyID string, archived bool) (*domain.Client, error)
 	AddComment(clientID, content string) (*domain.Comment, error)
+	GetPipelineStages(companyID string) ([]domain.PipelineStage, error)
+	UpdatePipelineStages(companyID string, stages []domain.PipelineStage) ([]domain.PipelineStage, error)
+	GetPipeline(companyID string) (*domain.Pipeline, error)
+	UpdateClientPipeline(id, companyID string, input ClientPipelineInput) (*domain.Client, error)
+}
+
+// ClientPipelineInput places a client in the sales pipeline. An empty Stage
+// takes the client out of it; LostReason is required by the lost stage.
+type ClientPipelineInput struct {
+	Stage             string
+	LeadSource        string
+	EstimatedValue    float64
+	ExpectedCloseDate string
+	LostReason        string
 }
 
 type CompanyService interface {
@@ -71,9 +328,77 @@ type CompanyService interface {
 	GetCompany(id string) (*domain.Company, error)
 	UpdateCompany(id, name, email, phone, address string) (*domain.Company, error)
 	UpdatePublicPage(companyID, slug, publicName, bio string) (*domain.Company, error)
+	UploadLogo(companyID string, data []byte) (*domain.Company, error)
 	GetPublicPageBySlug(slug string) (*domain.PublicCompanyProfile, error)
 }
 
+type FinancialService interface {
+	GetBudget(projectID, companyID string) ([]domain.BudgetLine, error)
+	SetBudget(projectID, companyID string, lines []domain.BudgetLine) ([]domain.BudgetLine, error)
+	CreateExpense(projectID, companyID, userID, taskID, category, description, supplier, expenseDate string, amount float64) (*domain.Expense, error)
+	ListExpenses(projectID, companyID, taskID string) ([]domain.Expense, error)
+	UpdateExpense(id, projectID, companyID, taskID, category, description, supplier, expenseDate string, amount float64) (*domain.Expense, error)
+	DeleteExpense(id, projectID, companyID string) error
+	GetFinancials(projectID, companyID string) (*domain.ProjectFinancials, error)
+}
+
+type MaterialService interface {
+	CreateMaterial(companyID, name, unit, description string, minStock float64) (*domain.Material, error)
+	ListMaterials(companyID string) ([]domain.Material, error)
+	UpdateMaterial(id, companyID, name, unit, description string, minStock float64) (*domain.Material, error)
+	DeleteMaterial(id, companyID string) error
+	GetProjectStock(projectID, companyID string, lowStockOnly bool) ([]domain.ProjectMaterialStock, error)
+	ListStockMovements(projectID, companyID, materialID string) ([]domain.StockMovement, error)
+	RecordStockMovement(projectID, companyID, userID, materialID, movementType, movementDate, notes string, quantity float64) (*domain.StockMovement, error)
+	TransferStock(projectID, companyID, userID, materialID, toProjectID, movementDate, notes string, quantity float64) ([]domain.StockMovement, error)
+	SetMaterialThreshold(projectID, companyID, materialID string, minQuantity float64) (*domain.MaterialThreshold, error)
+	CreatePurchaseRequest(projectID, companyID, userID, materialID, notes string, quantity float64) (*domain.PurchaseRequest, error)
+	ListPurchaseRequests(projectID, companyID, status string) ([]domain.PurchaseRequest, error)
+	ApprovePurchaseRequest(id, projectID, companyID, userID, note string) (*domain.PurchaseRequest, error)
+	RejectPurchaseRequest(id, projectID, companyID, userID, note string) (*domain.PurchaseRequest, error)
+	ReceivePurchaseRequest(id, projectID, companyID, userID, receivedDate string) (*domain.PurchaseRequest, error)
+}
+
 type DashboardService interface {
 	GetMetrics(companyID string) (*domain.DashboardMetrics, error)
 }
+
+// SyncMutation is a change made offline by a client. ID is the record's ID,
+// generated by the client for new records, and BaseVersion the version the
+// client last saw, 0 for records it created. Only the input matching
+// EntityType is set, and none for deletes.
+type SyncMutation struct {
+	EntityType  string
+	Operation   string
+	ID          string
+	ProjectID   string
+	BaseVersion int
+	DiaryEntry  *SyncDiaryEntryInput
+	Task        *SyncTaskInput
+	Subtask     *SyncSubtaskInput
+}
+
+type SyncDiaryEntryInput struct {
+	EntryDate  string
+	Title      string
+	TemplateID string
+	Items      []domain.DiaryItem
+}
+
+type SyncTaskInput struct {
+	Name       string
+	Status     string
+	Schedule   TaskScheduleInput
+	Assignment TaskAssignmentInput
+}
+
+type SyncSubtaskInput struct {
+	TaskID     string
+	Name       string
+	Status     string
+	Assignment TaskAssignmentInput
+}
+
+type SyncService interface {
+	Sync(companyID, userID, cursor string, mutations []SyncMutation) (*domain.SyncResponse, error)
+}
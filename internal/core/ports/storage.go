@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"construct-backend/internal/core/domain"
+	"io"
+	"time"
+)
+
+// FileStorage is the port for stored files. Attachments are uploaded and
+// downloaded through presigned URLs, so their contents never pass through the
+// services. Files written with Put under the "public/" prefix, such as
+// avatars, are served without a signature at PublicURL.
+type FileStorage interface {
+	PresignUpload(key, contentType string, expires time.Duration) (*domain.PresignedUpload, error)
+	PresignDownload(key, fileName string, expires time.Duration) (string, error)
+	Stat(key string) (*StoredFile, error)
+	Open(key string) (io.ReadCloser, error)
+	Put(key, contentType string, data []byte) error
+	PublicURL(key string) string
+	Delete(key string) error
+}
+
+// PublicStoragePrefix marks keys readable without a signed URL.
+const PublicStoragePrefix = "public/"
+
+// StoredFile describes an object already in storage.
+type StoredFile struct {
+	Size        int64
+	ContentType string
+}
+
+// SignedFileStore is implemented by storage drivers whose presigned URLs point
+// back at this API instead of a storage service of their own.
+type SignedFileStore interface {
+	VerifySignature(method, key string, expires int64, signature string) error
+	Write(key string, body io.Reader) error
+	Path(key string) (string, error)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *ProjectService) AssignTask(id, companyID string, assignment ports.TaskAssignmentInput) (*domain.Task, error) {
+	task, err := s.projectRepo.GetTaskByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found or access denied")
+	}
+
+	if assignment.AssigneeIDs != nil {
+		assignees, err := s.buildAssignees(companyID, task.ProjectID, task.ID, "", assignment.AssigneeIDs)
+		if err != nil {
+			return nil, err
+		}
+		task.Assignees = assignees
+	}
+	if assignment.Crew != nil {
+		task.Crew = strings.TrimSpace(*assignment.Crew)
+	}
+
+	if err := s.projectRepo.UpdateTask(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *ProjectService) AssignSubtask(id, companyID string, assignment ports.TaskAssignmentInput) (*domain.Subtask, error) {
+	subtask, err := s.projectRepo.GetSubtaskByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("subtask not found or access denied")
+	}
+
+	task, err := s.projectRepo.GetTaskByID(subtask.TaskID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found or access denied")
+	}
+
+	if assignment.AssigneeIDs != nil {
+		assignees, err := s.buildAssignees(companyID, task.ProjectID, task.ID, subtask.ID, assignment.AssigneeIDs)
+		if err != nil {
+			return nil, err
+		}
+		subtask.Assignees = assignees
+	}
+	if assignment.Crew != nil {
+		subtask.Crew = strings.TrimSpace(*assignment.Crew)
+	}
+
+	if err := s.projectRepo.UpdateSubtask(subtask); err != nil {
+		return nil, err
+	}
+
+	return subtask, nil
+}
+
+// ListMyTasks returns the tasks assigned to the user, directly or through one
+// of their subtasks, optionally filtered by status and due date range.
+func (s *ProjectService) ListMyTasks(userID, companyID, status, dueFrom, dueTo string) ([]domain.Task, error) {
+	var from, to time.Time
+	if dueFrom != "" {
+		parsed, err := parseDate(dueFrom)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date filter")
+		}
+		from = parsed
+	}
+	if dueTo != "" {
+		parsed, err := parseDate(dueTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date filter")
+		}
+		to = parsed
+	}
+	if status != "" && !domain.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status")
+	}
+
+	tasks, err := s.projectRepo.GetTasksByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Task, 0)
+	for _, task := range tasks {
+		if !isAssignedToTask(task, userID) {
+			continue
+		}
+		if status != "" && domain.NormalizeTaskStatus(task.Status) != status {
+			continue
+		}
+		if !from.IsZero() && task.DueDate.Before(from) {
+			continue
+		}
+		if !to.IsZero() && task.DueDate.After(to) {
+			continue
+		}
+		result = append(result, task)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DueDate.Before(result[j].DueDate)
+	})
+	return result, nil
+}
+
+// GetWorkload counts open and overdue work per company member.
+func (s *ProjectService) GetWorkload(companyID string) ([]domain.MemberWorkload, error) {
+	members, err := s.userRepo.ListUsersByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.projectRepo.GetTasksByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make(map[string]*domain.MemberWorkload, len(members))
+	result := make([]domain.MemberWorkload, len(members))
+	for index, member := range members {
+		result[index] = domain.MemberWorkload{
+			UserID: member.ID,
+			Name:   member.Name,
+			Email:  member.Email,
+		}
+		workloads[member.ID] = &result[index]
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if domain.NormalizeTaskStatus(task.Status) == domain.TaskStatusDone {
+			continue
+		}
+		overdue := !task.DueDate.IsZero() && task.DueDate.Before(now)
+		for _, assignee := range task.Assignees {
+			if workload, ok := workloads[assignee.UserID]; ok {
+				workload.OpenTasks++
+				if overdue {
+					workload.OverdueTasks++
+				}
+			}
+		}
+		for _, subtask := range task.Subtasks {
+			if subtask.Status == domain.SubtaskStatusCompleted {
+				continue
+			}
+			for _, assignee := range subtask.Assignees {
+				if workload, ok := workloads[assignee.UserID]; ok {
+					workload.OpenSubtasks++
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// buildAssignees validates the user IDs against the company members and turns
+// them into assignment records. A nil slice yields no assignees.
+func (s *ProjectService) buildAssignees(companyID, projectID, taskID, subtaskID string, userIDs []string) ([]domain.TaskAssignee, error) {
+	if len(userIDs) == 0 {
+		return []domain.TaskAssignee{}, nil
+	}
+
+	members, err := s.userRepo.ListUsersByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	isMember := make(map[string]bool, len(members))
+	for _, member := range members {
+		isMember[member.ID] = true
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(userIDs))
+	assignees := make([]domain.TaskAssignee, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !isMember[userID] {
+			return nil, fmt.Errorf("assignee is not a company member")
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		assignees = append(assignees, domain.TaskAssignee{
+			ID:        uuid.New().String(),
+			TaskID:    taskID,
+			SubtaskID: subtaskID,
+			ProjectID: projectID,
+			CompanyID: companyID,
+			UserID:    userID,
+			CreatedAt: now,
+		})
+	}
+	return assignees, nil
+}
+
+func isAssignedToTask(task domain.Task, userID string) bool {
+	for _, assignee := range task.Assignees {
+		if assignee.UserID == userID {
+			return true
+		}
+	}
+	for _, subtask := range task.Subtasks {
+		for _, assignee := range subtask.Assignees {
+			if assignee.UserID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	attachmentUploadExpiry   = 15 * time.Minute
+	attachmentDownloadExpiry = 15 * time.Minute
+)
+
+type AttachmentService struct {
+	attachmentRepo  ports.AttachmentRepository
+	projectRepo     ports.ProjectRepository
+	punchRepo       ports.PunchListRepository
+	changeOrderRepo ports.ChangeOrderRepository
+	storage         ports.FileStorage
+}
+
+func NewAttachmentService(attachmentRepo ports.AttachmentRepository, projectRepo ports.ProjectRepository, punchRepo ports.PunchListRepository, changeOrderRepo ports.ChangeOrderRepository, storage ports.FileStorage) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo:  attachmentRepo,
+		projectRepo:     projectRepo,
+		punchRepo:       punchRepo,
+		changeOrderRepo: changeOrderRepo,
+		storage:         storage,
+	}
+}
+
+// RequestUpload registers a pending attachment and returns a presigned URL the
+// client uses to send the file straight to storage.
+func (s *AttachmentService) RequestUpload(projectID, companyID, userID, entityType, entityID, fileName, contentType string, size int64) (*domain.AttachmentUpload, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+	if err := validateAttachmentFile(contentType, size); err != nil {
+		return nil, err
+	}
+
+	if entityType == "" {
+		entityType = domain.AttachmentEntityProject
+	}
+	if entityType == domain.AttachmentEntityProject {
+		entityID = projectID
+	}
+	if err := s.validateAttachmentEntity(projectID, companyID, entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	attachment := &domain.Attachment{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		ProjectID:   projectID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Status:      domain.AttachmentStatusPending,
+		UserID:      userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	attachment.StorageKey = attachmentStorageKey(attachment)
+
+	upload, err := s.storage.PresignUpload(attachment.StorageKey, contentType, attachmentUploadExpiry)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachmentRepo.CreateAttachment(attachment); err != nil {
+		return nil, err
+	}
+
+	return &domain.AttachmentUpload{Attachment: attachment, Upload: upload}, nil
+}
+
+// CompleteUpload checks the stored file against the upload policy and marks
+// the attachment as uploaded. Files that break the policy are removed.
+func (s *AttachmentService) CompleteUpload(id, projectID, companyID string) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	if attachment.Status == domain.AttachmentStatusUploaded {
+		return s.withDownloadURL(attachment)
+	}
+
+	stored, err := s.storage.Stat(attachment.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("file not uploaded")
+	}
+	if stored.Size > domain.MaxAttachmentSize {
+		_ = s.storage.Delete(attachment.StorageKey)
+		return nil, fmt.Errorf("file too large")
+	}
+	if stored.ContentType != attachment.ContentType {
+		_ = s.storage.Delete(attachment.StorageKey)
+		return nil, fmt.Errorf("file content does not match its type")
+	}
+
+	attachment.Size = stored.Size
+	attachment.Status = domain.AttachmentStatusUploaded
+	attachment.UpdatedAt = time.Now()
+	if err := s.attachmentRepo.UpdateAttachment(attachment); err != nil {
+		return nil, err
+	}
+
+	return s.withDownloadURL(attachment)
+}
+
+// ListAttachments returns the uploaded attachments of a project, optionally
+// narrowed to a single task, diary item, punch item or change order.
+func (s *AttachmentService) ListAttachments(projectID, companyID, entityType, entityID string) ([]domain.Attachment, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment.Status != domain.AttachmentStatusUploaded {
+			continue
+		}
+		if entityType != "" && attachment.EntityType != entityType {
+			continue
+		}
+		if entityID != "" && attachment.EntityID != entityID {
+			continue
+		}
+		withURL, err := s.withDownloadURL(&attachment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *withURL)
+	}
+	return result, nil
+}
+
+func (s *AttachmentService) GetAttachment(id, projectID, companyID string) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id, projectID, companyID)
+	if err != nil || attachment.Status != domain.AttachmentStatusUploaded {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	return s.withDownloadURL(attachment)
+}
+
+func (s *AttachmentService) DeleteAttachment(id, projectID, companyID string) error {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id, projectID, companyID)
+	if err != nil {
+		return fmt.Errorf("attachment not found")
+	}
+	// Files of a signed-off diary entry or of a change order put to the client
+	// are part of the record.
+	switch attachment.EntityType {
+	case domain.AttachmentEntityDiaryItem:
+		if entry, err := s.diaryItemEntry(projectID, companyID, attachment.EntityID); err == nil && entry.Locked() {
+			return fmt.Errorf("diary entry is locked")
+		}
+	case domain.AttachmentEntityChangeOrder:
+		if order, err := s.changeOrderRepo.GetChangeOrderByID(attachment.EntityID, projectID, companyID); err == nil && order.Locked() {
+			return fmt.Errorf("change order is locked")
+		}
+	}
+	if err := s.storage.Delete(attachment.StorageKey); err != nil {
+		return err
+	}
+	return s.attachmentRepo.DeleteAttachment(id, projectID, companyID)
+}
+
+// AttachToDiaryEntries fills the attachments of each diary item, with download
+// URLs. Entries must belong to a single project; public listings only carry
+// public items, so attachments of internal items are never exposed there.
+func (s *AttachmentService) AttachToDiaryEntries(entries []domain.DiaryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var items []*domain.DiaryItem
+	for i := range entries {
+		for j := range entries[i].Items {
+			items = append(items, &entries[i].Items[j])
+		}
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return s.attachTo(entries[0].ProjectID, entries[0].CompanyID, domain.AttachmentEntityDiaryItem, ids, func(i int, attachments []domain.Attachment) {
+		items[i].Attachments = attachments
+	})
+}
+
+// AttachToPunchItems fills the photos and other files of each punch item, with
+// download URLs. Items must belong to a single project.
+func (s *AttachmentService) AttachToPunchItems(items []domain.PunchItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	return s.attachTo(items[0].ProjectID, items[0].CompanyID, domain.AttachmentEntityPunchItem, ids, func(i int, attachments []domain.Attachment) {
+		items[i].Attachments = attachments
+	})
+}
+
+// AttachToChangeOrders fills the supporting documents of each change order,
+// with download URLs. Orders must belong to a single project.
+func (s *AttachmentService) AttachToChangeOrders(orders []domain.ChangeOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]string, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+	}
+	return s.attachTo(orders[0].ProjectID, orders[0].CompanyID, domain.AttachmentEntityChangeOrder, ids, func(i int, attachments []domain.Attachment) {
+		orders[i].Attachments = attachments
+	})
+}
+
+// attachTo looks up the uploaded attachments of the project's entities of
+// entityType and hands set, for each of ids, those of that entity with
+// download URLs.
+func (s *AttachmentService) attachTo(projectID, companyID, entityType string, ids []string, set func(i int, attachments []domain.Attachment)) error {
+	attachments, err := s.attachmentRepo.GetAttachmentsByProject(projectID, companyID)
+	if err != nil {
+		return err
+	}
+
+	byEntity := make(map[string][]domain.Attachment)
+	for _, attachment := range attachments {
+		if attachment.EntityType != entityType || attachment.Status != domain.AttachmentStatusUploaded {
+			continue
+		}
+		withURL, err := s.withDownloadURL(&attachment)
+		if err != nil {
+			return err
+		}
+		byEntity[attachment.EntityID] = append(byEntity[attachment.EntityID], *withURL)
+	}
+
+	for i, id := range ids {
+		set(i, byEntity[id])
+	}
+	return nil
+}
+
+func (s *AttachmentService) validateAttachmentEntity(projectID, companyID, entityType, entityID string) error {
+	switch entityType {
+	case domain.AttachmentEntityProject:
+		return nil
+	case domain.AttachmentEntityTask:
+		task, err := s.projectRepo.GetTaskByID(entityID, companyID)
+		if err != nil || task.ProjectID != projectID {
+			return fmt.Errorf("task not found")
+		}
+		return nil
+	case domain.AttachmentEntityDiaryItem:
+		entry, err := s.diaryItemEntry(projectID, companyID, entityID)
+		if err != nil {
+			return err
+		}
+		if entry.Locked() {
+			return fmt.Errorf("diary entry is locked")
+		}
+		return nil
+	case domain.AttachmentEntityPunchItem:
+		if _, err := s.punchRepo.GetPunchItemByID(entityID, projectID, companyID); err != nil {
+			return fmt.Errorf("punch item not found")
+		}
+		return nil
+	case domain.AttachmentEntityChangeOrder:
+		order, err := s.changeOrderRepo.GetChangeOrderByID(entityID, projectID, companyID)
+		if err != nil {
+			return fmt.Errorf("change order not found")
+		}
+		if order.Locked() {
+			return fmt.Errorf("change order is locked")
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid attachment entity")
+}
+
+func (s *AttachmentService) diaryItemEntry(projectID, companyID, itemID string) (*domain.DiaryEntry, error) {
+	entries, err := s.projectRepo.GetDiaryEntriesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		for _, item := range entry.Items {
+			if item.ID == itemID {
+				return &entry, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("diary item not found")
+}
+
+func (s *AttachmentService) withDownloadURL(attachment *domain.Attachment) (*domain.Attachment, error) {
+	url, err := s.storage.PresignDownload(attachment.StorageKey, attachment.FileName, attachmentDownloadExpiry)
+	if err != nil {
+		return nil, err
+	}
+	withURL := *attachment
+	withURL.URL = url
+	return &withURL, nil
+}
+
+func validateAttachmentFile(contentType string, size int64) error {
+	if !domain.IsAllowedAttachmentType(contentType) {
+		return fmt.Errorf("file type not allowed")
+	}
+	if size <= 0 {
+		return fmt.Errorf("invalid file size")
+	}
+	if size > domain.MaxAttachmentSize {
+		return fmt.Errorf("file too large")
+	}
+	return nil
+}
+
+// attachmentStorageKey namespaces files by company and project. The original
+// name is kept, reduced to safe characters, so downloads stay recognisable.
+func attachmentStorageKey(attachment *domain.Attachment) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, path.Base(attachment.FileName))
+	if strings.Trim(name, ".") == "" {
+		name = "file"
+	}
+	return path.Join(attachment.CompanyID, attachment.ProjectID, attachment.ID, name)
+}
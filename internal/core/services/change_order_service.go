@@ -0,0 +1,314 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOrderService struct {
+	changeOrderRepo ports.ChangeOrderRepository
+	projectRepo     ports.ProjectRepository
+	milestoneRepo   ports.MilestoneRepository
+	statusRepo      ports.StatusHistoryRepository
+}
+
+func NewChangeOrderService(changeOrderRepo ports.ChangeOrderRepository, projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository, statusRepo ports.StatusHistoryRepository) *ChangeOrderService {
+	return &ChangeOrderService{
+		changeOrderRepo: changeOrderRepo,
+		projectRepo:     projectRepo,
+		milestoneRepo:   milestoneRepo,
+		statusRepo:      statusRepo,
+	}
+}
+
+// CreateChangeOrder starts a draft numbered after the project's last order.
+func (s *ChangeOrderService) CreateChangeOrder(projectID, companyID, userID string, input ports.ChangeOrderInput) (*domain.ChangeOrder, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	orders, err := s.changeOrderRepo.GetChangeOrdersByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	number := 1
+	for _, order := range orders {
+		number = max(number, order.Number+1)
+	}
+
+	now := time.Now()
+	order := &domain.ChangeOrder{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CompanyID: companyID,
+		Number:    number,
+		Status:    domain.ChangeOrderStatusDraft,
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyChangeOrderInput(order, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.changeOrderRepo.CreateChangeOrder(order, statusTransition(domain.StatusEntityChangeOrder, order.ID, order.ProjectID, order.CompanyID, userID, "", order.Status, "")); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ListChangeOrders returns the change orders of a project in number order,
+// optionally only those with a given status.
+func (s *ChangeOrderService) ListChangeOrders(projectID, companyID, status string) ([]domain.ChangeOrder, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if status != "" && !domain.IsValidChangeOrderStatus(status) {
+		return nil, fmt.Errorf("invalid change order status")
+	}
+
+	orders, err := s.changeOrderRepo.GetChangeOrdersByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ChangeOrder, 0, len(orders))
+	for _, order := range orders {
+		if status == "" || order.Status == status {
+			result = append(result, order)
+		}
+	}
+	return result, nil
+}
+
+func (s *ChangeOrderService) GetChangeOrder(id, projectID, companyID string) (*domain.ChangeOrder, error) {
+	order, err := s.changeOrderRepo.GetChangeOrderByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("change order not found")
+	}
+	return order, nil
+}
+
+// UpdateChangeOrder edits a draft. Orders sent to the client must be
+// withdrawn to draft first, so the client never decides on a moving target.
+func (s *ChangeOrderService) UpdateChangeOrder(id, projectID, companyID string, input ports.ChangeOrderInput) (*domain.ChangeOrder, error) {
+	order, err := s.changeOrderRepo.GetChangeOrderByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("change order not found")
+	}
+	if order.Locked() {
+		return nil, fmt.Errorf("change order is locked")
+	}
+
+	if err := applyChangeOrderInput(order, input); err != nil {
+		return nil, err
+	}
+	order.UpdatedAt = time.Now()
+
+	if err := s.changeOrderRepo.UpdateChangeOrder(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ChangeChangeOrderStatus sends an order to the client or takes it back to
+// draft. Reworking a rejected order clears the client's decision.
+func (s *ChangeOrderService) ChangeChangeOrderStatus(id, projectID, companyID, userID, status, note string) (*domain.ChangeOrder, error) {
+	order, err := s.changeOrderRepo.GetChangeOrderByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("change order not found")
+	}
+
+	if !domain.IsValidChangeOrderStatus(status) {
+		return nil, fmt.Errorf("invalid change order status")
+	}
+	from := order.Status
+	if !domain.CanTransitionChangeOrder(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	now := time.Now()
+	switch status {
+	case domain.ChangeOrderStatusSent:
+		order.SentAt = &now
+		order.DecisionToken = uuid.New().String()
+	case domain.ChangeOrderStatusDraft:
+		order.SentAt = nil
+		order.DecisionToken = ""
+		order.DecidedBy, order.DecidedAt, order.DecisionNote = "", nil, ""
+	}
+	order.Status = status
+	order.UpdatedAt = now
+
+	if err := s.changeOrderRepo.UpdateChangeOrder(order, statusTransition(domain.StatusEntityChangeOrder, order.ID, order.ProjectID, order.CompanyID, userID, from, status, note)); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *ChangeOrderService) GetChangeOrderHistory(id, projectID, companyID string) ([]domain.StatusTransition, error) {
+	if _, err := s.changeOrderRepo.GetChangeOrderByID(id, projectID, companyID); err != nil {
+		return nil, fmt.Errorf("change order not found")
+	}
+
+	return s.statusRepo.GetStatusTransitions(projectID, domain.StatusEntityChangeOrder, id, companyID)
+}
+
+// DeleteChangeOrder only removes drafts; decided orders stay on record.
+func (s *ChangeOrderService) DeleteChangeOrder(id, projectID, companyID string) error {
+	order, err := s.changeOrderRepo.GetChangeOrderByID(id, projectID, companyID)
+	if err != nil {
+		return fmt.Errorf("change order not found")
+	}
+	if order.Locked() {
+		return fmt.Errorf("change order is locked")
+	}
+	return s.changeOrderRepo.DeleteChangeOrder(id, projectID, companyID)
+}
+
+// ListPublicChangeOrders returns the orders sent to the client of a shared
+// project, behind the same PIN as the public diary. Drafts are left out.
+func (s *ChangeOrderService) ListPublicChangeOrders(projectID, pin string) ([]domain.ChangeOrder, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.changeOrderRepo.GetChangeOrdersByProject(projectID, project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ChangeOrder, 0, len(orders))
+	for _, order := range orders {
+		if order.Status != domain.ChangeOrderStatusDraft {
+			order.DecisionToken = ""
+			result = append(result, order)
+		}
+	}
+	return result, nil
+}
+
+// changeOrderDecisions is how clients decide sent change orders.
+var changeOrderDecisions = publicDecisionFlow{
+	entityType: domain.StatusEntityChangeOrder,
+	noun:       "change order",
+	draft:      domain.ChangeOrderStatusDraft,
+	pending:    domain.ChangeOrderStatusSent,
+	approved:   domain.ChangeOrderStatusApproved,
+	rejected:   domain.ChangeOrderStatusRejected,
+}
+
+// DecidePublicChangeOrder records the client approving or rejecting a sent
+// order through the public project link, with the token of the link the order
+// was sent with. Approval pushes the project's open work back by the order's
+// schedule impact; its cost impact shows up in the project financials. If
+// pushing the work back fails part way, repeating the approval finishes it.
+func (s *ChangeOrderService) DecidePublicChangeOrder(projectID, orderID, pin, token, name, decision, note string) (*domain.ChangeOrder, error) {
+	var order *domain.ChangeOrder
+	project, err := changeOrderDecisions.decide(s.projectRepo, projectID, orderID, pin, token, name, decision, note,
+		func(companyID string) (string, string, error) {
+			var err error
+			order, err = s.changeOrderRepo.GetChangeOrderByID(orderID, projectID, companyID)
+			if err != nil {
+				return "", "", err
+			}
+			return order.Status, order.DecisionToken, nil
+		},
+		func(answer publicDecision, transition *domain.StatusTransition) error {
+			order.Status = answer.Status
+			order.DecidedBy = answer.Name
+			order.DecidedAt = &answer.At
+			order.DecisionNote = answer.Note
+			order.UpdatedAt = answer.At
+			return s.changeOrderRepo.DecideChangeOrder(order, domain.ChangeOrderStatusSent, transition)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.SchedulePending() {
+		if err := s.shiftOpenWork(order, project.CompanyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// shiftOpenWork moves the due dates of unfinished tasks and the target dates
+// of unreached milestones by the order's schedule impact, skipping those it
+// already moved. Tasks that have not started yet move as a whole; tasks under
+// way keep their start date. A negative shift never moves a due date before
+// its task's start.
+func (s *ChangeOrderService) shiftOpenWork(order *domain.ChangeOrder, companyID string) error {
+	days := order.ScheduleImpactDays
+	today := truncateToDay(time.Now())
+	shifted := make(map[string]bool, len(order.ShiftedWork))
+	for _, id := range order.ShiftedWork {
+		shifted[id] = true
+	}
+
+	projectTasks, err := s.projectRepo.GetTasksByProjectID(order.ProjectID)
+	if err != nil {
+		return err
+	}
+	var tasks []domain.Task
+	for _, task := range projectTasks {
+		if shifted[task.ID] || domain.NormalizeTaskStatus(task.Status) == domain.TaskStatusDone || task.DueDate.IsZero() {
+			continue
+		}
+		if !task.StartDate.IsZero() && !task.StartDate.Before(today) {
+			task.StartDate = task.StartDate.AddDate(0, 0, days)
+		}
+		task.DueDate = task.DueDate.AddDate(0, 0, days)
+		if !task.StartDate.IsZero() && task.DueDate.Before(task.StartDate) {
+			task.DueDate = task.StartDate
+		}
+		tasks = append(tasks, task)
+	}
+
+	projectMilestones, err := s.milestoneRepo.GetMilestonesByProject(order.ProjectID, companyID)
+	if err != nil {
+		return err
+	}
+	var milestones []domain.Milestone
+	for _, milestone := range projectMilestones {
+		if shifted[milestone.ID] || milestone.CompletedAt != nil || milestone.TargetDate.IsZero() {
+			continue
+		}
+		milestone.TargetDate = milestone.TargetDate.AddDate(0, 0, days)
+		milestone.UpdatedAt = time.Now()
+		milestones = append(milestones, milestone)
+	}
+
+	return s.changeOrderRepo.ShiftChangeOrderWork(order, tasks, milestones)
+}
+
+// applyChangeOrderInput validates and copies the editable fields. A cost
+// category is only needed when the order changes the cost.
+func applyChangeOrderInput(order *domain.ChangeOrder, input ports.ChangeOrderInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return fmt.Errorf("change order title is required")
+	}
+	if (input.CostImpact != 0 || input.Category != "") && !domain.IsValidCostCategory(input.Category) {
+		return fmt.Errorf("invalid cost category")
+	}
+
+	order.Title = title
+	order.Description = input.Description
+	order.Category = input.Category
+	order.CostImpact = input.CostImpact
+	order.ScheduleImpactDays = input.ScheduleImpactDays
+	return nil
+}
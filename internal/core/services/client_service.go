@@ -3,18 +3,25 @@ package services
 import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ClientService struct {
-	clientRepo ports.ClientRepository
+	clientRepo  ports.ClientRepository
+	invoiceRepo ports.InvoiceRepository
+	companyRepo ports.CompanyRepository
 }
 
-func NewClientService(clientRepo ports.ClientRepository) *ClientService {
+func NewClientService(clientRepo ports.ClientRepository, invoiceRepo ports.InvoiceRepository, companyRepo ports.CompanyRepository) *ClientService {
 	return &ClientService{
-		clientRepo: clientRepo,
+		clientRepo:  clientRepo,
+		invoiceRepo: invoiceRepo,
+		companyRepo: companyRepo,
 	}
 }
 
@@ -38,12 +45,43 @@ func (s *ClientService) CreateClient(companyID, userID, name, phone, address, su
 	return client, nil
 }
 
+// GetClient returns the client with the totals of its invoices.
 func (s *ClientService) GetClient(id, companyID string) (*domain.Client, error) {
-	return s.clientRepo.GetClientByID(id, companyID)
+	client, err := s.clientRepo.GetClientByID(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.invoiceRepo.GetInvoicesByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	billed := make([]domain.Invoice, 0)
+	for _, invoice := range invoices {
+		if invoice.ClientID == id {
+			billed = append(billed, invoice)
+		}
+	}
+	receivables := summarizeInvoices(billed, time.Now())
+	client.Receivables = &receivables
+
+	return client, nil
 }
 
-func (s *ClientService) ListClients(companyID string) ([]domain.Client, error) {
-	return s.clientRepo.GetAllClients(companyID)
+// ListClients returns either the active or the archived clients. Trashed
+// clients are never listed here.
+func (s *ClientService) ListClients(companyID string, archived bool) ([]domain.Client, error) {
+	clients, err := s.clientRepo.GetAllClients(companyID)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]domain.Client, 0, len(clients))
+	for _, client := range clients {
+		if (client.ArchivedAt != nil) == archived {
+			filtered = append(filtered, client)
+		}
+	}
+	return filtered, nil
 }
 
 func (s *ClientService) UpdateClient(id, name, phone, address, summary, companyID string) (*domain.Client, error) {
@@ -65,8 +103,43 @@ func (s *ClientService) UpdateClient(id, name, phone, address, summary, companyI
 	return client, nil
 }
 
+// DeleteClient moves the client to the trash. It is removed for good by the
+// retention job.
 func (s *ClientService) DeleteClient(id, companyID string) error {
-	return s.clientRepo.DeleteClient(id, companyID)
+	if _, err := s.clientRepo.GetClientByID(id, companyID); err != nil {
+		return fmt.Errorf("client not found")
+	}
+	return s.clientRepo.TrashClient(id, companyID, time.Now())
+}
+
+func (s *ClientService) RestoreClient(id, companyID string) error {
+	if err := s.clientRepo.RestoreClient(id, companyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("client not found in trash")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ClientService) SetClientArchived(id, companyID string, archived bool) (*domain.Client, error) {
+	client, err := s.clientRepo.GetClientByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("client not found")
+	}
+
+	now := time.Now()
+	client.ArchivedAt = nil
+	if archived {
+		client.ArchivedAt = &now
+	}
+	client.UpdatedAt = now
+
+	if err := s.clientRepo.UpdateClient(client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
 }
 
 func (s *ClientService) AddComment(clientID, content string) (*domain.Comment, error) {
@@ -4,6 +4,7 @@ import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
 	"errors"
+	"path"
 	"strings"
 	"time"
 
@@ -14,12 +15,14 @@ import (
 type CompanyService struct {
 	companyRepo ports.CompanyRepository
 	linkRepo    ports.LinkRepository
+	storage     ports.FileStorage
 }
 
-func NewCompanyService(companyRepo ports.CompanyRepository, linkRepo ports.LinkRepository) *CompanyService {
+func NewCompanyService(companyRepo ports.CompanyRepository, linkRepo ports.LinkRepository, storage ports.FileStorage) *CompanyService {
 	return &CompanyService{
 		companyRepo: companyRepo,
 		linkRepo:    linkRepo,
+		storage:     storage,
 	}
 }
 
@@ -112,6 +115,34 @@ func (s *CompanyService) UpdatePublicPage(companyID, slug, publicName, bio strin
 	return company, nil
 }
 
+// UploadLogo stores the company logo, in the standard sizes, as the public page
+// avatar and removes the previous one.
+func (s *CompanyService) UploadLogo(companyID string, data []byte) (*domain.Company, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	prefix := path.Join(ports.PublicStoragePrefix, "logos", companyID, uuid.New().String())
+	urls, err := storeImageVariants(s.storage, prefix, data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	previousKey, previousURLs := company.PublicAvatarKey, company.PublicAvatarThumbnails
+	company.PublicAvatar = urls["large"]
+	company.PublicAvatarThumbnails = urls
+	company.PublicAvatarKey = prefix
+	company.UpdatedAt = time.Now()
+	if err := s.companyRepo.UpdateCompany(company); err != nil {
+		deleteImageVariants(s.storage, prefix, urls)
+		return nil, err
+	}
+	deleteImageVariants(s.storage, previousKey, previousURLs)
+
+	return company, nil
+}
+
 func (s *CompanyService) GetPublicPageBySlug(slug string) (*domain.PublicCompanyProfile, error) {
 	company, err := s.companyRepo.GetCompanyBySlug(Slugify(slug))
 	if err != nil {
@@ -132,12 +163,13 @@ func (s *CompanyService) GetPublicPageBySlug(slug string) (*domain.PublicCompany
 	}
 
 	return &domain.PublicCompanyProfile{
-		CompanyID:  company.ID,
-		Slug:       company.Slug,
-		PublicName: publicName,
-		Bio:        company.PublicBio,
-		Avatar:     company.PublicAvatar,
-		Links:      links,
+		CompanyID:        company.ID,
+		Slug:             company.Slug,
+		PublicName:       publicName,
+		Bio:              company.PublicBio,
+		Avatar:           company.PublicAvatar,
+		AvatarThumbnails: company.PublicAvatarThumbnails,
+		Links:            links,
 	}, nil
 }
 
@@ -3,15 +3,22 @@ package services
 import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
+	"time"
 )
 
 type DashboardService struct {
 	dashboardRepo ports.DashboardRepository
+	invoiceRepo   ports.InvoiceRepository
+	clientRepo    ports.ClientRepository
+	companyRepo   ports.CompanyRepository
 }
 
-func NewDashboardService(dashboardRepo ports.DashboardRepository) *DashboardService {
+func NewDashboardService(dashboardRepo ports.DashboardRepository, invoiceRepo ports.InvoiceRepository, clientRepo ports.ClientRepository, companyRepo ports.CompanyRepository) *DashboardService {
 	return &DashboardService{
 		dashboardRepo: dashboardRepo,
+		invoiceRepo:   invoiceRepo,
+		clientRepo:    clientRepo,
+		companyRepo:   companyRepo,
 	}
 }
 
@@ -41,11 +48,35 @@ func (s *DashboardService) GetMetrics(companyID string) (*domain.DashboardMetric
 		return nil, err
 	}
 
+	overdueMilestones, err := s.dashboardRepo.CountOverdueMilestones(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.invoiceRepo.GetInvoicesByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	receivables := summarizeInvoices(invoices, time.Now())
+
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	clients, err := s.clientRepo.GetAllClients(companyID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.DashboardMetrics{
 		ProjectsInProgress: projectsInProgress,
 		CompletedProjects:  completedProjects,
 		ActiveTasks:        activeTasks,
 		LinkClicks:         linkClicks,
 		ClientsCount:       clientsCount,
+		OverdueMilestones:  overdueMilestones,
+		AccountsReceivable: receivables.Outstanding,
+		OverdueReceivables: receivables.Overdue,
+		Pipeline:           summarizePipeline(pipelineStagesOf(company), clients),
 	}, nil
 }
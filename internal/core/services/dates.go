@@ -0,0 +1,18 @@
+package services
+
+import (
+	"time"
+)
+
+// parseDate accepts either a plain date (2006-01-02) or a full RFC3339 timestamp.
+func parseDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// truncateToDay drops the time of day, in UTC, the zone plain dates parse in.
+func truncateToDay(value time.Time) time.Time {
+	return value.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	reportPhotoMaxSize = 1200
+	reportLogoMaxSize  = 400
+)
+
+type DiaryReportService struct {
+	projectRepo    ports.ProjectRepository
+	companyRepo    ports.CompanyRepository
+	attachmentRepo ports.AttachmentRepository
+	storage        ports.FileStorage
+	renderer       ports.DiaryReportRenderer
+}
+
+func NewDiaryReportService(projectRepo ports.ProjectRepository, companyRepo ports.CompanyRepository, attachmentRepo ports.AttachmentRepository, storage ports.FileStorage, renderer ports.DiaryReportRenderer) *DiaryReportService {
+	return &DiaryReportService{
+		projectRepo:    projectRepo,
+		companyRepo:    companyRepo,
+		attachmentRepo: attachmentRepo,
+		storage:        storage,
+		renderer:       renderer,
+	}
+}
+
+// ExportDiary renders the diary entries dated between from and to (both
+// optional, inclusive) as a daily work report. visibility narrows the items to
+// "public" or "internal" ones; "all" or empty keeps every item.
+func (s *DiaryReportService) ExportDiary(projectID, companyID, from, to, visibility string) ([]byte, error) {
+	project, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	if visibility == "" {
+		visibility = domain.DiaryVisibilityAll
+	}
+	if visibility != domain.DiaryVisibilityAll && visibility != domain.DiaryVisibilityPublic && visibility != domain.DiaryVisibilityInternal {
+		return nil, fmt.Errorf("invalid visibility")
+	}
+
+	entries, err := s.projectRepo.GetDiaryEntriesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(project, entries, from, to, visibility)
+}
+
+// ExportPublicDiary renders the public items of a shared project's diary,
+// behind the same PIN as the public diary listing.
+func (s *DiaryReportService) ExportPublicDiary(projectID, pin, from, to string) ([]byte, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.projectRepo.GetPublicDiaryEntriesByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(project, entries, from, to, domain.DiaryVisibilityPublic)
+}
+
+func (s *DiaryReportService) render(project *domain.Project, entries []domain.DiaryEntry, from, to, visibility string) ([]byte, error) {
+	report := &domain.DiaryReport{
+		Project:     project,
+		Visibility:  visibility,
+		Photos:      map[string][]domain.ReportImage{},
+		Files:       map[string][]string{},
+		GeneratedAt: time.Now(),
+	}
+
+	if from != "" {
+		parsed, err := parseDate(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date")
+		}
+		report.From = &parsed
+	}
+	if to != "" {
+		parsed, err := parseDate(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date")
+		}
+		report.To = &parsed
+	}
+	if report.From != nil && report.To != nil && report.To.Before(*report.From) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+
+	report.Entries = filterReportEntries(entries, report.From, report.To, visibility)
+
+	company, err := s.companyRepo.GetCompanyByID(project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	report.Company = company
+	report.Logo = loadCompanyLogo(s.storage, company)
+
+	if err := s.loadAttachments(report); err != nil {
+		return nil, err
+	}
+
+	return s.renderer.RenderDiaryReport(report)
+}
+
+// loadAttachments collects the files of the items kept in the report. Images
+// are embedded; anything else, or an image that cannot be read, is listed by
+// name.
+func (s *DiaryReportService) loadAttachments(report *domain.DiaryReport) error {
+	items := make(map[string]bool)
+	for _, entry := range report.Entries {
+		for _, item := range entry.Items {
+			items[item.ID] = true
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByProject(report.Project.ID, report.Project.CompanyID)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.EntityType != domain.AttachmentEntityDiaryItem || attachment.Status != domain.AttachmentStatusUploaded || !items[attachment.EntityID] {
+			continue
+		}
+		addReportAttachment(s.storage, attachment, report.Photos, report.Files)
+	}
+	return nil
+}
+
+// addReportAttachment embeds an image attachment in a report, keyed by the
+// record it belongs to. Anything else, or an image that cannot be read, is
+// listed by name.
+func addReportAttachment(storage ports.FileStorage, attachment domain.Attachment, photos map[string][]domain.ReportImage, files map[string][]string) {
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		photo, err := loadReportImage(storage, attachment.StorageKey, attachment.FileName, reportPhotoMaxSize)
+		if err == nil {
+			photos[attachment.EntityID] = append(photos[attachment.EntityID], *photo)
+			return
+		}
+	}
+	files[attachment.EntityID] = append(files[attachment.EntityID], attachment.FileName)
+}
+
+// loadCompanyLogo reads the company logo uploaded for the public page. A
+// missing or unreadable logo leaves the report without one.
+func loadCompanyLogo(storage ports.FileStorage, company *domain.Company) *domain.ReportImage {
+	logoURL := company.PublicAvatarThumbnails["medium"]
+	if company.PublicAvatarKey == "" || logoURL == "" {
+		return nil
+	}
+	key, err := imageVariantKey(company.PublicAvatarKey, logoURL)
+	if err != nil {
+		return nil
+	}
+	logo, err := loadReportImage(storage, key, "logo", reportLogoMaxSize)
+	if err != nil {
+		return nil
+	}
+	return logo
+}
+
+func loadReportImage(storage ports.FileStorage, key, name string, maxSize int) (*domain.ReportImage, error) {
+	file, err := storage.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	return reportImage(name, data, maxSize)
+}
+
+// filterReportEntries keeps the entries dated within the period, with only
+// the items of the requested visibility. Entries left without items are
+// dropped. The result is in chronological order, as a printed diary reads.
+func filterReportEntries(entries []domain.DiaryEntry, from, to *time.Time, visibility string) []domain.DiaryEntry {
+	result := make([]domain.DiaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		day := truncateToDay(entry.EntryDate)
+		if from != nil && day.Before(truncateToDay(*from)) {
+			continue
+		}
+		if to != nil && day.After(truncateToDay(*to)) {
+			continue
+		}
+
+		items := make([]domain.DiaryItem, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			if visibility == domain.DiaryVisibilityAll || item.Visibility == visibility {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		entry.Items = items
+		result = append(result, entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].EntryDate.Before(result[j].EntryDate)
+	})
+	return result
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *ProjectService) ListDiaryRevisions(entryID, projectID, companyID string) ([]domain.DiaryEntryRevision, error) {
+	if _, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID); err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	return s.projectRepo.GetDiaryRevisions(entryID, projectID, companyID)
+}
+
+// DiffDiaryRevisions compares two revisions of an entry. Zero values pick the
+// defaults: to is the latest revision and from the one before it.
+func (s *ProjectService) DiffDiaryRevisions(entryID, projectID, companyID string, from, to int) (*domain.DiaryRevisionDiff, error) {
+	revisions, err := s.ListDiaryRevisions(entryID, projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("revision not found")
+	}
+
+	if to == 0 {
+		to = revisions[len(revisions)-1].Revision
+	}
+	if from == 0 {
+		from = max(to-1, 1)
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid revision range")
+	}
+
+	var before, after *domain.DiaryEntryRevision
+	for i := range revisions {
+		switch revisions[i].Revision {
+		case from:
+			before = &revisions[i]
+		case to:
+			after = &revisions[i]
+		}
+	}
+	if from == to {
+		after = before
+	}
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("revision not found")
+	}
+
+	diff := diffDiaryContent(before.EntryDate, before.Title, before.Items, after.EntryDate, after.Title, after.Items)
+	diff.EntryID = entryID
+	diff.FromRevision = from
+	diff.ToRevision = to
+	return diff, nil
+}
+
+// recordDiaryRevision snapshots the entry's current content as its next
+// revision.
+func (s *ProjectService) recordDiaryRevision(entry *domain.DiaryEntry, number int, userID string, createdAt time.Time) error {
+	items := make([]domain.DiaryItem, len(entry.Items))
+	for i, item := range entry.Items {
+		item.Attachments = nil
+		items[i] = item
+	}
+
+	return s.projectRepo.CreateDiaryRevision(&domain.DiaryEntryRevision{
+		ID:        uuid.New().String(),
+		EntryID:   entry.ID,
+		ProjectID: entry.ProjectID,
+		CompanyID: entry.CompanyID,
+		Revision:  number,
+		UserID:    userID,
+		EntryDate: entry.EntryDate,
+		Title:     entry.Title,
+		Items:     items,
+		CreatedAt: createdAt,
+	})
+}
+
+// nextDiaryRevision returns the number the next revision of entry gets.
+// Entries written before revisions were kept get their stored state recorded
+// as revision 1 first, so the edit about to happen can still be diffed.
+func (s *ProjectService) nextDiaryRevision(entry *domain.DiaryEntry) (int, error) {
+	revisions, err := s.projectRepo.GetDiaryRevisions(entry.ID, entry.ProjectID, entry.CompanyID)
+	if err != nil {
+		return 0, err
+	}
+	if len(revisions) > 0 {
+		return revisions[len(revisions)-1].Revision + 1, nil
+	}
+
+	if err := s.recordDiaryRevision(entry, 1, entry.UserID, entry.UpdatedAt); err != nil {
+		return 0, err
+	}
+	return 2, nil
+}
+
+// matchDiaryItems gives each incoming item the identity of the stored item it
+// continues. Items sent back with their ID keep it; items without a known ID
+// take over a stored item with exactly the same content, so clients that
+// resend the whole entry do not churn IDs or orphan attachments. Items whose
+// content did not change also keep their UpdatedAt.
+func matchDiaryItems(existing, items []domain.DiaryItem, entryID string, now time.Time) []domain.DiaryItem {
+	stored := make(map[string]domain.DiaryItem, len(existing))
+	for _, item := range existing {
+		stored[item.ID] = item
+	}
+
+	matched := make([]domain.DiaryItem, len(items))
+	found := make([]bool, len(items))
+	for index, item := range items {
+		if previous, ok := stored[item.ID]; ok {
+			matched[index] = keepDiaryItem(item, previous, now)
+			found[index] = true
+			delete(stored, item.ID)
+		}
+	}
+	for index, item := range items {
+		if found[index] {
+			continue
+		}
+		for _, previous := range existing {
+			if _, free := stored[previous.ID]; free && len(diaryItemChanges(previous, item)) == 0 {
+				matched[index] = keepDiaryItem(item, previous, now)
+				found[index] = true
+				delete(stored, previous.ID)
+				break
+			}
+		}
+		if !found[index] {
+			item.ID = uuid.New().String()
+			item.CreatedAt = now
+			item.UpdatedAt = now
+			matched[index] = item
+		}
+	}
+
+	for index := range matched {
+		matched[index].DiaryEntryID = entryID
+		matched[index].SortOrder = index
+		matched[index].Attachments = nil
+	}
+	return matched
+}
+
+func keepDiaryItem(item, previous domain.DiaryItem, now time.Time) domain.DiaryItem {
+	item.ID = previous.ID
+	item.CreatedAt = previous.CreatedAt
+	item.UpdatedAt = now
+	if len(diaryItemChanges(previous, item)) == 0 {
+		item.UpdatedAt = previous.UpdatedAt
+	}
+	return item
+}
+
+func diffDiaryContent(fromDate time.Time, fromTitle string, fromItems []domain.DiaryItem, toDate time.Time, toTitle string, toItems []domain.DiaryItem) *domain.DiaryRevisionDiff {
+	diff := &domain.DiaryRevisionDiff{
+		EntryDateChanged: !fromDate.Equal(toDate),
+		TitleChanged:     fromTitle != toTitle,
+		Added:            []domain.DiaryItem{},
+		Removed:          []domain.DiaryItem{},
+		Changed:          []domain.DiaryItemChange{},
+	}
+
+	previous := make(map[string]domain.DiaryItem, len(fromItems))
+	for _, item := range fromItems {
+		previous[item.ID] = item
+	}
+	for _, item := range toItems {
+		before, ok := previous[item.ID]
+		if !ok {
+			diff.Added = append(diff.Added, item)
+			continue
+		}
+		delete(previous, item.ID)
+
+		fields := diaryItemChanges(before, item)
+		if before.SortOrder != item.SortOrder {
+			fields = append(fields, "sort_order")
+		}
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, domain.DiaryItemChange{ItemID: item.ID, Fields: fields, Before: before, After: item})
+		}
+	}
+	for _, item := range fromItems {
+		if _, ok := previous[item.ID]; ok {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	return diff
+}
+
+func diaryContentChanged(diff *domain.DiaryRevisionDiff) bool {
+	return diff.EntryDateChanged || diff.TitleChanged || len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Changed) > 0
+}
+
+// diaryItemChanges lists the content fields, by JSON name, that differ
+// between two versions of an item. Identity, position and timestamps are not
+// content.
+func diaryItemChanges(before, after domain.DiaryItem) []string {
+	var fields []string
+	if before.Type != after.Type {
+		fields = append(fields, "type")
+	}
+	if before.Label != after.Label {
+		fields = append(fields, "label")
+	}
+	if before.Content != after.Content {
+		fields = append(fields, "content")
+	}
+	if before.Visibility != after.Visibility {
+		fields = append(fields, "visibility")
+	}
+	if !reflect.DeepEqual(before.Weather, after.Weather) {
+		fields = append(fields, "weather")
+	}
+	if !sameList(before.Workforce, after.Workforce) {
+		fields = append(fields, "workforce")
+	}
+	if !sameList(before.Equipment, after.Equipment) {
+		fields = append(fields, "equipment")
+	}
+	if !reflect.DeepEqual(before.Occurrence, after.Occurrence) {
+		fields = append(fields, "occurrence")
+	}
+	if !sameList(before.Services, after.Services) {
+		fields = append(fields, "services")
+	}
+	return fields
+}
+
+// sameList compares two lists, treating nil and empty as equal.
+func sameList[T any](a, b []T) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubmitDiaryEntry hands a draft entry over for approval. From then on it can
+// no longer be edited unless an admin sends it back.
+func (s *ProjectService) SubmitDiaryEntry(entryID, projectID, companyID, userID string) (*domain.DiaryEntry, error) {
+	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	now := time.Now()
+	entry.SubmittedBy = userID
+	entry.SubmittedAt = &now
+	return s.transitionDiaryEntry(entry, userID, domain.DiaryStatusSubmitted, "")
+}
+
+// ApproveDiaryEntry signs a submitted entry off and locks it. Approving an
+// amendment marks the entry it corrects as amended.
+func (s *ProjectService) ApproveDiaryEntry(entryID, projectID, companyID, userID string) (*domain.DiaryEntry, error) {
+	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	var original *domain.DiaryEntry
+	if entry.AmendsEntryID != "" {
+		original, err = s.projectRepo.GetDiaryEntryByID(entry.AmendsEntryID, projectID, companyID)
+		if err != nil {
+			return nil, fmt.Errorf("diary entry not found")
+		}
+		if original.AmendedByID != "" {
+			return nil, fmt.Errorf("diary entry already amended")
+		}
+	}
+
+	approverName := ""
+	if user, err := s.userRepo.GetUserByID(userID); err == nil {
+		approverName = user.Name
+	}
+
+	now := time.Now()
+	entry.ApprovedBy = userID
+	entry.ApproverName = approverName
+	entry.ApprovedAt = &now
+	entry, err = s.transitionDiaryEntry(entry, userID, domain.DiaryStatusApproved, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if original != nil {
+		original.AmendedByID = entry.ID
+		original.UpdatedAt = now
+		if err := s.projectRepo.UpdateDiaryEntryStatus(original); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// RejectDiaryEntry sends a submitted entry back to draft; note explains what
+// must be fixed.
+func (s *ProjectService) RejectDiaryEntry(entryID, projectID, companyID, userID, note string) (*domain.DiaryEntry, error) {
+	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	entry.SubmittedBy = ""
+	entry.SubmittedAt = nil
+	return s.transitionDiaryEntry(entry, userID, domain.DiaryStatusDraft, note)
+}
+
+// AcknowledgePublicDiaryEntry records the client's acknowledgement of an
+// approved entry through the public project link, behind its PIN. name is the
+// person signing on the client's side.
+func (s *ProjectService) AcknowledgePublicDiaryEntry(projectID, entryID, pin, name string) (*domain.DiaryEntry, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("signer name is required")
+	}
+
+	// Only entries the client can see in the public diary may be acknowledged.
+	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, project.CompanyID)
+	if err != nil || !entry.Published() || !hasPublicDiaryItem(entry) {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	now := time.Now()
+	entry.AcknowledgedBy = name
+	entry.AcknowledgedAt = &now
+	entry, err = s.transitionDiaryEntry(entry, "", domain.DiaryStatusAcknowledged, "acknowledged by "+name)
+	if err != nil {
+		return nil, err
+	}
+
+	publicItems := make([]domain.DiaryItem, 0, len(entry.Items))
+	for _, item := range entry.Items {
+		if item.Visibility == "public" {
+			publicItems = append(publicItems, item)
+		}
+	}
+	entry.Items = publicItems
+	return entry, nil
+}
+
+// AmendDiaryEntry starts a correction of an approved entry. The original is
+// kept as it was signed; the amendment is a new draft for the same day that
+// goes through the workflow on its own.
+func (s *ProjectService) AmendDiaryEntry(entryID, projectID, companyID, userID, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
+	original, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+	if !original.Published() {
+		return nil, fmt.Errorf("only approved diary entries can be amended")
+	}
+	if original.AmendedByID != "" {
+		return nil, fmt.Errorf("diary entry already amended")
+	}
+
+	if err := s.checkEntryTemplate(original, items); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateDiaryServiceTasks(projectID, companyID, items); err != nil {
+		return nil, err
+	}
+
+	return s.createDiaryEntry(uuid.New().String(), projectID, companyID, userID, original.EntryDate, title, original.TemplateID, items, original.ID)
+}
+
+func (s *ProjectService) GetDiaryEntryHistory(entryID, projectID, companyID string) ([]domain.StatusTransition, error) {
+	if _, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID); err != nil {
+		return nil, fmt.Errorf("diary entry not found")
+	}
+
+	return s.statusRepo.GetStatusTransitions(projectID, domain.StatusEntityDiaryEntry, entryID, companyID)
+}
+
+func (s *ProjectService) transitionDiaryEntry(entry *domain.DiaryEntry, userID, status, note string) (*domain.DiaryEntry, error) {
+	from := domain.NormalizeDiaryStatus(entry.Status)
+	if !domain.CanTransitionDiaryEntry(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	entry.Status = status
+	entry.UpdatedAt = time.Now()
+	if err := s.projectRepo.UpdateDiaryEntryStatus(entry, statusTransition(domain.StatusEntityDiaryEntry, entry.ID, entry.ProjectID, entry.CompanyID, userID, from, status, note)); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func hasPublicDiaryItem(entry *domain.DiaryEntry) bool {
+	for _, item := range entry.Items {
+		if item.Visibility == "public" {
+			return true
+		}
+	}
+	return false
+}
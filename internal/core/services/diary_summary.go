@@ -0,0 +1,139 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// GetDiarySummary aggregates the weather, workforce, equipment, occurrence and
+// service items of the diary over a period: a month ("2006-01"), a from/to
+// date range, or the current month when neither is given.
+func (s *ProjectService) GetDiarySummary(projectID, companyID, month, from, to string) (*domain.DiarySummary, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	start, end, err := diarySummaryPeriod(month, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.projectRepo.GetDiaryEntriesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.DiarySummary{
+		ProjectID:             projectID,
+		From:                  start,
+		To:                    end,
+		WorkforceDays:         map[string]int{},
+		EquipmentHours:        map[string]float64{},
+		OccurrencesByCategory: map[string]int{},
+		TasksWorked:           []string{},
+	}
+
+	entryDays := map[time.Time]bool{}
+	rainDays := map[time.Time]bool{}
+	stoppageDays := map[time.Time]bool{}
+	headcountByDay := map[time.Time]int{}
+	tasks := map[string]bool{}
+
+	for _, entry := range entries {
+		day := truncateToDay(entry.EntryDate)
+		if day.Before(start) || day.After(end) {
+			continue
+		}
+		entryDays[day] = true
+
+		for _, item := range entry.Items {
+			switch item.Type {
+			case domain.DiaryItemWeather:
+				if item.Weather == nil {
+					continue
+				}
+				if item.Weather.Rainy() {
+					rainDays[day] = true
+				}
+				if item.Weather.RainStoppage {
+					stoppageDays[day] = true
+					summary.StoppageHours += item.Weather.StoppageHours
+				}
+			case domain.DiaryItemWorkforce:
+				for _, crew := range item.Workforce {
+					summary.WorkforceDays[crew.Trade] += crew.Headcount
+					headcountByDay[day] += crew.Headcount
+				}
+			case domain.DiaryItemEquipment:
+				for _, equipment := range item.Equipment {
+					summary.EquipmentHours[equipment.Name] += equipment.Hours * float64(equipment.Quantity)
+				}
+			case domain.DiaryItemOccurrence:
+				summary.Occurrences++
+				if item.Occurrence != nil {
+					summary.OccurrencesByCategory[item.Occurrence.Category]++
+				}
+			case domain.DiaryItemService:
+				for _, service := range item.Services {
+					summary.ServicesExecuted++
+					if service.TaskID != "" {
+						tasks[service.TaskID] = true
+					}
+				}
+			}
+		}
+	}
+
+	summary.EntryDays = len(entryDays)
+	summary.RainDays = len(rainDays)
+	summary.RainStoppageDays = len(stoppageDays)
+	if len(headcountByDay) > 0 {
+		total := 0
+		for _, headcount := range headcountByDay {
+			total += headcount
+		}
+		summary.AverageHeadcount = float64(total) / float64(len(headcountByDay))
+	}
+	for taskID := range tasks {
+		summary.TasksWorked = append(summary.TasksWorked, taskID)
+	}
+	sort.Strings(summary.TasksWorked)
+
+	return summary, nil
+}
+
+func diarySummaryPeriod(month, from, to string) (time.Time, time.Time, error) {
+	if month == "" && from == "" && to == "" {
+		month = time.Now().UTC().Format("2006-01")
+	}
+
+	if month != "" {
+		start, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid month")
+		}
+		return start, start.AddDate(0, 1, -1), nil
+	}
+
+	start, end := time.Time{}, truncateToDay(time.Now())
+	if from != "" {
+		parsed, err := parseDate(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date")
+		}
+		start = truncateToDay(parsed)
+	}
+	if to != "" {
+		parsed, err := parseDate(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date")
+		}
+		end = truncateToDay(parsed)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range")
+	}
+	return start, end, nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *TemplateService) CreateDiaryTemplate(companyID, name, description string, items []domain.DiaryTemplateItem) (*domain.DiaryTemplate, error) {
+	if err := validateDiaryTemplate(name, items); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &domain.DiaryTemplate{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		Items:       items,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.templateRepo.CreateDiaryTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) ListDiaryTemplates(companyID string) ([]domain.DiaryTemplate, error) {
+	return s.templateRepo.GetDiaryTemplatesByCompany(companyID)
+}
+
+func (s *TemplateService) GetDiaryTemplate(id, companyID string) (*domain.DiaryTemplate, error) {
+	template, err := s.templateRepo.GetDiaryTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary template not found")
+	}
+	return template, nil
+}
+
+func (s *TemplateService) UpdateDiaryTemplate(id, companyID, name, description string, items []domain.DiaryTemplateItem) (*domain.DiaryTemplate, error) {
+	template, err := s.templateRepo.GetDiaryTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("diary template not found")
+	}
+
+	if err := validateDiaryTemplate(name, items); err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(name)
+	template.Description = description
+	template.Items = items
+	template.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.UpdateDiaryTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) DeleteDiaryTemplate(id, companyID string) error {
+	if _, err := s.templateRepo.GetDiaryTemplateByID(id, companyID); err != nil {
+		return fmt.Errorf("diary template not found")
+	}
+	return s.templateRepo.DeleteDiaryTemplate(id, companyID)
+}
+
+func validateDiaryTemplate(name string, items []domain.DiaryTemplateItem) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("diary template items are required")
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item.Label) == "" {
+			return fmt.Errorf("diary field label is required")
+		}
+		if !domain.IsDiaryItemType(item.Type) {
+			return fmt.Errorf("invalid diary item type")
+		}
+		if item.Visibility != "public" && item.Visibility != "internal" {
+			return fmt.Errorf("invalid diary item visibility")
+		}
+		key := diaryTemplateKey(item.Type, item.Label)
+		if seen[key] {
+			return fmt.Errorf("duplicate diary template item")
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// applyDiaryTemplate lays the entry's items out as the template says:
+// template items first, in template order, then any extra items as sent.
+// Template items the entry leaves out are filled with their default content
+// and visibility when they have one.
+func applyDiaryTemplate(template *domain.DiaryTemplate, items []domain.DiaryItem) []domain.DiaryItem {
+	used := make([]bool, len(items))
+	laidOut := make([]domain.DiaryItem, 0, len(template.Items)+len(items))
+	for _, templateItem := range template.Items {
+		index := findTemplateItem(templateItem, items, used)
+		if index >= 0 {
+			used[index] = true
+			laidOut = append(laidOut, items[index])
+			continue
+		}
+		if templateItem.Content != "" && (templateItem.Type == domain.DiaryItemText || templateItem.Type == domain.DiaryItemField) {
+			laidOut = append(laidOut, domain.DiaryItem{
+				Type:       templateItem.Type,
+				Label:      templateItem.Label,
+				Content:    templateItem.Content,
+				Visibility: templateItem.Visibility,
+			})
+		}
+	}
+	for index, item := range items {
+		if !used[index] {
+			laidOut = append(laidOut, item)
+		}
+	}
+	return laidOut
+}
+
+// checkDiaryTemplateItems fails when a required template item is missing from
+// the entry or has nothing filled in.
+func checkDiaryTemplateItems(template *domain.DiaryTemplate, items []domain.DiaryItem) error {
+	used := make([]bool, len(items))
+	for _, templateItem := range template.Items {
+		index := findTemplateItem(templateItem, items, used)
+		if index >= 0 {
+			used[index] = true
+		}
+		if !templateItem.Required {
+			continue
+		}
+		if index < 0 || !diaryItemFilled(items[index]) {
+			return fmt.Errorf("required diary item missing: %s", templateItem.Label)
+		}
+	}
+	return nil
+}
+
+func findTemplateItem(templateItem domain.DiaryTemplateItem, items []domain.DiaryItem, used []bool) int {
+	key := diaryTemplateKey(templateItem.Type, templateItem.Label)
+	for index, item := range items {
+		if !used[index] && diaryTemplateKey(item.Type, item.Label) == key {
+			return index
+		}
+	}
+	return -1
+}
+
+func diaryItemFilled(item domain.DiaryItem) bool {
+	switch item.Type {
+	case domain.DiaryItemWeather:
+		return item.Weather != nil
+	case domain.DiaryItemWorkforce:
+		return len(item.Workforce) > 0
+	case domain.DiaryItemEquipment:
+		return len(item.Equipment) > 0
+	case domain.DiaryItemOccurrence:
+		return item.Occurrence != nil
+	case domain.DiaryItemService:
+		return len(item.Services) > 0
+	case domain.DiaryItemAttachment:
+		// Files are uploaded once the item exists.
+		return true
+	}
+	return strings.TrimSpace(item.Content) != ""
+}
+
+func diaryTemplateKey(itemType, label string) string {
+	return itemType + "\x00" + strings.ToLower(strings.TrimSpace(label))
+}
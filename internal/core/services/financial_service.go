@@ -0,0 +1,272 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FinancialService struct {
+	financialRepo   ports.FinancialRepository
+	projectRepo     ports.ProjectRepository
+	changeOrderRepo ports.ChangeOrderRepository
+}
+
+func NewFinancialService(financialRepo ports.FinancialRepository, projectRepo ports.ProjectRepository, changeOrderRepo ports.ChangeOrderRepository) *FinancialService {
+	return &FinancialService{
+		financialRepo:   financialRepo,
+		projectRepo:     projectRepo,
+		changeOrderRepo: changeOrderRepo,
+	}
+}
+
+func (s *FinancialService) GetBudget(projectID, companyID string) ([]domain.BudgetLine, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	lines, err := s.financialRepo.GetBudgetLines(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		lines = []domain.BudgetLine{}
+	}
+	return lines, nil
+}
+
+func (s *FinancialService) SetBudget(projectID, companyID string, lines []domain.BudgetLine) ([]domain.BudgetLine, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	now := time.Now()
+	budget := make([]domain.BudgetLine, 0, len(lines))
+	for _, line := range lines {
+		if !domain.IsValidCostCategory(line.Category) {
+			return nil, fmt.Errorf("invalid cost category")
+		}
+		if line.Amount < 0 {
+			return nil, fmt.Errorf("invalid amount")
+		}
+		budget = append(budget, domain.BudgetLine{
+			ID:          uuid.New().String(),
+			ProjectID:   projectID,
+			CompanyID:   companyID,
+			Category:    line.Category,
+			Description: line.Description,
+			Amount:      line.Amount,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	if err := s.financialRepo.ReplaceBudgetLines(projectID, companyID, budget); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+func (s *FinancialService) CreateExpense(projectID, companyID, userID, taskID, category, description, supplier, expenseDate string, amount float64) (*domain.Expense, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	parsedExpenseDate, err := s.validateExpense(projectID, companyID, taskID, category, expenseDate, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expense := &domain.Expense{
+		ID:          uuid.New().String(),
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		CompanyID:   companyID,
+		UserID:      userID,
+		Category:    category,
+		Description: description,
+		Supplier:    supplier,
+		Amount:      amount,
+		ExpenseDate: parsedExpenseDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.financialRepo.CreateExpense(expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+func (s *FinancialService) ListExpenses(projectID, companyID, taskID string) ([]domain.Expense, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	expenses, err := s.financialRepo.GetExpensesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]domain.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		if taskID != "" && expense.TaskID != taskID {
+			continue
+		}
+		filtered = append(filtered, expense)
+	}
+	return filtered, nil
+}
+
+func (s *FinancialService) UpdateExpense(id, projectID, companyID, taskID, category, description, supplier, expenseDate string, amount float64) (*domain.Expense, error) {
+	expense, err := s.financialRepo.GetExpenseByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found")
+	}
+
+	parsedExpenseDate, err := s.validateExpense(projectID, companyID, taskID, category, expenseDate, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	expense.TaskID = taskID
+	expense.Category = category
+	expense.Description = description
+	expense.Supplier = supplier
+	expense.Amount = amount
+	expense.ExpenseDate = parsedExpenseDate
+	expense.UpdatedAt = time.Now()
+
+	if err := s.financialRepo.UpdateExpense(expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+func (s *FinancialService) DeleteExpense(id, projectID, companyID string) error {
+	if _, err := s.financialRepo.GetExpenseByID(id, projectID, companyID); err != nil {
+		return fmt.Errorf("expense not found")
+	}
+
+	return s.financialRepo.DeleteExpense(id, projectID, companyID)
+}
+
+func (s *FinancialService) GetFinancials(projectID, companyID string) (*domain.ProjectFinancials, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	lines, err := s.financialRepo.GetBudgetLines(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.financialRepo.GetExpensesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.changeOrderRepo.GetChangeOrdersByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildProjectFinancials(projectID, lines, expenses, orders), nil
+}
+
+func (s *FinancialService) validateExpense(projectID, companyID, taskID, category, expenseDate string, amount float64) (time.Time, error) {
+	if !domain.IsValidCostCategory(category) {
+		return time.Time{}, fmt.Errorf("invalid cost category")
+	}
+	if amount <= 0 {
+		return time.Time{}, fmt.Errorf("invalid amount")
+	}
+
+	parsedExpenseDate, err := parseDate(expenseDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expense date")
+	}
+
+	if taskID != "" {
+		task, err := s.projectRepo.GetTaskByID(taskID, companyID)
+		if err != nil || task.ProjectID != projectID {
+			return time.Time{}, fmt.Errorf("task not found or access denied")
+		}
+	}
+
+	return parsedExpenseDate, nil
+}
+
+// buildProjectFinancials adds the cost impact of approved change orders to the
+// budget of their categories.
+func buildProjectFinancials(projectID string, lines []domain.BudgetLine, expenses []domain.Expense, orders []domain.ChangeOrder) *domain.ProjectFinancials {
+	budgeted := make(map[string]float64, len(domain.CostCategories))
+	actual := make(map[string]float64, len(domain.CostCategories))
+	taskActual := make(map[string]float64)
+
+	financials := &domain.ProjectFinancials{
+		ProjectID:     projectID,
+		ExpensesCount: len(expenses),
+	}
+
+	for _, line := range lines {
+		budgeted[line.Category] += line.Amount
+		financials.Budgeted += line.Amount
+	}
+
+	for _, order := range orders {
+		if order.Status != domain.ChangeOrderStatusApproved || order.CostImpact == 0 {
+			continue
+		}
+		budgeted[order.Category] += order.CostImpact
+		financials.Budgeted += order.CostImpact
+		financials.ApprovedChangeOrders += order.CostImpact
+	}
+
+	for _, expense := range expenses {
+		actual[expense.Category] += expense.Amount
+		financials.Actual += expense.Amount
+		if expense.TaskID != "" {
+			taskActual[expense.TaskID] += expense.Amount
+		}
+	}
+
+	financials.Variance = financials.Budgeted - financials.Actual
+	financials.ConsumedPercent = consumedPercent(financials.Actual, financials.Budgeted)
+
+	financials.Categories = make([]domain.CategoryFinancials, 0, len(domain.CostCategories))
+	for _, category := range domain.CostCategories {
+		financials.Categories = append(financials.Categories, domain.CategoryFinancials{
+			Category:        category,
+			Budgeted:        budgeted[category],
+			Actual:          actual[category],
+			Variance:        budgeted[category] - actual[category],
+			ConsumedPercent: consumedPercent(actual[category], budgeted[category]),
+		})
+	}
+
+	financials.Tasks = make([]domain.TaskCost, 0, len(taskActual))
+	for taskID, amount := range taskActual {
+		financials.Tasks = append(financials.Tasks, domain.TaskCost{TaskID: taskID, Actual: amount})
+	}
+	sort.Slice(financials.Tasks, func(i, j int) bool {
+		return financials.Tasks[i].Actual > financials.Tasks[j].Actual
+	})
+
+	return financials
+}
+
+func consumedPercent(actual, budgeted float64) float64 {
+	if budgeted <= 0 {
+		return 0
+	}
+	return (actual / budgeted) * 100
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"bytes"
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxImageUploadSize is the largest avatar or logo file accepted.
+	MaxImageUploadSize = 5 << 20
+
+	maxImagePixels = 40_000_000
+)
+
+// imageVariants are the standard sizes every avatar and logo is stored in.
+// "large" is the one kept in User.Avatar and Company.PublicAvatar.
+var imageVariants = []struct {
+	name string
+	size int
+}{
+	{"small", 64},
+	{"medium", 256},
+	{"large", 512},
+}
+
+// storeImageVariants validates an uploaded image, resizes it into the standard
+// variants and writes them under prefix. Re-encoding drops EXIF and any other
+// metadata, so each variant is turned to the EXIF orientation to keep photos
+// upright.
+// Avatars are cropped to a centred square, logos only fit inside it. It
+// returns the public URL of each variant, keyed by variant name.
+func storeImageVariants(storage ports.FileStorage, prefix string, data []byte, crop bool) (map[string]string, error) {
+	if len(data) > MaxImageUploadSize {
+		return nil, errors.New("image too large")
+	}
+
+	format := http.DetectContentType(data)
+	switch format {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return nil, errors.New("invalid image type")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("image dimensions too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	orientation := 1
+	if format == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	// JPEGs stay JPEG; everything else may carry transparency and goes to PNG.
+	contentType, ext := "image/png", ".png"
+	if format == "image/jpeg" {
+		contentType, ext = "image/jpeg", ".jpg"
+	}
+
+	urls := make(map[string]string, len(imageVariants))
+	for _, variant := range imageVariants {
+		resized := applyOrientation(resizeImage(img, variant.size, crop), orientation)
+
+		var buf bytes.Buffer
+		if contentType == "image/jpeg" {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key := path.Join(prefix, variant.name+ext)
+		if err := storage.Put(key, contentType, buf.Bytes()); err != nil {
+			return nil, err
+		}
+		urls[variant.name] = storage.PublicURL(key)
+	}
+
+	return urls, nil
+}
+
+// deleteImageVariants removes variants written by storeImageVariants. Failures
+// are ignored: a leftover thumbnail must not fail the upload replacing it.
+func deleteImageVariants(storage ports.FileStorage, prefix string, urls map[string]string) {
+	if prefix == "" {
+		return
+	}
+	for _, rawURL := range urls {
+		key, err := imageVariantKey(prefix, rawURL)
+		if err != nil {
+			continue
+		}
+		_ = storage.Delete(key)
+	}
+}
+
+// reportImage decodes a stored image and re-encodes it as a JPEG no larger
+// than maxSize on either side, upright and flattened onto white, ready to be
+// embedded in a report.
+func reportImage(name string, data []byte, maxSize int) (*domain.ReportImage, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("image dimensions too large")
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	resized := applyOrientation(resizeImage(img, maxSize, false), orientation)
+	flattened := image.NewRGBA(resized.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), resized, resized.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flattened, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+
+	bounds := flattened.Bounds()
+	return &domain.ReportImage{
+		Name:   name,
+		Data:   buf.Bytes(),
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}, nil
+}
+
+// imageVariantKey is the storage key of a variant written by
+// storeImageVariants, found from its prefix and public URL.
+func imageVariantKey(prefix, rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(prefix, path.Base(parsed.Path)), nil
+}
+
+// resizeImage scales src to fit a size × size box, after cropping it to a
+// centred square when crop is set. Both are symmetric, so the result can be
+// turned to its orientation afterwards, on far fewer pixels.
+func resizeImage(src image.Image, size int, crop bool) *image.RGBA {
+	bounds := src.Bounds()
+	srcRect := bounds
+	if crop {
+		side := min(bounds.Dx(), bounds.Dy())
+		x := bounds.Min.X + (bounds.Dx()-side)/2
+		y := bounds.Min.Y + (bounds.Dy()-side)/2
+		srcRect = image.Rect(x, y, x+side, y+side)
+	}
+
+	// Never upscale: small sources keep their size.
+	scale := math.Min(1, math.Min(float64(size)/float64(srcRect.Dx()), float64(size)/float64(srcRect.Dy())))
+	width := max(1, int(math.Round(float64(srcRect.Dx())*scale)))
+	height := max(1, int(math.Round(float64(srcRect.Dy())*scale)))
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, srcRect, draw.Src, nil)
+	return dst
+}
+
+// applyOrientation turns the image as the EXIF orientation tag (1-8) asks.
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG, returning 1 (no
+// change) when there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 0 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
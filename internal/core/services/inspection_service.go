@@ -0,0 +1,338 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// followUpLeadDays is how long the crew has to correct a failed item before
+// its follow-up task is due.
+const followUpLeadDays = 7
+
+type InspectionService struct {
+	inspectionRepo ports.InspectionRepository
+	templateRepo   ports.TemplateRepository
+	projectRepo    ports.ProjectRepository
+	projects       *ProjectService
+}
+
+func NewInspectionService(inspectionRepo ports.InspectionRepository, templateRepo ports.TemplateRepository, projectRepo ports.ProjectRepository, projects *ProjectService) *InspectionService {
+	return &InspectionService{
+		inspectionRepo: inspectionRepo,
+		templateRepo:   templateRepo,
+		projectRepo:    projectRepo,
+		projects:       projects,
+	}
+}
+
+// StartInspection runs a checklist template on a project. When taskID is set
+// the inspection is about that task, and follow-up tasks go to its crew.
+func (s *InspectionService) StartInspection(projectID, companyID, userID, templateID, taskID string) (*domain.Inspection, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	template, err := s.templateRepo.GetInspectionTemplateByID(templateID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection template not found")
+	}
+
+	if taskID != "" {
+		if _, err := s.projectTask(taskID, projectID, companyID); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]domain.InspectionItem, len(template.Items))
+	for index, item := range template.Items {
+		items[index] = domain.InspectionItem{Label: item.Label, Guidance: item.Guidance}
+	}
+
+	now := time.Now()
+	inspection := &domain.Inspection{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		CompanyID:  companyID,
+		TaskID:     taskID,
+		TemplateID: template.ID,
+		Name:       template.Name,
+		Status:     domain.InspectionStatusInProgress,
+		Items:      items,
+		Signatures: []domain.InspectionSignature{},
+		UserID:     userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.inspectionRepo.CreateInspection(inspection); err != nil {
+		return nil, err
+	}
+
+	return inspection, nil
+}
+
+// ListInspections returns the inspections of a project, optionally only those
+// about a task.
+func (s *InspectionService) ListInspections(projectID, companyID, taskID string) ([]domain.Inspection, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	inspections, err := s.inspectionRepo.GetInspectionsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if taskID == "" {
+		return inspections, nil
+	}
+
+	filtered := make([]domain.Inspection, 0, len(inspections))
+	for _, inspection := range inspections {
+		if inspection.TaskID == taskID {
+			filtered = append(filtered, inspection)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *InspectionService) GetInspection(id, projectID, companyID string) (*domain.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetInspectionByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection not found")
+	}
+	return inspection, nil
+}
+
+// RecordResults replaces the results of the checklist, one per item in
+// order; an empty result leaves the item unchecked. Every item that fails for
+// the first time gets a follow-up task to correct it. Items that later pass
+// keep their follow-up task, which the crew closes as usual.
+func (s *InspectionService) RecordResults(id, projectID, companyID, userID, notes string, results []ports.InspectionResultInput) (*domain.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetInspectionByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection not found")
+	}
+	if inspection.Status == domain.InspectionStatusCompleted {
+		return nil, fmt.Errorf("inspection is completed")
+	}
+	if len(results) != len(inspection.Items) {
+		return nil, fmt.Errorf("inspection results must match the checklist items")
+	}
+	for _, result := range results {
+		if result.Result != "" && !domain.IsValidInspectionResult(result.Result) {
+			return nil, fmt.Errorf("invalid inspection result")
+		}
+	}
+
+	for index, result := range results {
+		inspection.Items[index].Result = result.Result
+		inspection.Items[index].Notes = strings.TrimSpace(result.Notes)
+	}
+	inspection.Notes = notes
+
+	if err := s.createFollowUpTasks(inspection, userID); err != nil {
+		return nil, err
+	}
+
+	inspection.UpdatedAt = time.Now()
+	if err := s.inspectionRepo.UpdateInspection(inspection); err != nil {
+		return nil, err
+	}
+
+	return inspection, nil
+}
+
+// SignInspection adds a signature once every item has been checked. The first
+// signature completes the inspection; later ones (the engineer after the
+// inspector, say) are added to it.
+func (s *InspectionService) SignInspection(id, projectID, companyID, userID, name, role string) (*domain.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetInspectionByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection not found")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("signer name is required")
+	}
+	for _, item := range inspection.Items {
+		if item.Result == "" {
+			return nil, fmt.Errorf("inspection has unchecked items")
+		}
+	}
+
+	now := time.Now()
+	inspection.Signatures = append(inspection.Signatures, domain.InspectionSignature{
+		Name:     name,
+		Role:     strings.TrimSpace(role),
+		UserID:   userID,
+		SignedAt: now,
+	})
+	if inspection.Status != domain.InspectionStatusCompleted {
+		inspection.Status = domain.InspectionStatusCompleted
+		inspection.CompletedAt = &now
+	}
+	inspection.UpdatedAt = now
+
+	if err := s.inspectionRepo.UpdateInspection(inspection); err != nil {
+		return nil, err
+	}
+
+	return inspection, nil
+}
+
+// DeleteInspection keeps the follow-up tasks, which are ordinary tasks of the
+// project by now.
+func (s *InspectionService) DeleteInspection(id, projectID, companyID string) error {
+	if _, err := s.inspectionRepo.GetInspectionByID(id, projectID, companyID); err != nil {
+		return fmt.Errorf("inspection not found")
+	}
+	return s.inspectionRepo.DeleteInspection(id, projectID, companyID)
+}
+
+// GetQualitySummary counts the checked items of every inspection of the
+// project, overall and per checklist, and lists the failures whose follow-up
+// task is still open. Pass percentages leave out items marked not applicable.
+func (s *InspectionService) GetQualitySummary(projectID, companyID string) (*domain.QualitySummary, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	inspections, err := s.inspectionRepo.GetInspectionsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.projectRepo.GetTasksByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	taskStatus := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		taskStatus[task.ID] = task.Status
+	}
+
+	summary := &domain.QualitySummary{
+		ProjectID:           projectID,
+		Inspections:         len(inspections),
+		ByTemplate:          []domain.QualityTemplateSummary{},
+		OutstandingFailures: []domain.QualityFailure{},
+	}
+	byTemplate := map[string]int{}
+
+	for _, inspection := range inspections {
+		if inspection.Status == domain.InspectionStatusCompleted {
+			summary.Completed++
+		} else {
+			summary.InProgress++
+		}
+
+		index, ok := byTemplate[inspection.TemplateID]
+		if !ok {
+			index = len(summary.ByTemplate)
+			byTemplate[inspection.TemplateID] = index
+			summary.ByTemplate = append(summary.ByTemplate, domain.QualityTemplateSummary{
+				TemplateID: inspection.TemplateID,
+				Name:       inspection.Name,
+			})
+		}
+		templateSummary := &summary.ByTemplate[index]
+		templateSummary.Inspections++
+
+		for _, item := range inspection.Items {
+			switch item.Result {
+			case domain.InspectionResultPass:
+				summary.Passed++
+				templateSummary.Passed++
+			case domain.InspectionResultFail:
+				summary.Failed++
+				templateSummary.Failed++
+			case domain.InspectionResultNA:
+				summary.NotApplicable++
+			default:
+				summary.Pending++
+			}
+
+			if item.FollowUpTaskID == "" {
+				continue
+			}
+			summary.FollowUpTasks++
+			status, exists := taskStatus[item.FollowUpTaskID]
+			if !exists || status == domain.TaskStatusDone {
+				continue
+			}
+			summary.OpenFollowUpTasks++
+			if item.Result == domain.InspectionResultFail {
+				summary.OutstandingFailures = append(summary.OutstandingFailures, domain.QualityFailure{
+					InspectionID:   inspection.ID,
+					InspectionName: inspection.Name,
+					Label:          item.Label,
+					Notes:          item.Notes,
+					FollowUpTaskID: item.FollowUpTaskID,
+					FollowUpStatus: status,
+					InspectedAt:    inspection.UpdatedAt,
+				})
+			}
+		}
+	}
+
+	summary.PassPercent = passPercent(summary.Passed, summary.Failed)
+	for index := range summary.ByTemplate {
+		templateSummary := &summary.ByTemplate[index]
+		templateSummary.PassPercent = passPercent(templateSummary.Passed, templateSummary.Failed)
+	}
+
+	return summary, nil
+}
+
+// createFollowUpTasks adds a task to correct each failed item that has none
+// yet, through the project service so it is scheduled, tracked and synced
+// like any other task.
+func (s *InspectionService) createFollowUpTasks(inspection *domain.Inspection, userID string) error {
+	var crew *string
+	if inspection.TaskID != "" {
+		if task, err := s.projectTask(inspection.TaskID, inspection.ProjectID, inspection.CompanyID); err == nil && task.Crew != "" {
+			crew = &task.Crew
+		}
+	}
+
+	today := truncateToDay(time.Now())
+	schedule := ports.TaskScheduleInput{
+		StartDate: today.Format("2006-01-02"),
+		DueDate:   today.AddDate(0, 0, followUpLeadDays).Format("2006-01-02"),
+	}
+
+	for index := range inspection.Items {
+		item := &inspection.Items[index]
+		if item.Result != domain.InspectionResultFail || item.FollowUpTaskID != "" {
+			continue
+		}
+		name := fmt.Sprintf("Corrigir: %s (%s)", item.Label, inspection.Name)
+		task, err := s.projects.AddTask(inspection.ProjectID, name, domain.TaskStatusTodo, inspection.CompanyID, userID, schedule, ports.TaskAssignmentInput{Crew: crew})
+		if err != nil {
+			return err
+		}
+		item.FollowUpTaskID = task.ID
+	}
+
+	return nil
+}
+
+func (s *InspectionService) projectTask(taskID, projectID, companyID string) (*domain.Task, error) {
+	task, err := s.projectRepo.GetTaskByID(taskID, companyID)
+	if err != nil || task.ProjectID != projectID {
+		return nil, fmt.Errorf("task not found or access denied")
+	}
+	return task, nil
+}
+
+func passPercent(passed, failed int) float64 {
+	if passed+failed == 0 {
+		return 0
+	}
+	return roundProgress(float64(passed) / float64(passed+failed) * 100)
+}
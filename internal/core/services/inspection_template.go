@@ -0,0 +1,110 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *TemplateService) CreateInspectionTemplate(companyID, name, description string, items []domain.InspectionTemplateItem) (*domain.InspectionTemplate, error) {
+	if err := validateInspectionTemplate(name, items); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &domain.InspectionTemplate{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		Items:       trimInspectionTemplateItems(items),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.templateRepo.CreateInspectionTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) ListInspectionTemplates(companyID string) ([]domain.InspectionTemplate, error) {
+	return s.templateRepo.GetInspectionTemplatesByCompany(companyID)
+}
+
+func (s *TemplateService) GetInspectionTemplate(id, companyID string) (*domain.InspectionTemplate, error) {
+	template, err := s.templateRepo.GetInspectionTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection template not found")
+	}
+	return template, nil
+}
+
+func (s *TemplateService) UpdateInspectionTemplate(id, companyID, name, description string, items []domain.InspectionTemplateItem) (*domain.InspectionTemplate, error) {
+	template, err := s.templateRepo.GetInspectionTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("inspection template not found")
+	}
+
+	if err := validateInspectionTemplate(name, items); err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(name)
+	template.Description = description
+	template.Items = trimInspectionTemplateItems(items)
+	template.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.UpdateInspectionTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// DeleteInspectionTemplate leaves inspections already run from the template
+// as they are, since they carry their own copy of the checklist.
+func (s *TemplateService) DeleteInspectionTemplate(id, companyID string) error {
+	if _, err := s.templateRepo.GetInspectionTemplateByID(id, companyID); err != nil {
+		return fmt.Errorf("inspection template not found")
+	}
+	return s.templateRepo.DeleteInspectionTemplate(id, companyID)
+}
+
+func validateInspectionTemplate(name string, items []domain.InspectionTemplateItem) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("inspection checklist items are required")
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		label := strings.ToLower(strings.TrimSpace(item.Label))
+		if label == "" {
+			return fmt.Errorf("inspection item label is required")
+		}
+		if seen[label] {
+			return fmt.Errorf("duplicate inspection item")
+		}
+		seen[label] = true
+	}
+
+	return nil
+}
+
+func trimInspectionTemplateItems(items []domain.InspectionTemplateItem) []domain.InspectionTemplateItem {
+	trimmed := make([]domain.InspectionTemplateItem, len(items))
+	for index, item := range items {
+		trimmed[index] = domain.InspectionTemplateItem{
+			Label:    strings.TrimSpace(item.Label),
+			Guidance: strings.TrimSpace(item.Guidance),
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InvoiceService struct {
+	invoiceRepo   ports.InvoiceRepository
+	projectRepo   ports.ProjectRepository
+	milestoneRepo ports.MilestoneRepository
+}
+
+func NewInvoiceService(invoiceRepo ports.InvoiceRepository, projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository) *InvoiceService {
+	return &InvoiceService{
+		invoiceRepo:   invoiceRepo,
+		projectRepo:   projectRepo,
+		milestoneRepo: milestoneRepo,
+	}
+}
+
+// GetPaymentSchedule returns the project's installments in due date order,
+// with what has been invoiced, received and is overdue.
+func (s *InvoiceService) GetPaymentSchedule(projectID, companyID string) (*domain.PaymentSchedule, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	invoices, err := s.invoiceRepo.GetInvoicesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	markOverdueInvoices(invoices, now)
+	return &domain.PaymentSchedule{
+		ProjectID: projectID,
+		Invoices:  invoices,
+		Totals:    summarizeInvoices(invoices, now),
+	}, nil
+}
+
+// maxInstallments caps the installments created at once. Some stores create
+// a schedule in a single transaction, with two records per installment.
+const maxInstallments = 50
+
+// CreatePaymentSchedule adds several installments at once, numbered after the
+// project's last invoice. Nothing is created unless every installment is
+// valid.
+func (s *InvoiceService) CreatePaymentSchedule(projectID, companyID, userID string, installments []ports.InvoiceInput) ([]domain.Invoice, error) {
+	if len(installments) == 0 {
+		return nil, fmt.Errorf("installments are required")
+	}
+	if len(installments) > maxInstallments {
+		return nil, fmt.Errorf("too many installments")
+	}
+
+	return s.createInvoices(projectID, companyID, userID, installments)
+}
+
+func (s *InvoiceService) CreateInvoice(projectID, companyID, userID string, input ports.InvoiceInput) (*domain.Invoice, error) {
+	invoices, err := s.createInvoices(projectID, companyID, userID, []ports.InvoiceInput{input})
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoices[0], nil
+}
+
+func (s *InvoiceService) GetInvoice(id, projectID, companyID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	markOverdue(invoice, time.Now())
+	return invoice, nil
+}
+
+// UpdateInvoice edits an invoice that is not cancelled. The amount can not go
+// below what has already been paid; changing it settles the invoice again.
+func (s *InvoiceService) UpdateInvoice(id, projectID, companyID string, input ports.InvoiceInput) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	if invoice.Status == domain.InvoiceStatusCancelled {
+		return nil, fmt.Errorf("invoice is locked")
+	}
+
+	milestones, err := s.milestoneIDs(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyInvoiceInput(invoice, input, milestones); err != nil {
+		return nil, err
+	}
+	if invoice.Amount < invoice.AmountPaid {
+		return nil, fmt.Errorf("invoice amount is below the amount paid")
+	}
+
+	now := time.Now()
+	settleInvoice(invoice)
+	invoice.UpdatedAt = now
+
+	if err := s.invoiceRepo.UpdateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	markOverdue(invoice, now)
+	return invoice, nil
+}
+
+// CancelInvoice keeps the invoice on record without it counting as owed.
+// Invoices with payments must have them removed first.
+func (s *InvoiceService) CancelInvoice(id, projectID, companyID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	if invoice.Status == domain.InvoiceStatusCancelled {
+		return nil, fmt.Errorf("invoice is locked")
+	}
+	if len(invoice.Payments) > 0 {
+		return nil, fmt.Errorf("invoice has payments")
+	}
+
+	now := time.Now()
+	invoice.Status = domain.InvoiceStatusCancelled
+	invoice.CancelledAt = &now
+	invoice.UpdatedAt = now
+
+	if err := s.invoiceRepo.UpdateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *InvoiceService) DeleteInvoice(id, projectID, companyID string) error {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return fmt.Errorf("invoice not found")
+	}
+	if len(invoice.Payments) > 0 {
+		return fmt.Errorf("invoice has payments")
+	}
+	return s.invoiceRepo.DeleteInvoice(id, projectID, companyID)
+}
+
+// RecordPayment adds a full or partial payment. Payments can not exceed what
+// is still owed.
+func (s *InvoiceService) RecordPayment(id, projectID, companyID, userID string, input ports.PaymentInput) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	switch invoice.Status {
+	case domain.InvoiceStatusCancelled:
+		return nil, fmt.Errorf("invoice is locked")
+	case domain.InvoiceStatusPaid:
+		return nil, fmt.Errorf("invoice already paid")
+	}
+
+	amount := roundCurrency(input.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid payment amount")
+	}
+	if amount > roundCurrency(invoice.Outstanding()) {
+		return nil, fmt.Errorf("payment exceeds outstanding amount")
+	}
+
+	now := time.Now()
+	paidAt := truncateToDay(now)
+	if input.PaidAt != "" {
+		paidAt, err = parseDate(input.PaidAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payment date")
+		}
+	}
+
+	invoice.Payments = append(invoice.Payments, domain.InvoicePayment{
+		ID:        uuid.New().String(),
+		Amount:    amount,
+		PaidAt:    paidAt,
+		Method:    strings.TrimSpace(input.Method),
+		Notes:     input.Notes,
+		UserID:    userID,
+		CreatedAt: now,
+	})
+	settleInvoice(invoice)
+	invoice.UpdatedAt = now
+
+	if err := s.invoiceRepo.UpdateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	markOverdue(invoice, now)
+	return invoice, nil
+}
+
+// DeletePayment removes a payment recorded by mistake.
+func (s *InvoiceService) DeletePayment(id, projectID, companyID, paymentID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetInvoiceByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+
+	payments := make([]domain.InvoicePayment, 0, len(invoice.Payments))
+	for _, payment := range invoice.Payments {
+		if payment.ID != paymentID {
+			payments = append(payments, payment)
+		}
+	}
+	if len(payments) == len(invoice.Payments) {
+		return nil, fmt.Errorf("payment not found")
+	}
+
+	now := time.Now()
+	invoice.Payments = payments
+	settleInvoice(invoice)
+	invoice.UpdatedAt = now
+
+	if err := s.invoiceRepo.UpdateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	markOverdue(invoice, now)
+	return invoice, nil
+}
+
+// ListOverdueInvoices returns the company's unpaid invoices past their due
+// date, across all projects, oldest first.
+func (s *InvoiceService) ListOverdueInvoices(companyID string) ([]domain.Invoice, error) {
+	invoices, err := s.invoiceRepo.GetInvoicesByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	overdue := make([]domain.Invoice, 0)
+	for _, invoice := range invoices {
+		if markOverdue(&invoice, now) {
+			overdue = append(overdue, invoice)
+		}
+	}
+	return overdue, nil
+}
+
+// numberingAttempts is how many times createInvoices numbers the invoices
+// again after another request took one of the numbers first.
+const numberingAttempts = 3
+
+// createInvoices stores the installments as the project's next invoices. The
+// store refuses a number already in use, so invoices created at the same time
+// are numbered again rather than sharing a number.
+func (s *InvoiceService) createInvoices(projectID, companyID, userID string, inputs []ports.InvoiceInput) ([]domain.Invoice, error) {
+	for attempt := 1; ; attempt++ {
+		invoices, err := s.newInvoices(projectID, companyID, userID, inputs)
+		if err != nil {
+			return nil, err
+		}
+		err = s.invoiceRepo.CreateInvoices(invoices)
+		if err == nil {
+			return invoices, nil
+		}
+		if !errors.Is(err, ports.ErrVersionConflict) || attempt == numberingAttempts {
+			return nil, err
+		}
+	}
+}
+
+// newInvoices validates the installments and builds them as open invoices of
+// the project, billed to its client.
+func (s *InvoiceService) newInvoices(projectID, companyID, userID string, inputs []ports.InvoiceInput) ([]domain.Invoice, error) {
+	project, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	existing, err := s.invoiceRepo.GetInvoicesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	number := 1
+	for _, invoice := range existing {
+		number = max(number, invoice.Number+1)
+	}
+
+	milestones, err := s.milestoneIDs(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoices := make([]domain.Invoice, len(inputs))
+	for index, input := range inputs {
+		invoice := domain.Invoice{
+			ID:        uuid.New().String(),
+			ProjectID: projectID,
+			CompanyID: companyID,
+			ClientID:  project.ClientID,
+			Number:    number + index,
+			Status:    domain.InvoiceStatusOpen,
+			Payments:  []domain.InvoicePayment{},
+			UserID:    userID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := applyInvoiceInput(&invoice, input, milestones); err != nil {
+			return nil, err
+		}
+		invoices[index] = invoice
+	}
+	return invoices, nil
+}
+
+func (s *InvoiceService) milestoneIDs(projectID, companyID string) (map[string]bool, error) {
+	milestones, err := s.milestoneRepo.GetMilestonesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(milestones))
+	for _, milestone := range milestones {
+		ids[milestone.ID] = true
+	}
+	return ids, nil
+}
+
+// applyInvoiceInput validates and copies the editable fields. Installments
+// without a description are named after their number.
+func applyInvoiceInput(invoice *domain.Invoice, input ports.InvoiceInput, milestones map[string]bool) error {
+	amount := roundCurrency(input.Amount)
+	if amount <= 0 {
+		return fmt.Errorf("invalid invoice amount")
+	}
+	dueDate, err := parseDate(input.DueDate)
+	if err != nil {
+		return fmt.Errorf("invalid due date")
+	}
+	if input.MilestoneID != "" && !milestones[input.MilestoneID] {
+		return fmt.Errorf("milestone not found")
+	}
+
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		description = fmt.Sprintf("Parcela %d", invoice.Number)
+	}
+
+	invoice.Description = description
+	invoice.Amount = amount
+	invoice.DueDate = dueDate
+	invoice.MilestoneID = input.MilestoneID
+	invoice.Notes = input.Notes
+	return nil
+}
+
+// settleInvoice derives the amount paid and the status from the payments. A
+// fully paid invoice is paid on the date of its latest payment.
+func settleInvoice(invoice *domain.Invoice) {
+	paid := 0.0
+	var paidAt time.Time
+	for _, payment := range invoice.Payments {
+		paid += payment.Amount
+		if payment.PaidAt.After(paidAt) {
+			paidAt = payment.PaidAt
+		}
+	}
+	invoice.AmountPaid = roundCurrency(paid)
+	invoice.PaidAt = nil
+
+	switch {
+	case invoice.AmountPaid >= invoice.Amount:
+		invoice.Status = domain.InvoiceStatusPaid
+		invoice.PaidAt = &paidAt
+	case invoice.AmountPaid > 0:
+		invoice.Status = domain.InvoiceStatusPartiallyPaid
+	default:
+		invoice.Status = domain.InvoiceStatusOpen
+	}
+}
+
+// markOverdue sets the invoice's overdue flag and reports it.
+func markOverdue(invoice *domain.Invoice, now time.Time) bool {
+	invoice.Overdue = invoice.IsOverdue(now)
+	invoice.DaysOverdue = 0
+	if invoice.Overdue {
+		invoice.DaysOverdue = int(truncateToDay(now).Sub(truncateToDay(invoice.DueDate)).Hours() / 24)
+	}
+	return invoice.Overdue
+}
+
+func markOverdueInvoices(invoices []domain.Invoice, now time.Time) {
+	for index := range invoices {
+		markOverdue(&invoices[index], now)
+	}
+}
+
+func summarizeInvoices(invoices []domain.Invoice, now time.Time) domain.ReceivableTotals {
+	var totals domain.ReceivableTotals
+	for _, invoice := range invoices {
+		if invoice.Status == domain.InvoiceStatusCancelled {
+			continue
+		}
+		totals.Invoiced += invoice.Amount
+		totals.Received += invoice.AmountPaid
+		totals.Outstanding += invoice.Outstanding()
+		if invoice.IsOverdue(now) {
+			totals.Overdue += invoice.Outstanding()
+			totals.OverdueInvoices++
+		}
+	}
+	totals.Invoiced = roundCurrency(totals.Invoiced)
+	totals.Received = roundCurrency(totals.Received)
+	totals.Outstanding = roundCurrency(totals.Outstanding)
+	totals.Overdue = roundCurrency(totals.Overdue)
+	return totals
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxLeadMessageLength = 2000
+
+// LeadService captures prospects from the public company page as clients in
+// the first open stage of the sales pipeline.
+type LeadService struct {
+	clientRepo      ports.ClientRepository
+	companyRepo     ports.CompanyRepository
+	userRepo        ports.UserRepository
+	captchaVerifier ports.CaptchaVerifier
+	notifier        ports.LeadNotifier
+}
+
+// NewLeadService builds the service. captchaVerifier may be nil, in which
+// case captcha tokens are not checked.
+func NewLeadService(clientRepo ports.ClientRepository, companyRepo ports.CompanyRepository, userRepo ports.UserRepository, captchaVerifier ports.CaptchaVerifier, notifier ports.LeadNotifier) *LeadService {
+	return &LeadService{
+		clientRepo:      clientRepo,
+		companyRepo:     companyRepo,
+		userRepo:        userRepo,
+		captchaVerifier: captchaVerifier,
+		notifier:        notifier,
+	}
+}
+
+// CreatePublicLead creates the client with its message as a comment and
+// notifies the company's admins. A failed notification does not fail the
+// lead.
+func (s *LeadService) CreatePublicLead(slug string, input ports.PublicLeadInput) (*domain.Client, error) {
+	company, err := s.companyRepo.GetCompanyBySlug(Slugify(slug))
+	if err != nil {
+		return nil, fmt.Errorf("public page not found")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	phone := strings.TrimSpace(input.Phone)
+	message := strings.TrimSpace(input.Message)
+	if name == "" || phone == "" {
+		return nil, fmt.Errorf("name and phone are required")
+	}
+	if utf8.RuneCountInString(message) > maxLeadMessageLength {
+		return nil, fmt.Errorf("message is too long")
+	}
+
+	if s.captchaVerifier != nil {
+		valid, err := s.captchaVerifier.Verify(input.CaptchaToken, input.RemoteIP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify captcha: %w", err)
+		}
+		if !valid {
+			return nil, fmt.Errorf("captcha verification failed")
+		}
+	}
+
+	var stage string
+	for _, candidate := range pipelineStagesOf(company) {
+		if candidate.Outcome == domain.PipelineOutcomeOpen {
+			stage = candidate.ID
+			break
+		}
+	}
+
+	now := time.Now()
+	lead := &domain.Client{
+		ID:             uuid.New().String(),
+		CompanyID:      company.ID,
+		Name:           name,
+		Phone:          phone,
+		PipelineStage:  stage,
+		LeadSource:     domain.LeadSourcePublicPage,
+		StageChangedAt: &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.clientRepo.CreateClient(lead); err != nil {
+		return nil, err
+	}
+
+	if message != "" {
+		comment := &domain.Comment{
+			ID:        uuid.New().String(),
+			ClientID:  lead.ID,
+			Content:   message,
+			CreatedAt: now,
+		}
+		if err := s.clientRepo.AddComment(comment); err != nil {
+			return nil, err
+		}
+		lead.Comments = []domain.Comment{*comment}
+	}
+
+	s.notifyAdmins(company, lead, message)
+
+	return lead, nil
+}
+
+// notifyAdmins tells the company's admins about the lead. Failures are only
+// logged, by company and client ID: the lead's contact details stay out of
+// the log.
+func (s *LeadService) notifyAdmins(company *domain.Company, lead *domain.Client, message string) {
+	members, err := s.userRepo.ListUsersByCompanyID(company.ID)
+	if err != nil {
+		log.Printf("lead %s of company %s: list admins: %v", lead.ID, company.ID, err)
+		return
+	}
+	admins := make([]domain.User, 0, len(members))
+	for _, member := range members {
+		if member.Role == "admin" {
+			admins = append(admins, member)
+		}
+	}
+	if err := s.notifier.NotifyNewLead(company, admins, lead, message); err != nil {
+		log.Printf("lead %s of company %s: notify admins: %v", lead.ID, company.ID, err)
+	}
+}
@@ -3,10 +3,12 @@ package services
 import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type LinkService struct {
@@ -137,8 +139,26 @@ func (s *LinkService) GetLinkAnalytics(companyID, startDate, endDate string) (*d
 	return response, nil
 }
 
+// DeleteLink moves the link to the trash. It is removed for good, with its
+// clicks, by the retention job.
 func (s *LinkService) DeleteLink(id, companyID string) error {
-	return s.linkRepo.DeleteLink(id, companyID)
+	if err := s.linkRepo.TrashLink(id, companyID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("link not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *LinkService) RestoreLink(id, companyID string) error {
+	if err := s.linkRepo.RestoreLink(id, companyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("link not found in trash")
+		}
+		return err
+	}
+	return nil
 }
 
 func (s *LinkService) TrackLinkClick(id string) error {
@@ -0,0 +1,497 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MaterialService struct {
+	materialRepo ports.MaterialRepository
+	projectRepo  ports.ProjectRepository
+}
+
+func NewMaterialService(materialRepo ports.MaterialRepository, projectRepo ports.ProjectRepository) *MaterialService {
+	return &MaterialService{
+		materialRepo: materialRepo,
+		projectRepo:  projectRepo,
+	}
+}
+
+func (s *MaterialService) CreateMaterial(companyID, name, unit, description string, minStock float64) (*domain.Material, error) {
+	if minStock < 0 {
+		return nil, fmt.Errorf("invalid quantity")
+	}
+
+	now := time.Now()
+	material := &domain.Material{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		Name:        name,
+		Unit:        unit,
+		Description: description,
+		MinStock:    minStock,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.materialRepo.CreateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	return material, nil
+}
+
+func (s *MaterialService) ListMaterials(companyID string) ([]domain.Material, error) {
+	materials, err := s.materialRepo.GetMaterialsByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	if materials == nil {
+		materials = []domain.Material{}
+	}
+	return materials, nil
+}
+
+func (s *MaterialService) UpdateMaterial(id, companyID, name, unit, description string, minStock float64) (*domain.Material, error) {
+	material, err := s.materialRepo.GetMaterialByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("material not found")
+	}
+	if minStock < 0 {
+		return nil, fmt.Errorf("invalid quantity")
+	}
+
+	material.Name = name
+	material.Unit = unit
+	material.Description = description
+	material.MinStock = minStock
+	material.UpdatedAt = time.Now()
+
+	if err := s.materialRepo.UpdateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	return material, nil
+}
+
+func (s *MaterialService) DeleteMaterial(id, companyID string) error {
+	if _, err := s.materialRepo.GetMaterialByID(id, companyID); err != nil {
+		return fmt.Errorf("material not found")
+	}
+
+	return s.materialRepo.DeleteMaterial(id, companyID)
+}
+
+func (s *MaterialService) GetProjectStock(projectID, companyID string, lowStockOnly bool) ([]domain.ProjectMaterialStock, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	materials, err := s.materialRepo.GetMaterialsByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.materialRepo.GetStockMovementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds, err := s.materialRepo.GetMaterialThresholdsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := buildProjectStock(materials, movements, thresholds)
+	if !lowStockOnly {
+		return stock, nil
+	}
+
+	lowStock := make([]domain.ProjectMaterialStock, 0, len(stock))
+	for _, item := range stock {
+		if item.LowStock {
+			lowStock = append(lowStock, item)
+		}
+	}
+	return lowStock, nil
+}
+
+func (s *MaterialService) ListStockMovements(projectID, companyID, materialID string) ([]domain.StockMovement, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	movements, err := s.materialRepo.GetStockMovementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]domain.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		if materialID != "" && movement.MaterialID != materialID {
+			continue
+		}
+		filtered = append(filtered, movement)
+	}
+	return filtered, nil
+}
+
+func (s *MaterialService) RecordStockMovement(projectID, companyID, userID, materialID, movementType, movementDate, notes string, quantity float64) (*domain.StockMovement, error) {
+	if movementType != domain.StockMovementReceived && movementType != domain.StockMovementConsumed {
+		return nil, fmt.Errorf("invalid movement type")
+	}
+
+	parsedDate, err := s.validateMovement(projectID, companyID, materialID, movementDate, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if movementType == domain.StockMovementConsumed {
+		if err := s.ensureAvailable(projectID, companyID, materialID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	movement := domain.StockMovement{
+		ID:           uuid.New().String(),
+		CompanyID:    companyID,
+		ProjectID:    projectID,
+		MaterialID:   materialID,
+		Type:         movementType,
+		Quantity:     quantity,
+		Notes:        notes,
+		UserID:       userID,
+		MovementDate: parsedDate,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.materialRepo.CreateStockMovements([]domain.StockMovement{movement}); err != nil {
+		return nil, err
+	}
+
+	return &movement, nil
+}
+
+func (s *MaterialService) TransferStock(projectID, companyID, userID, materialID, toProjectID, movementDate, notes string, quantity float64) ([]domain.StockMovement, error) {
+	if toProjectID == "" || toProjectID == projectID {
+		return nil, fmt.Errorf("invalid destination project")
+	}
+
+	parsedDate, err := s.validateMovement(projectID, companyID, materialID, movementDate, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.projectRepo.GetProjectByID(toProjectID, companyID); err != nil {
+		return nil, fmt.Errorf("invalid destination project")
+	}
+
+	if err := s.ensureAvailable(projectID, companyID, materialID, quantity); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transferID := uuid.New().String()
+	movements := []domain.StockMovement{
+		{
+			ID:                   uuid.New().String(),
+			CompanyID:            companyID,
+			ProjectID:            projectID,
+			MaterialID:           materialID,
+			Type:                 domain.StockMovementTransferOut,
+			Quantity:             quantity,
+			TransferID:           transferID,
+			CounterpartProjectID: toProjectID,
+			Notes:                notes,
+			UserID:               userID,
+			MovementDate:         parsedDate,
+			CreatedAt:            now,
+		},
+		{
+			ID:                   uuid.New().String(),
+			CompanyID:            companyID,
+			ProjectID:            toProjectID,
+			MaterialID:           materialID,
+			Type:                 domain.StockMovementTransferIn,
+			Quantity:             quantity,
+			TransferID:           transferID,
+			CounterpartProjectID: projectID,
+			Notes:                notes,
+			UserID:               userID,
+			MovementDate:         parsedDate,
+			CreatedAt:            now,
+		},
+	}
+
+	if err := s.materialRepo.CreateStockMovements(movements); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (s *MaterialService) SetMaterialThreshold(projectID, companyID, materialID string, minQuantity float64) (*domain.MaterialThreshold, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if _, err := s.materialRepo.GetMaterialByID(materialID, companyID); err != nil {
+		return nil, fmt.Errorf("material not found")
+	}
+	if minQuantity < 0 {
+		return nil, fmt.Errorf("invalid quantity")
+	}
+
+	threshold := &domain.MaterialThreshold{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		ProjectID:   projectID,
+		MaterialID:  materialID,
+		MinQuantity: minQuantity,
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.materialRepo.SaveMaterialThreshold(threshold); err != nil {
+		return nil, err
+	}
+
+	return threshold, nil
+}
+
+func (s *MaterialService) CreatePurchaseRequest(projectID, companyID, userID, materialID, notes string, quantity float64) (*domain.PurchaseRequest, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if _, err := s.materialRepo.GetMaterialByID(materialID, companyID); err != nil {
+		return nil, fmt.Errorf("material not found")
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("invalid quantity")
+	}
+
+	now := time.Now()
+	request := &domain.PurchaseRequest{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		ProjectID:   projectID,
+		MaterialID:  materialID,
+		Quantity:    quantity,
+		Status:      domain.PurchaseRequestPending,
+		Notes:       notes,
+		RequestedBy: userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.materialRepo.CreatePurchaseRequest(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *MaterialService) ListPurchaseRequests(projectID, companyID, status string) ([]domain.PurchaseRequest, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	requests, err := s.materialRepo.GetPurchaseRequestsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]domain.PurchaseRequest, 0, len(requests))
+	for _, request := range requests {
+		if status != "" && request.Status != status {
+			continue
+		}
+		filtered = append(filtered, request)
+	}
+	return filtered, nil
+}
+
+func (s *MaterialService) ApprovePurchaseRequest(id, projectID, companyID, userID, note string) (*domain.PurchaseRequest, error) {
+	return s.decidePurchaseRequest(id, projectID, companyID, userID, note, domain.PurchaseRequestApproved)
+}
+
+func (s *MaterialService) RejectPurchaseRequest(id, projectID, companyID, userID, note string) (*domain.PurchaseRequest, error) {
+	return s.decidePurchaseRequest(id, projectID, companyID, userID, note, domain.PurchaseRequestRejected)
+}
+
+func (s *MaterialService) ReceivePurchaseRequest(id, projectID, companyID, userID, receivedDate string) (*domain.PurchaseRequest, error) {
+	request, err := s.materialRepo.GetPurchaseRequestByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase request not found")
+	}
+	if request.Status != domain.PurchaseRequestApproved {
+		return nil, fmt.Errorf("purchase request is not approved")
+	}
+
+	now := time.Now()
+	parsedDate := now
+	if receivedDate != "" {
+		parsedDate, err = parseDate(receivedDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid movement date")
+		}
+	}
+
+	movement := domain.StockMovement{
+		ID:                uuid.New().String(),
+		CompanyID:         companyID,
+		ProjectID:         projectID,
+		MaterialID:        request.MaterialID,
+		Type:              domain.StockMovementReceived,
+		Quantity:          request.Quantity,
+		PurchaseRequestID: request.ID,
+		UserID:            userID,
+		MovementDate:      parsedDate,
+		CreatedAt:         now,
+	}
+
+	if err := s.materialRepo.CreateStockMovements([]domain.StockMovement{movement}); err != nil {
+		return nil, err
+	}
+
+	request.Status = domain.PurchaseRequestReceived
+	request.ReceivedAt = &now
+	request.UpdatedAt = now
+
+	if err := s.materialRepo.UpdatePurchaseRequest(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *MaterialService) decidePurchaseRequest(id, projectID, companyID, userID, note, status string) (*domain.PurchaseRequest, error) {
+	request, err := s.materialRepo.GetPurchaseRequestByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase request not found")
+	}
+	if request.Status != domain.PurchaseRequestPending {
+		return nil, fmt.Errorf("purchase request is not pending")
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.DecidedBy = userID
+	request.DecisionNote = note
+	request.DecidedAt = &now
+	request.UpdatedAt = now
+
+	if err := s.materialRepo.UpdatePurchaseRequest(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *MaterialService) validateMovement(projectID, companyID, materialID, movementDate string, quantity float64) (time.Time, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return time.Time{}, fmt.Errorf("project not found or access denied")
+	}
+	if _, err := s.materialRepo.GetMaterialByID(materialID, companyID); err != nil {
+		return time.Time{}, fmt.Errorf("material not found")
+	}
+	if quantity <= 0 {
+		return time.Time{}, fmt.Errorf("invalid quantity")
+	}
+	if movementDate == "" {
+		return time.Now(), nil
+	}
+
+	parsedDate, err := parseDate(movementDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid movement date")
+	}
+	return parsedDate, nil
+}
+
+func (s *MaterialService) ensureAvailable(projectID, companyID, materialID string, quantity float64) error {
+	movements, err := s.materialRepo.GetStockMovementsByProject(projectID, companyID)
+	if err != nil {
+		return err
+	}
+
+	var balance float64
+	for _, movement := range movements {
+		if movement.MaterialID == materialID {
+			balance += signedQuantity(movement)
+		}
+	}
+
+	if quantity > balance {
+		return fmt.Errorf("insufficient stock")
+	}
+	return nil
+}
+
+func buildProjectStock(materials []domain.Material, movements []domain.StockMovement, thresholds []domain.MaterialThreshold) []domain.ProjectMaterialStock {
+	minQuantities := make(map[string]float64, len(thresholds))
+	for _, threshold := range thresholds {
+		minQuantities[threshold.MaterialID] = threshold.MinQuantity
+	}
+
+	stockByMaterial := make(map[string]*domain.ProjectMaterialStock, len(materials))
+	for _, material := range materials {
+		minQuantity, ok := minQuantities[material.ID]
+		if !ok {
+			minQuantity = material.MinStock
+		}
+		stockByMaterial[material.ID] = &domain.ProjectMaterialStock{
+			MaterialID:  material.ID,
+			Name:        material.Name,
+			Unit:        material.Unit,
+			MinQuantity: minQuantity,
+		}
+	}
+
+	touched := make(map[string]bool)
+	for _, movement := range movements {
+		stock, ok := stockByMaterial[movement.MaterialID]
+		if !ok {
+			continue
+		}
+		touched[movement.MaterialID] = true
+		switch movement.Type {
+		case domain.StockMovementReceived:
+			stock.Received += movement.Quantity
+		case domain.StockMovementConsumed:
+			stock.Consumed += movement.Quantity
+		case domain.StockMovementTransferIn:
+			stock.TransferredIn += movement.Quantity
+		case domain.StockMovementTransferOut:
+			stock.TransferredOut += movement.Quantity
+		}
+		stock.Balance += signedQuantity(movement)
+	}
+
+	stock := make([]domain.ProjectMaterialStock, 0, len(stockByMaterial))
+	for materialID, item := range stockByMaterial {
+		_, hasThreshold := minQuantities[materialID]
+		if !touched[materialID] && !hasThreshold {
+			continue
+		}
+		item.LowStock = item.MinQuantity > 0 && item.Balance <= item.MinQuantity
+		stock = append(stock, *item)
+	}
+	sort.Slice(stock, func(i, j int) bool {
+		return stock[i].Name < stock[j].Name
+	})
+	return stock
+}
+
+func signedQuantity(movement domain.StockMovement) float64 {
+	switch movement.Type {
+	case domain.StockMovementReceived, domain.StockMovementTransferIn:
+		return movement.Quantity
+	case domain.StockMovementConsumed, domain.StockMovementTransferOut:
+		return -movement.Quantity
+	}
+	return 0
+}
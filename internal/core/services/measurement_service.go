@@ -0,0 +1,565 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// quantityTolerance absorbs float rounding when comparing measured quantities
+// with contract quantities.
+const quantityTolerance = 1e-9
+
+type MeasurementService struct {
+	measurementRepo ports.MeasurementRepository
+	projectRepo     ports.ProjectRepository
+	statusRepo      ports.StatusHistoryRepository
+	companyRepo     ports.CompanyRepository
+	invoiceService  ports.InvoiceService
+	storage         ports.FileStorage
+	pdfRenderer     ports.MeasurementReportRenderer
+	csvRenderer     ports.MeasurementReportRenderer
+}
+
+func NewMeasurementService(measurementRepo ports.MeasurementRepository, projectRepo ports.ProjectRepository, statusRepo ports.StatusHistoryRepository, companyRepo ports.CompanyRepository, invoiceService ports.InvoiceService, storage ports.FileStorage, pdfRenderer, csvRenderer ports.MeasurementReportRenderer) *MeasurementService {
+	return &MeasurementService{
+		measurementRepo: measurementRepo,
+		projectRepo:     projectRepo,
+		statusRepo:      statusRepo,
+		companyRepo:     companyRepo,
+		invoiceService:  invoiceService,
+		storage:         storage,
+		pdfRenderer:     pdfRenderer,
+		csvRenderer:     csvRenderer,
+	}
+}
+
+func (s *MeasurementService) CreateContractItem(projectID, companyID string, input ports.ContractItemInput) (*domain.ContractItem, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	now := time.Now()
+	item := &domain.ContractItem{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CompanyID: companyID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyContractItemInput(item, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.measurementRepo.CreateContractItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *MeasurementService) ListContractItems(projectID, companyID string) ([]domain.ContractItem, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	return s.measurementRepo.GetContractItemsByProject(projectID, companyID)
+}
+
+// UpdateContractItem edits a contract item. Its quantity can not go below what
+// approved measurements already recorded. Measurements already submitted keep
+// the values they were made with.
+func (s *MeasurementService) UpdateContractItem(id, projectID, companyID string, input ports.ContractItemInput) (*domain.ContractItem, error) {
+	item, err := s.measurementRepo.GetContractItemByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("contract item not found")
+	}
+
+	if err := applyContractItemInput(item, input); err != nil {
+		return nil, err
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Quantity+quantityTolerance < approvedQuantities(measurements)[id] {
+		return nil, fmt.Errorf("contract quantity is below the measured quantity")
+	}
+
+	item.UpdatedAt = time.Now()
+	if err := s.measurementRepo.UpdateContractItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// DeleteContractItem removes a contract item no measurement has recorded
+// progress on.
+func (s *MeasurementService) DeleteContractItem(id, projectID, companyID string) error {
+	if _, err := s.measurementRepo.GetContractItemByID(id, projectID, companyID); err != nil {
+		return fmt.Errorf("contract item not found")
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+	if err != nil {
+		return err
+	}
+	for _, measurement := range measurements {
+		for _, line := range measurement.Items {
+			if line.ContractItemID == id && line.ExecutedQuantity != 0 {
+				return fmt.Errorf("contract item has measurements")
+			}
+		}
+	}
+
+	return s.measurementRepo.DeleteContractItem(id, projectID, companyID)
+}
+
+// CreateMeasurement starts the project's next measurement as a draft. The
+// previous measurements must all be approved, so each one builds on settled
+// quantities.
+func (s *MeasurementService) CreateMeasurement(projectID, companyID, userID string, input ports.MeasurementInput) (*domain.Measurement, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	number := 1
+	for _, measurement := range measurements {
+		if measurement.Status != domain.MeasurementStatusApproved {
+			return nil, fmt.Errorf("previous measurement is not approved")
+		}
+		number = max(number, measurement.Number+1)
+	}
+
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CompanyID: companyID,
+		Number:    number,
+		Status:    domain.MeasurementStatusDraft,
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyMeasurementInput(measurement, input, measurements); err != nil {
+		return nil, err
+	}
+
+	if err := s.measurementRepo.CreateMeasurement(measurement, statusTransition(domain.StatusEntityMeasurement, measurement.ID, measurement.ProjectID, measurement.CompanyID, userID, "", measurement.Status, "")); err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// ListMeasurements returns the measurements of a project in number order,
+// optionally only those with a given status.
+func (s *MeasurementService) ListMeasurements(projectID, companyID, status string) ([]domain.Measurement, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if status != "" && !domain.IsValidMeasurementStatus(status) {
+		return nil, fmt.Errorf("invalid measurement status")
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Measurement, 0, len(measurements))
+	for _, measurement := range measurements {
+		if status == "" || measurement.Status == status {
+			result = append(result, measurement)
+		}
+	}
+	return result, nil
+}
+
+func (s *MeasurementService) GetMeasurement(id, projectID, companyID string) (*domain.Measurement, error) {
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+	return measurement, nil
+}
+
+// UpdateMeasurement edits a draft, measuring it again against the current
+// contract items.
+func (s *MeasurementService) UpdateMeasurement(id, projectID, companyID string, input ports.MeasurementInput) (*domain.Measurement, error) {
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+	if measurement.Locked() {
+		return nil, fmt.Errorf("measurement is locked")
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyMeasurementInput(measurement, input, measurements); err != nil {
+		return nil, err
+	}
+	measurement.UpdatedAt = time.Now()
+
+	if err := s.measurementRepo.UpdateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// ChangeMeasurementStatus submits a measurement to the client or takes it
+// back to draft. Submitting measures it again, so the client sees the current
+// contract prices; reworking a rejected measurement clears the decision.
+func (s *MeasurementService) ChangeMeasurementStatus(id, projectID, companyID, userID, status, note string) (*domain.Measurement, error) {
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	if !domain.IsValidMeasurementStatus(status) {
+		return nil, fmt.Errorf("invalid measurement status")
+	}
+	from := measurement.Status
+	if !domain.CanTransitionMeasurement(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	now := time.Now()
+	switch status {
+	case domain.MeasurementStatusSubmitted:
+		measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, companyID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyMeasurementInput(measurement, measurementInput(measurement), measurements); err != nil {
+			return nil, err
+		}
+		measurement.SubmittedAt = &now
+		measurement.DecisionToken = uuid.New().String()
+	case domain.MeasurementStatusDraft:
+		measurement.SubmittedAt = nil
+		measurement.DecisionToken = ""
+		measurement.DecidedBy, measurement.DecidedAt, measurement.DecisionNote = "", nil, ""
+	}
+	measurement.Status = status
+	measurement.UpdatedAt = now
+
+	if err := s.measurementRepo.UpdateMeasurement(measurement, statusTransition(domain.StatusEntityMeasurement, measurement.ID, measurement.ProjectID, measurement.CompanyID, userID, from, status, note)); err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+func (s *MeasurementService) GetMeasurementHistory(id, projectID, companyID string) ([]domain.StatusTransition, error) {
+	if _, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID); err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	return s.statusRepo.GetStatusTransitions(projectID, domain.StatusEntityMeasurement, id, companyID)
+}
+
+// DeleteMeasurement only removes drafts; decided measurements stay on record.
+func (s *MeasurementService) DeleteMeasurement(id, projectID, companyID string) error {
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return fmt.Errorf("measurement not found")
+	}
+	if measurement.Locked() {
+		return fmt.Errorf("measurement is locked")
+	}
+	return s.measurementRepo.DeleteMeasurement(id, projectID, companyID)
+}
+
+// BillMeasurement invoices the amount of an approved measurement, due on
+// dueDate.
+func (s *MeasurementService) BillMeasurement(id, projectID, companyID, userID, dueDate string) (*domain.Invoice, error) {
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+	if measurement.Status != domain.MeasurementStatusApproved {
+		return nil, fmt.Errorf("only approved measurements can be billed")
+	}
+	if measurement.InvoiceID != "" {
+		return nil, fmt.Errorf("measurement already billed")
+	}
+	if measurement.Amount <= 0 {
+		return nil, fmt.Errorf("measurement has no billable amount")
+	}
+
+	invoice, err := s.invoiceService.CreateInvoice(projectID, companyID, userID, ports.InvoiceInput{
+		Description: fmt.Sprintf("Medição nº %d", measurement.Number),
+		Amount:      measurement.Amount,
+		DueDate:     dueDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	measurement.InvoiceID = invoice.ID
+	measurement.UpdatedAt = time.Now()
+	if err := s.measurementRepo.UpdateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// ExportMeasurement renders a measurement as "pdf" or "csv".
+func (s *MeasurementService) ExportMeasurement(id, projectID, companyID, format string) ([]byte, error) {
+	project, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	measurement, err := s.measurementRepo.GetMeasurementByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	return s.render(project, measurement, format)
+}
+
+// ListPublicMeasurements returns the measurements submitted to the client of a
+// shared project, behind the same PIN as the public diary. Drafts are left
+// out.
+func (s *MeasurementService) ListPublicMeasurements(projectID, pin string) ([]domain.Measurement, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByProject(projectID, project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Measurement, 0, len(measurements))
+	for _, measurement := range measurements {
+		if measurement.Status != domain.MeasurementStatusDraft {
+			measurement.DecisionToken = ""
+			result = append(result, measurement)
+		}
+	}
+	return result, nil
+}
+
+func (s *MeasurementService) ExportPublicMeasurement(projectID, measurementID, pin, format string) ([]byte, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement, err := s.measurementRepo.GetMeasurementByID(measurementID, projectID, project.CompanyID)
+	if err != nil || measurement.Status == domain.MeasurementStatusDraft {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	return s.render(project, measurement, format)
+}
+
+// measurementDecisions is how clients decide submitted measurements.
+var measurementDecisions = publicDecisionFlow{
+	entityType: domain.StatusEntityMeasurement,
+	noun:       "measurement",
+	draft:      domain.MeasurementStatusDraft,
+	pending:    domain.MeasurementStatusSubmitted,
+	approved:   domain.MeasurementStatusApproved,
+	rejected:   domain.MeasurementStatusRejected,
+}
+
+// DecidePublicMeasurement records the client approving or rejecting a
+// submitted measurement through the public project link, with the token of
+// the link the measurement was submitted with.
+func (s *MeasurementService) DecidePublicMeasurement(projectID, measurementID, pin, token, name, decision, note string) (*domain.Measurement, error) {
+	var measurement *domain.Measurement
+	_, err := measurementDecisions.decide(s.projectRepo, projectID, measurementID, pin, token, name, decision, note,
+		func(companyID string) (string, string, error) {
+			var err error
+			measurement, err = s.measurementRepo.GetMeasurementByID(measurementID, projectID, companyID)
+			if err != nil {
+				return "", "", err
+			}
+			return measurement.Status, measurement.DecisionToken, nil
+		},
+		func(answer publicDecision, transition *domain.StatusTransition) error {
+			measurement.Status = answer.Status
+			measurement.DecidedBy = answer.Name
+			measurement.DecidedAt = &answer.At
+			measurement.DecisionNote = answer.Note
+			measurement.UpdatedAt = answer.At
+			return s.measurementRepo.DecideMeasurement(measurement, domain.MeasurementStatusSubmitted, transition)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+func (s *MeasurementService) render(project *domain.Project, measurement *domain.Measurement, format string) ([]byte, error) {
+	renderer := s.pdfRenderer
+	switch format {
+	case "", "pdf":
+	case "csv":
+		renderer = s.csvRenderer
+	default:
+		return nil, fmt.Errorf("invalid export format")
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderer.RenderMeasurementReport(&domain.MeasurementReport{
+		Company:     company,
+		Logo:        loadCompanyLogo(s.storage, company),
+		Project:     project,
+		Measurement: measurement,
+		GeneratedAt: time.Now(),
+	})
+}
+
+// applyMeasurementInput validates the period and measures every contract item
+// of the project: what approved measurements recorded before, what was
+// executed in the period, the accumulated quantity and the amount billed.
+// measurements are the project's measurements, the one being edited included.
+func (s *MeasurementService) applyMeasurementInput(measurement *domain.Measurement, input ports.MeasurementInput, measurements []domain.Measurement) error {
+	periodStart, err := parseDate(input.PeriodStart)
+	if err != nil {
+		return fmt.Errorf("invalid measurement period")
+	}
+	periodEnd, err := parseDate(input.PeriodEnd)
+	if err != nil || periodEnd.Before(periodStart) {
+		return fmt.Errorf("invalid measurement period")
+	}
+
+	contractItems, err := s.measurementRepo.GetContractItemsByProject(measurement.ProjectID, measurement.CompanyID)
+	if err != nil {
+		return err
+	}
+	if len(contractItems) == 0 {
+		return fmt.Errorf("contract items are required")
+	}
+
+	known := make(map[string]bool, len(contractItems))
+	for _, item := range contractItems {
+		known[item.ID] = true
+	}
+	for id, quantity := range input.Quantities {
+		if !known[id] {
+			return fmt.Errorf("contract item not found")
+		}
+		if quantity < 0 {
+			return fmt.Errorf("invalid measured quantity")
+		}
+	}
+
+	previous := approvedQuantities(measurements)
+	lines := make([]domain.MeasurementItem, 0, len(contractItems))
+	amount, accumulatedAmount, contractTotal := 0.0, 0.0, 0.0
+	for _, item := range contractItems {
+		executed := input.Quantities[item.ID]
+		accumulated := previous[item.ID] + executed
+		if accumulated > item.Quantity+quantityTolerance {
+			return fmt.Errorf("measured quantity exceeds contract quantity")
+		}
+
+		line := domain.MeasurementItem{
+			ContractItemID:      item.ID,
+			Code:                item.Code,
+			Description:         item.Description,
+			Unit:                item.Unit,
+			UnitPrice:           item.UnitPrice,
+			PlannedQuantity:     item.Quantity,
+			PreviousQuantity:    previous[item.ID],
+			ExecutedQuantity:    executed,
+			AccumulatedQuantity: accumulated,
+			AccumulatedPercent:  roundProgress(accumulated / item.Quantity * 100),
+			Amount:              roundCurrency(executed * item.UnitPrice),
+		}
+		lines = append(lines, line)
+
+		amount += line.Amount
+		accumulatedAmount += accumulated * item.UnitPrice
+		contractTotal += item.Quantity * item.UnitPrice
+	}
+
+	measurement.PeriodStart = periodStart
+	measurement.PeriodEnd = periodEnd
+	measurement.Notes = input.Notes
+	measurement.Items = lines
+	measurement.Amount = roundCurrency(amount)
+	measurement.AccumulatedAmount = roundCurrency(accumulatedAmount)
+	measurement.ContractTotal = roundCurrency(contractTotal)
+	measurement.AccumulatedPercent = 0
+	if contractTotal > 0 {
+		measurement.AccumulatedPercent = roundProgress(accumulatedAmount / contractTotal * 100)
+	}
+	return nil
+}
+
+// measurementInput turns a measurement back into the input it was made from.
+func measurementInput(measurement *domain.Measurement) ports.MeasurementInput {
+	quantities := make(map[string]float64, len(measurement.Items))
+	for _, line := range measurement.Items {
+		if line.ExecutedQuantity != 0 {
+			quantities[line.ContractItemID] = line.ExecutedQuantity
+		}
+	}
+	return ports.MeasurementInput{
+		PeriodStart: measurement.PeriodStart.UTC().Format("2006-01-02"),
+		PeriodEnd:   measurement.PeriodEnd.UTC().Format("2006-01-02"),
+		Notes:       measurement.Notes,
+		Quantities:  quantities,
+	}
+}
+
+// approvedQuantities sums the quantities approved measurements recorded, by
+// contract item.
+func approvedQuantities(measurements []domain.Measurement) map[string]float64 {
+	quantities := map[string]float64{}
+	for _, measurement := range measurements {
+		if measurement.Status != domain.MeasurementStatusApproved {
+			continue
+		}
+		for _, line := range measurement.Items {
+			quantities[line.ContractItemID] += line.ExecutedQuantity
+		}
+	}
+	return quantities
+}
+
+func applyContractItemInput(item *domain.ContractItem, input ports.ContractItemInput) error {
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		return fmt.Errorf("contract item description is required")
+	}
+	if input.Quantity <= 0 || input.UnitPrice < 0 {
+		return fmt.Errorf("invalid contract item amount")
+	}
+
+	item.Code = strings.TrimSpace(input.Code)
+	item.Description = description
+	item.Unit = strings.TrimSpace(input.Unit)
+	item.Quantity = input.Quantity
+	item.UnitPrice = input.UnitPrice
+	return nil
+}
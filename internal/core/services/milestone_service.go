@@ -0,0 +1,142 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MilestoneService struct {
+	projectRepo   ports.ProjectRepository
+	milestoneRepo ports.MilestoneRepository
+}
+
+func NewMilestoneService(projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository) *MilestoneService {
+	return &MilestoneService{
+		projectRepo:   projectRepo,
+		milestoneRepo: milestoneRepo,
+	}
+}
+
+// CreateMilestone adds a milestone with the given weight, or the default one
+// when weight is nil.
+func (s *MilestoneService) CreateMilestone(projectID, companyID, name, description, targetDate string, weight *float64) (*domain.Milestone, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	parsedTargetDate, err := parseMilestoneInput(name, targetDate, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	milestone := &domain.Milestone{
+		ID:          uuid.New().String(),
+		ProjectID:   projectID,
+		CompanyID:   companyID,
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		TargetDate:  parsedTargetDate,
+		Weight:      defaultWeight,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if weight != nil {
+		milestone.Weight = *weight
+	}
+
+	if err := s.milestoneRepo.CreateMilestone(milestone); err != nil {
+		return nil, err
+	}
+
+	if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, projectID, companyID); err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
+
+func (s *MilestoneService) ListMilestones(projectID, companyID string) ([]domain.Milestone, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	return s.milestoneRepo.GetMilestonesByProject(projectID, companyID)
+}
+
+// UpdateMilestone edits a milestone, keeping its weight when weight is nil.
+func (s *MilestoneService) UpdateMilestone(id, projectID, companyID, name, description, targetDate string, weight *float64) (*domain.Milestone, error) {
+	milestone, err := s.milestoneRepo.GetMilestoneByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("milestone not found")
+	}
+
+	parsedTargetDate, err := parseMilestoneInput(name, targetDate, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone.Name = strings.TrimSpace(name)
+	milestone.Description = description
+	milestone.TargetDate = parsedTargetDate
+	if weight != nil {
+		milestone.Weight = *weight
+	}
+	milestone.UpdatedAt = time.Now()
+
+	if err := s.milestoneRepo.UpdateMilestone(milestone); err != nil {
+		return nil, err
+	}
+
+	if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, projectID, companyID); err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
+
+func (s *MilestoneService) DeleteMilestone(id, projectID, companyID string) error {
+	if _, err := s.milestoneRepo.GetMilestoneByID(id, projectID, companyID); err != nil {
+		return fmt.Errorf("milestone not found")
+	}
+
+	// Tasks outlive their milestone and roll up into the project directly.
+	tasks, err := s.projectRepo.GetTasksByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.MilestoneID != id {
+			continue
+		}
+		task.MilestoneID = ""
+		if err := s.projectRepo.UpdateTask(&task); err != nil {
+			return err
+		}
+	}
+
+	if err := s.milestoneRepo.DeleteMilestone(id, projectID, companyID); err != nil {
+		return err
+	}
+
+	return refreshProjectProgress(s.projectRepo, s.milestoneRepo, projectID, companyID)
+}
+
+func parseMilestoneInput(name, targetDate string, weight *float64) (time.Time, error) {
+	if strings.TrimSpace(name) == "" {
+		return time.Time{}, fmt.Errorf("milestone name is required")
+	}
+	if weight != nil && *weight < 0 {
+		return time.Time{}, fmt.Errorf("invalid weight")
+	}
+	parsedTargetDate, err := parseDate(targetDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid target date")
+	}
+	return parsedTargetDate, nil
+}
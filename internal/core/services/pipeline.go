@@ -0,0 +1,318 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetPipelineStages returns the company's pipeline stages, or the defaults
+// when it has not configured any.
+func (s *ClientService) GetPipelineStages(companyID string) ([]domain.PipelineStage, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+	return pipelineStagesOf(company), nil
+}
+
+// UpdatePipelineStages replaces the company's pipeline stages. The pipeline
+// needs at least one open stage and exactly one won and one lost stage, and a
+// stage cannot be removed while clients are in it.
+func (s *ClientService) UpdatePipelineStages(companyID string, stages []domain.PipelineStage) ([]domain.PipelineStage, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	seen := make(map[string]bool, len(stages))
+	outcomes := make(map[string]int, 3)
+	configured := make([]domain.PipelineStage, 0, len(stages))
+	for _, stage := range stages {
+		stage.Name = strings.TrimSpace(stage.Name)
+		if stage.Name == "" {
+			return nil, fmt.Errorf("pipeline stage name is required")
+		}
+		switch stage.Outcome {
+		case domain.PipelineOutcomeOpen, domain.PipelineOutcomeWon, domain.PipelineOutcomeLost:
+		default:
+			return nil, fmt.Errorf("invalid pipeline stage outcome")
+		}
+		stage.ID = strings.TrimSpace(stage.ID)
+		if stage.ID == "" {
+			stage.ID = uuid.New().String()
+		}
+		if seen[stage.ID] {
+			return nil, fmt.Errorf("duplicate pipeline stage")
+		}
+		seen[stage.ID] = true
+		outcomes[stage.Outcome]++
+		configured = append(configured, stage)
+	}
+	if outcomes[domain.PipelineOutcomeOpen] == 0 || outcomes[domain.PipelineOutcomeWon] != 1 || outcomes[domain.PipelineOutcomeLost] != 1 {
+		return nil, fmt.Errorf("pipeline needs open stages and one won and one lost stage")
+	}
+
+	clients, err := s.clientRepo.GetAllClients(companyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, client := range clients {
+		if client.PipelineStage != "" && !seen[client.PipelineStage] {
+			return nil, fmt.Errorf("pipeline stage in use")
+		}
+	}
+
+	company.PipelineStages = configured
+	company.UpdatedAt = time.Now()
+	if err := s.companyRepo.UpdateCompany(company); err != nil {
+		return nil, err
+	}
+
+	return configured, nil
+}
+
+// GetPipeline returns the kanban of the active clients in the pipeline, the
+// most recently moved first in each column.
+func (s *ClientService) GetPipeline(companyID string) (*domain.Pipeline, error) {
+	stages, err := s.GetPipelineStages(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.clientRepo.GetAllClients(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]domain.PipelineColumn, len(stages))
+	index := make(map[string]int, len(stages))
+	for position, stage := range stages {
+		columns[position] = domain.PipelineColumn{Stage: stage, Clients: make([]domain.Client, 0)}
+		index[stage.ID] = position
+	}
+	for _, client := range clients {
+		position, ok := index[client.PipelineStage]
+		if !ok || client.ArchivedAt != nil {
+			continue
+		}
+		column := &columns[position]
+		column.Clients = append(column.Clients, client)
+		column.Count++
+		column.Value = roundCurrency(column.Value + client.EstimatedValue)
+	}
+	for position := range columns {
+		sort.SliceStable(columns[position].Clients, func(i, j int) bool {
+			return stageChangedAt(columns[position].Clients[i]).After(stageChangedAt(columns[position].Clients[j]))
+		})
+	}
+
+	return &domain.Pipeline{Columns: columns}, nil
+}
+
+// UpdateClientPipeline moves a client through the pipeline and updates its
+// lead details. Losing a client requires a reason and records the open stage
+// it was lost from; any other stage clears both.
+func (s *ClientService) UpdateClientPipeline(id, companyID string, input ports.ClientPipelineInput) (*domain.Client, error) {
+	client, err := s.clientRepo.GetClientByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("client not found")
+	}
+
+	stages, err := s.GetPipelineStages(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stage domain.PipelineStage
+	if input.Stage != "" {
+		found, ok := findPipelineStage(stages, input.Stage)
+		if !ok {
+			return nil, fmt.Errorf("invalid pipeline stage")
+		}
+		stage = found
+	}
+	if input.LeadSource != "" && !domain.IsValidLeadSource(input.LeadSource) {
+		return nil, fmt.Errorf("invalid lead source")
+	}
+	if input.EstimatedValue < 0 {
+		return nil, fmt.Errorf("invalid estimated value")
+	}
+	var expectedClose *time.Time
+	if input.ExpectedCloseDate != "" {
+		parsed, err := parseDate(input.ExpectedCloseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected close date")
+		}
+		expectedClose = &parsed
+	}
+	lostReason := strings.TrimSpace(input.LostReason)
+	if stage.Outcome == domain.PipelineOutcomeLost && lostReason == "" {
+		return nil, fmt.Errorf("lost reason is required")
+	}
+
+	now := time.Now()
+	if input.Stage != client.PipelineStage {
+		if stage.Outcome == domain.PipelineOutcomeLost {
+			if previous, ok := findPipelineStage(stages, client.PipelineStage); ok && previous.Outcome == domain.PipelineOutcomeOpen {
+				client.LostFromStage = previous.ID
+			}
+		}
+		client.PipelineStage = input.Stage
+		client.StageChangedAt = &now
+	}
+	if stage.Outcome == domain.PipelineOutcomeLost {
+		client.LostReason = lostReason
+	} else {
+		client.LostReason = ""
+		client.LostFromStage = ""
+	}
+	client.LeadSource = input.LeadSource
+	client.EstimatedValue = roundCurrency(input.EstimatedValue)
+	client.ExpectedCloseDate = expectedClose
+	client.UpdatedAt = now
+
+	if err := s.clientRepo.UpdateClient(client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func pipelineStagesOf(company *domain.Company) []domain.PipelineStage {
+	if len(company.PipelineStages) == 0 {
+		return domain.DefaultPipelineStages()
+	}
+	return company.PipelineStages
+}
+
+func findPipelineStage(stages []domain.PipelineStage, id string) (domain.PipelineStage, bool) {
+	for _, stage := range stages {
+		if stage.ID == id {
+			return stage, true
+		}
+	}
+	return domain.PipelineStage{}, false
+}
+
+func stageChangedAt(client domain.Client) time.Time {
+	if client.StageChangedAt != nil {
+		return *client.StageChangedAt
+	}
+	return client.CreatedAt
+}
+
+// summarizePipeline computes the conversion metrics of the clients in the
+// pipeline. Archived clients still count, as their outcome is history.
+func summarizePipeline(stages []domain.PipelineStage, clients []domain.Client) domain.PipelineMetrics {
+	openIndex := make(map[string]int)
+	for _, stage := range stages {
+		if stage.Outcome == domain.PipelineOutcomeOpen {
+			openIndex[stage.ID] = len(openIndex)
+		}
+	}
+	wonLevel := len(openIndex)
+
+	metrics := domain.PipelineMetrics{
+		BySource: make([]domain.PipelineSourceMetrics, 0),
+		ByStage:  make([]domain.PipelineStageMetrics, 0, len(stages)),
+	}
+	current := make(map[string]int)
+	values := make(map[string]float64)
+	// reached counts the clients that got at least as far as each level:
+	// the open stages in order, then the won stage.
+	reached := make([]int, wonLevel+1)
+	sources := make(map[string]*domain.PipelineSourceMetrics)
+	for _, client := range clients {
+		stage, ok := findPipelineStage(stages, client.PipelineStage)
+		if !ok {
+			continue
+		}
+		current[stage.ID]++
+		values[stage.ID] += client.EstimatedValue
+
+		source := client.LeadSource
+		if source == "" {
+			source = domain.LeadSourceUnknown
+		}
+		bySource, ok := sources[source]
+		if !ok {
+			bySource = &domain.PipelineSourceMetrics{Source: source}
+			sources[source] = bySource
+		}
+		bySource.Leads++
+
+		level := 0
+		switch stage.Outcome {
+		case domain.PipelineOutcomeOpen:
+			level = openIndex[stage.ID]
+			metrics.OpenLeads++
+			metrics.OpenValue += client.EstimatedValue
+			bySource.Open++
+		case domain.PipelineOutcomeWon:
+			level = wonLevel
+			metrics.Won++
+			metrics.WonValue += client.EstimatedValue
+			bySource.Won++
+			bySource.WonValue += client.EstimatedValue
+		case domain.PipelineOutcomeLost:
+			level = openIndex[client.LostFromStage]
+			metrics.Lost++
+			bySource.Lost++
+		}
+		for reach := 0; reach <= level; reach++ {
+			reached[reach]++
+		}
+	}
+	metrics.OpenValue = roundCurrency(metrics.OpenValue)
+	metrics.WonValue = roundCurrency(metrics.WonValue)
+	metrics.WinRate = percentOf(metrics.Won, metrics.Won+metrics.Lost)
+
+	for _, bySource := range sources {
+		bySource.WonValue = roundCurrency(bySource.WonValue)
+		bySource.WinRate = percentOf(bySource.Won, bySource.Won+bySource.Lost)
+		metrics.BySource = append(metrics.BySource, *bySource)
+	}
+	sort.Slice(metrics.BySource, func(i, j int) bool {
+		if metrics.BySource[i].Leads != metrics.BySource[j].Leads {
+			return metrics.BySource[i].Leads > metrics.BySource[j].Leads
+		}
+		return metrics.BySource[i].Source < metrics.BySource[j].Source
+	})
+
+	for _, stage := range stages {
+		byStage := domain.PipelineStageMetrics{
+			StageID: stage.ID,
+			Name:    stage.Name,
+			Outcome: stage.Outcome,
+			Current: current[stage.ID],
+			Value:   roundCurrency(values[stage.ID]),
+		}
+		switch stage.Outcome {
+		case domain.PipelineOutcomeOpen:
+			level := openIndex[stage.ID]
+			byStage.Reached = reached[level]
+			byStage.ConversionRate = percentOf(reached[level+1], reached[level])
+		case domain.PipelineOutcomeWon:
+			byStage.Reached = metrics.Won
+		case domain.PipelineOutcomeLost:
+			byStage.Reached = metrics.Lost
+		}
+		metrics.ByStage = append(metrics.ByStage, byStage)
+	}
+
+	return metrics
+}
+
+// percentOf returns part as a percentage of total, 0 when there is no total.
+func percentOf(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundProgress(float64(part) / float64(total) * 100)
+}
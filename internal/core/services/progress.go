@@ -0,0 +1,131 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"math"
+	"time"
+)
+
+// taskProgress is 100 for done tasks, otherwise the share of completed
+// subtasks.
+func taskProgress(task domain.Task) float64 {
+	if domain.NormalizeTaskStatus(task.Status) == domain.TaskStatusDone {
+		return 100
+	}
+	if len(task.Subtasks) == 0 {
+		return 0
+	}
+	completed := 0
+	for _, subtask := range task.Subtasks {
+		if subtask.Status == domain.SubtaskStatusCompleted {
+			completed++
+		}
+	}
+	return float64(completed) / float64(len(task.Subtasks)) * 100
+}
+
+// defaultWeight is the weight of tasks and milestones created without one. A
+// weight of 0 is kept as given: the task or milestone is left out of the
+// roll-up.
+const defaultWeight = 1.0
+
+func roundProgress(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// rollUpProgress computes milestone progress from their tasks and project
+// progress from milestones plus the tasks that belong to no milestone, all
+// weighted. Milestones are updated in place. A milestone or project whose
+// parts all weigh 0 stays at 0.
+func rollUpProgress(tasks []domain.Task, milestones []domain.Milestone, now time.Time) float64 {
+	type accumulator struct {
+		weighted float64
+		weight   float64
+	}
+
+	byMilestone := make(map[string]*accumulator, len(milestones))
+	for _, milestone := range milestones {
+		byMilestone[milestone.ID] = &accumulator{}
+	}
+
+	project := accumulator{}
+	for _, task := range tasks {
+		weight := task.Weight
+		progress := taskProgress(task)
+		if acc, ok := byMilestone[task.MilestoneID]; ok {
+			acc.weighted += weight * progress
+			acc.weight += weight
+			continue
+		}
+		project.weighted += weight * progress
+		project.weight += weight
+	}
+
+	for index := range milestones {
+		milestone := &milestones[index]
+		acc := byMilestone[milestone.ID]
+		milestone.Progress = 0
+		if acc.weight > 0 {
+			milestone.Progress = roundProgress(acc.weighted / acc.weight)
+		}
+		if milestone.Progress >= 100 && milestone.CompletedAt == nil {
+			completedAt := now
+			milestone.CompletedAt = &completedAt
+		} else if milestone.Progress < 100 {
+			milestone.CompletedAt = nil
+		}
+
+		weight := milestone.Weight
+		project.weighted += weight * milestone.Progress
+		project.weight += weight
+	}
+
+	if project.weight == 0 {
+		return 0
+	}
+	return roundProgress(project.weighted / project.weight)
+}
+
+// refreshProjectProgress recomputes and persists the progress of a project and
+// its milestones.
+func refreshProjectProgress(projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository, projectID, companyID string) error {
+	project, err := projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := projectRepo.GetTasksByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+
+	milestones, err := milestoneRepo.GetMilestonesByProject(projectID, companyID)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]float64, len(milestones))
+	for _, milestone := range milestones {
+		previous[milestone.ID] = milestone.Progress
+	}
+
+	now := time.Now()
+	progress := rollUpProgress(tasks, milestones, now)
+
+	for index := range milestones {
+		milestone := &milestones[index]
+		if previous[milestone.ID] == milestone.Progress {
+			continue
+		}
+		milestone.UpdatedAt = now
+		if err := milestoneRepo.UpdateMilestone(milestone); err != nil {
+			return err
+		}
+	}
+
+	if project.Progress == progress {
+		return nil
+	}
+	return projectRepo.UpdateProjectProgress(projectID, companyID, progress)
+}
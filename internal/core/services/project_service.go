@@ -3,20 +3,31 @@ package services
 import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ProjectService struct {
-	projectRepo ports.ProjectRepository
+	projectRepo   ports.ProjectRepository
+	milestoneRepo ports.MilestoneRepository
+	statusRepo    ports.StatusHistoryRepository
+	userRepo      ports.UserRepository
+	templateRepo  ports.TemplateRepository
 }
 
-func NewProjectService(projectRepo ports.ProjectRepository) *ProjectService {
+func NewProjectService(projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository, statusRepo ports.StatusHistoryRepository, userRepo ports.UserRepository, templateRepo ports.TemplateRepository) *ProjectService {
 	return &ProjectService{
-		projectRepo: projectRepo,
+		projectRepo:   projectRepo,
+		milestoneRepo: milestoneRepo,
+		statusRepo:    statusRepo,
+		userRepo:      userRepo,
+		templateRepo:  templateRepo,
 	}
 }
 
@@ -33,25 +44,36 @@ func (s *ProjectService) CreateProject(companyID, userID, name, clientID, addres
 		Address:   address,
 		Summary:   summary,
 		StartDate: parsedStartDate,
+		Status:    domain.ProjectStatusPlanning,
 		UserID:    userID,
 		CompanyID: companyID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.projectRepo.CreateProject(project); err != nil {
+	if err := s.projectRepo.CreateProject(project, statusTransition(domain.StatusEntityProject, project.ID, project.ID, companyID, userID, "", project.Status, "")); err != nil {
 		return nil, err
 	}
 
 	return project, nil
 }
 
-func (s *ProjectService) ListProjects(companyID string) ([]domain.Project, error) {
-	return s.projectRepo.GetAllProjects(companyID)
+// ListProjects returns either the active or the archived projects. Trashed
+// projects are never listed here.
+func (s *ProjectService) ListProjects(companyID string, archived bool) ([]domain.Project, error) {
+	projects, err := s.projectRepo.GetAllProjects(companyID)
+	if err != nil {
+		return nil, err
+	}
+	return filterArchivedProjects(projects, archived), nil
 }
 
-func (s *ProjectService) ListProjectsByClient(clientID, companyID string) ([]domain.Project, error) {
-	return s.projectRepo.GetProjectsByClientID(clientID, companyID)
+func (s *ProjectService) ListProjectsByClient(clientID, companyID string, archived bool) ([]domain.Project, error) {
+	projects, err := s.projectRepo.GetProjectsByClientID(clientID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return filterArchivedProjects(projects, archived), nil
 }
 
 func (s *ProjectService) GetProject(id, companyID string) (*domain.Project, error) {
@@ -59,16 +81,7 @@ func (s *ProjectService) GetProject(id, companyID string) (*domain.Project, erro
 }
 
 func (s *ProjectService) GetPublicProject(id, pin string) (*domain.Project, error) {
-	project, err := s.projectRepo.GetPublicProjectByID(id)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := validatePublicProjectPin(project, pin); err != nil {
-		return nil, err
-	}
-
-	return project, nil
+	return publicProject(s.projectRepo, id, pin)
 }
 
 func (s *ProjectService) VerifyPublicProjectPin(id, pin string) error {
@@ -106,22 +119,63 @@ func (s *ProjectService) UpdateProject(id, name, clientID, address, summary, sta
 	return project, nil
 }
 
+// DeleteProject moves the project to the trash. It is removed for good by the
+// retention job.
 func (s *ProjectService) DeleteProject(id, companyID string) error {
-	return s.projectRepo.DeleteProject(id, companyID)
+	if _, err := s.projectRepo.GetProjectByID(id, companyID); err != nil {
+		return fmt.Errorf("project not found or access denied")
+	}
+	return s.projectRepo.TrashProject(id, companyID, time.Now())
 }
 
-func (s *ProjectService) AddTask(projectID, name, status, dueDate, companyID, userID string) (*domain.Task, error) {
-	parsedDueDate, errParse := time.Parse(time.RFC3339, dueDate)
-	if errParse != nil {
-		parsedDueDate, _ = time.Parse("2006-01-02", dueDate)
+func (s *ProjectService) RestoreProject(id, companyID string) error {
+	if err := s.projectRepo.RestoreProject(id, companyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("project not found in trash")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ProjectService) SetProjectArchived(id, companyID string, archived bool) (*domain.Project, error) {
+	project, err := s.projectRepo.GetProjectByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	now := time.Now()
+	project.ArchivedAt = nil
+	if archived {
+		project.ArchivedAt = &now
+	}
+	project.UpdatedAt = now
+
+	if err := s.projectRepo.UpdateProject(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (s *ProjectService) AddTask(projectID, name, status, companyID, userID string, schedule ports.TaskScheduleInput, assignment ports.TaskAssignmentInput) (*domain.Task, error) {
+	return s.addTask(uuid.New().String(), projectID, name, status, companyID, userID, schedule, assignment)
+}
+
+// addTask creates a task under the given ID, which offline clients generate
+// themselves.
+func (s *ProjectService) addTask(id, projectID, name, status, companyID, userID string, schedule ports.TaskScheduleInput, assignment ports.TaskAssignmentInput) (*domain.Task, error) {
+	status = domain.NormalizeTaskStatus(status)
+	if !domain.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status")
 	}
 
 	task := &domain.Task{
-		ID:        uuid.New().String(),
+		ID:        id,
 		ProjectID: projectID,
 		Name:      name,
 		Status:    status,
-		DueDate:   parsedDueDate,
+		Weight:    defaultWeight,
 		UserID:    userID,
 		CompanyID: companyID,
 		CreatedAt: time.Now(),
@@ -133,22 +187,46 @@ func (s *ProjectService) AddTask(projectID, name, status, dueDate, companyID, us
 		return nil, fmt.Errorf("project not found or access denied")
 	}
 
-	if err := s.projectRepo.AddTask(task); err != nil {
+	if err := s.applyTaskSchedule(task, schedule); err != nil {
+		return nil, err
+	}
+
+	assignees, err := s.buildAssignees(companyID, projectID, task.ID, "", assignment.AssigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+	task.Assignees = assignees
+	if assignment.Crew != nil {
+		task.Crew = strings.TrimSpace(*assignment.Crew)
+	}
+
+	if err := s.projectRepo.AddTask(task, statusTransition(domain.StatusEntityTask, task.ID, projectID, companyID, userID, "", task.Status, "")); err != nil {
+		return nil, err
+	}
+
+	if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, projectID, companyID); err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *ProjectService) AddSubtask(taskID, name, status, companyID, userID string) (*domain.Subtask, error) {
+func (s *ProjectService) AddSubtask(taskID, name, status, companyID, userID string, assignment ports.TaskAssignmentInput) (*domain.Subtask, error) {
+	return s.addSubtask(uuid.New().String(), taskID, name, status, companyID, userID, assignment)
+}
+
+func (s *ProjectService) addSubtask(id, taskID, name, status, companyID, userID string, assignment ports.TaskAssignmentInput) (*domain.Subtask, error) {
 	// Verify task ownership
-	_, err := s.projectRepo.GetTaskByID(taskID, companyID)
+	task, err := s.projectRepo.GetTaskByID(taskID, companyID)
 	if err != nil {
 		return nil, fmt.Errorf("task not found or access denied")
 	}
+	if !domain.ValidSubtaskStatus(status) {
+		return nil, fmt.Errorf("invalid subtask status")
+	}
 
 	subtask := &domain.Subtask{
-		ID:        uuid.New().String(),
+		ID:        id,
 		TaskID:    taskID,
 		Name:      name,
 		Status:    status,
@@ -157,51 +235,152 @@ func (s *ProjectService) AddSubtask(taskID, name, status, companyID, userID stri
 		CreatedAt: time.Now(),
 	}
 
+	assignees, err := s.buildAssignees(companyID, task.ProjectID, taskID, subtask.ID, assignment.AssigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+	subtask.Assignees = assignees
+	if assignment.Crew != nil {
+		subtask.Crew = strings.TrimSpace(*assignment.Crew)
+	}
+
 	if err := s.projectRepo.AddSubtask(subtask); err != nil {
 		return nil, err
 	}
 
+	if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, task.ProjectID, companyID); err != nil {
+		return nil, err
+	}
+
 	return subtask, nil
 }
 
-func (s *ProjectService) UpdateTask(id, companyID, status string) (*domain.Task, error) {
+func (s *ProjectService) UpdateTask(id, companyID, userID, status string, schedule ports.TaskScheduleInput) (*domain.Task, error) {
 	task, err := s.projectRepo.GetTaskByID(id, companyID)
 	if err != nil {
 		return nil, err
 	}
 
-	if status != "" {
-		task.Status = status
-	} else {
-		// Fallback to toggle if no status provided (for backward compatibility if needed)
-		if task.Status == "Completed" {
-			task.Status = "Pending"
-		} else {
-			task.Status = "Completed"
+	if err := s.applyTaskSchedule(task, schedule); err != nil {
+		return nil, err
+	}
+
+	// Resending the current status edits the task without a transition.
+	if status == "" || domain.NormalizeTaskStatus(status) == domain.NormalizeTaskStatus(task.Status) {
+		if err := s.projectRepo.UpdateTask(task); err != nil {
+			return nil, err
 		}
+		if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, task.ProjectID, companyID); err != nil {
+			return nil, err
+		}
+		return task, nil
 	}
 
-	if err := s.projectRepo.UpdateTask(task); err != nil {
+	return s.transitionTask(task, userID, status, "")
+}
+
+func (s *ProjectService) ChangeTaskStatus(id, companyID, userID, status, note string) (*domain.Task, error) {
+	task, err := s.projectRepo.GetTaskByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found or access denied")
+	}
+
+	return s.transitionTask(task, userID, status, note)
+}
+
+func (s *ProjectService) ChangeProjectStatus(id, companyID, userID, status, note string) (*domain.Project, error) {
+	project, err := s.projectRepo.GetProjectByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	if !domain.IsValidProjectStatus(status) {
+		return nil, fmt.Errorf("invalid project status")
+	}
+
+	from := domain.NormalizeProjectStatus(project.Status)
+	if !domain.CanTransitionProject(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	project.Status = status
+	project.UpdatedAt = time.Now()
+	if err := s.projectRepo.UpdateProject(project, statusTransition(domain.StatusEntityProject, project.ID, project.ID, companyID, userID, from, status, note)); err != nil {
 		return nil, err
 	}
 
-	if status == "Completed" {
-		if err := s.projectRepo.UpdateSubtaskByTaskID(task.ID); err != nil {
-			return nil, err
-		}
+	return project, nil
+}
+
+func (s *ProjectService) GetProjectStatusHistory(id, companyID string) ([]domain.StatusTransition, error) {
+	if _, err := s.projectRepo.GetProjectByID(id, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
 	}
 
-	return task, nil
+	return s.statusRepo.GetStatusTransitions(id, domain.StatusEntityProject, id, companyID)
+}
+
+func (s *ProjectService) GetTaskStatusHistory(id, companyID string) ([]domain.StatusTransition, error) {
+	task, err := s.projectRepo.GetTaskByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found or access denied")
+	}
+
+	return s.statusRepo.GetStatusTransitions(task.ProjectID, domain.StatusEntityTask, id, companyID)
 }
 
 func (s *ProjectService) DeleteTask(id, companyID string) error {
-	return s.projectRepo.DeleteTask(id, companyID)
+	task, err := s.projectRepo.GetTaskByID(id, companyID)
+	if err != nil {
+		return err
+	}
+
+	// Drop dependencies on the deleted task so successors stay schedulable.
+	tasks, err := s.projectRepo.GetTasksByProjectID(task.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, other := range tasks {
+		if other.ID == id {
+			continue
+		}
+		dependencies := make([]domain.TaskDependency, 0, len(other.Dependencies))
+		for _, dependency := range other.Dependencies {
+			if dependency.DependsOnTaskID != id {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		if len(dependencies) == len(other.Dependencies) {
+			continue
+		}
+		other.Dependencies = dependencies
+		if err := s.projectRepo.UpdateTask(&other); err != nil {
+			return err
+		}
+	}
+
+	if err := s.projectRepo.DeleteTask(id, companyID); err != nil {
+		return err
+	}
+
+	return refreshProjectProgress(s.projectRepo, s.milestoneRepo, task.ProjectID, companyID)
 }
 
 func (s *ProjectService) DeleteSubtask(id, companyID string) error {
-	return s.projectRepo.DeleteSubtask(id, companyID)
+	subtask, err := s.projectRepo.GetSubtaskByID(id, companyID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.projectRepo.DeleteSubtask(id, companyID); err != nil {
+		return err
+	}
+
+	return s.refreshTaskProject(subtask.TaskID, companyID)
 }
 
+// UpdateSubtask ticks or unticks a subtask. Subtask statuses sit outside the
+// status workflow, so no transition is recorded.
 func (s *ProjectService) UpdateSubtask(id, companyID string) (*domain.Subtask, error) {
 	subtask, err := s.projectRepo.GetSubtaskByID(id, companyID)
 	if err != nil {
@@ -209,10 +388,10 @@ func (s *ProjectService) UpdateSubtask(id, companyID string) (*domain.Subtask, e
 	}
 
 	status := subtask.Status
-	if status == "Completed" {
-		status = "Pending"
+	if status == domain.SubtaskStatusCompleted {
+		status = domain.SubtaskStatusPending
 	} else {
-		status = "Completed"
+		status = domain.SubtaskStatusCompleted
 	}
 
 	subtask.Status = status
@@ -221,6 +400,10 @@ func (s *ProjectService) UpdateSubtask(id, companyID string) (*domain.Subtask, e
 		return nil, err
 	}
 
+	if err := s.refreshTaskProject(subtask.TaskID, companyID); err != nil {
+		return nil, err
+	}
+
 	return subtask, nil
 }
 
@@ -233,32 +416,54 @@ func (s *ProjectService) GetSubtask(id, companyID string) (*domain.Subtask, erro
 }
 
 func (s *ProjectService) ListTasks(projectID string) ([]domain.Task, error) {
+	return s.projectRepo.GetTasksByProjectID(projectID)
+}
+
+func (s *ProjectService) GetSchedule(projectID, companyID string) (*domain.ProjectSchedule, error) {
+	project, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
 	tasks, err := s.projectRepo.GetTasksByProjectID(projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	totalTasks := len(tasks)
-	if totalTasks > 0 {
-		completedTasks := 0
-		for _, task := range tasks {
-			if task.Status == "Completed" {
-				completedTasks++
-			}
-		}
-		percentage := (float64(completedTasks) / float64(totalTasks)) * 100
-		fmt.Printf("Project %s: %.2f%% tasks completed\n", projectID, percentage)
-	}
+	return computeSchedule(project, tasks)
+}
 
-	return tasks, nil
+// CreateDiaryEntry writes a new draft entry. With a templateID the items are
+// laid out from that diary template, which fills in defaults and rejects the
+// entry while a required item is missing.
+func (s *ProjectService) CreateDiaryEntry(projectID, companyID, userID, entryDate, title, templateID string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
+	return s.addDiaryEntry(uuid.New().String(), projectID, companyID, userID, entryDate, title, templateID, items)
 }
 
-func (s *ProjectService) CreateDiaryEntry(projectID, companyID, userID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
+func (s *ProjectService) addDiaryEntry(id, projectID, companyID, userID, entryDate, title, templateID string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
 	_, err := s.projectRepo.GetProjectByID(projectID, companyID)
 	if err != nil {
 		return nil, fmt.Errorf("project not found or access denied")
 	}
 
+	if templateID != "" {
+		template, err := s.templateRepo.GetDiaryTemplateByID(templateID, companyID)
+		if err != nil {
+			return nil, fmt.Errorf("diary template not found")
+		}
+		items = applyDiaryTemplate(template, items)
+		if err := checkDiaryTemplateItems(template, items); err != nil {
+			return nil, err
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("diary items are required")
+	}
+
+	if err := s.validateDiaryServiceTasks(projectID, companyID, items); err != nil {
+		return nil, err
+	}
+
 	parsedEntryDate, err := time.Parse("2006-01-02", entryDate)
 	if err != nil {
 		parsedEntryDate, err = time.Parse(time.RFC3339, entryDate)
@@ -267,36 +472,43 @@ func (s *ProjectService) CreateDiaryEntry(projectID, companyID, userID, entryDat
 		}
 	}
 
+	return s.createDiaryEntry(id, projectID, companyID, userID, parsedEntryDate, title, templateID, items, "")
+}
+
+func (s *ProjectService) createDiaryEntry(id, projectID, companyID, userID string, entryDate time.Time, title, templateID string, items []domain.DiaryItem, amendsEntryID string) (*domain.DiaryEntry, error) {
 	now := time.Now()
 	entry := &domain.DiaryEntry{
-		ID:        uuid.New().String(),
-		ProjectID: projectID,
-		UserID:    userID,
-		CompanyID: companyID,
-		EntryDate: parsedEntryDate,
-		Title:     title,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            id,
+		ProjectID:     projectID,
+		UserID:        userID,
+		CompanyID:     companyID,
+		EntryDate:     entryDate,
+		Title:         title,
+		TemplateID:    templateID,
+		Status:        domain.DiaryStatusDraft,
+		AmendsEntryID: amendsEntryID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
 	for index, item := range items {
-		entry.Items = append(entry.Items, domain.DiaryItem{
-			ID:           uuid.New().String(),
-			DiaryEntryID: entry.ID,
-			Type:         item.Type,
-			Label:        item.Label,
-			Content:      item.Content,
-			Visibility:   item.Visibility,
-			SortOrder:    index,
-			CreatedAt:    now,
-			UpdatedAt:    now,
-		})
+		item.ID = uuid.New().String()
+		item.DiaryEntryID = entry.ID
+		item.SortOrder = index
+		item.Attachments = nil
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		entry.Items = append(entry.Items, item)
 	}
 
 	if err := s.projectRepo.CreateDiaryEntry(entry); err != nil {
 		return nil, err
 	}
 
+	if err := s.recordDiaryRevision(entry, 1, userID, now); err != nil {
+		return nil, err
+	}
+
 	return entry, nil
 }
 
@@ -310,23 +522,38 @@ func (s *ProjectService) ListDiaryEntries(projectID, companyID string) ([]domain
 }
 
 func (s *ProjectService) ListPublicDiaryEntries(projectID, pin string) ([]domain.DiaryEntry, error) {
-	project, err := s.projectRepo.GetPublicProjectByID(projectID)
-	if err != nil {
-		return nil, fmt.Errorf("project not found or not public")
-	}
-
-	if err := validatePublicProjectPin(project, pin); err != nil {
+	if _, err := publicProject(s.projectRepo, projectID, pin); err != nil {
 		return nil, err
 	}
 
 	return s.projectRepo.GetPublicDiaryEntriesByProject(projectID)
 }
 
-func (s *ProjectService) UpdateDiaryEntry(entryID, projectID, companyID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
+// UpdateDiaryEntry edits a draft entry in place and records the result as a
+// new revision. Saving unchanged content writes nothing.
+func (s *ProjectService) UpdateDiaryEntry(entryID, projectID, companyID, userID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
 	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
 	if err != nil {
 		return nil, fmt.Errorf("diary entry not found")
 	}
+	return s.editDiaryEntry(entry, userID, entryDate, title, items)
+}
+
+// editDiaryEntry applies an edit to entry as loaded; the write fails with
+// ports.ErrVersionConflict if the entry changed since.
+func (s *ProjectService) editDiaryEntry(entry *domain.DiaryEntry, userID, entryDate, title string, items []domain.DiaryItem) (*domain.DiaryEntry, error) {
+	projectID, companyID := entry.ProjectID, entry.CompanyID
+	if entry.Locked() {
+		return nil, fmt.Errorf("diary entry is locked")
+	}
+
+	if err := s.checkEntryTemplate(entry, items); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateDiaryServiceTasks(projectID, companyID, items); err != nil {
+		return nil, err
+	}
 
 	parsedEntryDate, err := time.Parse("2006-01-02", entryDate)
 	if err != nil {
@@ -337,26 +564,26 @@ func (s *ProjectService) UpdateDiaryEntry(entryID, projectID, companyID, entryDa
 	}
 
 	now := time.Now()
+	matched := matchDiaryItems(entry.Items, items, entry.ID, now)
+	if !diaryContentChanged(diffDiaryContent(entry.EntryDate, entry.Title, entry.Items, parsedEntryDate, title, matched)) {
+		return entry, nil
+	}
+
+	revision, err := s.nextDiaryRevision(entry)
+	if err != nil {
+		return nil, err
+	}
+
 	entry.EntryDate = parsedEntryDate
 	entry.Title = title
 	entry.UpdatedAt = now
-	entry.Items = nil
+	entry.Items = matched
 
-	for index, item := range items {
-		entry.Items = append(entry.Items, domain.DiaryItem{
-			ID:           uuid.New().String(),
-			DiaryEntryID: entry.ID,
-			Type:         item.Type,
-			Label:        item.Label,
-			Content:      item.Content,
-			Visibility:   item.Visibility,
-			SortOrder:    index,
-			CreatedAt:    now,
-			UpdatedAt:    now,
-		})
+	if err := s.projectRepo.UpdateDiaryEntry(entry); err != nil {
+		return nil, err
 	}
 
-	if err := s.projectRepo.UpdateDiaryEntry(entry); err != nil {
+	if err := s.recordDiaryRevision(entry, revision, userID, now); err != nil {
 		return nil, err
 	}
 
@@ -364,14 +591,223 @@ func (s *ProjectService) UpdateDiaryEntry(entryID, projectID, companyID, entryDa
 }
 
 func (s *ProjectService) DeleteDiaryEntry(entryID, projectID, companyID string) error {
-	_, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
+	entry, err := s.projectRepo.GetDiaryEntryByID(entryID, projectID, companyID)
 	if err != nil {
 		return fmt.Errorf("diary entry not found")
 	}
+	if entry.Locked() {
+		return fmt.Errorf("diary entry is locked")
+	}
 
 	return s.projectRepo.DeleteDiaryEntry(entryID, projectID, companyID)
 }
 
+// checkEntryTemplate keeps an entry created from a diary template true to its
+// required items. Entries whose template has since been deleted are free.
+func (s *ProjectService) checkEntryTemplate(entry *domain.DiaryEntry, items []domain.DiaryItem) error {
+	if entry.TemplateID == "" {
+		return nil
+	}
+	template, err := s.templateRepo.GetDiaryTemplateByID(entry.TemplateID, entry.CompanyID)
+	if err != nil {
+		return nil
+	}
+	return checkDiaryTemplateItems(template, items)
+}
+
+// validateDiaryServiceTasks checks that services executed are linked to tasks
+// of the same project.
+func (s *ProjectService) validateDiaryServiceTasks(projectID, companyID string, items []domain.DiaryItem) error {
+	for _, item := range items {
+		for _, service := range item.Services {
+			if service.TaskID == "" {
+				continue
+			}
+			task, err := s.projectRepo.GetTaskByID(service.TaskID, companyID)
+			if err != nil || task.ProjectID != projectID {
+				return fmt.Errorf("service task not found")
+			}
+		}
+	}
+	return nil
+}
+
+// applyTaskSchedule copies the planning fields onto the task, validates its
+// dependencies against the rest of the project and rejects a project graph
+// with a cycle.
+func (s *ProjectService) applyTaskSchedule(task *domain.Task, schedule ports.TaskScheduleInput) error {
+	if schedule.StartDate != "" {
+		startDate, err := parseDate(schedule.StartDate)
+		if err != nil {
+			return fmt.Errorf("invalid start date")
+		}
+		task.StartDate = startDate
+	}
+	if schedule.DueDate != "" {
+		dueDate, err := parseDate(schedule.DueDate)
+		if err != nil {
+			return fmt.Errorf("invalid due date")
+		}
+		task.DueDate = dueDate
+	}
+	if schedule.Duration != nil {
+		if *schedule.Duration < 0 {
+			return fmt.Errorf("invalid duration")
+		}
+		task.Duration = *schedule.Duration
+	}
+	if !task.StartDate.IsZero() && !task.DueDate.IsZero() && task.DueDate.Before(task.StartDate) {
+		return fmt.Errorf("due date before start date")
+	}
+	if schedule.Weight != nil {
+		if *schedule.Weight < 0 {
+			return fmt.Errorf("invalid weight")
+		}
+		task.Weight = *schedule.Weight
+	}
+	if schedule.MilestoneID != nil {
+		if *schedule.MilestoneID != "" {
+			if _, err := s.milestoneRepo.GetMilestoneByID(*schedule.MilestoneID, task.ProjectID, task.CompanyID); err != nil {
+				return fmt.Errorf("milestone not found")
+			}
+		}
+		task.MilestoneID = *schedule.MilestoneID
+	}
+
+	tasks, err := s.projectRepo.GetTasksByProjectID(task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	dependencies := task.Dependencies
+	if schedule.Dependencies != nil {
+		if dependencies, err = newTaskDependencies(task, tasks, schedule.Dependencies); err != nil {
+			return err
+		}
+	}
+
+	// The whole graph is checked on every write, not only when this task's
+	// dependencies change: a cycle left by concurrent edits blocks further
+	// writes until one of them removes it.
+	graph := make([]domain.Task, 0, len(tasks)+1)
+	for _, other := range tasks {
+		if other.ID != task.ID {
+			graph = append(graph, other)
+		}
+	}
+	candidate := *task
+	candidate.Dependencies = dependencies
+	graph = append(graph, candidate)
+	if _, err := orderTasks(graph); err != nil {
+		return err
+	}
+
+	task.Dependencies = dependencies
+	return nil
+}
+
+// newTaskDependencies validates the dependencies requested for task against
+// the project's tasks and builds them, dropping repeated predecessors.
+func newTaskDependencies(task *domain.Task, tasks []domain.Task, requested []domain.TaskDependency) ([]domain.TaskDependency, error) {
+	known := make(map[string]bool, len(tasks))
+	for _, other := range tasks {
+		known[other.ID] = true
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(requested))
+	dependencies := make([]domain.TaskDependency, 0, len(requested))
+	for _, dependency := range requested {
+		if dependency.DependsOnTaskID == task.ID || !known[dependency.DependsOnTaskID] {
+			return nil, fmt.Errorf("invalid dependency")
+		}
+		if dependency.Type == "" {
+			dependency.Type = domain.DependencyFinishToStart
+		}
+		if dependency.Type != domain.DependencyFinishToStart && dependency.Type != domain.DependencyStartToStart {
+			return nil, fmt.Errorf("invalid dependency type")
+		}
+		if seen[dependency.DependsOnTaskID] {
+			continue
+		}
+		seen[dependency.DependsOnTaskID] = true
+
+		dependencies = append(dependencies, domain.TaskDependency{
+			ID:              uuid.New().String(),
+			TaskID:          task.ID,
+			DependsOnTaskID: dependency.DependsOnTaskID,
+			ProjectID:       task.ProjectID,
+			CompanyID:       task.CompanyID,
+			Type:            dependency.Type,
+			LagDays:         dependency.LagDays,
+			CreatedAt:       now,
+		})
+	}
+	return dependencies, nil
+}
+
+// refreshTaskProject recomputes progress for the project owning the task.
+func (s *ProjectService) refreshTaskProject(taskID, companyID string) error {
+	task, err := s.projectRepo.GetTaskByID(taskID, companyID)
+	if err != nil {
+		return err
+	}
+	return refreshProjectProgress(s.projectRepo, s.milestoneRepo, task.ProjectID, companyID)
+}
+
+// transitionTask moves a task to a new status, persisting it together with
+// the transition record. Finishing a task also completes its subtasks.
+func (s *ProjectService) transitionTask(task *domain.Task, userID, status, note string) (*domain.Task, error) {
+	if !domain.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status")
+	}
+
+	from := domain.NormalizeTaskStatus(task.Status)
+	if !domain.CanTransitionTask(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	task.Status = status
+	if err := s.projectRepo.UpdateTask(task, statusTransition(domain.StatusEntityTask, task.ID, task.ProjectID, task.CompanyID, userID, from, status, note)); err != nil {
+		return nil, err
+	}
+
+	if status == domain.TaskStatusDone {
+		if err := s.projectRepo.UpdateSubtaskByTaskID(task.ID, task.CompanyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := refreshProjectProgress(s.projectRepo, s.milestoneRepo, task.ProjectID, task.CompanyID); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// statusTransition builds the status history entry of an entity moving from
+// one status to another. from is empty when the entity is created.
+func statusTransition(entityType, entityID, projectID, companyID, userID, from, to, note string) *domain.StatusTransition {
+	return &domain.StatusTransition{
+		ID:         uuid.New().String(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		ProjectID:  projectID,
+		CompanyID:  companyID,
+		FromStatus: from,
+		ToStatus:   to,
+		UserID:     userID,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// validDecisionToken checks the token of a public decision link against the
+// one issued, in constant time.
+func validDecisionToken(issued, token string) bool {
+	return issued != "" && subtle.ConstantTimeCompare([]byte(issued), []byte(token)) == 1
+}
+
 func validatePublicProjectPin(project *domain.Project, pin string) error {
 	if len(pin) != 4 {
 		return fmt.Errorf("invalid public project access")
@@ -408,3 +844,13 @@ func onlyDigits(value string) string {
 	}
 	return builder.String()
 }
+
+func filterArchivedProjects(projects []domain.Project, archived bool) []domain.Project {
+	filtered := make([]domain.Project, 0, len(projects))
+	for _, project := range projects {
+		if (project.ArchivedAt != nil) == archived {
+			filtered = append(filtered, project)
+		}
+	}
+	return filtered
+}
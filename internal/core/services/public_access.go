@@ -0,0 +1,102 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// publicProject resolves a project shared through its public link, checking
+// the PIN the client entered.
+func publicProject(projectRepo ports.ProjectRepository, projectID, pin string) (*domain.Project, error) {
+	project, err := projectRepo.GetPublicProjectByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or not public")
+	}
+	if err := validatePublicProjectPin(project, pin); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// publicDecisionFlow describes a kind of record the client approves or
+// rejects through the public project link once it has been sent to them.
+type publicDecisionFlow struct {
+	// entityType is the record's kind in the status history, and noun names
+	// it in errors.
+	entityType string
+	noun       string
+
+	draft    string
+	pending  string
+	approved string
+	rejected string
+}
+
+// publicDecision is the client's answer, as applied to the record.
+type publicDecision struct {
+	Status string
+	Name   string
+	Note   string
+	At     time.Time
+}
+
+// decide records the client's decision on the record entityID of the project
+// behind projectID and pin. The decision may move money, so besides the PIN it
+// needs the token of the link the record was sent with. load fetches the
+// record and returns its status and that token; apply stores the decision on
+// it, together with the transition recording it, only while the record is
+// still pending. name is the person signing on the client's side. Repeating
+// the decision the record already carries succeeds without storing anything,
+// so a retried request is harmless.
+func (f publicDecisionFlow) decide(
+	projectRepo ports.ProjectRepository,
+	projectID, entityID, pin, token, name, decision, note string,
+	load func(companyID string) (status, issuedToken string, err error),
+	apply func(decision publicDecision, transition *domain.StatusTransition) error,
+) (*domain.Project, error) {
+	project, err := publicProject(projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("signer name is required")
+	}
+	if decision != f.approved && decision != f.rejected {
+		return nil, fmt.Errorf("invalid %s decision", f.noun)
+	}
+
+	status, issuedToken, err := load(project.CompanyID)
+	if err != nil || status == f.draft {
+		return nil, fmt.Errorf("%s not found", f.noun)
+	}
+	if !validDecisionToken(issuedToken, token) {
+		return nil, fmt.Errorf("invalid public project access")
+	}
+	if status == decision {
+		return project, nil
+	}
+	if status != f.pending {
+		return nil, fmt.Errorf("%s already decided", f.noun)
+	}
+
+	answer := publicDecision{Status: decision, Name: name, Note: strings.TrimSpace(note), At: time.Now()}
+	auditNote := decision + " by " + name
+	if answer.Note != "" {
+		auditNote += ": " + answer.Note
+	}
+	transition := statusTransition(f.entityType, entityID, projectID, project.CompanyID, "", f.pending, decision, auditNote)
+	if err := apply(answer, transition); err != nil {
+		if errors.Is(err, ports.ErrVersionConflict) {
+			return nil, fmt.Errorf("%s already decided", f.noun)
+		}
+		return nil, err
+	}
+
+	return project, nil
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PunchListService struct {
+	punchRepo      ports.PunchListRepository
+	projectRepo    ports.ProjectRepository
+	statusRepo     ports.StatusHistoryRepository
+	companyRepo    ports.CompanyRepository
+	attachmentRepo ports.AttachmentRepository
+	storage        ports.FileStorage
+	renderer       ports.PunchListReportRenderer
+}
+
+func NewPunchListService(punchRepo ports.PunchListRepository, projectRepo ports.ProjectRepository, statusRepo ports.StatusHistoryRepository, companyRepo ports.CompanyRepository, attachmentRepo ports.AttachmentRepository, storage ports.FileStorage, renderer ports.PunchListReportRenderer) *PunchListService {
+	return &PunchListService{
+		punchRepo:      punchRepo,
+		projectRepo:    projectRepo,
+		statusRepo:     statusRepo,
+		companyRepo:    companyRepo,
+		attachmentRepo: attachmentRepo,
+		storage:        storage,
+		renderer:       renderer,
+	}
+}
+
+func (s *PunchListService) CreatePunchItem(projectID, companyID, userID, title, description, floor, room, trade, priority, dueDate string) (*domain.PunchItem, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	now := time.Now()
+	item := &domain.PunchItem{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CompanyID: companyID,
+		Status:    domain.PunchStatusOpen,
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyPunchItemInput(item, title, description, floor, room, trade, priority, dueDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.punchRepo.CreatePunchItem(item, statusTransition(domain.StatusEntityPunchItem, item.ID, item.ProjectID, item.CompanyID, userID, "", item.Status, "")); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ListPunchItems returns the punch list of a project, optionally narrowed by
+// status, trade and floor.
+func (s *PunchListService) ListPunchItems(projectID, companyID, status, trade, floor string) ([]domain.PunchItem, error) {
+	if _, err := s.projectRepo.GetProjectByID(projectID, companyID); err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if status != "" && !domain.IsValidPunchStatus(status) {
+		return nil, fmt.Errorf("invalid punch item status")
+	}
+
+	items, err := s.punchRepo.GetPunchItemsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterPunchItems(items, status, trade, floor), nil
+}
+
+func (s *PunchListService) GetPunchItem(id, projectID, companyID string) (*domain.PunchItem, error) {
+	item, err := s.punchRepo.GetPunchItemByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("punch item not found")
+	}
+	return item, nil
+}
+
+func (s *PunchListService) UpdatePunchItem(id, projectID, companyID, title, description, floor, room, trade, priority, dueDate string) (*domain.PunchItem, error) {
+	item, err := s.punchRepo.GetPunchItemByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("punch item not found")
+	}
+
+	if err := applyPunchItemInput(item, title, description, floor, room, trade, priority, dueDate); err != nil {
+		return nil, err
+	}
+	item.UpdatedAt = time.Now()
+
+	if err := s.punchRepo.UpdatePunchItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ChangePunchItemStatus moves an item through fixed and verified, recording
+// who did it. Reopening an item clears the fix, the verification and any
+// client approval, since they no longer hold.
+func (s *PunchListService) ChangePunchItemStatus(id, projectID, companyID, userID, status, note string) (*domain.PunchItem, error) {
+	item, err := s.punchRepo.GetPunchItemByID(id, projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("punch item not found")
+	}
+
+	if !domain.IsValidPunchStatus(status) {
+		return nil, fmt.Errorf("invalid punch item status")
+	}
+	from := item.Status
+	if !domain.CanTransitionPunchItem(from, status) {
+		return nil, fmt.Errorf("status transition not allowed")
+	}
+
+	now := time.Now()
+	switch status {
+	case domain.PunchStatusFixed:
+		item.FixedBy = userID
+		item.FixedAt = &now
+	case domain.PunchStatusVerified:
+		item.VerifiedBy = userID
+		item.VerifiedAt = &now
+	case domain.PunchStatusOpen:
+		item.FixedBy, item.FixedAt = "", nil
+		item.VerifiedBy, item.VerifiedAt = "", nil
+		item.ClientApprovedBy, item.ClientApprovedAt = "", nil
+	}
+	item.Status = status
+	item.UpdatedAt = now
+
+	if err := s.punchRepo.UpdatePunchItem(item, statusTransition(domain.StatusEntityPunchItem, item.ID, item.ProjectID, item.CompanyID, userID, from, status, note)); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *PunchListService) GetPunchItemHistory(id, projectID, companyID string) ([]domain.StatusTransition, error) {
+	if _, err := s.punchRepo.GetPunchItemByID(id, projectID, companyID); err != nil {
+		return nil, fmt.Errorf("punch item not found")
+	}
+
+	return s.statusRepo.GetStatusTransitions(projectID, domain.StatusEntityPunchItem, id, companyID)
+}
+
+func (s *PunchListService) DeletePunchItem(id, projectID, companyID string) error {
+	if _, err := s.punchRepo.GetPunchItemByID(id, projectID, companyID); err != nil {
+		return fmt.Errorf("punch item not found")
+	}
+	return s.punchRepo.DeletePunchItem(id, projectID, companyID)
+}
+
+// ListPublicPunchItems returns the whole punch list of a shared project,
+// behind the same PIN as the public diary.
+func (s *PunchListService) ListPublicPunchItems(projectID, pin string) ([]domain.PunchItem, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.punchRepo.GetPunchItemsByProject(projectID, project.CompanyID)
+}
+
+// ApprovePublicPunchItem records the client accepting a verified fix through
+// the public project link. name is the person signing on the client's side.
+func (s *PunchListService) ApprovePublicPunchItem(projectID, itemID, pin, name string) (*domain.PunchItem, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("signer name is required")
+	}
+
+	item, err := s.punchRepo.GetPunchItemByID(itemID, projectID, project.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("punch item not found")
+	}
+	if item.Status != domain.PunchStatusVerified {
+		return nil, fmt.Errorf("only verified punch items can be approved")
+	}
+	if item.ClientApprovedAt != nil {
+		return nil, fmt.Errorf("punch item already approved")
+	}
+
+	now := time.Now()
+	item.ClientApprovedBy = name
+	item.ClientApprovedAt = &now
+	item.UpdatedAt = now
+	if err := s.punchRepo.UpdatePunchItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ExportPunchList renders the punch list of a project, optionally narrowed to
+// one status, with the photos of each item.
+func (s *PunchListService) ExportPunchList(projectID, companyID, status string) ([]byte, error) {
+	project, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+	if status != "" && !domain.IsValidPunchStatus(status) {
+		return nil, fmt.Errorf("invalid punch item status")
+	}
+
+	items, err := s.punchRepo.GetPunchItemsByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(project, filterPunchItems(items, status, "", ""), status)
+}
+
+func (s *PunchListService) ExportPublicPunchList(projectID, pin string) ([]byte, error) {
+	project, err := publicProject(s.projectRepo, projectID, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.punchRepo.GetPunchItemsByProject(projectID, project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(project, items, "")
+}
+
+func (s *PunchListService) render(project *domain.Project, items []domain.PunchItem, status string) ([]byte, error) {
+	report := &domain.PunchListReport{
+		Project:     project,
+		Status:      status,
+		Items:       sortPunchItemsForReport(items),
+		Photos:      map[string][]domain.ReportImage{},
+		Files:       map[string][]string{},
+		GeneratedAt: time.Now(),
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	report.Company = company
+	report.Logo = loadCompanyLogo(s.storage, company)
+
+	if len(report.Items) > 0 {
+		kept := make(map[string]bool, len(report.Items))
+		for _, item := range report.Items {
+			kept[item.ID] = true
+		}
+		attachments, err := s.attachmentRepo.GetAttachmentsByProject(project.ID, project.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		for _, attachment := range attachments {
+			if attachment.EntityType != domain.AttachmentEntityPunchItem || attachment.Status != domain.AttachmentStatusUploaded || !kept[attachment.EntityID] {
+				continue
+			}
+			addReportAttachment(s.storage, attachment, report.Photos, report.Files)
+		}
+	}
+
+	return s.renderer.RenderPunchListReport(report)
+}
+
+func applyPunchItemInput(item *domain.PunchItem, title, description, floor, room, trade, priority, dueDate string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("punch item title is required")
+	}
+	if priority == "" {
+		priority = domain.PunchPriorityMedium
+	}
+	if !domain.IsValidPunchPriority(priority) {
+		return fmt.Errorf("invalid punch item priority")
+	}
+
+	item.DueDate = nil
+	if dueDate != "" {
+		parsed, err := parseDate(dueDate)
+		if err != nil {
+			return fmt.Errorf("invalid due date")
+		}
+		item.DueDate = &parsed
+	}
+
+	item.Title = title
+	item.Description = description
+	item.Floor = strings.TrimSpace(floor)
+	item.Room = strings.TrimSpace(room)
+	item.Trade = strings.TrimSpace(trade)
+	item.Priority = priority
+	return nil
+}
+
+func filterPunchItems(items []domain.PunchItem, status, trade, floor string) []domain.PunchItem {
+	result := make([]domain.PunchItem, 0, len(items))
+	for _, item := range items {
+		if status != "" && item.Status != status {
+			continue
+		}
+		if trade != "" && !strings.EqualFold(item.Trade, trade) {
+			continue
+		}
+		if floor != "" && !strings.EqualFold(item.Floor, floor) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// sortPunchItemsForReport groups the printed list by location, floor then
+// room, so it can be walked through on site.
+func sortPunchItemsForReport(items []domain.PunchItem) []domain.PunchItem {
+	sorted := append([]domain.PunchItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Floor != sorted[j].Floor {
+			return sorted[i].Floor < sorted[j].Floor
+		}
+		return sorted[i].Room < sorted[j].Room
+	})
+	return sorted
+}
@@ -0,0 +1,465 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuoteService struct {
+	quoteRepo   ports.QuoteRepository
+	clientRepo  ports.ClientRepository
+	companyRepo ports.CompanyRepository
+	projectRepo ports.ProjectRepository
+	storage     ports.FileStorage
+	renderer    ports.QuoteReportRenderer
+}
+
+func NewQuoteService(quoteRepo ports.QuoteRepository, clientRepo ports.ClientRepository, companyRepo ports.CompanyRepository, projectRepo ports.ProjectRepository, storage ports.FileStorage, renderer ports.QuoteReportRenderer) *QuoteService {
+	return &QuoteService{
+		quoteRepo:   quoteRepo,
+		clientRepo:  clientRepo,
+		companyRepo: companyRepo,
+		projectRepo: projectRepo,
+		storage:     storage,
+		renderer:    renderer,
+	}
+}
+
+// CreateQuote starts a draft numbered after the company's last quote.
+func (s *QuoteService) CreateQuote(companyID, userID string, input ports.QuoteInput) (*domain.Quote, error) {
+	quotes, err := s.quoteRepo.GetQuotesByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	number := 1
+	for _, quote := range quotes {
+		number = max(number, quote.Number+1)
+	}
+
+	now := time.Now()
+	quote := &domain.Quote{
+		ID:        uuid.New().String(),
+		CompanyID: companyID,
+		Number:    number,
+		Version:   1,
+		Status:    domain.QuoteStatusDraft,
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyQuoteInput(quote, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.quoteRepo.CreateQuote(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// ListQuotes returns the company's quotes, newest first, optionally only
+// those with a status or for a client.
+func (s *QuoteService) ListQuotes(companyID, status, clientID string) ([]domain.Quote, error) {
+	if status != "" && !isQuoteStatus(status) {
+		return nil, fmt.Errorf("invalid quote status")
+	}
+
+	quotes, err := s.quoteRepo.GetQuotesByCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		if status != "" && quote.Status != status {
+			continue
+		}
+		if clientID != "" && quote.ClientID != clientID {
+			continue
+		}
+		result = append(result, quote)
+	}
+	return result, nil
+}
+
+func (s *QuoteService) GetQuote(id, companyID string) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+	return quote, nil
+}
+
+// UpdateQuote edits a quote until it is accepted. Changing a quote the client
+// has already seen, sent or rejected, starts a new version as a draft, which
+// must be sent again; the public link shows nothing until then.
+func (s *QuoteService) UpdateQuote(id, companyID string, input ports.QuoteInput) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if quote.Status == domain.QuoteStatusAccepted {
+		return nil, fmt.Errorf("quote is locked")
+	}
+
+	if err := s.applyQuoteInput(quote, input); err != nil {
+		return nil, err
+	}
+	if quote.Status != domain.QuoteStatusDraft {
+		quote.Version++
+		quote.Status = domain.QuoteStatusDraft
+		quote.SentAt = nil
+		quote.DecidedBy, quote.DecidedAt, quote.DecisionNote = "", nil, ""
+	}
+	quote.UpdatedAt = time.Now()
+
+	if err := s.quoteRepo.UpdateQuote(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// SendQuote puts the current version before the client, keeping a copy of
+// it, and gives the quote its public link the first time.
+func (s *QuoteService) SendQuote(id, companyID, userID string) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if quote.Status != domain.QuoteStatusDraft {
+		return nil, fmt.Errorf("only draft quotes can be sent")
+	}
+
+	now := time.Now()
+	if err := s.quoteRepo.CreateQuoteVersion(&domain.QuoteVersion{
+		ID:              uuid.New().String(),
+		QuoteID:         quote.ID,
+		CompanyID:       quote.CompanyID,
+		Version:         quote.Version,
+		Title:           quote.Title,
+		Address:         quote.Address,
+		Notes:           quote.Notes,
+		ValidUntil:      quote.ValidUntil,
+		Items:           quote.Items,
+		BDIPercent:      quote.BDIPercent,
+		DiscountPercent: quote.DiscountPercent,
+		Subtotal:        quote.Subtotal,
+		BDIAmount:       quote.BDIAmount,
+		DiscountAmount:  quote.DiscountAmount,
+		Total:           quote.Total,
+		UserID:          userID,
+		CreatedAt:       now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if quote.PublicToken == "" {
+		quote.PublicToken = uuid.New().String()
+	}
+	quote.Status = domain.QuoteStatusSent
+	quote.SentAt = &now
+	quote.UpdatedAt = now
+
+	if err := s.quoteRepo.UpdateQuote(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteService) ListQuoteVersions(id, companyID string) ([]domain.QuoteVersion, error) {
+	if _, err := s.quoteRepo.GetQuoteByID(id, companyID); err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+
+	return s.quoteRepo.GetQuoteVersions(id, companyID)
+}
+
+// DeleteQuote keeps accepted quotes, which are the agreement with the client.
+func (s *QuoteService) DeleteQuote(id, companyID string) error {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return fmt.Errorf("quote not found")
+	}
+	if quote.Status == domain.QuoteStatusAccepted {
+		return fmt.Errorf("quote is locked")
+	}
+	return s.quoteRepo.DeleteQuote(id, companyID)
+}
+
+func (s *QuoteService) ExportQuote(id, companyID string) ([]byte, error) {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+	return s.render(quote)
+}
+
+// ConvertQuote creates the project of an accepted quote in one go: one task
+// per stage, with the stage's items as subtasks and weighted by their cost,
+// and a budget line per item. Items without a stage become tasks of their
+// own. The budget is the direct cost; BDI and discounts only affect the price.
+func (s *QuoteService) ConvertQuote(id, companyID, userID, name, startDate string) (*domain.Project, error) {
+	quote, err := s.quoteRepo.GetQuoteByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if quote.Status != domain.QuoteStatusAccepted {
+		return nil, fmt.Errorf("only accepted quotes can be converted")
+	}
+	if quote.ProjectID != "" {
+		return nil, fmt.Errorf("quote already converted")
+	}
+
+	now := time.Now()
+	start := truncateToDay(now)
+	if startDate != "" {
+		start, err = parseDate(startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date")
+		}
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = quote.Title
+	}
+	summary := fmt.Sprintf("Orçamento nº %d (versão %d)", quote.Number, quote.Version)
+	project := newBundleProject(companyID, userID, name, quote.ClientID, quote.Address, summary, start, now)
+
+	bundle := &domain.ProjectBundle{Project: project}
+	stageTasks := map[string]int{}
+	for _, item := range quote.Items {
+		index, ok := stageTasks[item.Stage]
+		if item.Stage == "" || !ok {
+			taskName := item.Stage
+			if taskName == "" {
+				taskName = item.Description
+			}
+			index = len(bundle.Tasks)
+			bundle.Tasks = append(bundle.Tasks, domain.Task{
+				ID:        uuid.New().String(),
+				ProjectID: project.ID,
+				Name:      taskName,
+				StartDate: start,
+				Status:    domain.TaskStatusTodo,
+				UserID:    userID,
+				CompanyID: companyID,
+				CreatedAt: now,
+			})
+			if item.Stage != "" {
+				stageTasks[item.Stage] = index
+			}
+		}
+
+		task := &bundle.Tasks[index]
+		task.Weight += item.Cost()
+		if item.Stage != "" {
+			task.Subtasks = append(task.Subtasks, domain.Subtask{
+				ID:        uuid.New().String(),
+				TaskID:    task.ID,
+				Name:      item.Description,
+				Status:    domain.SubtaskStatusPending,
+				UserID:    userID,
+				CompanyID: companyID,
+				CreatedAt: now,
+			})
+		}
+	}
+	for _, item := range quote.Items {
+		bundle.BudgetLines = append(bundle.BudgetLines, domain.BudgetLine{
+			ID:          uuid.New().String(),
+			ProjectID:   project.ID,
+			CompanyID:   companyID,
+			Category:    item.Category,
+			Description: item.Description,
+			Amount:      roundCurrency(item.Cost()),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	// Claim the quote before creating anything, so a second conversion
+	// running at the same time fails instead of creating a duplicate project.
+	quote.ProjectID = project.ID
+	quote.UpdatedAt = now
+	if err := s.quoteRepo.SetQuoteProject(quote, ""); err != nil {
+		if errors.Is(err, ports.ErrVersionConflict) {
+			return nil, fmt.Errorf("quote already converted")
+		}
+		return nil, err
+	}
+
+	created, err := createProjectBundle(s.projectRepo, bundle, userID)
+	if err != nil {
+		// Release the claim unless the project made it in after all.
+		if _, getErr := s.projectRepo.GetProjectByID(project.ID, companyID); getErr != nil {
+			quote.ProjectID = ""
+			_ = s.quoteRepo.SetQuoteProject(quote, project.ID)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetPublicQuote returns a quote through its public link. Drafts, including a
+// new version being prepared, are not shown.
+func (s *QuoteService) GetPublicQuote(token string) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.GetQuoteByPublicToken(token)
+	if err != nil || quote.Status == domain.QuoteStatusDraft {
+		return nil, fmt.Errorf("quote not found")
+	}
+	return quote, nil
+}
+
+func (s *QuoteService) ExportPublicQuote(token string) ([]byte, error) {
+	quote, err := s.GetPublicQuote(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(quote)
+}
+
+// DecidePublicQuote records the client accepting or rejecting the sent
+// version. name is the person signing on the client's side. Quotes past their
+// validity can no longer be accepted.
+func (s *QuoteService) DecidePublicQuote(token, name, decision, note string) (*domain.Quote, error) {
+	quote, err := s.GetPublicQuote(token)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("signer name is required")
+	}
+	if decision != domain.QuoteStatusAccepted && decision != domain.QuoteStatusRejected {
+		return nil, fmt.Errorf("invalid quote decision")
+	}
+	if quote.Status != domain.QuoteStatusSent {
+		return nil, fmt.Errorf("quote already decided")
+	}
+
+	now := time.Now()
+	if decision == domain.QuoteStatusAccepted && quote.Expired(now) {
+		return nil, fmt.Errorf("quote has expired")
+	}
+
+	quote.Status = decision
+	quote.DecidedBy = name
+	quote.DecidedAt = &now
+	quote.DecisionNote = strings.TrimSpace(note)
+	quote.UpdatedAt = now
+
+	if err := s.quoteRepo.UpdateQuote(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteService) render(quote *domain.Quote) ([]byte, error) {
+	company, err := s.companyRepo.GetCompanyByID(quote.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepo.GetClientWithTrashed(quote.ClientID, quote.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderer.RenderQuoteReport(&domain.QuoteReport{
+		Company:     company,
+		Logo:        loadCompanyLogo(s.storage, company),
+		Client:      client,
+		Quote:       quote,
+		GeneratedAt: time.Now(),
+	})
+}
+
+// applyQuoteInput validates the input, prices the items and recomputes the
+// totals.
+func (s *QuoteService) applyQuoteInput(quote *domain.Quote, input ports.QuoteInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return fmt.Errorf("quote title is required")
+	}
+	if _, err := s.clientRepo.GetClientByID(input.ClientID, quote.CompanyID); err != nil {
+		return fmt.Errorf("client not found")
+	}
+	if len(input.Items) == 0 {
+		return fmt.Errorf("quote items are required")
+	}
+	if input.BDIPercent < 0 || input.DiscountPercent < 0 || input.DiscountPercent > 100 {
+		return fmt.Errorf("invalid quote percentage")
+	}
+
+	quote.ValidUntil = nil
+	if input.ValidUntil != "" {
+		validUntil, err := parseDate(input.ValidUntil)
+		if err != nil {
+			return fmt.Errorf("invalid validity date")
+		}
+		quote.ValidUntil = &validUntil
+	}
+
+	items := make([]domain.QuoteItem, len(input.Items))
+	subtotal := 0.0
+	for index, item := range input.Items {
+		item.Description = strings.TrimSpace(item.Description)
+		item.Stage = strings.TrimSpace(item.Stage)
+		item.Unit = strings.TrimSpace(item.Unit)
+		if item.Description == "" {
+			return fmt.Errorf("quote item description is required")
+		}
+		if !domain.IsValidCostCategory(item.Category) {
+			return fmt.Errorf("invalid cost category")
+		}
+		if item.Quantity <= 0 || item.UnitPrice < 0 {
+			return fmt.Errorf("invalid quote item amount")
+		}
+		if item.DiscountPercent < 0 || item.DiscountPercent > 100 {
+			return fmt.Errorf("invalid quote percentage")
+		}
+		cost := roundCurrency(item.Cost())
+		item.DiscountAmount = roundCurrency(cost * item.DiscountPercent / 100)
+		item.Total = roundCurrency(cost - item.DiscountAmount)
+		subtotal += item.Total
+		items[index] = item
+	}
+
+	quote.ClientID = input.ClientID
+	quote.Title = title
+	quote.Address = strings.TrimSpace(input.Address)
+	quote.Notes = input.Notes
+	quote.Items = items
+	quote.BDIPercent = input.BDIPercent
+	quote.DiscountPercent = input.DiscountPercent
+	quote.Subtotal = roundCurrency(subtotal)
+	quote.BDIAmount = roundCurrency(subtotal * input.BDIPercent / 100)
+	quote.DiscountAmount = roundCurrency((quote.Subtotal + quote.BDIAmount) * input.DiscountPercent / 100)
+	quote.Total = roundCurrency(quote.Subtotal + quote.BDIAmount - quote.DiscountAmount)
+	return nil
+}
+
+func isQuoteStatus(status string) bool {
+	switch status {
+	case domain.QuoteStatusDraft, domain.QuoteStatusSent, domain.QuoteStatusAccepted, domain.QuoteStatusRejected:
+		return true
+	}
+	return false
+}
+
+func roundCurrency(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+var errDependencyCycle = errors.New("dependency cycle detected")
+
+const day = 24 * time.Hour
+
+// taskDuration returns the planned duration in days, deriving it from the
+// start and due dates when it was not set explicitly.
+func taskDuration(task domain.Task) int {
+	if task.Duration > 0 {
+		return task.Duration
+	}
+	if !task.StartDate.IsZero() && !task.DueDate.IsZero() && task.DueDate.After(task.StartDate) {
+		return daysBetween(task.StartDate, task.DueDate)
+	}
+	return 0
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// orderTasks returns the tasks in topological order of their dependencies, or
+// errDependencyCycle when the graph cannot be ordered. Dependencies on tasks
+// outside the given set are ignored.
+func orderTasks(tasks []domain.Task) ([]domain.Task, error) {
+	byID := make(map[string]domain.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	inDegree := make(map[string]int, len(tasks))
+	successors := make(map[string][]string, len(tasks))
+	for _, task := range tasks {
+		inDegree[task.ID] += 0
+		for _, dependency := range task.Dependencies {
+			if _, ok := byID[dependency.DependsOnTaskID]; !ok {
+				continue
+			}
+			inDegree[task.ID]++
+			successors[dependency.DependsOnTaskID] = append(successors[dependency.DependsOnTaskID], task.ID)
+		}
+	}
+
+	queue := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if inDegree[task.ID] == 0 {
+			queue = append(queue, task.ID)
+		}
+	}
+
+	ordered := make([]domain.Task, 0, len(tasks))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, byID[id])
+		for _, successorID := range successors[id] {
+			inDegree[successorID]--
+			if inDegree[successorID] == 0 {
+				queue = append(queue, successorID)
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, errDependencyCycle
+	}
+	return ordered, nil
+}
+
+// computeSchedule runs the critical path method over the project's tasks.
+// Durations and lags are expressed in calendar days.
+func computeSchedule(project *domain.Project, tasks []domain.Task) (*domain.ProjectSchedule, error) {
+	ordered, err := orderTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := scheduleOrigin(project, tasks)
+	schedule := &domain.ProjectSchedule{
+		ProjectID:    project.ID,
+		StartDate:    origin,
+		FinishDate:   origin,
+		Tasks:        []domain.ScheduledTask{},
+		CriticalPath: []string{},
+	}
+	if len(ordered) == 0 {
+		return schedule, nil
+	}
+
+	durations := make(map[string]int, len(ordered))
+	earlyStart := make(map[string]int, len(ordered))
+	earlyFinish := make(map[string]int, len(ordered))
+	known := make(map[string]bool, len(ordered))
+	for _, task := range ordered {
+		known[task.ID] = true
+	}
+
+	projectFinish := 0
+	for _, task := range ordered {
+		durations[task.ID] = taskDuration(task)
+
+		start := 0
+		if !task.StartDate.IsZero() {
+			start = daysBetween(origin, task.StartDate)
+		}
+		for _, dependency := range task.Dependencies {
+			if !known[dependency.DependsOnTaskID] {
+				continue
+			}
+			var constraint int
+			if dependency.Type == domain.DependencyStartToStart {
+				constraint = earlyStart[dependency.DependsOnTaskID] + dependency.LagDays
+			} else {
+				constraint = earlyFinish[dependency.DependsOnTaskID] + dependency.LagDays
+			}
+			if constraint > start {
+				start = constraint
+			}
+		}
+
+		earlyStart[task.ID] = start
+		earlyFinish[task.ID] = start + durations[task.ID]
+		if earlyFinish[task.ID] > projectFinish {
+			projectFinish = earlyFinish[task.ID]
+		}
+	}
+
+	lateFinish := make(map[string]int, len(ordered))
+	lateStart := make(map[string]int, len(ordered))
+	for _, task := range ordered {
+		lateFinish[task.ID] = projectFinish
+	}
+	for index := len(ordered) - 1; index >= 0; index-- {
+		task := ordered[index]
+		lateStart[task.ID] = lateFinish[task.ID] - durations[task.ID]
+		for _, dependency := range task.Dependencies {
+			predecessorID := dependency.DependsOnTaskID
+			if !known[predecessorID] {
+				continue
+			}
+			var limit int
+			if dependency.Type == domain.DependencyStartToStart {
+				limit = lateStart[task.ID] - dependency.LagDays + durations[predecessorID]
+			} else {
+				limit = lateStart[task.ID] - dependency.LagDays
+			}
+			if limit < lateFinish[predecessorID] {
+				lateFinish[predecessorID] = limit
+			}
+		}
+	}
+
+	for _, task := range ordered {
+		totalFloat := lateStart[task.ID] - earlyStart[task.ID]
+		dependsOn := make([]string, 0, len(task.Dependencies))
+		for _, dependency := range task.Dependencies {
+			dependsOn = append(dependsOn, dependency.DependsOnTaskID)
+		}
+		schedule.Tasks = append(schedule.Tasks, domain.ScheduledTask{
+			TaskID:      task.ID,
+			Name:        task.Name,
+			Status:      domain.NormalizeTaskStatus(task.Status),
+			Duration:    durations[task.ID],
+			EarlyStart:  origin.Add(time.Duration(earlyStart[task.ID]) * day),
+			EarlyFinish: origin.Add(time.Duration(earlyFinish[task.ID]) * day),
+			LateStart:   origin.Add(time.Duration(lateStart[task.ID]) * day),
+			LateFinish:  origin.Add(time.Duration(lateFinish[task.ID]) * day),
+			TotalFloat:  totalFloat,
+			Critical:    totalFloat <= 0,
+			DependsOn:   dependsOn,
+		})
+	}
+
+	sort.SliceStable(schedule.Tasks, func(i, j int) bool {
+		return schedule.Tasks[i].EarlyStart.Before(schedule.Tasks[j].EarlyStart)
+	})
+	for _, task := range schedule.Tasks {
+		if task.Critical {
+			schedule.CriticalPath = append(schedule.CriticalPath, task.TaskID)
+		}
+	}
+
+	schedule.Duration = projectFinish
+	schedule.FinishDate = origin.Add(time.Duration(projectFinish) * day)
+	return schedule, nil
+}
+
+// scheduleOrigin anchors day zero of the schedule on the project start date,
+// falling back to the earliest task start.
+func scheduleOrigin(project *domain.Project, tasks []domain.Task) time.Time {
+	origin := project.StartDate
+	for _, task := range tasks {
+		if task.StartDate.IsZero() {
+			continue
+		}
+		if origin.IsZero() || task.StartDate.Before(origin) {
+			origin = task.StartDate
+		}
+	}
+	if origin.IsZero() {
+		origin = time.Now()
+	}
+	return origin.Truncate(day)
+}
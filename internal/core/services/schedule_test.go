@@ -0,0 +1,126 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// scheduledDays is a scheduled task as day offsets from the project start.
+type scheduledDays struct {
+	earlyStart, earlyFinish, lateStart, lateFinish, totalFloat int
+}
+
+func dependency(taskID, dependsOn, dependencyType string, lagDays int) domain.TaskDependency {
+	return domain.TaskDependency{TaskID: taskID, DependsOnTaskID: dependsOn, Type: dependencyType, LagDays: lagDays}
+}
+
+func TestComputeSchedule(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		tasks        []domain.Task
+		want         map[string]scheduledDays
+		duration     int
+		criticalPath []string
+	}{
+		{
+			// A runs 3 days, B starts 2 days after A finishes; C is free to
+			// slip until the project ends.
+			name: "finish to start with lag",
+			tasks: []domain.Task{
+				{ID: "a", Duration: 3},
+				{ID: "b", Duration: 4, Dependencies: []domain.TaskDependency{dependency("b", "a", domain.DependencyFinishToStart, 2)}},
+				{ID: "c", Duration: 2},
+			},
+			want: map[string]scheduledDays{
+				"a": {0, 3, 0, 3, 0},
+				"b": {5, 9, 5, 9, 0},
+				"c": {0, 2, 7, 9, 7},
+			},
+			duration:     9,
+			criticalPath: []string{"a", "b"},
+		},
+		{
+			// B starts a day after A starts and outlasts it, so A's latest
+			// finish is bound by B's latest start, not by the project end.
+			name: "start to start with lag",
+			tasks: []domain.Task{
+				{ID: "a", Duration: 2},
+				{ID: "b", Duration: 6, Dependencies: []domain.TaskDependency{dependency("b", "a", domain.DependencyStartToStart, 1)}},
+			},
+			want: map[string]scheduledDays{
+				"a": {0, 2, 0, 2, 0},
+				"b": {1, 7, 1, 7, 0},
+			},
+			duration:     7,
+			criticalPath: []string{"a", "b"},
+		},
+		{
+			// C waits for both: 2 days after A starts and right after B
+			// finishes, whichever is later.
+			name: "mixed predecessors",
+			tasks: []domain.Task{
+				{ID: "a", Duration: 5},
+				{ID: "b", Duration: 1},
+				{ID: "c", Duration: 3, Dependencies: []domain.TaskDependency{
+					dependency("c", "a", domain.DependencyStartToStart, 2),
+					dependency("c", "b", domain.DependencyFinishToStart, 0),
+				}},
+			},
+			want: map[string]scheduledDays{
+				"a": {0, 5, 0, 5, 0},
+				"b": {0, 1, 1, 2, 1},
+				"c": {2, 5, 2, 5, 0},
+			},
+			duration:     5,
+			criticalPath: []string{"a", "c"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			project := &domain.Project{ID: "project", StartDate: start}
+			schedule, err := computeSchedule(project, test.tasks)
+			if err != nil {
+				t.Fatalf("computeSchedule: %v", err)
+			}
+
+			if schedule.Duration != test.duration {
+				t.Errorf("duration = %d, want %d", schedule.Duration, test.duration)
+			}
+			if want := start.AddDate(0, 0, test.duration); !schedule.FinishDate.Equal(want) {
+				t.Errorf("finish date = %s, want %s", schedule.FinishDate, want)
+			}
+			if !slices.Equal(schedule.CriticalPath, test.criticalPath) {
+				t.Errorf("critical path = %v, want %v", schedule.CriticalPath, test.criticalPath)
+			}
+			for _, task := range schedule.Tasks {
+				got := scheduledDays{
+					earlyStart:  daysBetween(start, task.EarlyStart),
+					earlyFinish: daysBetween(start, task.EarlyFinish),
+					lateStart:   daysBetween(start, task.LateStart),
+					lateFinish:  daysBetween(start, task.LateFinish),
+					totalFloat:  task.TotalFloat,
+				}
+				if got != test.want[task.TaskID] {
+					t.Errorf("task %s = %+v, want %+v", task.TaskID, got, test.want[task.TaskID])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeScheduleRejectsCycles(t *testing.T) {
+	tasks := []domain.Task{
+		{ID: "a", Duration: 1, Dependencies: []domain.TaskDependency{dependency("a", "c", domain.DependencyFinishToStart, 0)}},
+		{ID: "b", Duration: 1, Dependencies: []domain.TaskDependency{dependency("b", "a", domain.DependencyStartToStart, 0)}},
+		{ID: "c", Duration: 1, Dependencies: []domain.TaskDependency{dependency("c", "b", domain.DependencyFinishToStart, 0)}},
+	}
+	if _, err := computeSchedule(&domain.Project{ID: "project"}, tasks); !errors.Is(err, errDependencyCycle) {
+		t.Fatalf("err = %v, want errDependencyCycle", err)
+	}
+}
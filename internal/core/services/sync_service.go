@@ -0,0 +1,500 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// syncPageSize caps how many feed changes one sync returns.
+const syncPageSize = 500
+
+type SyncService struct {
+	projects    *ProjectService
+	projectRepo ports.ProjectRepository
+	syncRepo    ports.SyncRepository
+}
+
+func NewSyncService(projectService *ProjectService, syncRepo ports.SyncRepository) *SyncService {
+	return &SyncService{
+		projects:    projectService,
+		projectRepo: projectService.projectRepo,
+		syncRepo:    syncRepo,
+	}
+}
+
+// Sync applies a batch of offline changes in order and returns what changed on
+// the server since cursor. An empty cursor returns every synced record of the
+// company's live projects instead. A change based on a stale version is not
+// applied and comes back as a conflict holding the server's copy, so the
+// client decides how to merge it.
+func (s *SyncService) Sync(companyID, userID, cursor string, mutations []ports.SyncMutation) (*domain.SyncResponse, error) {
+	response := &domain.SyncResponse{
+		Results: make([]domain.SyncResult, 0, len(mutations)),
+		Changes: []domain.SyncRecord{},
+	}
+	for _, mutation := range mutations {
+		response.Results = append(response.Results, s.apply(companyID, userID, mutation))
+	}
+
+	if cursor == "" {
+		if err := s.snapshot(companyID, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	if err := s.changesSince(companyID, cursor, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *SyncService) apply(companyID, userID string, mutation ports.SyncMutation) domain.SyncResult {
+	result := domain.SyncResult{EntityType: mutation.EntityType, ID: mutation.ID}
+
+	var (
+		record  any
+		version int
+		err     error
+	)
+	switch {
+	case uuid.Validate(mutation.ID) != nil:
+		err = fmt.Errorf("invalid record id")
+	case mutation.Operation != domain.SyncOperationUpsert && mutation.Operation != domain.SyncOperationDelete:
+		err = fmt.Errorf("invalid sync operation")
+	case mutation.EntityType == domain.SyncEntityDiaryEntry:
+		record, version, err = s.applyDiaryEntry(companyID, userID, mutation)
+	case mutation.EntityType == domain.SyncEntityTask:
+		record, version, err = s.applyTask(companyID, userID, mutation)
+	case mutation.EntityType == domain.SyncEntitySubtask:
+		record, version, err = s.applySubtask(companyID, userID, mutation)
+	default:
+		err = fmt.Errorf("invalid sync entity type")
+	}
+
+	if errors.Is(err, ports.ErrVersionConflict) && record == nil {
+		// The record changed between the version check and the write.
+		record, version, err = s.serverCopy(companyID, mutation)
+		if err == nil {
+			err = ports.ErrVersionConflict
+		}
+	}
+
+	switch {
+	case errors.Is(err, ports.ErrVersionConflict):
+		result.Status = domain.SyncResultConflict
+	case err != nil:
+		result.Status = domain.SyncResultRejected
+		result.Error = err.Error()
+		return result
+	default:
+		result.Status = domain.SyncResultApplied
+	}
+	result.Version = version
+	result.Record = record
+	return result
+}
+
+func (s *SyncService) applyDiaryEntry(companyID, userID string, mutation ports.SyncMutation) (any, int, error) {
+	current, err := s.projectRepo.GetDiaryEntryByID(mutation.ID, mutation.ProjectID, companyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	input := mutation.DiaryEntry
+
+	if current == nil {
+		if mutation.BaseVersion != 0 {
+			return nil, 0, ports.ErrVersionConflict
+		}
+		if mutation.Operation == domain.SyncOperationDelete {
+			return nil, 0, nil
+		}
+		if input == nil {
+			return nil, 0, fmt.Errorf("sync data is required")
+		}
+		entry, err := s.projects.addDiaryEntry(mutation.ID, mutation.ProjectID, companyID, userID, input.EntryDate, input.Title, input.TemplateID, input.Items)
+		if err != nil {
+			return nil, 0, err
+		}
+		return entry, entry.Version, nil
+	}
+
+	if current.Version != mutation.BaseVersion {
+		return current, current.Version, ports.ErrVersionConflict
+	}
+	if mutation.Operation == domain.SyncOperationDelete {
+		return nil, 0, s.projects.DeleteDiaryEntry(mutation.ID, mutation.ProjectID, companyID)
+	}
+	if input == nil {
+		return nil, 0, fmt.Errorf("sync data is required")
+	}
+	entry, err := s.projects.editDiaryEntry(current, userID, input.EntryDate, input.Title, input.Items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry, entry.Version, nil
+}
+
+func (s *SyncService) applyTask(companyID, userID string, mutation ports.SyncMutation) (any, int, error) {
+	current, err := s.projectRepo.GetTaskByID(mutation.ID, companyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	input := mutation.Task
+
+	if current == nil {
+		if mutation.BaseVersion != 0 {
+			return nil, 0, ports.ErrVersionConflict
+		}
+		if mutation.Operation == domain.SyncOperationDelete {
+			return nil, 0, nil
+		}
+		if input == nil {
+			return nil, 0, fmt.Errorf("sync data is required")
+		}
+		if strings.TrimSpace(input.Name) == "" {
+			return nil, 0, fmt.Errorf("task name is required")
+		}
+		if input.Schedule.DueDate == "" {
+			return nil, 0, fmt.Errorf("due date is required")
+		}
+		task, err := s.projects.addTask(mutation.ID, mutation.ProjectID, input.Name, input.Status, companyID, userID, input.Schedule, input.Assignment)
+		if err != nil {
+			return nil, 0, err
+		}
+		return task, task.Version, nil
+	}
+
+	if current.Version != mutation.BaseVersion {
+		return current, current.Version, ports.ErrVersionConflict
+	}
+	if mutation.Operation == domain.SyncOperationDelete {
+		return nil, 0, s.projects.DeleteTask(mutation.ID, companyID)
+	}
+	if input == nil {
+		return nil, 0, fmt.Errorf("sync data is required")
+	}
+
+	task := current
+	if name := strings.TrimSpace(input.Name); name != "" {
+		task.Name = name
+	}
+	if err := s.projects.applyTaskSchedule(task, input.Schedule); err != nil {
+		return nil, 0, err
+	}
+	if err := s.assignTask(task, input.Assignment); err != nil {
+		return nil, 0, err
+	}
+
+	// One write per change, so the client gets back a single new version.
+	status := domain.NormalizeTaskStatus(input.Status)
+	if input.Status != "" && status != domain.NormalizeTaskStatus(task.Status) {
+		task, err = s.projects.transitionTask(task, userID, status, "")
+		if err != nil {
+			return nil, 0, err
+		}
+		return task, task.Version, nil
+	}
+
+	if err := s.projectRepo.UpdateTask(task); err != nil {
+		return nil, 0, err
+	}
+	if err := refreshProjectProgress(s.projectRepo, s.projects.milestoneRepo, task.ProjectID, companyID); err != nil {
+		return nil, 0, err
+	}
+	return task, task.Version, nil
+}
+
+func (s *SyncService) applySubtask(companyID, userID string, mutation ports.SyncMutation) (any, int, error) {
+	current, err := s.projectRepo.GetSubtaskByID(mutation.ID, companyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	input := mutation.Subtask
+
+	if current == nil {
+		if mutation.BaseVersion != 0 {
+			return nil, 0, ports.ErrVersionConflict
+		}
+		if mutation.Operation == domain.SyncOperationDelete {
+			return nil, 0, nil
+		}
+		if input == nil {
+			return nil, 0, fmt.Errorf("sync data is required")
+		}
+		if strings.TrimSpace(input.Name) == "" {
+			return nil, 0, fmt.Errorf("subtask name is required")
+		}
+		subtask, err := s.projects.addSubtask(mutation.ID, input.TaskID, input.Name, input.Status, companyID, userID, input.Assignment)
+		if err != nil {
+			return nil, 0, err
+		}
+		return subtask, subtask.Version, nil
+	}
+
+	if current.Version != mutation.BaseVersion {
+		return current, current.Version, ports.ErrVersionConflict
+	}
+	if mutation.Operation == domain.SyncOperationDelete {
+		return nil, 0, s.projects.DeleteSubtask(mutation.ID, companyID)
+	}
+	if input == nil {
+		return nil, 0, fmt.Errorf("sync data is required")
+	}
+
+	subtask := current
+	if name := strings.TrimSpace(input.Name); name != "" {
+		subtask.Name = name
+	}
+	if input.Status != "" {
+		if !domain.ValidSubtaskStatus(input.Status) {
+			return nil, 0, fmt.Errorf("invalid subtask status")
+		}
+		subtask.Status = input.Status
+	}
+	if input.Assignment.AssigneeIDs != nil || input.Assignment.Crew != nil {
+		task, err := s.projectRepo.GetTaskByID(subtask.TaskID, companyID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("task not found or access denied")
+		}
+		if input.Assignment.AssigneeIDs != nil {
+			assignees, err := s.projects.buildAssignees(companyID, task.ProjectID, task.ID, subtask.ID, input.Assignment.AssigneeIDs)
+			if err != nil {
+				return nil, 0, err
+			}
+			subtask.Assignees = assignees
+		}
+		if input.Assignment.Crew != nil {
+			subtask.Crew = strings.TrimSpace(*input.Assignment.Crew)
+		}
+	}
+
+	if err := s.projectRepo.UpdateSubtask(subtask); err != nil {
+		return nil, 0, err
+	}
+	if err := s.projects.refreshTaskProject(subtask.TaskID, companyID); err != nil {
+		return nil, 0, err
+	}
+	return subtask, subtask.Version, nil
+}
+
+// serverCopy reads the stored record a mutation targets, nil if it is gone.
+func (s *SyncService) serverCopy(companyID string, mutation ports.SyncMutation) (any, int, error) {
+	record, err := s.currentRecord(companyID, domain.SyncChange{
+		EntityType: mutation.EntityType,
+		EntityID:   mutation.ID,
+		ProjectID:  mutation.ProjectID,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return record.Record, record.Version, nil
+}
+
+func (s *SyncService) assignTask(task *domain.Task, assignment ports.TaskAssignmentInput) error {
+	if assignment.AssigneeIDs != nil {
+		assignees, err := s.projects.buildAssignees(task.CompanyID, task.ProjectID, task.ID, "", assignment.AssigneeIDs)
+		if err != nil {
+			return err
+		}
+		task.Assignees = assignees
+	}
+	if assignment.Crew != nil {
+		task.Crew = strings.TrimSpace(*assignment.Crew)
+	}
+	return nil
+}
+
+// snapshot fills the response with every synced record of the company's live
+// projects. The cursor is read first so nothing written meanwhile is missed.
+func (s *SyncService) snapshot(companyID string, response *domain.SyncResponse) error {
+	cursor, err := s.syncRepo.GetLatestSyncCursor(companyID)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := s.projectRepo.GetTasksByCompanyID(companyID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		response.Changes = append(response.Changes, domain.SyncRecord{
+			EntityType: domain.SyncEntityTask,
+			ID:         task.ID,
+			ProjectID:  task.ProjectID,
+			Version:    task.Version,
+			Record:     task,
+		})
+		for _, subtask := range task.Subtasks {
+			response.Changes = append(response.Changes, domain.SyncRecord{
+				EntityType: domain.SyncEntitySubtask,
+				ID:         subtask.ID,
+				ProjectID:  task.ProjectID,
+				Version:    subtask.Version,
+				Record:     subtask,
+			})
+		}
+	}
+
+	projects, err := s.projectRepo.GetAllProjects(companyID)
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		entries, err := s.projectRepo.GetDiaryEntriesByProject(project.ID, companyID)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			response.Changes = append(response.Changes, domain.SyncRecord{
+				EntityType: domain.SyncEntityDiaryEntry,
+				ID:         entry.ID,
+				ProjectID:  entry.ProjectID,
+				Version:    entry.Version,
+				Record:     entry,
+			})
+		}
+	}
+
+	response.Cursor = cursor
+	return nil
+}
+
+// changesSince fills the response with the records changed after cursor, one
+// per record in the order of their latest change.
+func (s *SyncService) changesSince(companyID, cursor string, response *domain.SyncResponse) error {
+	changes, err := s.syncRepo.GetSyncChanges(companyID, cursor, syncPageSize)
+	if err != nil {
+		return err
+	}
+
+	response.Cursor = cursor
+	response.HasMore = len(changes) == syncPageSize
+	if len(changes) > 0 {
+		response.Cursor = changes[len(changes)-1].Cursor
+	}
+
+	records := make([]domain.SyncRecord, 0, len(changes))
+	for _, change := range changes {
+		if change.EntityType == domain.SyncEntityProject {
+			projectRecords, err := s.projectRecords(companyID, change)
+			if err != nil {
+				return err
+			}
+			records = append(records, projectRecords...)
+			continue
+		}
+		record, err := s.currentRecord(companyID, change)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	latest := make(map[string]int, len(records))
+	for i, record := range records {
+		latest[record.EntityType+"#"+record.ID] = i
+	}
+	for i, record := range records {
+		if latest[record.EntityType+"#"+record.ID] == i {
+			response.Changes = append(response.Changes, record)
+		}
+	}
+	return nil
+}
+
+// projectRecords lists every synced record of a project that was trashed,
+// restored or created from a template: tombstones if it is gone, the current
+// records otherwise.
+func (s *SyncService) projectRecords(companyID string, change domain.SyncChange) ([]domain.SyncRecord, error) {
+	tasks, err := s.projectRepo.GetTasksByProjectID(change.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.projectRepo.GetDiaryEntriesByProject(change.ProjectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	deleted := change.Deleted
+	if !deleted {
+		if _, err := s.projectRepo.GetProjectByID(change.ProjectID, companyID); errors.Is(err, gorm.ErrRecordNotFound) {
+			deleted = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	var records []domain.SyncRecord
+	add := func(entityType, id string, version int, record any) {
+		synced := domain.SyncRecord{
+			EntityType: entityType,
+			ID:         id,
+			ProjectID:  change.ProjectID,
+			Version:    version,
+			Deleted:    deleted,
+		}
+		if !deleted {
+			synced.Record = record
+		}
+		records = append(records, synced)
+	}
+	for _, task := range tasks {
+		if task.CompanyID != companyID {
+			continue
+		}
+		add(domain.SyncEntityTask, task.ID, task.Version, task)
+		for _, subtask := range task.Subtasks {
+			add(domain.SyncEntitySubtask, subtask.ID, subtask.Version, subtask)
+		}
+	}
+	for _, entry := range entries {
+		add(domain.SyncEntityDiaryEntry, entry.ID, entry.Version, entry)
+	}
+	return records, nil
+}
+
+func (s *SyncService) currentRecord(companyID string, change domain.SyncChange) (domain.SyncRecord, error) {
+	record := domain.SyncRecord{
+		EntityType: change.EntityType,
+		ID:         change.EntityID,
+		ProjectID:  change.ProjectID,
+		Version:    change.Version,
+		Deleted:    true,
+	}
+	if change.Deleted {
+		return record, nil
+	}
+
+	var err error
+	switch change.EntityType {
+	case domain.SyncEntityDiaryEntry:
+		var entry *domain.DiaryEntry
+		if entry, err = s.projectRepo.GetDiaryEntryByID(change.EntityID, change.ProjectID, companyID); err == nil {
+			record.Version, record.Record = entry.Version, entry
+		}
+	case domain.SyncEntityTask:
+		var task *domain.Task
+		if task, err = s.projectRepo.GetTaskByID(change.EntityID, companyID); err == nil {
+			record.Version, record.Record = task.Version, task
+		}
+	case domain.SyncEntitySubtask:
+		var subtask *domain.Subtask
+		if subtask, err = s.projectRepo.GetSubtaskByID(change.EntityID, companyID); err == nil {
+			record.Version, record.Record = subtask.Version, subtask
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return record, nil
+	}
+	if err != nil {
+		return record, err
+	}
+	record.Deleted = record.Record == nil
+	return record, nil
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateService struct {
+	templateRepo  ports.TemplateRepository
+	projectRepo   ports.ProjectRepository
+	milestoneRepo ports.MilestoneRepository
+}
+
+func NewTemplateService(templateRepo ports.TemplateRepository, projectRepo ports.ProjectRepository, milestoneRepo ports.MilestoneRepository) *TemplateService {
+	return &TemplateService{
+		templateRepo:  templateRepo,
+		projectRepo:   projectRepo,
+		milestoneRepo: milestoneRepo,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(companyID, name, description string, tasks []domain.TemplateTask, presets []domain.DiaryFieldPreset) (*domain.ProjectTemplate, error) {
+	if err := validateTemplate(name, tasks, presets); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &domain.ProjectTemplate{
+		ID:                uuid.New().String(),
+		CompanyID:         companyID,
+		Name:              strings.TrimSpace(name),
+		Description:       description,
+		Tasks:             tasks,
+		DiaryFieldPresets: presets,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := s.templateRepo.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) ListTemplates(companyID string) ([]domain.ProjectTemplate, error) {
+	return s.templateRepo.GetTemplatesByCompany(companyID)
+}
+
+func (s *TemplateService) GetTemplate(id, companyID string) (*domain.ProjectTemplate, error) {
+	template, err := s.templateRepo.GetTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, nil
+}
+
+func (s *TemplateService) UpdateTemplate(id, companyID, name, description string, tasks []domain.TemplateTask, presets []domain.DiaryFieldPreset) (*domain.ProjectTemplate, error) {
+	template, err := s.templateRepo.GetTemplateByID(id, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("template not found")
+	}
+
+	if err := validateTemplate(name, tasks, presets); err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(name)
+	template.Description = description
+	template.Tasks = tasks
+	template.DiaryFieldPresets = presets
+	template.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.UpdateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) DeleteTemplate(id, companyID string) error {
+	if _, err := s.templateRepo.GetTemplateByID(id, companyID); err != nil {
+		return fmt.Errorf("template not found")
+	}
+	return s.templateRepo.DeleteTemplate(id, companyID)
+}
+
+// CloneProject copies a project with its milestones, tasks, subtasks,
+// dependencies and assignees. Work progress is reset, and when a new start
+// date is given every date is shifted by the same amount.
+func (s *TemplateService) CloneProject(projectID, companyID, userID, name, startDate string) (*domain.Project, error) {
+	source, err := s.projectRepo.GetProjectByID(projectID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found or access denied")
+	}
+
+	tasks, err := s.projectRepo.GetTasksByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.milestoneRepo.GetMilestonesByProject(projectID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := source.StartDate
+	if startDate != "" {
+		start, err = parseDate(startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date")
+		}
+	}
+	shift := start.Sub(source.StartDate)
+	if source.StartDate.IsZero() {
+		shift = 0
+	}
+	shiftDate := func(value time.Time) time.Time {
+		if value.IsZero() {
+			return value
+		}
+		return value.Add(shift)
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = source.Name
+	}
+
+	now := time.Now()
+	project := newBundleProject(companyID, userID, name, source.ClientID, source.Address, source.Summary, start, now)
+	project.DiaryFieldPresets = source.DiaryFieldPresets
+
+	milestoneIDs := make(map[string]string, len(milestones))
+	bundle := &domain.ProjectBundle{Project: project}
+	for _, milestone := range milestones {
+		milestoneIDs[milestone.ID] = uuid.New().String()
+		bundle.Milestones = append(bundle.Milestones, domain.Milestone{
+			ID:          milestoneIDs[milestone.ID],
+			ProjectID:   project.ID,
+			CompanyID:   companyID,
+			Name:        milestone.Name,
+			Description: milestone.Description,
+			TargetDate:  shiftDate(milestone.TargetDate),
+			Weight:      milestone.Weight,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	taskIDs := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		taskIDs[task.ID] = uuid.New().String()
+	}
+
+	for _, task := range tasks {
+		clone := domain.Task{
+			ID:          taskIDs[task.ID],
+			ProjectID:   project.ID,
+			Name:        task.Name,
+			MilestoneID: milestoneIDs[task.MilestoneID],
+			Weight:      task.Weight,
+			StartDate:   shiftDate(task.StartDate),
+			Duration:    task.Duration,
+			DueDate:     shiftDate(task.DueDate),
+			Status:      domain.TaskStatusTodo,
+			UserID:      userID,
+			CompanyID:   companyID,
+			Crew:        task.Crew,
+			CreatedAt:   now,
+		}
+		for _, assignee := range task.Assignees {
+			clone.Assignees = append(clone.Assignees, domain.TaskAssignee{
+				ID:        uuid.New().String(),
+				TaskID:    clone.ID,
+				ProjectID: project.ID,
+				CompanyID: companyID,
+				UserID:    assignee.UserID,
+				CreatedAt: now,
+			})
+		}
+		for _, dependency := range task.Dependencies {
+			dependsOn, ok := taskIDs[dependency.DependsOnTaskID]
+			if !ok {
+				continue
+			}
+			clone.Dependencies = append(clone.Dependencies, domain.TaskDependency{
+				ID:              uuid.New().String(),
+				TaskID:          clone.ID,
+				DependsOnTaskID: dependsOn,
+				ProjectID:       project.ID,
+				CompanyID:       companyID,
+				Type:            dependency.Type,
+				LagDays:         dependency.LagDays,
+				CreatedAt:       now,
+			})
+		}
+		for _, subtask := range task.Subtasks {
+			subtaskClone := domain.Subtask{
+				ID:        uuid.New().String(),
+				TaskID:    clone.ID,
+				Name:      subtask.Name,
+				Status:    domain.SubtaskStatusPending,
+				UserID:    userID,
+				CompanyID: companyID,
+				Crew:      subtask.Crew,
+				CreatedAt: now,
+			}
+			for _, assignee := range subtask.Assignees {
+				subtaskClone.Assignees = append(subtaskClone.Assignees, domain.TaskAssignee{
+					ID:        uuid.New().String(),
+					TaskID:    clone.ID,
+					SubtaskID: subtaskClone.ID,
+					ProjectID: project.ID,
+					CompanyID: companyID,
+					UserID:    assignee.UserID,
+					CreatedAt: now,
+				})
+			}
+			clone.Subtasks = append(clone.Subtasks, subtaskClone)
+		}
+		bundle.Tasks = append(bundle.Tasks, clone)
+	}
+
+	return createProjectBundle(s.projectRepo, bundle, userID)
+}
+
+func (s *TemplateService) CreateProjectFromTemplate(templateID, companyID, userID, name, clientID, address, summary, startDate string) (*domain.Project, error) {
+	template, err := s.templateRepo.GetTemplateByID(templateID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("template not found")
+	}
+
+	now := time.Now()
+	start := now.UTC().Truncate(24 * time.Hour)
+	if startDate != "" {
+		start, err = parseDate(startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date")
+		}
+	}
+
+	project := newBundleProject(companyID, userID, name, clientID, address, summary, start, now)
+	project.DiaryFieldPresets = template.DiaryFieldPresets
+
+	taskIDs := make([]string, len(template.Tasks))
+	for index := range template.Tasks {
+		taskIDs[index] = uuid.New().String()
+	}
+
+	bundle := &domain.ProjectBundle{Project: project}
+	for index, templateTask := range template.Tasks {
+		weight := defaultWeight
+		if templateTask.Weight != nil {
+			weight = *templateTask.Weight
+		}
+		task := domain.Task{
+			ID:        taskIDs[index],
+			ProjectID: project.ID,
+			Name:      templateTask.Name,
+			Weight:    weight,
+			StartDate: start.AddDate(0, 0, templateTask.StartOffsetDays),
+			Duration:  templateTask.Duration,
+			DueDate:   start.AddDate(0, 0, templateTask.DueOffsetDays),
+			Status:    domain.TaskStatusTodo,
+			UserID:    userID,
+			CompanyID: companyID,
+			Crew:      templateTask.Crew,
+			CreatedAt: now,
+		}
+		for _, dependency := range templateTask.Dependencies {
+			dependencyType := dependency.Type
+			if dependencyType == "" {
+				dependencyType = domain.DependencyFinishToStart
+			}
+			task.Dependencies = append(task.Dependencies, domain.TaskDependency{
+				ID:              uuid.New().String(),
+				TaskID:          task.ID,
+				DependsOnTaskID: taskIDs[dependency.TaskIndex],
+				ProjectID:       project.ID,
+				CompanyID:       companyID,
+				Type:            dependencyType,
+				LagDays:         dependency.LagDays,
+				CreatedAt:       now,
+			})
+		}
+		for _, templateSubtask := range templateTask.Subtasks {
+			task.Subtasks = append(task.Subtasks, domain.Subtask{
+				ID:        uuid.New().String(),
+				TaskID:    task.ID,
+				Name:      templateSubtask.Name,
+				Status:    domain.SubtaskStatusPending,
+				UserID:    userID,
+				CompanyID: companyID,
+				Crew:      templateSubtask.Crew,
+				CreatedAt: now,
+			})
+		}
+		bundle.Tasks = append(bundle.Tasks, task)
+	}
+
+	return createProjectBundle(s.projectRepo, bundle, userID)
+}
+
+// maxBundleRecords caps the milestones, tasks, subtasks and budget lines a new
+// project is created with. Some stores write a bundle in several transactions, and a
+// bounded bundle keeps that, and undoing it after a failure, short.
+const maxBundleRecords = 2000
+
+// createProjectBundle stores a new project with its children and its initial
+// status.
+func createProjectBundle(projectRepo ports.ProjectRepository, bundle *domain.ProjectBundle, userID string) (*domain.Project, error) {
+	records := len(bundle.Milestones) + len(bundle.Tasks) + len(bundle.BudgetLines)
+	for _, task := range bundle.Tasks {
+		records += len(task.Subtasks)
+	}
+	if records > maxBundleRecords {
+		return nil, fmt.Errorf("project is too large")
+	}
+	// Clones copy the source's graph as stored, so it is checked again here
+	// like every graph a project is created with.
+	if _, err := orderTasks(bundle.Tasks); err != nil {
+		return nil, err
+	}
+
+	project := bundle.Project
+	bundle.Transitions = []domain.StatusTransition{{
+		ID:         uuid.New().String(),
+		EntityType: domain.StatusEntityProject,
+		EntityID:   project.ID,
+		ProjectID:  project.ID,
+		CompanyID:  project.CompanyID,
+		ToStatus:   project.Status,
+		UserID:     userID,
+		CreatedAt:  project.CreatedAt,
+	}}
+	if err := projectRepo.CreateProjectBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	return projectRepo.GetProjectByID(project.ID, project.CompanyID)
+}
+
+func newBundleProject(companyID, userID, name, clientID, address, summary string, start, now time.Time) *domain.Project {
+	return &domain.Project{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ClientID:  clientID,
+		Address:   address,
+		Summary:   summary,
+		StartDate: start,
+		Status:    domain.ProjectStatusPlanning,
+		UserID:    userID,
+		CompanyID: companyID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func validateTemplate(name string, tasks []domain.TemplateTask, presets []domain.DiaryFieldPreset) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+
+	records := len(tasks)
+	for _, task := range tasks {
+		records += len(task.Subtasks)
+	}
+	if records > maxBundleRecords {
+		return fmt.Errorf("template is too large")
+	}
+
+	graph := make([]domain.Task, len(tasks))
+	for index, task := range tasks {
+		if strings.TrimSpace(task.Name) == "" {
+			return fmt.Errorf("template task name is required")
+		}
+		if task.StartOffsetDays < 0 || task.DueOffsetDays < task.StartOffsetDays || task.Duration < 0 || (task.Weight != nil && *task.Weight < 0) {
+			return fmt.Errorf("invalid template task schedule")
+		}
+		graph[index].ID = fmt.Sprint(index)
+		for _, dependency := range task.Dependencies {
+			if dependency.TaskIndex < 0 || dependency.TaskIndex >= len(tasks) || dependency.TaskIndex == index {
+				return fmt.Errorf("invalid dependency")
+			}
+			if dependency.Type != "" && dependency.Type != domain.DependencyFinishToStart && dependency.Type != domain.DependencyStartToStart {
+				return fmt.Errorf("invalid dependency type")
+			}
+			graph[index].Dependencies = append(graph[index].Dependencies, domain.TaskDependency{
+				DependsOnTaskID: fmt.Sprint(dependency.TaskIndex),
+			})
+		}
+	}
+	if _, err := orderTasks(graph); err != nil {
+		return err
+	}
+
+	for _, preset := range presets {
+		if strings.TrimSpace(preset.Label) == "" {
+			return fmt.Errorf("diary field label is required")
+		}
+		if preset.Visibility != "public" && preset.Visibility != "internal" {
+			return fmt.Errorf("invalid diary item visibility")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"errors"
+	"testing"
+)
+
+// A clone copies its source's graph as stored, so a cycle left there must not
+// reach the new project.
+func TestCreateProjectBundleRejectsCycles(t *testing.T) {
+	bundle := &domain.ProjectBundle{
+		Project: &domain.Project{ID: "project", CompanyID: "company"},
+		Tasks: []domain.Task{
+			{ID: "a", Dependencies: []domain.TaskDependency{dependency("a", "b", domain.DependencyFinishToStart, 0)}},
+			{ID: "b", Dependencies: []domain.TaskDependency{dependency("b", "a", domain.DependencyFinishToStart, 0)}},
+		},
+	}
+	if _, err := createProjectBundle(nil, bundle, "user"); !errors.Is(err, errDependencyCycle) {
+		t.Fatalf("err = %v, want errDependencyCycle", err)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TrashService struct {
+	trashRepo      ports.TrashRepository
+	projectRepo    ports.ProjectRepository
+	clientRepo     ports.ClientRepository
+	linkRepo       ports.LinkRepository
+	attachmentRepo ports.AttachmentRepository
+	storage        ports.FileStorage
+}
+
+func NewTrashService(trashRepo ports.TrashRepository, projectRepo ports.ProjectRepository, clientRepo ports.ClientRepository, linkRepo ports.LinkRepository, attachmentRepo ports.AttachmentRepository, storage ports.FileStorage) *TrashService {
+	return &TrashService{
+		trashRepo:      trashRepo,
+		projectRepo:    projectRepo,
+		clientRepo:     clientRepo,
+		linkRepo:       linkRepo,
+		attachmentRepo: attachmentRepo,
+		storage:        storage,
+	}
+}
+
+func (s *TrashService) ListTrash(companyID string) (*domain.Trash, error) {
+	return s.trashRepo.GetTrash(companyID)
+}
+
+// PurgeExpired permanently deletes, children included, everything that has
+// been in the trash for longer than retention. Clients still referenced by a
+// project, a quote or an invoice stay in the trash until those are gone. It returns how many
+// projects, clients and links were removed.
+func (s *TrashService) PurgeExpired(retention time.Duration) (int, error) {
+	trash, err := s.trashRepo.GetTrashBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, project := range trash.Projects {
+		keys, err := s.attachmentKeys(project)
+		if err != nil {
+			return purged, fmt.Errorf("purge project %s: %w", project.ID, err)
+		}
+		if err := s.projectRepo.DeleteProject(project.ID, project.CompanyID); err != nil {
+			return purged, fmt.Errorf("purge project %s: %w", project.ID, err)
+		}
+		purged++
+		if err := s.deleteFiles(keys); err != nil {
+			return purged, fmt.Errorf("purge files of project %s: %w", project.ID, err)
+		}
+	}
+	for _, client := range trash.Clients {
+		err := s.clientRepo.DeleteClient(client.ID, client.CompanyID)
+		if errors.Is(err, ports.ErrClientInUse) {
+			continue
+		}
+		if err != nil {
+			return purged, fmt.Errorf("purge client %s: %w", client.ID, err)
+		}
+		purged++
+	}
+	for _, link := range trash.Links {
+		if err := s.linkRepo.DeleteLink(link.ID, link.CompanyID); err != nil {
+			return purged, fmt.Errorf("purge link %s: %w", link.ID, err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// attachmentKeys lists the storage keys of a project's attachments. They are
+// collected before the project is deleted, since the attachment records go
+// with it.
+func (s *TrashService) attachmentKeys(project domain.Project) ([]string, error) {
+	attachments, err := s.attachmentRepo.GetAttachmentsByProject(project.ID, project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		keys = append(keys, attachment.StorageKey)
+	}
+	return keys, nil
+}
+
+// deleteFiles removes the stored files, going on past a failure so that one
+// file does not keep the others behind. It returns the first error.
+func (s *TrashService) deleteFiles(keys []string) error {
+	var first error
+	for _, key := range keys {
+		if err := s.storage.Delete(key); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"construct-backend/internal/core/domain"
+	"construct-backend/internal/core/ports"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeTrashRepository struct {
+	ports.TrashRepository
+	trash domain.Trash
+}
+
+func (f *fakeTrashRepository) GetTrashBefore(time.Time) (*domain.Trash, error) {
+	return &f.trash, nil
+}
+
+// fakeClientRepository refuses to delete the clients in inUse, as the stores
+// do for clients that projects, quotes or invoices still reference.
+type fakeClientRepository struct {
+	ports.ClientRepository
+	inUse   map[string]bool
+	deleted []string
+}
+
+func (f *fakeClientRepository) DeleteClient(id, companyID string) error {
+	if f.inUse[id] {
+		return ports.ErrClientInUse
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestPurgeExpiredKeepsClientsInUse(t *testing.T) {
+	trash := &fakeTrashRepository{trash: domain.Trash{Clients: []domain.Client{
+		{ID: "quoted", CompanyID: "company"},
+		{ID: "unused", CompanyID: "company"},
+		{ID: "invoiced", CompanyID: "company"},
+	}}}
+	clients := &fakeClientRepository{inUse: map[string]bool{"quoted": true, "invoiced": true}}
+	service := NewTrashService(trash, nil, clients, nil, nil, nil)
+
+	purged, err := service.PurgeExpired(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeExpired: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("purged %d records, want 1", purged)
+	}
+	if !slices.Equal(clients.deleted, []string{"unused"}) {
+		t.Fatalf("deleted clients %v, want only the unused one", clients.deleted)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"construct-backend/internal/core/domain"
 	"construct-backend/internal/core/ports"
 	"errors"
-
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -16,12 +16,14 @@ import (
 type UserService struct {
 	userRepo ports.UserRepository
 	linkRepo ports.LinkRepository
+	storage  ports.FileStorage
 }
 
-func NewUserService(userRepo ports.UserRepository, linkRepo ports.LinkRepository) *UserService {
+func NewUserService(userRepo ports.UserRepository, linkRepo ports.LinkRepository, storage ports.FileStorage) *UserService {
 	return &UserService{
 		userRepo: userRepo,
 		linkRepo: linkRepo,
+		storage:  storage,
 	}
 }
 
@@ -97,6 +99,33 @@ func (s *UserService) UpdateBio(userID, bio string) error {
 	return nil
 }
 
+// UploadAvatar stores the user's avatar, cropped square in the standard sizes,
+// and removes the previous one.
+func (s *UserService) UploadAvatar(userID string, data []byte) (*domain.User, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	prefix := path.Join(ports.PublicStoragePrefix, "avatars", userID, uuid.New().String())
+	urls, err := storeImageVariants(s.storage, prefix, data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	previousKey, previousURLs := user.AvatarKey, user.AvatarThumbnails
+	user.Avatar = urls["large"]
+	user.AvatarThumbnails = urls
+	user.AvatarKey = prefix
+	if err := s.userRepo.UpdateAvatar(user); err != nil {
+		deleteImageVariants(s.storage, prefix, urls)
+		return nil, err
+	}
+	deleteImageVariants(s.storage, previousKey, previousURLs)
+
+	return user, nil
+}
+
 func (s *UserService) GetProfile(userID string) (*domain.User, error) {
 	return s.userRepo.GetUserByID(userID)
 }